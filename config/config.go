@@ -1,7 +1,9 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -15,29 +17,61 @@ import (
 //   - Port: The port number on which the server will listen for incoming requests.
 //   - MysqlURI: The URI for connecting to the MySQL database used by the API.
 //   - Migrate: it's used to specify whether to run database migrations or not. If the value is set to "true", the application will run database migrations on startup. If the value is set to "false", the application will not run database migrations.
+//   - Storage: The storage backend of the content of the rulesheets, `gitlab` (the default), `github`, `gitea` or `local`.
+//   - LocalPath: The directory where the `local` storage backend keeps the content of the rulesheets.
 //   - GitlabToken: This's a token used for authentication with GitLab API. It allows the application to access GitLab resources on behalf of a user or a bot account.
 //   - GitlabURL: The URL of the GitLab instance that the API will interact with.
 //   - GitlabNamespace: The namespace or group name in GitLab where the project is located.
 //   - GitlabPrefix: The GitlabPrefix property is a string that represents the prefix to be used for all GitLab API requests. It is used to specify the namespace or group where the project's located. For example, if the GitlabPrefix is set to "mygroup/myproject", all API requests will be made.
 //   - GitlabDefaultBranch: This property represents the default branch name for a GitLab repository. When creating a new repository, GitLab will use this branch as the default branch.
 //   - GitlabCIScript - GitlabCIScript is a property in the Config struct that represents the GitLab CI script that will be used for building and testing the project. It is specified in the configuration file using the key "FEATWS_API_GITLAB_CI_SCRIPT".
+//   - GitlabTagFormat: The name of the annotated tag created on each version published by the API, where `{version}` is replaced by the version number. Leave it empty to disable the tags.
+//   - GitlabReviewMode: When enabled, the changes of a rulesheet are committed to a draft branch and a merge request is opened against the default branch, to be approved and published (merged) or discarded through the API.
+//   - GithubToken: The token used for authentication with the GitHub API by the `github` storage backend.
+//   - GithubURL: The URL of the GitHub API, e.g. `https://api.github.com` or `https://<host>/api/v3` on GitHub Enterprise.
+//   - GithubOwner: The organization that owns the repositories of the rulesheets on GitHub.
+//   - GithubCIScript: The GitHub Actions workflow committed to `.github/workflows/featws.yml` on the repositories. Leave it empty to not commit a workflow.
+//   - GiteaToken: The token used for authentication with the Gitea API by the `gitea` storage backend.
+//   - GiteaURL: The URL of the Gitea API, e.g. `https://<host>/api/v1`.
+//   - GiteaOwner: The organization that owns the repositories of the rulesheets on Gitea.
+//   - GiteaCIScript: The Gitea Actions workflow committed to `.gitea/workflows/featws.yml` on the repositories. Leave it empty to not commit a workflow.
+//   - SyncInterval: The interval of the sync worker, which pushes the pending changes of the rulesheets from the outbox to the storage backend. The retries of a failed attempt wait twice as long as the previous one, starting from it.
+//   - SyncMaxAttempts: The number of attempts to push a change before its sync is marked as failed, waiting to be retried by an operator.
+//   - TestsBlockUpdates: Whether the creations and updates of the rulesheets whose test cases fail are rejected.
 //   - ExternalHost - This property represents the external host name or IP address of the server where the application is running. It is used to configure the application to listen on a specific network interface or to generate URLs that can be accessed from outside the server.
 //   - OpenAMURL: The URL of the OpenAM server used for authentication.
 //   - AuthMode - This property specifies the authentication mode used by the API. It can have values like "jwt", "oauth2", "basic", etc.
+//
+// The `GitlabPrefix`, `GitlabDefaultBranch` and `GitlabTagFormat` properties also define the name, the branch and the tags of the repositories on GitHub and Gitea.
 type Config struct {
-	AllowOrigins        string `mapstructure:"ALLOW_ORIGINS"`
-	Port                string `mapstructure:"PORT"`
-	MysqlURI            string `mapstructure:"FEATWS_API_MYSQL_URI"`
-	Migrate             string `mapstructure:"MIGRATE"`
-	GitlabToken         string `mapstructure:"FEATWS_API_GITLAB_TOKEN"`
-	GitlabURL           string `mapstructure:"FEATWS_API_GITLAB_URL"`
-	GitlabNamespace     string `mapstructure:"FEATWS_API_GITLAB_NAMESPACE"`
-	GitlabPrefix        string `mapstructure:"FEATWS_API_GITLAB_PREFIX"`
-	GitlabDefaultBranch string `mapstructure:"FEATWS_API_GITLAB_DEFAULT_BRANCH"`
-	GitlabCIScript      string `mapstructure:"FEATWS_API_GITLAB_CI_SCRIPT"`
-	ExternalHost        string `mapstructure:"EXTERNAL_HOST"`
-	OpenAMURL           string `mapstructure:"OPENAM_URL"`
-	AuthMode            string `mapstructure:"FEATWS_API_AUTH_MODE"`
+	AllowOrigins        string        `mapstructure:"ALLOW_ORIGINS"`
+	Port                string        `mapstructure:"PORT"`
+	MysqlURI            string        `mapstructure:"FEATWS_API_MYSQL_URI"`
+	Migrate             string        `mapstructure:"MIGRATE"`
+	Storage             string        `mapstructure:"FEATWS_API_STORAGE"`
+	LocalPath           string        `mapstructure:"FEATWS_API_LOCAL_PATH"`
+	GitlabToken         string        `mapstructure:"FEATWS_API_GITLAB_TOKEN"`
+	GitlabURL           string        `mapstructure:"FEATWS_API_GITLAB_URL"`
+	GitlabNamespace     string        `mapstructure:"FEATWS_API_GITLAB_NAMESPACE"`
+	GitlabPrefix        string        `mapstructure:"FEATWS_API_GITLAB_PREFIX"`
+	GitlabDefaultBranch string        `mapstructure:"FEATWS_API_GITLAB_DEFAULT_BRANCH"`
+	GitlabCIScript      string        `mapstructure:"FEATWS_API_GITLAB_CI_SCRIPT"`
+	GitlabTagFormat     string        `mapstructure:"FEATWS_API_GITLAB_TAG_FORMAT"`
+	GitlabReviewMode    bool          `mapstructure:"FEATWS_API_GITLAB_REVIEW_MODE"`
+	GithubToken         string        `mapstructure:"FEATWS_API_GITHUB_TOKEN"`
+	GithubURL           string        `mapstructure:"FEATWS_API_GITHUB_URL"`
+	GithubOwner         string        `mapstructure:"FEATWS_API_GITHUB_OWNER"`
+	GithubCIScript      string        `mapstructure:"FEATWS_API_GITHUB_CI_SCRIPT"`
+	GiteaToken          string        `mapstructure:"FEATWS_API_GITEA_TOKEN"`
+	GiteaURL            string        `mapstructure:"FEATWS_API_GITEA_URL"`
+	GiteaOwner          string        `mapstructure:"FEATWS_API_GITEA_OWNER"`
+	GiteaCIScript       string        `mapstructure:"FEATWS_API_GITEA_CI_SCRIPT"`
+	SyncInterval        time.Duration `mapstructure:"FEATWS_API_SYNC_INTERVAL"`
+	SyncMaxAttempts     int           `mapstructure:"FEATWS_API_SYNC_MAX_ATTEMPTS"`
+	TestsBlockUpdates   bool          `mapstructure:"FEATWS_API_TESTS_BLOCK_UPDATES"`
+	ExternalHost        string        `mapstructure:"EXTERNAL_HOST"`
+	OpenAMURL           string        `mapstructure:"OPENAM_URL"`
+	AuthMode            string        `mapstructure:"FEATWS_API_AUTH_MODE"`
 }
 
 var config = &Config{}
@@ -53,12 +87,27 @@ func LoadConfig() (err error) {
 	viper.SetDefault("ALLOW_ORIGINS", "http://localhost")
 	viper.SetDefault("PORT", "9007")
 	viper.SetDefault("FEATWS_API_MYSQL_URI", "api:api@tcp(localhost:3306)/api")
+	viper.SetDefault("FEATWS_API_STORAGE", "gitlab")
+	viper.SetDefault("FEATWS_API_LOCAL_PATH", "./data/rulesheets")
 	viper.SetDefault("FEATWS_API_GITLAB_TOKEN", "")
 	viper.SetDefault("FEATWS_API_GITLAB_URL", "")
 	viper.SetDefault("FEATWS_API_GITLAB_NAMESPACE", "")
 	viper.SetDefault("FEATWS_API_GITLAB_PREFIX", "")
 	viper.SetDefault("FEATWS_API_GITLAB_DEFAULT_BRANCH", "main")
 	viper.SetDefault("FEATWS_API_GITLAB_CI_SCRIPT", "")
+	viper.SetDefault("FEATWS_API_GITLAB_TAG_FORMAT", "v{version}")
+	viper.SetDefault("FEATWS_API_GITLAB_REVIEW_MODE", false)
+	viper.SetDefault("FEATWS_API_GITHUB_TOKEN", "")
+	viper.SetDefault("FEATWS_API_GITHUB_URL", "https://api.github.com")
+	viper.SetDefault("FEATWS_API_GITHUB_OWNER", "")
+	viper.SetDefault("FEATWS_API_GITHUB_CI_SCRIPT", "")
+	viper.SetDefault("FEATWS_API_GITEA_TOKEN", "")
+	viper.SetDefault("FEATWS_API_GITEA_URL", "")
+	viper.SetDefault("FEATWS_API_GITEA_OWNER", "")
+	viper.SetDefault("FEATWS_API_GITEA_CI_SCRIPT", "")
+	viper.SetDefault("FEATWS_API_SYNC_INTERVAL", "30s")
+	viper.SetDefault("FEATWS_API_SYNC_MAX_ATTEMPTS", 5)
+	viper.SetDefault("FEATWS_API_TESTS_BLOCK_UPDATES", false)
 	viper.SetDefault("EXTERNAL_HOST", "localhost:9007")
 	viper.SetDefault("MIGRATE", "")
 	viper.SetDefault("OPENAM_URL", "")
@@ -74,10 +123,27 @@ func LoadConfig() (err error) {
 	}
 
 	err = viper.Unmarshal(config)
+	if err != nil {
+		return
+	}
+
+	err = config.validate()
 
 	return
 }
 
+// validate checks the properties that would make the API fail after it starts, like the interval of the
+// sync worker, which can't be used by a ticker unless it's positive.
+func (c *Config) validate() error {
+	if c.SyncInterval <= 0 {
+		return fmt.Errorf("FEATWS_API_SYNC_INTERVAL must be a positive duration, like 30s, but it's %s", c.SyncInterval)
+	}
+	if c.SyncMaxAttempts < 1 {
+		return fmt.Errorf("FEATWS_API_SYNC_MAX_ATTEMPTS must be at least 1, but it's %d", c.SyncMaxAttempts)
+	}
+	return nil
+}
+
 // GetConfig returns a pointer to a Config object.
 func GetConfig() *Config {
 	return config
@@ -2,13 +2,18 @@ package v1
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/gosimple/slug"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/bancodobrasil/featws-api/dtos"
+	"github.com/bancodobrasil/featws-api/patch"
 	payloads "github.com/bancodobrasil/featws-api/payloads/v1"
 	responses "github.com/bancodobrasil/featws-api/responses/v1"
 	"github.com/bancodobrasil/featws-api/services"
@@ -19,20 +24,68 @@ import (
 //
 // Property:
 //   - CreateRulesheet: A function that handles the creation of a new rulesheet. It should receive data from the client and store it in a db or other storage system.
+//   - ImportRulesheet: is a function that handles the HTTP POST request to create or update a rulesheet from uploaded files, a legacy rules.featws file or a bundle of features.json, parameters.json and rules.json, with a dry-run option.
 //   - GetRulesheets: is a function that handles the HTTP GET request to retrieve a list of all rulesheets. It returns a gin.HandlerFunc which is a function that handles the request and sends the response.
 //   - GetRulesheet: is a function that handles the HTTP GET request to retrieve a specific rulesheet from a database or other data source. It takes in a gin.Context object as a parameter and returns a gin.HandlerFunc that can be used as a middleware to handle the request. The function should typically extract the ID
+//   - GetRulesheetBySlug: is a function that handles the HTTP GET request to retrieve a specific rulesheet by its slug, like `GetRulesheet`.
+//   - ResolveRulesheetSlug: is a function that finds the rulesheet of the "slug" param and sets its ID on the "id" param, so the next handlers of the route, like `UpdateRulesheet` and `DeleteRulesheet`, can be used by slug.
 //   - UpdateRulesheet: is a function that handles the updating of a specific rulesheet. It takes in a gin context object and returns a gin handler function. This handler function should retrieve the updated rulesheet data from the request body, validate it, and update the corresponding rulesheet in the database. The handler
+//   - PatchRulesheet: is a function that handles the HTTP PATCH request to change a part of a specific rulesheet with a JSON Merge Patch or a JSON Patch, which is validated and saved like an update.
 //   - DeleteRulesheet: is a function that handles the deletion of a specific
 //
 // rulesheet. It is a gin.HandlerFunc, which means it is a function that takes in a gin.Context object
 // as its parameter and returns nothing. The function should retrieve the ID of the rulesheet to be
 // deleted from the request parameters or
+//   - GetRulesheetVersions: is a function that handles the HTTP GET request to list the versions published for a specific rulesheet, with pagination.
+//   - GetRulesheetVersion: is a function that handles the HTTP GET request to retrieve a specific rulesheet as it was at a given version number or commit SHA.
+//   - RollbackRulesheet: is a function that handles the HTTP POST request to restore the content of a specific rulesheet from a previous version.
+//   - GetRulesheetDiff: is a function that handles the HTTP GET request to compare two versions of a specific rulesheet, listing the features, parameters and rules that were added, removed or changed.
+//   - EvaluateRulesheet: is a function that handles the HTTP POST request to compute the features of a specific rulesheet for a set of parameters, without publishing it.
+//   - RunRulesheetTests: is a function that handles the HTTP POST request to run the test cases of a specific rulesheet, returning pass or fail for each one with the actual and expected values.
+//   - GetRulesheetGraph: is a function that handles the HTTP GET request to build the dependency graph between the rules, features and parameters of a specific rulesheet, as JSON, Graphviz DOT or Mermaid.
+//   - ExportRulesheet: is a function that handles the HTTP GET request to write the rules of a specific rulesheet on another format, like the legacy rules.featws format.
+//   - ConvertRulesheet: is a function that handles the HTTP POST request to convert the string rules of a specific legacy rulesheet, loaded from its rules.featws file, into structured rules, previewing the result or committing it.
+//   - ConvertRulesheets: is a function that handles the HTTP POST request to convert every legacy rulesheet like `ConvertRulesheet`.
+//   - GetRulesheetChanges: is a function that handles the HTTP GET request to list the pending changes of a specific rulesheet, waiting on a merge request on review mode.
+//   - ApproveRulesheetChange: is a function that handles the HTTP POST request to approve a pending change of a specific rulesheet.
+//   - PublishRulesheetChange: is a function that handles the HTTP POST request to publish (merge) an approved pending change of a specific rulesheet.
+//   - DiscardRulesheetChange: is a function that handles the HTTP DELETE request to discard a pending change of a specific rulesheet.
+//   - GetRulesheetRule, GetRulesheetFeature, GetRulesheetParameter: are functions that handle the HTTP GET requests to retrieve a single rule, feature or parameter of a specific rulesheet by its name.
+//   - UpdateRulesheetRule, UpdateRulesheetFeature, UpdateRulesheetParameter: are functions that handle the HTTP PUT requests to create or replace a single rule, feature or parameter of a specific rulesheet, committed with a message of the change, like "Update rule discount".
+//   - DeleteRulesheetRule, DeleteRulesheetFeature, DeleteRulesheetParameter: are functions that handle the HTTP DELETE requests to remove a single rule, feature or parameter of a specific rulesheet, committed with a message of the change.
 type Rulesheets interface {
 	CreateRulesheet() gin.HandlerFunc
+	ImportRulesheet() gin.HandlerFunc
 	GetRulesheets() gin.HandlerFunc
 	GetRulesheet() gin.HandlerFunc
+	GetRulesheetBySlug() gin.HandlerFunc
+	ResolveRulesheetSlug() gin.HandlerFunc
 	UpdateRulesheet() gin.HandlerFunc
+	PatchRulesheet() gin.HandlerFunc
 	DeleteRulesheet() gin.HandlerFunc
+	GetRulesheetVersions() gin.HandlerFunc
+	GetRulesheetVersion() gin.HandlerFunc
+	RollbackRulesheet() gin.HandlerFunc
+	GetRulesheetDiff() gin.HandlerFunc
+	EvaluateRulesheet() gin.HandlerFunc
+	RunRulesheetTests() gin.HandlerFunc
+	GetRulesheetGraph() gin.HandlerFunc
+	ExportRulesheet() gin.HandlerFunc
+	ConvertRulesheet() gin.HandlerFunc
+	ConvertRulesheets() gin.HandlerFunc
+	GetRulesheetChanges() gin.HandlerFunc
+	ApproveRulesheetChange() gin.HandlerFunc
+	PublishRulesheetChange() gin.HandlerFunc
+	DiscardRulesheetChange() gin.HandlerFunc
+	GetRulesheetRule() gin.HandlerFunc
+	UpdateRulesheetRule() gin.HandlerFunc
+	DeleteRulesheetRule() gin.HandlerFunc
+	GetRulesheetFeature() gin.HandlerFunc
+	UpdateRulesheetFeature() gin.HandlerFunc
+	DeleteRulesheetFeature() gin.HandlerFunc
+	GetRulesheetParameter() gin.HandlerFunc
+	UpdateRulesheetParameter() gin.HandlerFunc
+	DeleteRulesheetParameter() gin.HandlerFunc
 }
 
 // The type "rulesheets" contains a service called "services.Rulesheets". The "service" property is a variable of type "services.Rulesheets". It is likely
@@ -64,6 +117,8 @@ func NewRulesheets(service services.Rulesheets) Rulesheets {
 // @Description  		```
 // @Description 		Ambos esses parâmetros devem ser uma string, ou seja, deve estar entre "aspas". Não é possível ter uma folha de regra com o mesmo nome de outra.
 // @Description			Para criar uma folha de regra basta clicar em **Try it out** , complete a folha de regra com os dados desejados, em seguida, clique em **Execute**.
+// @Description			As expressões *condition* e *dynamic* das regras são validadas antes de salvar. Uma expressão inválida, ou que referencia um parâmetro (*$nome*) ou uma feature (*#nome*) não declarados em *parameters* ou *features* nem definidos por outra regra, ou cujo tipo não corresponde ao tipo declarado na regra (*type*) ou na feature (*boolean*, *integer*, *decimal*, *string*, *date*, *list* ou *map*), retorna 400 com o caminho da regra (por exemplo, *rules.desconto[2].condition*) e a posição do erro na expressão em *validation_errors*. Um valor em texto só é uma referência quando é apenas a referência a um parâmetro ou feature declarado, como *#limite*; nos demais textos, como *#FF0000* ou *https://x/#secao*, o que parece uma referência é mantido como texto e retornado como aviso em *warnings*, assim como os parâmetros declarados que não são usados por nenhuma regra.
+// @Description			Os casos de teste em *tests*, com os valores dos parâmetros (*parameters*) e os valores esperados das features (*expected*), são salvos no *tests.json*; quando omitidos na atualização, os casos já salvos são mantidos. Com *FEATWS_API_TESTS_BLOCK_UPDATES* habilitado, a folha de regra cujos casos de teste falham não é salva e retorna 422 com as features que não correspondem em *validation_errors*.
 // @Tags 				Rulesheet
 // @Accept  			json
 // @Produce  			json
@@ -71,6 +126,7 @@ func NewRulesheets(service services.Rulesheets) Rulesheets {
 // @Success 			200 {object} payloads.Rulesheet
 // @Header 				200 {string} Authorization "token access"
 // @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			422 {object} responses.Error "Unprocessable Entity"
 // @Failure 			500 {object} responses.Error "Internal Server Error"
 // @Failure 			default {object} responses.Error
 // @Response 			404 "Not Found"
@@ -117,6 +173,28 @@ func (rc *rulesheets) CreateRulesheet() gin.HandlerFunc {
 			return
 		}
 
+		// validate the expressions of the rules and their references before anything is committed
+		validationErr, warnings := validateRules(&dto)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, validationErr)
+			log.Errorf("Error on validate the rule expressions: %v", validationErr)
+			return
+		}
+
+		testsErr, err := checkTests(&dto)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on run the rulesheet tests: %v", err)
+			return
+		}
+		if testsErr != nil {
+			c.JSON(http.StatusUnprocessableEntity, testsErr)
+			log.Errorf("Error on run the rulesheet tests: %v", testsErr)
+			return
+		}
+
 		err = rc.service.Create(ctx, &dto)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, responses.Error{
@@ -127,20 +205,220 @@ func (rc *rulesheets) CreateRulesheet() gin.HandlerFunc {
 		}
 
 		var response = responses.NewRulesheet(&dto)
+		response.Warnings = warnings
 		//id := c.Query("id")
 		c.JSON(http.StatusCreated, response)
 	}
 
 }
 
+// ImportRulesheet 	godoc
+// @Summary 			Importar Folha de Regra a partir de Arquivos
+// @Description 		Cria ou atualiza uma folha de regra a partir de arquivos enviados em um formulário *multipart*, para migrar as folhas de regra de outros sistemas sem escrever as chamadas à API. Os arquivos, enviados no campo *files*, são identificados pelo nome: um arquivo *.featws* com as regras no formato legado, que são convertidas para regras estruturadas como na conversão das regras em texto, ou um pacote com *features.json*, *parameters.json*, *rules.json* e, opcionalmente, *tests.json*, como são salvos no repositório. Os campos *name*, *slug* e *description* do formulário identificam a folha de regra: se já existe uma com o mesmo *slug* (ou, sem ele, com o *slug* gerado a partir do *name*), ela é atualizada, mantendo o nome, a descrição e os casos de teste que não forem enviados; senão, é criada. O conteúdo é validado como na criação e na atualização. Com *dryRun=true*, a folha de regra é só validada e retornada, sem ser salva.
+// @Tags 				Rulesheet
+// @Accept  			multipart/form-data
+// @Produce  			json
+// @Param				files formData file true "Rulesheet files: a .featws file or features.json, parameters.json, rules.json and tests.json"
+// @Param				name formData string false "Rulesheet name, required to create it"
+// @Param				slug formData string false "Rulesheet slug"
+// @Param				description formData string false "Rulesheet description"
+// @Param				dryRun query bool false "Only validate the import"
+// @Success 			200 {object} responses.Import
+// @Success 			201 {object} responses.Import
+// @Header 				200 {string} Authorization "token access"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			409 {object} responses.Error "Conflict"
+// @Failure 			422 {object} responses.Error "Unprocessable Entity"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/import [post]
+// ImportRulesheet returns a `gin.HandlerFunc` that reads the files uploaded on the request into a
+// rulesheet, validates it like the creations and updates do and then creates it, or updates the rulesheet
+// with the same slug, unless the "dryRun" query parameter is set. It returns a 201 status code when the
+// rulesheet is created and a 200 status code otherwise.
+func (rc *rulesheets) ImportRulesheet() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 1000*time.Second)
+		defer cancel()
+
+		dryRun, err := parseBoolQuery(c.Request.URL.Query(), "dryRun")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		form, err := c.MultipartForm()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on read the multipart form: %v", err)
+			return
+		}
+
+		files, err := readUploadedFiles(form)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on read the uploaded files: %v", err)
+			return
+		}
+
+		payload, problems, err := services.ReadImportFiles(files)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on read the imported files: %v", err)
+			return
+		}
+		if len(problems) > 0 {
+			validationErr := &responses.Error{ValidationErrors: make([]responses.ValidationError, 0, len(problems))}
+			for _, problem := range problems {
+				validationErr.ValidationErrors = append(validationErr.ValidationErrors, responses.ValidationError{
+					Field:    problem.Path,
+					Tag:      problem.Tag,
+					Error:    problem.Message,
+					Position: problem.Position,
+				})
+			}
+			c.JSON(http.StatusBadRequest, validationErr)
+			log.Errorf("Error on convert the imported rules: %v", validationErr)
+			return
+		}
+
+		payload.Name = c.PostForm("name")
+		payload.Slug = c.PostForm("slug")
+		payload.Description = c.PostForm("description")
+		if payload.Slug == "" {
+			payload.Slug = slug.Make(payload.Name)
+		}
+
+		// the rulesheet with the same slug is updated
+		var foudedEntity *dtos.Rulesheet
+		if payload.Slug != "" {
+			foudedEntity, err = rc.service.GetBySlug(ctx, payload.Slug)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, responses.Error{
+					Error: err.Error(),
+				})
+				log.Errorf("Error on fetch the rulesheet to import: %v", err)
+				return
+			}
+		}
+
+		if foudedEntity != nil {
+			payload.ID = foudedEntity.ID
+			if payload.Name == "" {
+				payload.Name = foudedEntity.Name
+			}
+			if payload.Description == "" {
+				payload.Description = foudedEntity.Description
+			}
+		}
+
+		// use the validator libraty to validate required fields
+		if validationErr := validatePayload(payload); validationErr != nil {
+			c.JSON(http.StatusBadRequest, validationErr)
+			log.Errorf("Error on validate required fields: %v", validationErr)
+			return
+		}
+
+		dto, err := dtos.NewRulesheetV1(*payload)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on define rulesheet entity: %v", err)
+			return
+		}
+
+		// validate the expressions of the rules and their references before anything is committed
+		validationErr, warnings := validateRules(&dto)
+		if validationErr != nil {
+			c.JSON(http.StatusBadRequest, validationErr)
+			log.Errorf("Error on validate the rule expressions: %v", validationErr)
+			return
+		}
+
+		// the test cases that weren't given are kept
+		if foudedEntity != nil && dto.Tests == nil {
+			dto.Tests = foudedEntity.Tests
+		}
+
+		testsErr, err := checkTests(&dto)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on run the rulesheet tests: %v", err)
+			return
+		}
+		if testsErr != nil {
+			c.JSON(http.StatusUnprocessableEntity, testsErr)
+			log.Errorf("Error on run the rulesheet tests: %v", testsErr)
+			return
+		}
+
+		result := &dto
+		if !dryRun && foudedEntity == nil {
+			err = rc.service.Create(ctx, result)
+		} else if !dryRun {
+			result, err = rc.service.Update(ctx, dto)
+		}
+		if err != nil {
+			if errors.Is(err, services.ErrVersionConflict) {
+				c.JSON(http.StatusConflict, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			if errors.Is(err, services.ErrLegacyRules) {
+				c.JSON(http.StatusUnprocessableEntity, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on import rulesheet: %v", err)
+			return
+		}
+
+		response := responses.Import{
+			Rulesheet: responses.NewRulesheet(result),
+			Created:   foudedEntity == nil,
+			DryRun:    dryRun,
+		}
+		response.Rulesheet.Warnings = warnings
+
+		if response.Created && !dryRun {
+			c.JSON(http.StatusCreated, response)
+			return
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
 // GetRulesheets 		godoc
 // @Summary 			Listar as Folhas de Regra
 // @Description			É possível listar as folhas de regra de algumas maneiras como veremos a seguir:
 // @Description
 // @Description			- **Sem nenhum parâmetro:** Ao realizar a chamada do endpoint sem a passagem de parâmetros, todas as folhas de regra existentes serão retornadas, contendo informações como nome, ID, e caso estejam disponíveis, descrição e slug.
-// @Description			- **Usando o *count*:** Ao habilitar o *count* para *True* será retornado do endpoint o número de Folhas de Regras existentes.
-// @Description			- **Usando o *limit*:** Ao utilizar o parâmetro *limit* deve-se especificar o número máximo de respostas desejadas que serão retornadas pela array.
-// @Description			- **Usando o *page*:** Ao utilizar o parâmetro *page*, serão retornadas as folhas de regra correspondentes a essa página, onde as folhas são ordenadas em ordem crescente pelo seu ID.
+// @Description			- **Paginação:** A resposta traz a página de folhas de regra em *items*, o número total de folhas de regra que atendem aos filtros em *total*, e os parâmetros *page* e *limit* usados. O parâmetro *limit* define o número máximo de folhas de regra da página, 10 por padrão, e *page* o número da página, a partir de 1.
+// @Description			- **Usando o *count*:** Ao habilitar o *count* será retornado apenas o *total*, sem os *items*.
+// @Description			- **Usando o *sort*:** Ordena as folhas de regra pelos campos informados, separados por vírgula, dentre *id*, *name*, *slug*, *createdAt* e *updatedAt*. Os campos precedidos por *-* são ordenados de forma decrescente, como em *name,-updatedAt*. Por padrão, e como desempate, as folhas de regra são ordenadas de forma crescente pelo ID.
+// @Description			- **Usando o *cursor*:** Quando há mais folhas de regra, a resposta traz em *next* o cursor da próxima página, que pode ser passado no parâmetro *cursor*, com o mesmo *sort*, para buscar as folhas de regra seguintes sem calcular o deslocamento, o que é mais eficiente em tabelas grandes. O *cursor* não pode ser usado junto com o *page*.
+// @Description			- **Usando os filtros:** Os parâmetros *name* e *slug* filtram as folhas de regra cujo nome ou slug começa com o texto informado, *description* filtra as que têm o texto na descrição e *q* busca o texto no nome, slug ou descrição, sem diferenciar maiúsculas e minúsculas. Os parâmetros *createdFrom*, *createdTo*, *updatedFrom* e *updatedTo* limitam as datas de criação e de atualização, como *2023-01-31* (o dia inteiro) ou *2023-01-31T10:00:00Z*. O parâmetro *deleted* aceita *exclude*, o padrão, que ignora as folhas de regra excluídas, *only*, que retorna só as excluídas, ou *include*, que retorna todas. Os filtros também valem para o *count*.
 // @Description
 // @Description			Para listar as folhas de regra basta clicar em **Try it out** , complete com o formado desejados, em seguida, clique em **Execute**.
 // @Tags 				Rulesheet
@@ -149,7 +427,18 @@ func (rc *rulesheets) CreateRulesheet() gin.HandlerFunc {
 // @Param				count query boolean false "Total of results"
 // @Param				limit query integer false "Max length of the array returned"
 // @Param				page query integer false "Page number that is multiplied by 'limit' to calculate the offset"
-// @Success 			200 {array} payloads.Rulesheet
+// @Param				sort query string false "Fields to order by, like 'name,-updatedAt'"
+// @Param				cursor query string false "Cursor of the next page, from 'next'"
+// @Param				name query string false "Prefix of the name"
+// @Param				slug query string false "Prefix of the slug"
+// @Param				description query string false "Text on the description"
+// @Param				q query string false "Text on the name, slug or description"
+// @Param				createdFrom query string false "Created at or after the date"
+// @Param				createdTo query string false "Created at or before the date"
+// @Param				updatedFrom query string false "Updated at or after the date"
+// @Param				updatedTo query string false "Updated at or before the date"
+// @Param				deleted query string false "Deleted state" Enums(exclude, only, include)
+// @Success 			200 {object} responses.RulesheetPage
 // @Header 				200 {string} Authorization "token access"
 // @Failure 			400 {object} responses.Error "Bad Format"
 // @Failure 			500 {object} responses.Error "Internal Server Error"
@@ -159,10 +448,10 @@ func (rc *rulesheets) CreateRulesheet() gin.HandlerFunc {
 // @Security 			Authentication Bearer Token
 // @Router 				/rulesheets/ [get]
 // GetRulesheets returns a `gin.HandlerFunc`, this function handles HTTP requests to retrieve rulesheets
-// from a database. It first extracts any query parameters from the request URL and uses them to set options
-// for the database query. If the `count` parameter is present, it returns the count of rulesheets that match
-// the query. Otherwise, it retrieves the rulesheets from the database and returns them as a JSON response.
-// The response is formatted using a `responses.Rulesheet` struct.
+// from a database. It first extracts any query parameters from the request URL and uses them to set the filter
+// and options for the database query, returning a 400 status code if the filter, the pagination or the sort is invalid. It always
+// counts the rulesheets that match the query and, unless the `count` parameter is present, retrieves a page of them from the database.
+// The response is formatted using a `responses.RulesheetPage` struct, with the cursor of the next page when there are more rulesheets.
 func (rc *rulesheets) GetRulesheets() gin.HandlerFunc {
 
 	return func(c *gin.Context) {
@@ -171,84 +460,88 @@ func (rc *rulesheets) GetRulesheets() gin.HandlerFunc {
 		defer cancel()
 
 		query := c.Request.URL.Query()
-		filter := make(map[string]interface{})
-		// TODO: Implement filters correctly
-
-		// for param, value := range query {
-		// 	if len(value) == 1 {
-		// 		filter[param] = value[0]
-		// 		continue
-		// 	}
-		// 	filter[param] = value
-		// }
-
-		opts := &services.FindOptions{}
-
-		limit, ok := query["limit"]
-		if ok {
-			limitInt, err := strconv.Atoi(limit[0])
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, responses.Error{
-					Error: err.Error(),
-				})
-				log.Errorf("Error on fetch more than one rulesheet: %v", err)
-				return
-			}
-			opts.Limit = limitInt
+		filter, err := parseRulesheetFilter(query)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on parse the rulesheet filter: %v", err)
+			return
 		}
 
-		page, ok := query["page"]
-		if ok {
-			pageInt, err := strconv.Atoi(page[0])
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, responses.Error{
-					Error: err.Error(),
-				})
-				log.Errorf("Error on fetch more than one rulesheet: %v", err)
-				return
+		opts, err := parseFindOptions(query)
+		if err == nil {
+			opts.Sort, err = services.ParseSort(query.Get("sort"))
+		}
+		if err == nil {
+			opts.Cursor = query.Get("cursor")
+			if _, hasPage := query["page"]; hasPage && opts.Cursor != "" {
+				err = errors.New("the query params 'page' and 'cursor' can't be used together")
 			}
-			opts.Page = pageInt
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on parse the find options: %v", err)
+			return
 		}
 
-		_, isCount := query["count"]
-
-		if !isCount {
-			dtos, err := rc.service.Find(ctx, filter, opts)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, responses.Error{
-					Error: err.Error(),
-				})
-				log.Errorf("Error on fetch more than one rulesheet: %v", err)
-				return
-			}
+		if opts.Limit == 0 {
+			opts.Limit = defaultPageLimit
+		}
+		if opts.Page == 0 && opts.Cursor == "" {
+			opts.Page = 1
+		}
 
-			var response = make([]responses.Rulesheet, len(dtos))
+		total, err := rc.service.Count(ctx, filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on count filterValue: %v", err)
+			return
+		}
 
-			for index, dto := range dtos {
-				response[index] = responses.NewRulesheet(dto)
-			}
+		response := responses.RulesheetPage{
+			Items: make([]responses.Rulesheet, 0),
+			Total: total,
+			Page:  opts.Page,
+			Limit: opts.Limit,
+		}
 
+		if _, isCount := query["count"]; isCount {
 			c.JSON(http.StatusOK, response)
-		} else {
-			count, err := rc.service.Count(ctx, filter)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, responses.Error{
-					Error: err.Error(),
-				})
-				log.Errorf("Error on count filterValue: %v", err)
-				return
-			}
+			return
+		}
 
-			var response = []responses.Rulesheet{
-				{
-					FindResult: responses.FindResult{
-						Count: count,
-					},
-				},
+		dtos, err := rc.service.Find(ctx, filter, opts)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, services.ErrInvalidCursor) || errors.Is(err, services.ErrInvalidSort) {
+				status = http.StatusBadRequest
 			}
+			c.JSON(status, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on fetch more than one rulesheet: %v", err)
+			return
+		}
 
-			c.JSON(http.StatusOK, response)
+		for _, dto := range dtos {
+			response.Items = append(response.Items, responses.NewRulesheet(dto))
 		}
+
+		// on the keyset pagination the rulesheets before the cursor aren't known, so a full page means there may be more
+		hasNext := len(dtos) == opts.Limit
+		if opts.Cursor == "" {
+			hasNext = int64((opts.Page-1)*opts.Limit+len(dtos)) < total
+		}
+		if hasNext && len(dtos) > 0 {
+			response.Next = services.NextCursor(dtos[len(dtos)-1], opts.Sort)
+		}
+
+		c.JSON(http.StatusOK, response)
 	}
 }
 
@@ -261,6 +554,7 @@ func (rc *rulesheets) GetRulesheets() gin.HandlerFunc {
 // @Param				id path string true "Rulesheet ID"
 // @Success 			200 {array} payloads.Rulesheet
 // @Header 				200 {string} Authorization "token access"
+// @Header 				200 {string} ETag "Versão da folha de regra"
 // @Failure 			400 {object} responses.Error "Bad Format"
 // @Failure 			500 {object} responses.Error "Internal Server Error"
 // @Failure 			default {object} responses.Error
@@ -302,6 +596,7 @@ func (rc *rulesheets) GetRulesheet() gin.HandlerFunc {
 		if entity != nil {
 			var response = responses.NewRulesheet(entity)
 
+			setRulesheetETag(c, entity)
 			c.JSON(http.StatusOK, response)
 			return
 		}
@@ -310,17 +605,119 @@ func (rc *rulesheets) GetRulesheet() gin.HandlerFunc {
 	}
 }
 
+// GetRulesheetBySlug 	godoc
+// @Summary 			Obter Folha de Regra por Slug
+// @Description 		Para se obter a folha de regra pelo slug, que é a identidade usada pelos consumidores, pelo nome do projeto no GitLab e pelo ruller, basta clicar em **Try it out** e colocar o slug desejado em *slug*. Em seguida, clique em **Execute** e caso o slug exista retornará a folha de regra, como na busca por ID.
+// @Description 		A folha de regra também pode ser atualizada e excluída pelo slug, com os métodos *PUT*, *PATCH* e *DELETE* em */rulesheets/slug/{slug}*, que funcionam como os de */rulesheets/{id}*.
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				slug path string true "Rulesheet slug"
+// @Success 			200 {object} responses.Rulesheet
+// @Header 				200 {string} Authorization "token access"
+// @Header 				200 {string} ETag "Versão da folha de regra"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Response 			404 "Not Found"
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/slug/{slug} [get]
+// GetRulesheetBySlug returns a `gin.HandlerFunc` that retrieves a rulesheet by the slug param, responding
+// like `GetRulesheet`: the rulesheet with its ETag and a 200 status code, or a 404 status code if there's no
+// rulesheet with the slug.
+func (rc *rulesheets) GetRulesheetBySlug() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+
+		slug, exists := c.Params.Get("slug")
+
+		if !exists {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: "Required param 'slug'",
+			})
+			log.Error("Error on check if the rulesheet exist")
+			return
+		}
+
+		entity, err := rc.service.GetBySlug(ctx, slug)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on fetch unique rulesheet by slug: %v", err)
+			return
+		}
+
+		if entity == nil {
+			c.String(http.StatusNotFound, "")
+			return
+		}
+
+		setRulesheetETag(c, entity)
+		c.JSON(http.StatusOK, responses.NewRulesheet(entity))
+	}
+}
+
+// ResolveRulesheetSlug returns a `gin.HandlerFunc` that finds the rulesheet of the slug param and sets its ID
+// on the id param, before the next handlers of the route, so the handlers of the routes by ID can be used by
+// slug. It aborts with a 404 status code if there's no rulesheet with the slug.
+func (rc *rulesheets) ResolveRulesheetSlug() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+
+		slug, exists := c.Params.Get("slug")
+
+		if !exists {
+			c.AbortWithStatusJSON(http.StatusBadRequest, responses.Error{
+				Error: "Required param 'slug'",
+			})
+			log.Error("Error on check if the rulesheet exist")
+			return
+		}
+
+		entity, err := rc.service.GetBySlug(ctx, slug)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on fetch unique rulesheet by slug: %v", err)
+			return
+		}
+
+		if entity == nil {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+
+		c.Params = append(c.Params, gin.Param{Key: "id", Value: fmt.Sprint(entity.ID)})
+		c.Next()
+	}
+}
+
 // UpdateRulesheet 		godoc
 // @Summary 			Atualizar Folha de Regra por ID
 // @Description			Para atualizar ou editar uma folha de regra, é necessário enviar o ID da folha desejada no campo *id*, juntamente com os parâmetros da regra no corpo da solicitação no parâmetro *rulesheet*. Para realizar essa atualização clique no botão **Try it out** e preencher os campos com os dados desejados, em seguida, clicar em **Execute** para enviar a solicitação de atualização.
+// @Description			As expressões *condition* e *dynamic* das regras são validadas antes de salvar. Uma expressão inválida, ou que referencia um parâmetro (*$nome*) ou uma feature (*#nome*) não declarados em *parameters* ou *features* nem definidos por outra regra, ou cujo tipo não corresponde ao tipo declarado na regra (*type*) ou na feature (*boolean*, *integer*, *decimal*, *string*, *date*, *list* ou *map*), retorna 400 com o caminho da regra (por exemplo, *rules.desconto[2].condition*) e a posição do erro na expressão em *validation_errors*. Um valor em texto só é uma referência quando é apenas a referência a um parâmetro ou feature declarado, como *#limite*; nos demais textos, como *#FF0000* ou *https://x/#secao*, o que parece uma referência é mantido como texto e retornado como aviso em *warnings*, assim como os parâmetros declarados que não são usados por nenhuma regra.
+// @Description			Os casos de teste em *tests*, com os valores dos parâmetros (*parameters*) e os valores esperados das features (*expected*), são salvos no *tests.json*; quando omitidos na atualização, os casos já salvos são mantidos. Com *FEATWS_API_TESTS_BLOCK_UPDATES* habilitado, a folha de regra cujos casos de teste falham não é salva e retorna 422 com as features que não correspondem em *validation_errors*.
 // @Tags 				Rulesheet
 // @Accept  			json
 // @Produce  			json
 // @Param				id path string true "Rulesheet ID"
+// @Param				If-Match header string false "Versão da folha de regra lida pelo cliente (ETag)"
 // @Param				rulesheet body payloads.Rulesheet true "Rulesheet body"
 // @Success 			200 {array} payloads.Rulesheet
 // @Header 				200 {string} Authorization "token access"
+// @Header 				200 {string} ETag "Versão da folha de regra"
 // @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			422 {object} responses.Error "Unprocessable Entity"
+// @Failure 			409 {object} responses.Error "Conflict"
 // @Failure 			500 {object} responses.Error "Internal Server Error"
 // @Failure 			default {object} responses.Error
 // @Response 			404 "Not Found"
@@ -331,7 +728,8 @@ func (rc *rulesheets) GetRulesheet() gin.HandlerFunc {
 // request with a JSON payload containing the updated information for the entity, validates the
 // payload, and updates the entity in the database using a service. If the update is successful, it
 // returns a JSON response with the updated entity information. If the entity is not found, it returns
-// a 404 status code.
+// a 404 status code. When the request carries an `If-Match` header with a version other than the current
+// one, or the rulesheet is changed by another update meanwhile, it returns a 409 status code.
 func (rc *rulesheets) UpdateRulesheet() gin.HandlerFunc {
 
 	return func(c *gin.Context) {
@@ -356,6 +754,14 @@ func (rc *rulesheets) UpdateRulesheet() gin.HandlerFunc {
 			return
 		}
 
+		ifMatch := c.GetHeader("If-Match")
+		if ifMatch != "" && !matchesETag(ifMatch, foudedEntity.Version) {
+			c.JSON(http.StatusConflict, responses.Error{
+				Error: fmt.Sprintf("%s: the current version is %s", services.ErrVersionConflict, foudedEntity.Version),
+			})
+			return
+		}
+
 		var payload payloads.Rulesheet
 
 		// validate the request body
@@ -364,6 +770,7 @@ func (rc *rulesheets) UpdateRulesheet() gin.HandlerFunc {
 				Error: err.Error(),
 			})
 			log.Errorf("Error on binding the payload: %v", err)
+			return
 		}
 
 		// use the validator libraty to validate required fields
@@ -373,9 +780,6 @@ func (rc *rulesheets) UpdateRulesheet() gin.HandlerFunc {
 			return
 		}
 
-		iid, _ := strconv.ParseUint(id, 10, 32)
-		payload.ID = uint(iid)
-
 		if payload.Slug != "" {
 			c.JSON(http.StatusBadRequest, responses.Error{
 				Error: "You can't update a slug already defined",
@@ -385,32 +789,230 @@ func (rc *rulesheets) UpdateRulesheet() gin.HandlerFunc {
 		}
 		payload.Slug = foudedEntity.Slug
 
-		dto, err := dtos.NewRulesheetV1(payload)
+		rc.saveRulesheet(ctx, c, id, payload, foudedEntity, ifMatch, "")
+	}
+}
+
+// saveRulesheet validates the payload of a rulesheet changed by an update or a patch, its rules and its
+// test cases, and saves it over the current rulesheet, with the commit message if it's given, responding
+// with the updated rulesheet and its warnings. The test cases that weren't given are kept. When the
+// `If-Match` header was given, the rulesheet is only saved if it wasn't changed by another update meanwhile.
+func (rc *rulesheets) saveRulesheet(ctx context.Context, c *gin.Context, id string, payload payloads.Rulesheet, foudedEntity *dtos.Rulesheet, ifMatch string, commitMessage string) {
+	iid, _ := strconv.ParseUint(id, 10, 32)
+	payload.ID = uint(iid)
+
+	dto, err := dtos.NewRulesheetV1(payload)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, responses.Error{
+			Error: err.Error(),
+		})
+		log.Errorf("Error on define entity: %v", err)
+		return
+	}
+
+	// the string rules would be saved as an empty rules.json
+	if dto.HasStringRule {
+		c.JSON(http.StatusUnprocessableEntity, responses.Error{
+			Error: services.ErrLegacyRules.Error(),
+		})
+		return
+	}
+
+	// validate the expressions of the rules and their references before anything is committed
+	validationErr, warnings := validateRules(&dto)
+	if validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		log.Errorf("Error on validate the rule expressions: %v", validationErr)
+		return
+	}
+
+	// the test cases that weren't given are kept
+	if dto.Tests == nil {
+		dto.Tests = foudedEntity.Tests
+	}
+
+	testsErr, err := checkTests(&dto)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, responses.Error{
+			Error: err.Error(),
+		})
+		log.Errorf("Error on run the rulesheet tests: %v", err)
+		return
+	}
+	if testsErr != nil {
+		c.JSON(http.StatusUnprocessableEntity, testsErr)
+		log.Errorf("Error on run the rulesheet tests: %v", testsErr)
+		return
+	}
+
+	if ifMatch != "" && ifMatch != "*" {
+		dto.BaseVersion = foudedEntity.Version
+	}
+	dto.CommitMessage = commitMessage
+
+	updatedEntity, err := rc.service.Update(ctx, dto)
+	if err != nil {
+		if errors.Is(err, services.ErrVersionConflict) {
+			c.JSON(http.StatusConflict, responses.Error{
+				Error: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrLegacyRules) {
+			c.JSON(http.StatusUnprocessableEntity, responses.Error{
+				Error: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, responses.Error{
+			Error: err.Error(),
+		})
+		log.Errorf("Error on update entity: %v", err)
+		return
+	}
+
+	if updatedEntity != nil {
+		var response = responses.NewRulesheet(updatedEntity)
+		response.Warnings = warnings
+
+		setRulesheetETag(c, updatedEntity)
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	c.String(http.StatusNotFound, "")
+}
+
+// PatchRulesheet 		godoc
+// @Summary 			Alterar parte de uma Folha de Regra por ID
+// @Description			Altera parte de uma folha de regra sem enviar o documento inteiro. O documento alterado tem os campos *name*, *description*, *slug*, *features*, *parameters*, *rules* e *tests*, como no corpo da atualização, e a alteração pode ser enviada de duas formas, de acordo com o *Content-Type*:
+// @Description
+// @Description			- **application/merge-patch+json** (RFC 7396): um objeto com os campos alterados, que são mesclados no documento. Os campos com *null* são removidos e as listas são substituídas inteiras, como em *{"rules": {"desconto": {"value": 0.2}}}*.
+// @Description			- **application/json-patch+json** (RFC 6902): uma lista de operações (*add*, *remove*, *replace*, *move*, *copy* e *test*) com o caminho do valor alterado, como em *[{"op": "replace", "path": "/rules/desconto/0/condition", "value": "$idade > 60"}]*.
+// @Description
+// @Description			A folha de regra alterada é validada e salva como na atualização, em um único commit. Um patch inválido retorna 400, uma operação cujo caminho não existe ou cujo *test* falha retorna 422 e a alteração do slug não é permitida.
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Rulesheet ID"
+// @Param				If-Match header string false "Versão da folha de regra lida pelo cliente (ETag)"
+// @Param				patch body object true "JSON Merge Patch or JSON Patch"
+// @Success 			200 {object} responses.Rulesheet
+// @Header 				200 {string} Authorization "token access"
+// @Header 				200 {string} ETag "Versão da folha de regra"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			409 {object} responses.Error "Conflict"
+// @Failure 			415 {object} responses.Error "Unsupported Media Type"
+// @Failure 			422 {object} responses.Error "Unprocessable Entity"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Response 			404 "Not Found"
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id} [patch]
+// PatchRulesheet returns a `gin.HandlerFunc` that applies a JSON Merge Patch or a JSON Patch, chosen by the
+// `Content-Type` of the request, to the current content of a rulesheet, then validates and saves it like
+// `UpdateRulesheet`. It returns a 415 status code for any other content type, a 400 status code if the patch
+// is invalid or changes the slug, and a 422 status code if an operation references a value that doesn't
+// exist or a `test` operation fails.
+func (rc *rulesheets) PatchRulesheet() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+
+		id, exists := c.Params.Get("id")
+
+		if !exists {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: "Required param 'id'",
+			})
+			log.Error("Error on check if the rulesheet exist")
+			return
+		}
+
+		apply, ok := map[string]func(document []byte, patch []byte) ([]byte, error){
+			patch.MediaTypeMergePatch: patch.Merge,
+			patch.MediaTypeJSONPatch:  patch.Apply,
+		}[c.ContentType()]
+		if !ok {
+			c.JSON(http.StatusUnsupportedMediaType, responses.Error{
+				Error: fmt.Sprintf("the content type must be '%s' or '%s'", patch.MediaTypeMergePatch, patch.MediaTypeJSONPatch),
+			})
+			return
+		}
+
+		foudedEntity, err := rc.service.Get(ctx, id)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, responses.Error{
+			c.String(http.StatusNotFound, "")
+			log.Errorf("You are trying to patch a non existing record: %v", err)
+			return
+		}
+
+		ifMatch := c.GetHeader("If-Match")
+		if ifMatch != "" && !matchesETag(ifMatch, foudedEntity.Version) {
+			c.JSON(http.StatusConflict, responses.Error{
+				Error: fmt.Sprintf("%s: the current version is %s", services.ErrVersionConflict, foudedEntity.Version),
+			})
+			return
+		}
+
+		body, err := c.GetRawData()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, responses.Error{
 				Error: err.Error(),
 			})
-			log.Errorf("Error on define entity: %v", err)
+			log.Errorf("Error on read the patch: %v", err)
 			return
 		}
 
-		updatedEntity, err := rc.service.Update(ctx, dto)
+		document, err := json.Marshal(services.NewRulesheetPayload(foudedEntity))
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, responses.Error{
 				Error: err.Error(),
 			})
-			log.Errorf("Error on update entity: %v", err)
+			log.Errorf("Error on write the rulesheet to patch: %v", err)
 			return
 		}
 
-		if updatedEntity != nil {
-			var response = responses.NewRulesheet(updatedEntity)
+		patched, err := apply(document, body)
+		if err != nil {
+			status := http.StatusUnprocessableEntity
+			if errors.Is(err, patch.ErrInvalidPatch) {
+				status = http.StatusBadRequest
+			}
+			c.JSON(status, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on apply the patch: %v", err)
+			return
+		}
 
-			c.JSON(http.StatusOK, response)
+		var payload payloads.Rulesheet
+		if err := json.Unmarshal(patched, &payload); err != nil {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on read the patched rulesheet: %v", err)
 			return
 		}
 
-		c.String(http.StatusNotFound, "")
+		// use the validator libraty to validate required fields
+		if validationErr := validatePayload(&payload); validationErr != nil {
+			c.JSON(http.StatusBadRequest, validationErr)
+			log.Errorf("Error on validate required fields: %v", validationErr)
+			return
+		}
+
+		if payload.Slug != foudedEntity.Slug {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: "You can't update a slug already defined",
+			})
+			return
+		}
+
+		rc.saveRulesheet(ctx, c, id, payload, foudedEntity, ifMatch, "")
 	}
 }
 
@@ -470,3 +1072,1550 @@ func (rc *rulesheets) DeleteRulesheet() gin.HandlerFunc {
 		c.String(http.StatusNoContent, "")
 	}
 }
+
+// GetRulesheetVersions godoc
+// @Summary 			Listar as Versões de uma Folha de Regra
+// @Description 		Lista as versões publicadas de uma folha de regra, da mais recente para a mais antiga. Cada versão contém o número da versão (*VERSION*), o SHA do commit, o autor, a data e a mensagem do commit. Utilize os parâmetros *limit* e *page* para paginar o resultado.
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Rulesheet ID"
+// @Param				limit query integer false "Max length of the array returned"
+// @Param				page query integer false "Page number that is multiplied by 'limit' to calculate the offset"
+// @Success 			200 {array} responses.Version
+// @Header 				200 {string} Authorization "token access"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Response 			404 "Not Found"
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id}/versions [get]
+// GetRulesheetVersions returns a `gin.HandlerFunc` that lists the versions published for the rulesheet
+// with the ID passed in the request. The `limit` and `page` query parameters are used to paginate the
+// result, and a 400 status code is returned if any of them is invalid.
+func (rc *rulesheets) GetRulesheetVersions() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+
+		id, exists := c.Params.Get("id")
+
+		if !exists {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: "Required param 'id'",
+			})
+			log.Error("Error on check if the rulesheet exist")
+			return
+		}
+
+		opts, err := parseFindOptions(c.Request.URL.Query())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on parse the pagination: %v", err)
+			return
+		}
+
+		dtos, err := rc.service.History(ctx, id, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on fetch rulesheet versions: %v", err)
+			return
+		}
+
+		var response = make([]responses.Version, len(dtos))
+
+		for index, dto := range dtos {
+			response[index] = responses.NewVersion(dto)
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// GetRulesheetVersion godoc
+// @Summary 			Obter uma Versão da Folha de Regra
+// @Description 		Retorna a folha de regra com as *features*, os *parameters* e as *rules* exatamente como estavam em uma versão. O campo *version* aceita o número da versão (*VERSION*) ou o SHA de um commit do repositório da folha de regra.
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Rulesheet ID"
+// @Param				version path string true "Version number or commit SHA"
+// @Success 			200 {object} responses.Rulesheet
+// @Header 				200 {string} Authorization "token access"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			404 {object} responses.Error "Not Found"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id}/versions/{version} [get]
+// GetRulesheetVersion returns a `gin.HandlerFunc` that retrieves the rulesheet with the ID passed in the
+// request as it was at the version passed in the request. If the version doesn't exist, a 404 status code
+// is returned.
+func (rc *rulesheets) GetRulesheetVersion() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		id, exists := c.Params.Get("id")
+
+		if !exists {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: "Required param 'id'",
+			})
+			log.Error("Error on check if the rulesheet exist")
+			return
+		}
+
+		version, exists := c.Params.Get("version")
+
+		if !exists {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: "Required param 'version'",
+			})
+			log.Error("Error on check if the rulesheet version exist")
+			return
+		}
+
+		entity, err := rc.service.GetVersion(ctx, id, version)
+		if err != nil {
+			if errors.Is(err, services.ErrVersionNotFound) {
+				c.JSON(http.StatusNotFound, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on fetch rulesheet version: %v", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, responses.NewRulesheet(entity))
+	}
+}
+
+// RollbackRulesheet godoc
+// @Summary 			Restaurar uma Versão da Folha de Regra
+// @Description 		Restaura as *features*, os *parameters* e as *rules* de uma folha de regra a partir de uma versão anterior, informada pelo número da versão (*VERSION*) ou pelo SHA de um commit no campo *version*. O conteúdo restaurado é validado e salvo como uma atualização, em um novo commit que avança o número da versão: as expressões inválidas retornam 400 e os casos de teste que falham, com *FEATWS_API_TESTS_BLOCK_UPDATES* habilitado, ou uma versão com as regras em texto do *rules.featws* retornam 422. Com o cabeçalho *If-Match*, a folha de regra só é restaurada se a versão atual for a informada; senão, retorna 409.
+// @Description
+// @Description  		```
+// @Description  		{
+// @Description  			"version": "3"
+// @Description  		}
+// @Description  		```
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Rulesheet ID"
+// @Param				If-Match header string false "Versão da folha de regra lida pelo cliente (ETag)"
+// @Param				rollback body payloads.Rollback true "Rollback body"
+// @Success 			200 {object} responses.Rulesheet
+// @Header 				200 {string} Authorization "token access"
+// @Header 				200 {string} ETag "Versão da folha de regra"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			404 {object} responses.Error "Not Found"
+// @Failure 			409 {object} responses.Error "Conflict"
+// @Failure 			422 {object} responses.Error "Unprocessable Entity"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id}/rollback [post]
+// RollbackRulesheet returns a `gin.HandlerFunc` that restores the content of the rulesheet with the ID
+// passed in the request from the version passed in the request body. The restored content is validated
+// and saved like an update, through `saveRulesheet`, honoring the `If-Match` header. If the version doesn't
+// exist, a 404 status code is returned.
+func (rc *rulesheets) RollbackRulesheet() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		id, exists := c.Params.Get("id")
+
+		if !exists {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: "Required param 'id'",
+			})
+			log.Error("Error on check if the rulesheet exist")
+			return
+		}
+
+		foudedEntity, err := rc.service.Get(ctx, id)
+		if err != nil || foudedEntity == nil {
+			c.String(http.StatusNotFound, "")
+			log.Errorf("You are trying to rollback a non existing record: %v", err)
+			return
+		}
+
+		ifMatch := c.GetHeader("If-Match")
+		if ifMatch != "" && !matchesETag(ifMatch, foudedEntity.Version) {
+			c.JSON(http.StatusConflict, responses.Error{
+				Error: fmt.Sprintf("%s: the current version is %s", services.ErrVersionConflict, foudedEntity.Version),
+			})
+			return
+		}
+
+		var payload payloads.Rollback
+
+		// validate the request body
+		if err := c.BindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on binding the payload: %v", err)
+			return
+		}
+
+		// use the validator libraty to validate required fields
+		if validationErr := validatePayload(&payload); validationErr != nil {
+			c.JSON(http.StatusBadRequest, validationErr)
+			log.Errorf("Error on validate required fields: %v", validationErr)
+			return
+		}
+
+		target, err := rc.service.GetVersion(ctx, id, payload.Version)
+		if err != nil {
+			if errors.Is(err, services.ErrVersionNotFound) {
+				c.JSON(http.StatusNotFound, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on rollback rulesheet: %v", err)
+			return
+		}
+
+		// the restored content is validated and saved like an update
+		restored := services.NewRulesheetPayload(target)
+		restored.Slug = foudedEntity.Slug
+		if validationErr := validatePayload(&restored); validationErr != nil {
+			c.JSON(http.StatusBadRequest, validationErr)
+			log.Errorf("Error on validate required fields: %v", validationErr)
+			return
+		}
+
+		rc.saveRulesheet(ctx, c, id, restored, foudedEntity, ifMatch, fmt.Sprintf("[FEATWS BOT] Rollback to version %s", payload.Version))
+	}
+}
+
+// GetRulesheetDiff godoc
+// @Summary 			Comparar Versões da Folha de Regra
+// @Description 		Compara duas versões de uma folha de regra e lista as *features*, os *parameters* e as *rules* que foram adicionados (*added*), removidos (*removed*) ou alterados (*changed*), com os valores antigo (*old*) e novo (*new*) de cada um. As *features* e os *parameters* são identificados pelo nome e as *rules* pelo caminho, separado por pontos, dentro do *rules.json*. Os parâmetros *from* e *to* aceitam o número da versão (*VERSION*) ou o SHA de um commit.
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Rulesheet ID"
+// @Param				from query string true "Version number or commit SHA to compare from"
+// @Param				to query string true "Version number or commit SHA to compare to"
+// @Success 			200 {object} responses.Diff
+// @Header 				200 {string} Authorization "token access"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			404 {object} responses.Error "Not Found"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id}/diff [get]
+// GetRulesheetDiff returns a `gin.HandlerFunc` that compares the rulesheet with the ID passed in the
+// request at the `from` and `to` versions passed as query parameters. If any of the versions doesn't
+// exist, a 404 status code is returned.
+func (rc *rulesheets) GetRulesheetDiff() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		id, exists := c.Params.Get("id")
+
+		if !exists {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: "Required param 'id'",
+			})
+			log.Error("Error on check if the rulesheet exist")
+			return
+		}
+
+		query := c.Request.URL.Query()
+
+		for _, param := range []string{"from", "to"} {
+			if query.Get(param) == "" {
+				c.JSON(http.StatusBadRequest, responses.Error{
+					Error: fmt.Sprintf("Required query param '%s'", param),
+				})
+				log.Errorf("Error on check the query param '%s'", param)
+				return
+			}
+		}
+
+		diff, err := rc.service.Diff(ctx, id, query.Get("from"), query.Get("to"))
+		if err != nil {
+			if errors.Is(err, services.ErrVersionNotFound) {
+				c.JSON(http.StatusNotFound, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on diff rulesheet versions: %v", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, responses.NewDiff(diff))
+	}
+}
+
+// EvaluateRulesheet godoc
+// @Summary 			Avaliar a Folha de Regra
+// @Description 		Calcula as *features* de uma folha de regra para os valores dos parâmetros informados em *parameters*, sem publicar a folha de regra nem aguardar a compilação e o deploy do ruller. Em cada lista de regras vale a primeira regra cuja condição (*condition*) é atendida, e a expressão *dynamic* tem precedência sobre o *value*. A resposta traz o valor de cada *feature*, as regras calculadas com o caminho (por exemplo, *rules.desconto[1]*) e se a condição foi atendida (*matched*), e os erros encontrados no cálculo em *errors*. O campo *version* aceita o número da versão (*VERSION*) ou o SHA de um commit; quando vazio, é avaliado o conteúdo atual. Apenas as regras estruturadas do *rules.json* são avaliadas: uma folha de regra só com as regras em texto do *rules.featws* retorna 422.
+// @Description
+// @Description  		```
+// @Description  		{
+// @Description  			"version": "3",
+// @Description  			"parameters": {
+// @Description  				"idade": 30
+// @Description  			}
+// @Description  		}
+// @Description  		```
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Rulesheet ID"
+// @Param				evaluation body payloads.Evaluation true "Evaluation body"
+// @Success 			200 {object} responses.Evaluation
+// @Header 				200 {string} Authorization "token access"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			404 {object} responses.Error "Not Found"
+// @Failure 			422 {object} responses.Error "Unprocessable Entity"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id}/evaluate [post]
+// EvaluateRulesheet returns a `gin.HandlerFunc` that computes the features of the rulesheet with the ID
+// passed in the request for the parameters passed in the request body. If the rulesheet or the version
+// doesn't exist, a 404 status code is returned, and if it only has string rules, a 422 status code is
+// returned.
+func (rc *rulesheets) EvaluateRulesheet() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		id, exists := c.Params.Get("id")
+
+		if !exists {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: "Required param 'id'",
+			})
+			log.Error("Error on check if the rulesheet exist")
+			return
+		}
+
+		var payload payloads.Evaluation
+
+		// validate the request body
+		if err := c.BindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on binding the payload: %v", err)
+			return
+		}
+
+		evaluation, err := rc.service.Evaluate(ctx, id, payload.Version, payload.Parameters)
+		if err != nil {
+			if errors.Is(err, services.ErrVersionNotFound) {
+				c.JSON(http.StatusNotFound, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			if errors.Is(err, services.ErrStringRulesNotSupported) {
+				c.JSON(http.StatusUnprocessableEntity, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on evaluate rulesheet: %v", err)
+			return
+		}
+
+		if evaluation == nil {
+			c.String(http.StatusNotFound, "")
+			return
+		}
+
+		c.JSON(http.StatusOK, responses.NewEvaluation(evaluation))
+	}
+}
+
+// RunRulesheetTests godoc
+// @Summary 			Executar os Casos de Teste da Folha de Regra
+// @Description 		Executa os casos de teste salvos no *tests.json* de uma folha de regra, calculando as *features* para os parâmetros (*parameters*) de cada caso e comparando com os valores esperados (*expected*). A resposta traz a quantidade de casos que passaram (*passed*) e que falharam (*failed*) e, para cada caso, as features cujo valor calculado (*actual*) não corresponde ao esperado (*expected*). O parâmetro *version* aceita o número da versão (*VERSION*) ou o SHA de um commit; quando vazio, são executados os casos do conteúdo atual. Uma folha de regra só com as regras em texto do *rules.featws* retorna 422.
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Rulesheet ID"
+// @Param				version query string false "Version number or commit SHA to run the tests against"
+// @Success 			200 {object} responses.TestRun
+// @Header 				200 {string} Authorization "token access"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			404 {object} responses.Error "Not Found"
+// @Failure 			422 {object} responses.Error "Unprocessable Entity"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id}/tests/run [post]
+// RunRulesheetTests returns a `gin.HandlerFunc` that runs the test cases of the rulesheet with the ID passed
+// in the request, at the version passed as a query parameter or at its current content. If the rulesheet
+// or the version doesn't exist, a 404 status code is returned, and if it only has string rules, a 422
+// status code is returned.
+func (rc *rulesheets) RunRulesheetTests() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		id, exists := c.Params.Get("id")
+
+		if !exists {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: "Required param 'id'",
+			})
+			log.Error("Error on check if the rulesheet exist")
+			return
+		}
+
+		run, err := rc.service.RunTests(ctx, id, c.Request.URL.Query().Get("version"))
+		if err != nil {
+			if errors.Is(err, services.ErrVersionNotFound) {
+				c.JSON(http.StatusNotFound, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			if errors.Is(err, services.ErrStringRulesNotSupported) {
+				c.JSON(http.StatusUnprocessableEntity, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on run rulesheet tests: %v", err)
+			return
+		}
+
+		if run == nil {
+			c.String(http.StatusNotFound, "")
+			return
+		}
+
+		c.JSON(http.StatusOK, responses.NewTestRun(run))
+	}
+}
+
+// GetRulesheetGraph godoc
+// @Summary 			Grafo de Dependências da Folha de Regra
+// @Description 		Retorna o grafo de dependências entre as regras, features e parâmetros de uma folha de regra: cada regra depende dos parâmetros (*$nome*) e features (*#nome*) referenciados nas suas condições, valores dinâmicos e valores em texto que são só uma referência a um parâmetro ou feature declarado, inclusive nos mapas e listas de regras, e as features definidas por outra regra apontam para essa regra. As dependências circulares entre as regras são listadas em *cycles*, da primeira regra de volta a ela mesma. O parâmetro *format* aceita *json*, que é o padrão, *dot*, a linguagem do Graphviz, ou *mermaid*, um fluxograma do Mermaid, em que as arestas dos ciclos são destacadas. O parâmetro *version* aceita o número da versão (*VERSION*) ou o SHA de um commit; quando vazio, é usado o conteúdo atual. As regras em texto do *rules.featws* são convertidas como na conversão, sem salvar.
+// @Tags 				Rulesheet
+// @Produce  			json
+// @Produce  			plain
+// @Param				id path string true "Rulesheet ID"
+// @Param				format query string false "Graph format" Enums(json, dot, mermaid)
+// @Param				version query string false "Version number or commit SHA"
+// @Success 			200 {object} responses.Graph
+// @Header 				200 {string} Authorization "token access"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			404 {object} responses.Error "Not Found"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id}/graph [get]
+// GetRulesheetGraph returns a `gin.HandlerFunc` that builds the dependency graph of the rulesheet with the
+// ID passed in the request, at the version passed as a query parameter or at its current content, and
+// writes it on the format passed as a query parameter. If the format isn't supported, a 400 status code is
+// returned, and if the rulesheet or the version doesn't exist, a 404 status code is returned.
+func (rc *rulesheets) GetRulesheetGraph() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		id, exists := c.Params.Get("id")
+
+		if !exists {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: "Required param 'id'",
+			})
+			log.Error("Error on check if the rulesheet exist")
+			return
+		}
+
+		query := c.Request.URL.Query()
+		format := query.Get("format")
+		if format == "" {
+			format = services.FormatJSON
+		}
+
+		if format != services.FormatJSON && format != services.FormatDOT && format != services.FormatMermaid {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: services.ErrFormatNotSupported.Error(),
+			})
+			return
+		}
+
+		graph, err := rc.service.Graph(ctx, id, query.Get("version"))
+		if err != nil {
+			if errors.Is(err, services.ErrVersionNotFound) {
+				c.JSON(http.StatusNotFound, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on build rulesheet graph: %v", err)
+			return
+		}
+
+		if graph == nil {
+			c.String(http.StatusNotFound, "")
+			return
+		}
+
+		switch format {
+		case services.FormatDOT:
+			c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", []byte(graph.DOT()))
+		case services.FormatMermaid:
+			c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(graph.Mermaid()))
+		default:
+			c.JSON(http.StatusOK, responses.NewGraph(graph))
+		}
+	}
+}
+
+// ExportRulesheet godoc
+// @Summary 			Exportar as Regras da Folha de Regra
+// @Description 		Escreve as regras de uma folha de regra no formato *featws*, o formato INI do *rules.featws* lido pelo *go-featws*, para as equipes e ferramentas que só entendem esse formato. As regras em texto viram chaves da seção padrão, os mapas de regras viram seções nomeadas (*[secao]*) e as listas de mapas viram seções *[[lista]]*, em ordem alfabética; os valores que seriam alterados na leitura, como os que têm *;* ou estão entre aspas, são escritos entre crases. As regras estruturadas que só têm um valor (*value*) em texto, número ou booleano são escritas como o literal do valor, como *10* ou *'ouro'*, que a conversão das regras em texto lê de volta como a mesma regra. O arquivo gerado é lido de volta com as mesmas regras. O parâmetro *format* aceita só *featws*, que é o padrão, e o parâmetro *version* aceita o número da versão (*VERSION*) ou o SHA de um commit; quando vazio, é exportado o conteúdo atual. Uma folha de regra com regras que têm condição (*condition*) ou valor dinâmico (*dynamic*), ou que estão em uma lista de regras, que não podem ser escritas nesse formato, retorna 422 com o caminho dessas regras.
+// @Tags 				Rulesheet
+// @Produce  			plain
+// @Param				id path string true "Rulesheet ID"
+// @Param				format query string false "Export format" Enums(featws)
+// @Param				version query string false "Version number or commit SHA to export"
+// @Success 			200 {string} string "rules.featws"
+// @Header 				200 {string} Authorization "token access"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			404 {object} responses.Error "Not Found"
+// @Failure 			422 {object} responses.Error "Unprocessable Entity"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id}/export [get]
+// ExportRulesheet returns a `gin.HandlerFunc` that writes the rules of the rulesheet with the ID passed in
+// the request on the format passed as a query parameter, at the version passed as a query parameter or at
+// its current content, as a rules.featws attachment. If the format isn't supported, a 400 status code is
+// returned, if the rulesheet or the version doesn't exist, a 404 status code is returned, and if the rules
+// can't be written on the format, a 422 status code is returned.
+func (rc *rulesheets) ExportRulesheet() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		id, exists := c.Params.Get("id")
+
+		if !exists {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: "Required param 'id'",
+			})
+			log.Error("Error on check if the rulesheet exist")
+			return
+		}
+
+		query := c.Request.URL.Query()
+		format := query.Get("format")
+		if format == "" {
+			format = services.FormatFeatws
+		}
+
+		content, err := rc.service.Export(ctx, id, query.Get("version"), format)
+		if err != nil {
+			if errors.Is(err, services.ErrFormatNotSupported) {
+				c.JSON(http.StatusBadRequest, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			if errors.Is(err, services.ErrVersionNotFound) {
+				c.JSON(http.StatusNotFound, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			if errors.Is(err, services.ErrNotExportable) {
+				c.JSON(http.StatusUnprocessableEntity, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on export rulesheet: %v", err)
+			return
+		}
+
+		if content == nil {
+			c.String(http.StatusNotFound, "")
+			return
+		}
+
+		c.Header("Content-Disposition", "attachment; filename=\"rules.featws\"")
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", content)
+	}
+}
+
+// ConvertRulesheet godoc
+// @Summary 			Converter as Regras em Texto da Folha de Regra
+// @Description 		Converte as regras em texto de uma folha de regra legada, carregadas do *rules.featws*, para regras estruturadas. Cada texto é lido como uma expressão: um literal, como *10* ou *'ouro'*, vira o valor (*value*) da regra e as demais expressões viram o valor dinâmico (*dynamic*). As seções nomeadas viram mapas de regras e as seções *[[array]]* viram listas. Por padrão, a conversão é só uma prévia; com *commit=true*, as regras convertidas são validadas como numa atualização e, se não houver erros, salvas no *rules.json*, removendo o *rules.featws*. Os problemas encontrados são retornados em *errors*. Uma folha de regra que já é estruturada retorna 409.
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Rulesheet ID"
+// @Param				commit query bool false "Save the converted rules"
+// @Success 			200 {object} responses.Conversion
+// @Header 				200 {string} Authorization "token access"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			404 {object} responses.Error "Not Found"
+// @Failure 			409 {object} responses.Error "Conflict"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id}/convert [post]
+// ConvertRulesheet returns a `gin.HandlerFunc` that converts the string rules of the rulesheet with the ID
+// passed in the request, committing the result when the "commit" query parameter is set. If the rulesheet
+// doesn't exist, a 404 status code is returned, and if it has no string rules, a 409 status code is
+// returned.
+func (rc *rulesheets) ConvertRulesheet() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		id, exists := c.Params.Get("id")
+
+		if !exists {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: "Required param 'id'",
+			})
+			log.Error("Error on check if the rulesheet exist")
+			return
+		}
+
+		commit, err := parseBoolQuery(c.Request.URL.Query(), "commit")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		conversion, err := rc.service.ConvertLegacy(ctx, id, commit)
+		if err != nil {
+			if errors.Is(err, services.ErrNotLegacy) {
+				c.JSON(http.StatusConflict, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on convert rulesheet: %v", err)
+			return
+		}
+
+		if conversion == nil {
+			c.String(http.StatusNotFound, "")
+			return
+		}
+
+		c.JSON(http.StatusOK, responses.NewConversion(conversion))
+	}
+}
+
+// ConvertRulesheets godoc
+// @Summary 			Converter as Regras em Texto das Folhas de Regra
+// @Description 		Converte as regras em texto de todas as folhas de regra legadas, carregadas do *rules.featws*, para regras estruturadas, da mesma forma que a conversão de uma folha de regra. As folhas de regra que já são estruturadas são ignoradas. Por padrão, a conversão é só uma prévia; com *commit=true*, cada folha de regra cujas regras convertidas não têm erros é salva no *rules.json*, removendo o *rules.featws*, e as demais retornam *committed* falso com os problemas em *errors*.
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				commit query bool false "Save the converted rules"
+// @Success 			200 {array} responses.Conversion
+// @Header 				200 {string} Authorization "token access"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/convert [post]
+// ConvertRulesheets returns a `gin.HandlerFunc` that converts the string rules of every legacy rulesheet,
+// committing the results when the "commit" query parameter is set.
+func (rc *rulesheets) ConvertRulesheets() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Minute)
+		defer cancel()
+
+		commit, err := parseBoolQuery(c.Request.URL.Query(), "commit")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		conversions, err := rc.service.ConvertAllLegacy(ctx, commit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on convert rulesheets: %v", err)
+			return
+		}
+
+		result := make([]responses.Conversion, 0, len(conversions))
+		for _, conversion := range conversions {
+			result = append(result, responses.NewConversion(conversion))
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// GetRulesheetChanges godoc
+// @Summary 			Listar as Alterações Pendentes da Folha de Regra
+// @Description 		No modo de revisão (*FEATWS_API_GITLAB_REVIEW_MODE*), as alterações de uma folha de regra são salvas em um branch de rascunho e abrem um merge request no GitLab, que precisa ser aprovado antes de ser publicado. Enquanto pendente, a alteração não avança a versão: a folha de regra e a sincronização mantêm a versão publicada. Essa operação lista as alterações pendentes de uma folha de regra, com o título, o autor, o branch e as aprovações de cada uma.
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Rulesheet ID"
+// @Success 			200 {array} responses.ChangeRequest
+// @Header 				200 {string} Authorization "token access"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			501 {object} responses.Error "Not Implemented"
+// @Failure 			default {object} responses.Error
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id}/changes [get]
+// GetRulesheetChanges returns a `gin.HandlerFunc` that lists the pending changes of the rulesheet with the
+// ID passed in the request. If the storage backend doesn't support the review mode, a 501 status code is
+// returned.
+func (rc *rulesheets) GetRulesheetChanges() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		id, exists := c.Params.Get("id")
+
+		if !exists {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: "Required param 'id'",
+			})
+			log.Error("Error on check if the rulesheet exist")
+			return
+		}
+
+		dtos, err := rc.service.ListChanges(ctx, id)
+		if err != nil {
+			if errors.Is(err, services.ErrNotSupported) {
+				c.JSON(http.StatusNotImplemented, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on fetch rulesheet changes: %v", err)
+			return
+		}
+
+		var response = make([]responses.ChangeRequest, len(dtos))
+
+		for index, dto := range dtos {
+			response[index] = responses.NewChangeRequest(dto)
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// ApproveRulesheetChange godoc
+// @Summary 			Aprovar uma Alteração Pendente da Folha de Regra
+// @Description 		Aprova o merge request de uma alteração pendente de uma folha de regra. A aprovação é registrada no GitLab em nome do usuário do token configurado na API.
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Rulesheet ID"
+// @Param				change path integer true "Change request ID"
+// @Success 			200 {object} responses.ChangeRequest
+// @Header 				200 {string} Authorization "token access"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			404 {object} responses.Error "Not Found"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			501 {object} responses.Error "Not Implemented"
+// @Failure 			default {object} responses.Error
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id}/changes/{change}/approve [post]
+// ApproveRulesheetChange returns a `gin.HandlerFunc` that approves the pending change passed in the request.
+// If the change isn't pending, a 404 status code is returned.
+func (rc *rulesheets) ApproveRulesheetChange() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		id, change, ok := getChangeParams(c)
+		if !ok {
+			return
+		}
+
+		dto, err := rc.service.ApproveChange(ctx, id, change)
+		if err != nil {
+			if errors.Is(err, services.ErrChangeRequestNotFound) {
+				c.JSON(http.StatusNotFound, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			if errors.Is(err, services.ErrNotSupported) {
+				c.JSON(http.StatusNotImplemented, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on approve rulesheet change: %v", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, responses.NewChangeRequest(dto))
+	}
+}
+
+// PublishRulesheetChange godoc
+// @Summary 			Publicar uma Alteração Pendente da Folha de Regra
+// @Description 		Publica uma alteração pendente de uma folha de regra, fazendo o merge do seu merge request no branch padrão, avançando o número da versão (*VERSION*) e criando a tag da nova versão. A versão é definida na publicação, a partir da versão atual do branch padrão. A alteração precisa ter sido aprovada e não pode ter conflitos com outra alteração publicada antes dela.
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Rulesheet ID"
+// @Param				change path integer true "Change request ID"
+// @Success 			200 {object} responses.Rulesheet
+// @Header 				200 {string} Authorization "token access"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			404 {object} responses.Error "Not Found"
+// @Failure 			409 {object} responses.Error "Conflict"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			501 {object} responses.Error "Not Implemented"
+// @Failure 			default {object} responses.Error
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id}/changes/{change}/publish [post]
+// PublishRulesheetChange returns a `gin.HandlerFunc` that publishes the pending change passed in the request.
+// If the change isn't pending, a 404 status code is returned, and if it isn't approved or can't be merged,
+// a 409 status code is returned.
+func (rc *rulesheets) PublishRulesheetChange() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		id, change, ok := getChangeParams(c)
+		if !ok {
+			return
+		}
+
+		entity, err := rc.service.PublishChange(ctx, id, change)
+		if err != nil {
+			if errors.Is(err, services.ErrChangeRequestNotFound) {
+				c.JSON(http.StatusNotFound, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			if errors.Is(err, services.ErrChangeRequestNotApproved) || errors.Is(err, services.ErrChangeRequestNotMergeable) {
+				c.JSON(http.StatusConflict, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			if errors.Is(err, services.ErrNotSupported) {
+				c.JSON(http.StatusNotImplemented, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on publish rulesheet change: %v", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, responses.NewRulesheet(entity))
+	}
+}
+
+// DiscardRulesheetChange godoc
+// @Summary 			Descartar uma Alteração Pendente da Folha de Regra
+// @Description 		Descarta uma alteração pendente de uma folha de regra, fechando o seu merge request e removendo o branch de rascunho.
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Rulesheet ID"
+// @Param				change path integer true "Change request ID"
+// @Success 			204 "No Content"
+// @Header 				200 {string} Authorization "token access"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			404 {object} responses.Error "Not Found"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			501 {object} responses.Error "Not Implemented"
+// @Failure 			default {object} responses.Error
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id}/changes/{change} [delete]
+// DiscardRulesheetChange returns a `gin.HandlerFunc` that discards the pending change passed in the request.
+// If the change isn't pending, a 404 status code is returned.
+func (rc *rulesheets) DiscardRulesheetChange() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		id, change, ok := getChangeParams(c)
+		if !ok {
+			return
+		}
+
+		err := rc.service.DiscardChange(ctx, id, change)
+		if err != nil {
+			if errors.Is(err, services.ErrChangeRequestNotFound) {
+				c.JSON(http.StatusNotFound, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			if errors.Is(err, services.ErrNotSupported) {
+				c.JSON(http.StatusNotImplemented, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on discard rulesheet change: %v", err)
+			return
+		}
+
+		c.String(http.StatusNoContent, "")
+	}
+}
+
+// getChangeParams reads the rulesheet ID and the change request ID from the request params. If any of
+// them is missing or invalid, it writes a 400 status code and returns false.
+func getChangeParams(c *gin.Context) (id string, change int, ok bool) {
+
+	id, exists := c.Params.Get("id")
+
+	if !exists {
+		c.JSON(http.StatusBadRequest, responses.Error{
+			Error: "Required param 'id'",
+		})
+		log.Error("Error on check if the rulesheet exist")
+		return
+	}
+
+	change, err := strconv.Atoi(c.Param("change"))
+	if err != nil || change < 1 {
+		c.JSON(http.StatusBadRequest, responses.Error{
+			Error: "The param 'change' must be a positive integer",
+		})
+		log.Error("Error on check the change request param")
+		return
+	}
+
+	return id, change, true
+}
+
+// GetRulesheetRule 		godoc
+// @Summary 			Obter Regra de uma Folha de Regra
+// @Description 		Retorna a regra com o nome *name* da folha de regra com o ID *id*, ou 404 caso a folha de regra ou a regra não existam.
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Rulesheet ID"
+// @Param				name path string true "Rule name"
+// @Success 			200 {object} object
+// @Header 				200 {string} Authorization "token access"
+// @Header 				200 {string} ETag "Versão da folha de regra"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Response 			404 "Not Found"
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id}/rules/{name} [get]
+// GetRulesheetRule returns a `gin.HandlerFunc` that retrieves a rule of a rulesheet by its name.
+func (rc *rulesheets) GetRulesheetRule() gin.HandlerFunc {
+	return rc.getRulesheetItem(ruleItems)
+}
+
+// UpdateRulesheetRule 	godoc
+// @Summary 			Criar ou Atualizar Regra de uma Folha de Regra
+// @Description 		Cria ou substitui a regra com o nome *name* da folha de regra com o ID *id*, sem enviar a folha de regra inteira. O corpo é a regra no mesmo formato das *rules* da folha de regra: uma regra, como *{"condition": "$idade > 60", "value": 0.2}*, uma lista de regras ou uma regra em texto.
+// @Description 		A folha de regra alterada é validada e salva como na atualização, em um commit com a mensagem da alteração, como *[FEATWS BOT] Update rule nome*.
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Rulesheet ID"
+// @Param				name path string true "Rule name"
+// @Param				If-Match header string false "Versão da folha de regra lida pelo cliente (ETag)"
+// @Param				rule body object true "Rule body"
+// @Success 			200 {object} responses.Rulesheet
+// @Header 				200 {string} Authorization "token access"
+// @Header 				200 {string} ETag "Versão da folha de regra"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			409 {object} responses.Error "Conflict"
+// @Failure 			422 {object} responses.Error "Unprocessable Entity"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Response 			404 "Not Found"
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id}/rules/{name} [put]
+// UpdateRulesheetRule returns a `gin.HandlerFunc` that creates or replaces a rule of a rulesheet by its
+// name, committing the change with a message like `[FEATWS BOT] Update rule name`.
+func (rc *rulesheets) UpdateRulesheetRule() gin.HandlerFunc {
+	return rc.changeRulesheetItem(ruleItems, false)
+}
+
+// DeleteRulesheetRule 	godoc
+// @Summary 			Deletar Regra de uma Folha de Regra
+// @Description 		Remove a regra com o nome *name* da folha de regra com o ID *id*. A folha de regra alterada é validada e salva como na atualização, em um commit com a mensagem *[FEATWS BOT] Delete rule nome*, e retorna 404 caso a regra não exista.
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Rulesheet ID"
+// @Param				name path string true "Rule name"
+// @Param				If-Match header string false "Versão da folha de regra lida pelo cliente (ETag)"
+// @Success 			200 {object} responses.Rulesheet
+// @Header 				200 {string} Authorization "token access"
+// @Header 				200 {string} ETag "Versão da folha de regra"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			409 {object} responses.Error "Conflict"
+// @Failure 			422 {object} responses.Error "Unprocessable Entity"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Response 			404 "Not Found"
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id}/rules/{name} [delete]
+// DeleteRulesheetRule returns a `gin.HandlerFunc` that removes a rule of a rulesheet by its name,
+// committing the change with a message like `[FEATWS BOT] Delete rule name`.
+func (rc *rulesheets) DeleteRulesheetRule() gin.HandlerFunc {
+	return rc.changeRulesheetItem(ruleItems, true)
+}
+
+// GetRulesheetFeature 		godoc
+// @Summary 			Obter Feature de uma Folha de Regra
+// @Description 		Retorna a feature com o nome *name* da folha de regra com o ID *id*, ou 404 caso a folha de regra ou a feature não existam.
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Rulesheet ID"
+// @Param				name path string true "Feature name"
+// @Success 			200 {object} responses.Feature
+// @Header 				200 {string} Authorization "token access"
+// @Header 				200 {string} ETag "Versão da folha de regra"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Response 			404 "Not Found"
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id}/features/{name} [get]
+// GetRulesheetFeature returns a `gin.HandlerFunc` that retrieves a feature of a rulesheet by its name.
+func (rc *rulesheets) GetRulesheetFeature() gin.HandlerFunc {
+	return rc.getRulesheetItem(featureItems)
+}
+
+// UpdateRulesheetFeature 	godoc
+// @Summary 			Criar ou Atualizar Feature de uma Folha de Regra
+// @Description 		Cria ou substitui a feature com o nome *name* da folha de regra com o ID *id*, sem enviar a folha de regra inteira. O corpo é a feature, como *{"type": "decimal", "description": "Desconto"}*, com o nome do caminho quando *name* é omitido.
+// @Description 		A folha de regra alterada é validada e salva como na atualização, em um commit com a mensagem da alteração, como *[FEATWS BOT] Update feature nome*.
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Rulesheet ID"
+// @Param				name path string true "Feature name"
+// @Param				If-Match header string false "Versão da folha de regra lida pelo cliente (ETag)"
+// @Param				feature body payloads.Feature true "Feature body"
+// @Success 			200 {object} responses.Rulesheet
+// @Header 				200 {string} Authorization "token access"
+// @Header 				200 {string} ETag "Versão da folha de regra"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			409 {object} responses.Error "Conflict"
+// @Failure 			422 {object} responses.Error "Unprocessable Entity"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Response 			404 "Not Found"
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id}/features/{name} [put]
+// UpdateRulesheetFeature returns a `gin.HandlerFunc` that creates or replaces a feature of a rulesheet by its
+// name, committing the change with a message like `[FEATWS BOT] Update feature name`.
+func (rc *rulesheets) UpdateRulesheetFeature() gin.HandlerFunc {
+	return rc.changeRulesheetItem(featureItems, false)
+}
+
+// DeleteRulesheetFeature 	godoc
+// @Summary 			Deletar Feature de uma Folha de Regra
+// @Description 		Remove a feature com o nome *name* da folha de regra com o ID *id*. A folha de regra alterada é validada e salva como na atualização, em um commit com a mensagem *[FEATWS BOT] Delete feature nome*, e retorna 404 caso a feature não exista.
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Rulesheet ID"
+// @Param				name path string true "Feature name"
+// @Param				If-Match header string false "Versão da folha de regra lida pelo cliente (ETag)"
+// @Success 			200 {object} responses.Rulesheet
+// @Header 				200 {string} Authorization "token access"
+// @Header 				200 {string} ETag "Versão da folha de regra"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			409 {object} responses.Error "Conflict"
+// @Failure 			422 {object} responses.Error "Unprocessable Entity"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Response 			404 "Not Found"
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id}/features/{name} [delete]
+// DeleteRulesheetFeature returns a `gin.HandlerFunc` that removes a feature of a rulesheet by its name,
+// committing the change with a message like `[FEATWS BOT] Delete feature name`.
+func (rc *rulesheets) DeleteRulesheetFeature() gin.HandlerFunc {
+	return rc.changeRulesheetItem(featureItems, true)
+}
+
+// GetRulesheetParameter 		godoc
+// @Summary 			Obter Parâmetro de uma Folha de Regra
+// @Description 		Retorna o parâmetro com o nome *name* da folha de regra com o ID *id*, ou 404 caso a folha de regra ou o parâmetro não existam.
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Rulesheet ID"
+// @Param				name path string true "Parameter name"
+// @Success 			200 {object} responses.Parameter
+// @Header 				200 {string} Authorization "token access"
+// @Header 				200 {string} ETag "Versão da folha de regra"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Response 			404 "Not Found"
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id}/parameters/{name} [get]
+// GetRulesheetParameter returns a `gin.HandlerFunc` that retrieves a parameter of a rulesheet by its name.
+func (rc *rulesheets) GetRulesheetParameter() gin.HandlerFunc {
+	return rc.getRulesheetItem(parameterItems)
+}
+
+// UpdateRulesheetParameter 	godoc
+// @Summary 			Criar ou Atualizar Parâmetro de uma Folha de Regra
+// @Description 		Cria ou substitui o parâmetro com o nome *name* da folha de regra com o ID *id*, sem enviar a folha de regra inteira. O corpo é o parâmetro, como *{"type": "integer", "required": true}*, com o nome do caminho quando *name* é omitido.
+// @Description 		A folha de regra alterada é validada e salva como na atualização, em um commit com a mensagem da alteração, como *[FEATWS BOT] Update parameter nome*.
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Rulesheet ID"
+// @Param				name path string true "Parameter name"
+// @Param				If-Match header string false "Versão da folha de regra lida pelo cliente (ETag)"
+// @Param				parameter body payloads.Parameter true "Parameter body"
+// @Success 			200 {object} responses.Rulesheet
+// @Header 				200 {string} Authorization "token access"
+// @Header 				200 {string} ETag "Versão da folha de regra"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			409 {object} responses.Error "Conflict"
+// @Failure 			422 {object} responses.Error "Unprocessable Entity"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Response 			404 "Not Found"
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id}/parameters/{name} [put]
+// UpdateRulesheetParameter returns a `gin.HandlerFunc` that creates or replaces a parameter of a rulesheet by its
+// name, committing the change with a message like `[FEATWS BOT] Update parameter name`.
+func (rc *rulesheets) UpdateRulesheetParameter() gin.HandlerFunc {
+	return rc.changeRulesheetItem(parameterItems, false)
+}
+
+// DeleteRulesheetParameter 	godoc
+// @Summary 			Deletar Parâmetro de uma Folha de Regra
+// @Description 		Remove o parâmetro com o nome *name* da folha de regra com o ID *id*. A folha de regra alterada é validada e salva como na atualização, em um commit com a mensagem *[FEATWS BOT] Delete parameter nome*, e retorna 404 caso o parâmetro não exista.
+// @Tags 				Rulesheet
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Rulesheet ID"
+// @Param				name path string true "Parameter name"
+// @Param				If-Match header string false "Versão da folha de regra lida pelo cliente (ETag)"
+// @Success 			200 {object} responses.Rulesheet
+// @Header 				200 {string} Authorization "token access"
+// @Header 				200 {string} ETag "Versão da folha de regra"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			409 {object} responses.Error "Conflict"
+// @Failure 			422 {object} responses.Error "Unprocessable Entity"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Response 			404 "Not Found"
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/rulesheets/{id}/parameters/{name} [delete]
+// DeleteRulesheetParameter returns a `gin.HandlerFunc` that removes a parameter of a rulesheet by its name,
+// committing the change with a message like `[FEATWS BOT] Delete parameter name`.
+func (rc *rulesheets) DeleteRulesheetParameter() gin.HandlerFunc {
+	return rc.changeRulesheetItem(parameterItems, true)
+}
+
+// rulesheetItems is a kind of the items of a rulesheet that are read and changed one at a time by their
+// names: its rules, features or parameters.
+//
+// Property:
+//   - kind: the name of the kind of the items, used on the commit messages, like `rule`.
+//   - get: returns the response of the item of the rulesheet with the name, or false if there's none.
+//   - put: reads the item from the request body and sets it on the payload of the rulesheet, returning whether it replaced an existing item.
+//   - remove: removes the item with the name from the payload of the rulesheet, returning false if there's none.
+type rulesheetItems struct {
+	kind   string
+	get    func(rulesheet *dtos.Rulesheet, name string) (interface{}, bool)
+	put    func(c *gin.Context, payload *payloads.Rulesheet, name string) (bool, error)
+	remove func(payload *payloads.Rulesheet, name string) bool
+}
+
+// ruleItems are the rules of a rulesheet, each one with any of the formats of the "rules" of the
+// rulesheet: a rule, a list of rules or a string rule.
+var ruleItems = rulesheetItems{
+	kind: "rule",
+	get: func(rulesheet *dtos.Rulesheet, name string) (interface{}, bool) {
+		if rulesheet.Rules == nil {
+			return nil, false
+		}
+		rule, ok := (*rulesheet.Rules)[name]
+		return rule, ok
+	},
+	put: func(c *gin.Context, payload *payloads.Rulesheet, name string) (bool, error) {
+		var rule interface{}
+		if err := c.ShouldBindJSON(&rule); err != nil {
+			return false, err
+		}
+		if rule == nil {
+			return false, errors.New("the rule is required")
+		}
+
+		rules := copyRules(payload.Rules)
+		_, exists := rules[name]
+		rules[name] = rule
+		payload.Rules = &rules
+		return exists, nil
+	},
+	remove: func(payload *payloads.Rulesheet, name string) bool {
+		rules := copyRules(payload.Rules)
+		if _, exists := rules[name]; !exists {
+			return false
+		}
+		delete(rules, name)
+		payload.Rules = &rules
+		return true
+	},
+}
+
+// featureItems are the features declared by a rulesheet.
+var featureItems = rulesheetItems{
+	kind: "feature",
+	get: func(rulesheet *dtos.Rulesheet, name string) (interface{}, bool) {
+		if rulesheet.Features != nil {
+			for _, feature := range *rulesheet.Features {
+				if feature.Name == name {
+					return responses.NewFeature(feature), true
+				}
+			}
+		}
+		return nil, false
+	},
+	put: func(c *gin.Context, payload *payloads.Rulesheet, name string) (bool, error) {
+		var feature payloads.Feature
+		if err := bindItem(c, &feature, &feature.Name, name); err != nil {
+			return false, err
+		}
+
+		features := make([]payloads.Feature, 0)
+		if payload.Features != nil {
+			features = *payload.Features
+		}
+		payload.Features = &features
+		for index := range features {
+			if features[index].Name == name {
+				features[index] = feature
+				return true, nil
+			}
+		}
+		features = append(features, feature)
+		return false, nil
+	},
+	remove: func(payload *payloads.Rulesheet, name string) bool {
+		if payload.Features == nil {
+			return false
+		}
+		features := *payload.Features
+		for index := range features {
+			if features[index].Name == name {
+				features = append(features[:index], features[index+1:]...)
+				payload.Features = &features
+				return true
+			}
+		}
+		return false
+	},
+}
+
+// parameterItems are the parameters declared by a rulesheet.
+var parameterItems = rulesheetItems{
+	kind: "parameter",
+	get: func(rulesheet *dtos.Rulesheet, name string) (interface{}, bool) {
+		if rulesheet.Parameters != nil {
+			for _, parameter := range *rulesheet.Parameters {
+				if parameter.Name == name {
+					return responses.NewParameter(parameter), true
+				}
+			}
+		}
+		return nil, false
+	},
+	put: func(c *gin.Context, payload *payloads.Rulesheet, name string) (bool, error) {
+		var parameter payloads.Parameter
+		if err := bindItem(c, &parameter, &parameter.Name, name); err != nil {
+			return false, err
+		}
+
+		parameters := make([]payloads.Parameter, 0)
+		if payload.Parameters != nil {
+			parameters = *payload.Parameters
+		}
+		payload.Parameters = &parameters
+		for index := range parameters {
+			if parameters[index].Name == name {
+				parameters[index] = parameter
+				return true, nil
+			}
+		}
+		parameters = append(parameters, parameter)
+		return false, nil
+	},
+	remove: func(payload *payloads.Rulesheet, name string) bool {
+		if payload.Parameters == nil {
+			return false
+		}
+		parameters := *payload.Parameters
+		for index := range parameters {
+			if parameters[index].Name == name {
+				parameters = append(parameters[:index], parameters[index+1:]...)
+				payload.Parameters = &parameters
+				return true
+			}
+		}
+		return false
+	},
+}
+
+// copyRules copies the rules of a payload, so they can be changed without changing the rulesheet they
+// were copied from.
+func copyRules(rules *map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	if rules != nil {
+		for name, rule := range *rules {
+			result[name] = rule
+		}
+	}
+	return result
+}
+
+// bindItem reads a feature or parameter from the request body. Its name is the one of the path when it's
+// omitted, and must be the same otherwise.
+func bindItem(c *gin.Context, item interface{}, itemName *string, name string) error {
+	if err := c.ShouldBindJSON(item); err != nil {
+		return err
+	}
+
+	if *itemName == "" {
+		*itemName = name
+	}
+	if *itemName != name {
+		return fmt.Errorf("the name '%s' is different from the name of the path '%s'", *itemName, name)
+	}
+
+	return validate.Struct(item)
+}
+
+// getRulesheetItem returns a `gin.HandlerFunc` that responds with the item of the rulesheet of the "id"
+// param with the name of the "name" param, or a 404 status code if the rulesheet or the item don't exist.
+func (rc *rulesheets) getRulesheetItem(items rulesheetItems) gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+
+		id, exists := c.Params.Get("id")
+
+		if !exists {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: "Required param 'id'",
+			})
+			log.Error("Error on check if the rulesheet exist")
+			return
+		}
+
+		entity, err := rc.service.Get(ctx, id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on fetch unique rulesheet: %v", err)
+			return
+		}
+
+		if entity == nil {
+			c.String(http.StatusNotFound, "")
+			return
+		}
+
+		item, ok := items.get(entity, c.Param("name"))
+		if !ok {
+			c.String(http.StatusNotFound, "")
+			return
+		}
+
+		setRulesheetETag(c, entity)
+		c.JSON(http.StatusOK, item)
+	}
+}
+
+// changeRulesheetItem returns a `gin.HandlerFunc` that creates or replaces, when `remove` isn't set, or
+// removes the item of the rulesheet of the "id" param with the name of the "name" param. The rulesheet is
+// validated and saved like `UpdateRulesheet`, with a commit message of the change, like
+// `[FEATWS BOT] Update rule discount`. It returns a 404 status code if the rulesheet doesn't exist or the
+// removed item doesn't exist, and a 400 status code if the item of the request body is invalid.
+func (rc *rulesheets) changeRulesheetItem(items rulesheetItems, remove bool) gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+
+		id, exists := c.Params.Get("id")
+
+		if !exists {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: "Required param 'id'",
+			})
+			log.Error("Error on check if the rulesheet exist")
+			return
+		}
+		name := c.Param("name")
+
+		foudedEntity, err := rc.service.Get(ctx, id)
+		if err != nil || foudedEntity == nil {
+			c.String(http.StatusNotFound, "")
+			log.Errorf("You are trying to change a non existing record: %v", err)
+			return
+		}
+
+		ifMatch := c.GetHeader("If-Match")
+		if ifMatch != "" && !matchesETag(ifMatch, foudedEntity.Version) {
+			c.JSON(http.StatusConflict, responses.Error{
+				Error: fmt.Sprintf("%s: the current version is %s", services.ErrVersionConflict, foudedEntity.Version),
+			})
+			return
+		}
+
+		payload := services.NewRulesheetPayload(foudedEntity)
+
+		action := "Delete"
+		if remove {
+			if !items.remove(&payload, name) {
+				c.String(http.StatusNotFound, "")
+				return
+			}
+		} else {
+			replaced, err := items.put(c, &payload, name)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, responses.Error{
+					Error: err.Error(),
+				})
+				log.Errorf("Error on read the %s: %v", items.kind, err)
+				return
+			}
+
+			action = "Create"
+			if replaced {
+				action = "Update"
+			}
+		}
+
+		rc.saveRulesheet(ctx, c, id, payload, foudedEntity, ifMatch, fmt.Sprintf("[FEATWS BOT] %s %s %s", action, items.kind, name))
+	}
+}
@@ -5,15 +5,19 @@ import (
 	"encoding/json"
 	"errors"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
+	"github.com/bancodobrasil/featws-api/config"
 	v1 "github.com/bancodobrasil/featws-api/controllers/v1"
 	"github.com/bancodobrasil/featws-api/dtos"
 	mock_services "github.com/bancodobrasil/featws-api/mocks/services"
 	payloads "github.com/bancodobrasil/featws-api/payloads/v1"
+	responses "github.com/bancodobrasil/featws-api/responses/v1"
 	"github.com/bancodobrasil/featws-api/services"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -99,9 +103,120 @@ func TestRulesheet_GetRulesheetByID(t *testing.T) {
 		srv.On("Get", mock.Anything, "1").Return(reponseEntity, nil)
 		v1.NewRulesheets(srv).GetRulesheet()(c)
 		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("ETag"))
+	})
+
+	// It tests that the ETag header carries the version of the rulesheet.
+	t.Run("ETag Flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+		srv := new(mock_services.Rulesheets)
+		srv.On("Get", mock.Anything, "1").Return(&dtos.Rulesheet{ID: uint(1), Name: "Test", Version: "4"}, nil)
+		v1.NewRulesheets(srv).GetRulesheet()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+
+	})
+
+}
+
+// TestRulesheet_GetRulesheetBySlug tests the GetRulesheetBySlug function and the routes by slug that resolve
+// the ID of the rulesheet before the handlers by ID.
+func TestRulesheet_GetRulesheetBySlug(t *testing.T) {
+	// It tests that the rulesheet with the slug is returned with its ETag.
+	t.Run("Normal flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+
+		c.Params = gin.Params{gin.Param{Key: "slug", Value: "test"}}
+		srv := new(mock_services.Rulesheets)
+		srv.On("GetBySlug", mock.Anything, "test").Return(&dtos.Rulesheet{ID: uint(1), Name: "Test", Slug: "test", Version: "4"}, nil)
+		v1.NewRulesheets(srv).GetRulesheetBySlug()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"id":1,"name":"Test","slug":"test","version":"4"}`, w.Body.String())
+		assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+	})
+
+	// It tests that an unknown slug returns a 404.
+	t.Run("Not found flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+
+		c.Params = gin.Params{gin.Param{Key: "slug", Value: "unknown"}}
+		srv := new(mock_services.Rulesheets)
+		srv.On("GetBySlug", mock.Anything, "unknown").Return(nil, nil)
+		v1.NewRulesheets(srv).GetRulesheetBySlug()(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	// It tests that an error of the service returns a 500.
+	t.Run("Error flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+
+		c.Params = gin.Params{gin.Param{Key: "slug", Value: "test"}}
+		srv := new(mock_services.Rulesheets)
+		srv.On("GetBySlug", mock.Anything, "test").Return(nil, errors.New("error"))
+		v1.NewRulesheets(srv).GetRulesheetBySlug()(c)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	// It tests that the DELETE route by slug deletes the rulesheet with the ID of the slug.
+	t.Run("Delete by slug flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("GetBySlug", mock.Anything, "test").Return(&dtos.Rulesheet{ID: uint(7), Slug: "test"}, nil)
+		srv.On("Delete", mock.Anything, "7").Return(true, nil)
+		controller := v1.NewRulesheets(srv)
+
+		router := gin.New()
+		router.DELETE("/rulesheets/slug/:slug", controller.ResolveRulesheetSlug(), controller.DeleteRulesheet())
 
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/rulesheets/slug/test", nil))
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		srv.AssertExpectations(t)
 	})
 
+	// It tests that the routes by slug return a 404 without calling the next handler when the slug is unknown.
+	t.Run("Resolve unknown slug flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("GetBySlug", mock.Anything, "unknown").Return(nil, nil)
+		controller := v1.NewRulesheets(srv)
+
+		router := gin.New()
+		router.DELETE("/rulesheets/slug/:slug", controller.ResolveRulesheetSlug(), controller.DeleteRulesheet())
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/rulesheets/slug/unknown", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		srv.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
 }
 
 // TestRulesheet_GetAllRulesheets tests the GetAllRulesheets function in the Rulesheets API endpoint, covering various
@@ -109,7 +224,7 @@ func TestRulesheet_GetRulesheetByID(t *testing.T) {
 func TestRulesheet_GetAllRulesheets(t *testing.T) {
 	// It tests the error handling of a limit query parameter that cannot be parsed. It creates a new HTTP
 	// request with a malformed limit query parameter and sends it to a mock Rulesheets service using the Gin.
-	// Thetest then checks that the HTTP response code is equal to 500 using the assert package.
+	// Thetest then checks that the HTTP response code is equal to 400 using the assert package.
 	t.Run("Error on parse limit Query Flow", func(t *testing.T) {
 		gin.SetMode(gin.TestMode)
 
@@ -122,12 +237,12 @@ func TestRulesheet_GetAllRulesheets(t *testing.T) {
 
 		srv := new(mock_services.Rulesheets)
 		v1.NewRulesheets(srv).GetRulesheets()(c)
-		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
 
 	})
 
 	// It tests the error handling of a function that retrieves rulesheets from a server. The test creates
-	// a mock server and sends a request with an invalid page query parameter. The test expects the server to return a 500.
+	// a mock server and sends a request with an invalid page query parameter. The test expects the server to return a 400.
 	t.Run("Error on parse page Query Flow", func(t *testing.T) {
 		gin.SetMode(gin.TestMode)
 
@@ -140,7 +255,7 @@ func TestRulesheet_GetAllRulesheets(t *testing.T) {
 
 		srv := new(mock_services.Rulesheets)
 		v1.NewRulesheets(srv).GetRulesheets()(c)
-		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
 
 	})
 
@@ -163,7 +278,8 @@ func TestRulesheet_GetAllRulesheets(t *testing.T) {
 			Limit: 1,
 			Page:  1,
 		}
-		filter := make(map[string]interface{})
+		filter := &services.RulesheetFilter{}
+		srv.On("Count", mock.Anything, filter).Return(int64(0), nil)
 		srv.On("Find", mock.Anything, filter, findOpts).Return(nil, nil)
 		v1.NewRulesheets(srv).GetRulesheets()(c)
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -188,7 +304,7 @@ func TestRulesheet_GetAllRulesheets(t *testing.T) {
 			Limit: 1,
 			Page:  1,
 		}
-		filter := make(map[string]interface{})
+		filter := &services.RulesheetFilter{}
 		reponseEntities := []*dtos.Rulesheet{
 			{
 				ID:   uint(1),
@@ -200,6 +316,7 @@ func TestRulesheet_GetAllRulesheets(t *testing.T) {
 			},
 		}
 
+		srv.On("Count", mock.Anything, filter).Return(int64(2), nil)
 		srv.On("Find", mock.Anything, filter, findOpts).Return(reponseEntities, nil)
 		v1.NewRulesheets(srv).GetRulesheets()(c)
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -224,7 +341,8 @@ func TestRulesheet_GetAllRulesheets(t *testing.T) {
 			Limit: 1,
 			Page:  1,
 		}
-		filter := make(map[string]interface{})
+		filter := &services.RulesheetFilter{}
+		srv.On("Count", mock.Anything, filter).Return(int64(1), nil)
 		srv.On("Find", mock.Anything, filter, findOpts).Return(nil, errors.New("error"))
 		v1.NewRulesheets(srv).GetRulesheets()(c)
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -232,8 +350,8 @@ func TestRulesheet_GetAllRulesheets(t *testing.T) {
 
 	// It tests the normal flow of a function that retrieves rulesheets with a count query. It creates a
 	// test context and sets the request URL with query parameters for limit, page, and count. It then
-	// creates a mock service for rulesheets and sets expectations for the Find and Count methods. Finally,
-	// it calls the GetRulesheets function and asserts that the HTTP status code returned is 200.
+	// creates a mock service for rulesheets and sets expectations for the Count method. Finally, it calls the
+	// GetRulesheets function and asserts that the HTTP status code returned is 200, with the total and no items.
 	t.Run("Normal flow with count query", func(t *testing.T) {
 		gin.SetMode(gin.TestMode)
 
@@ -245,16 +363,16 @@ func TestRulesheet_GetAllRulesheets(t *testing.T) {
 		c.Request.URL, _ = url.Parse("?limit=1&page=1&count=true")
 
 		srv := new(mock_services.Rulesheets)
-		findOpts := &services.FindOptions{}
-		filter := make(map[string]interface{})
-		srv.On("Find", mock.Anything, filter, findOpts).Return(nil, nil)
-		srv.On("Count", mock.Anything, filter).Return(int64(0), nil)
+		filter := &services.RulesheetFilter{}
+		srv.On("Count", mock.Anything, filter).Return(int64(3), nil)
 		v1.NewRulesheets(srv).GetRulesheets()(c)
 		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"items":[],"total":3,"page":1,"limit":1}`, w.Body.String())
+		srv.AssertNotCalled(t, "Find", mock.Anything, mock.Anything, mock.Anything)
 	})
 
 	// It tests the error flow of a function that retrieves rulesheets with a count query parameter. The test
-	// creates a mock service for rulesheets and sets expectations for the Find and Count methods. It then sends
+	// creates a mock service for rulesheets and sets expectations for the Count method. It then sends
 	// a GET request to the function with a URL containing the count query parameter. The test asserts that the response
 	// code is HTTP 500.
 	t.Run("Error flow with count query", func(t *testing.T) {
@@ -268,14 +386,180 @@ func TestRulesheet_GetAllRulesheets(t *testing.T) {
 		c.Request.URL, _ = url.Parse("?limit=1&page=1&count=true")
 
 		srv := new(mock_services.Rulesheets)
-		findOpts := &services.FindOptions{}
-		filter := make(map[string]interface{})
-		srv.On("Find", mock.Anything, filter, findOpts).Return(nil, nil)
+		filter := &services.RulesheetFilter{}
 		srv.On("Count", mock.Anything, filter).Return(int64(0), errors.New("error"))
 		v1.NewRulesheets(srv).GetRulesheets()(c)
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 	})
 
+	// It tests that the filter query parameters are passed to the service, with the end of the range of a
+	// date at the end of the day.
+	t.Run("Normal flow with filters", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.URL, _ = url.Parse("?name=desc&slug=desc-&description=gold&q=vip&createdFrom=2023-01-01T10:00:00Z&updatedTo=2023-01-31&deleted=only")
+
+		srv := new(mock_services.Rulesheets)
+		createdFrom := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+		updatedTo := time.Date(2023, 1, 31, 23, 59, 59, 999999999, time.UTC)
+		filter := &services.RulesheetFilter{
+			Name:        "desc",
+			Slug:        "desc-",
+			Description: "gold",
+			Search:      "vip",
+			CreatedFrom: &createdFrom,
+			UpdatedTo:   &updatedTo,
+			Deleted:     services.DeletedOnly,
+		}
+		srv.On("Count", mock.Anything, filter).Return(int64(0), nil)
+		srv.On("Find", mock.Anything, filter, &services.FindOptions{Limit: 10, Page: 1}).Return([]*dtos.Rulesheet{}, nil)
+		v1.NewRulesheets(srv).GetRulesheets()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		srv.AssertExpectations(t)
+	})
+
+	// It tests that the page is returned on the envelope, with the cursor of the next page when there are
+	// more rulesheets after it.
+	t.Run("Normal flow with next page", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.URL, _ = url.Parse("?limit=2&sort=name,-updatedAt")
+
+		srv := new(mock_services.Rulesheets)
+		filter := &services.RulesheetFilter{}
+		sort := []services.SortField{{Field: "name"}, {Field: "updatedAt", Desc: true}}
+		findOpts := &services.FindOptions{Limit: 2, Page: 1, Sort: sort}
+		reponseEntities := []*dtos.Rulesheet{
+			{ID: uint(2), Name: "Gold"},
+			{ID: uint(1), Name: "Silver"},
+		}
+		srv.On("Count", mock.Anything, filter).Return(int64(3), nil)
+		srv.On("Find", mock.Anything, filter, findOpts).Return(reponseEntities, nil)
+		v1.NewRulesheets(srv).GetRulesheets()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response responses.RulesheetPage
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Items, 2)
+		assert.Equal(t, int64(3), response.Total)
+		assert.Equal(t, 1, response.Page)
+		assert.Equal(t, 2, response.Limit)
+		assert.Equal(t, services.NextCursor(reponseEntities[1], sort), response.Next)
+	})
+
+	// It tests that the last page has no cursor of the next page.
+	t.Run("Normal flow on the last page", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.URL, _ = url.Parse("?limit=2&page=2")
+
+		srv := new(mock_services.Rulesheets)
+		filter := &services.RulesheetFilter{}
+		srv.On("Count", mock.Anything, filter).Return(int64(3), nil)
+		srv.On("Find", mock.Anything, filter, &services.FindOptions{Limit: 2, Page: 2}).Return([]*dtos.Rulesheet{{ID: uint(3)}}, nil)
+		v1.NewRulesheets(srv).GetRulesheets()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), `"next"`)
+	})
+
+	// It tests that the cursor is passed to the service, without a page, and that a full page has the cursor
+	// of the next one.
+	t.Run("Normal flow with cursor", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.URL, _ = url.Parse("?limit=1&cursor=abc")
+
+		srv := new(mock_services.Rulesheets)
+		filter := &services.RulesheetFilter{}
+		reponseEntities := []*dtos.Rulesheet{{ID: uint(5)}}
+		srv.On("Count", mock.Anything, filter).Return(int64(9), nil)
+		srv.On("Find", mock.Anything, filter, &services.FindOptions{Limit: 1, Cursor: "abc"}).Return(reponseEntities, nil)
+		v1.NewRulesheets(srv).GetRulesheets()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response responses.RulesheetPage
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 0, response.Page)
+		assert.Equal(t, services.NextCursor(reponseEntities[0], nil), response.Next)
+	})
+
+	// It tests that an invalid cursor returns a 400.
+	t.Run("Error flow with invalid cursor", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.URL, _ = url.Parse("?cursor=abc")
+
+		srv := new(mock_services.Rulesheets)
+		filter := &services.RulesheetFilter{}
+		srv.On("Count", mock.Anything, filter).Return(int64(9), nil)
+		srv.On("Find", mock.Anything, filter, &services.FindOptions{Limit: 10, Cursor: "abc"}).Return(nil, services.ErrInvalidCursor)
+		v1.NewRulesheets(srv).GetRulesheets()(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	// It tests that an unknown sort field, a page with a cursor and a limit that isn't positive return a 400
+	// without calling the service.
+	t.Run("Error flow with invalid options", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		for _, query := range []string{"?sort=unknown", "?page=2&cursor=abc", "?limit=0"} {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = &http.Request{
+				Header: make(http.Header),
+			}
+			c.Request.URL, _ = url.Parse(query)
+
+			srv := new(mock_services.Rulesheets)
+			v1.NewRulesheets(srv).GetRulesheets()(c)
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+			srv.AssertNotCalled(t, "Count", mock.Anything, mock.Anything)
+		}
+	})
+
+	// It tests that the invalid filters are rejected with 400 Bad Request.
+	for _, query := range []string{"?createdFrom=yesterday", "?updatedTo=2023-31-01", "?deleted=yes"} {
+		t.Run("Error on parse filter flow "+query, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = &http.Request{
+				Header: make(http.Header),
+			}
+			c.Request.URL, _ = url.Parse(query)
+
+			srv := new(mock_services.Rulesheets)
+			v1.NewRulesheets(srv).GetRulesheets()(c)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			srv.AssertNotCalled(t, "Find", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
 }
 
 // TestRulesheet_CreateRulesheet is a test function for creating a rulesheet in a Go application, with different scenarios for
@@ -603,6 +887,36 @@ func TestRulesheet_UpdateRulesheet(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
+	// It tests that a payload that can't be bound stops the update, even when the fields that were decoded
+	// would pass the validation.
+	t.Run("Error on binding a partially decoded payload flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{"name":"Test","id":"1"}`)))
+
+		srv := new(mock_services.Rulesheets)
+
+		oldRulesheet := &dtos.Rulesheet{
+			ID:   uint(1),
+			Name: "Test",
+		}
+
+		srv.On("Get", mock.Anything, "1").Return(oldRulesheet, nil)
+
+		v1.NewRulesheets(srv).UpdateRulesheet()(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		srv.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
 	// It is testing the "UpdateRulesheet" function of the "Rulesheets" API endpoint. The test is checking if
 	// the API returns a status code of 400 (Bad Request) when a required field is missing in the request payload.
 	// The test creates a mock service for the "Rulesheets" endpoint and sets up the necessary request context and
@@ -770,6 +1084,80 @@ func TestRulesheet_UpdateRulesheet(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, w.Code)
 	})
 
+	// It tests that an update with an `If-Match` header matching the current version is based on it and
+	// returns the ETag of the new version.
+	t.Run("Normal flow with If-Match", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.Header.Set("If-Match", `W/"2"`)
+
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		bytedPayload, _ := json.Marshal(&payloads.Rulesheet{Name: "Test"})
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader(bytedPayload))
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Get", mock.Anything, "1").Return(&dtos.Rulesheet{ID: uint(1), Name: "Test", Version: "2"}, nil)
+		srv.On("Update", mock.Anything, dtos.Rulesheet{ID: uint(1), Name: "Test", BaseVersion: "2"}).Return(&dtos.Rulesheet{ID: uint(1), Name: "Test", Version: "3"}, nil)
+		v1.NewRulesheets(srv).UpdateRulesheet()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	})
+
+	// It tests that an update with an `If-Match` header of an outdated version is rejected without updating.
+	t.Run("Error on outdated If-Match flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.Header.Set("If-Match", `"1"`)
+
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		bytedPayload, _ := json.Marshal(&payloads.Rulesheet{Name: "Test"})
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader(bytedPayload))
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Get", mock.Anything, "1").Return(&dtos.Rulesheet{ID: uint(1), Name: "Test", Version: "2"}, nil)
+		v1.NewRulesheets(srv).UpdateRulesheet()(c)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		srv.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	// It tests that an update is rejected when the rulesheet is changed by another update while saving it.
+	t.Run("Error on version conflict while saving flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.Header.Set("If-Match", `"2"`)
+
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		bytedPayload, _ := json.Marshal(&payloads.Rulesheet{Name: "Test"})
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader(bytedPayload))
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Get", mock.Anything, "1").Return(&dtos.Rulesheet{ID: uint(1), Name: "Test", Version: "2"}, nil)
+		srv.On("Update", mock.Anything, mock.Anything).Return(nil, services.ErrVersionConflict)
+		v1.NewRulesheets(srv).UpdateRulesheet()(c)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
 	// t.Run("Error on define entity Flow", func(t *testing.T) {
 	// 	gin.SetMode(gin.TestMode)
 
@@ -815,26 +1203,155 @@ func TestRulesheet_UpdateRulesheet(t *testing.T) {
 	// })
 }
 
-// estRulesheet_DeleteRulesheet is a test function that tests the DeleteRulesheet function in the Rulesheets API endpoint,
-// covering different scenarios and expected HTTP status codes.
-func TestRulesheet_DeleteRulesheet(t *testing.T) {
-	// It tests is the normal flow of the DeleteRulesheet function in the v1 package of a Rulesheets service.
-	t.Run("Normal flow", func(t *testing.T) {
+// newPatchContext creates the context of a PATCH request of the rulesheet 1 with the given content type.
+func newPatchContext(w *httptest.ResponseRecorder, contentType string, body string) *gin.Context {
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPatch, "/api/v1/rulesheets/1", bytes.NewReader([]byte(body)))
+	c.Request.Header.Set("Content-Type", contentType)
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+	return c
+}
+
+// newPatchedRulesheet returns the rulesheet patched by the PatchRulesheet tests.
+func newPatchedRulesheet() *dtos.Rulesheet {
+	rules := map[string]interface{}{
+		"discount": []interface{}{
+			map[string]interface{}{"condition": "$age > 60", "value": 0.2},
+			map[string]interface{}{"value": 0},
+		},
+	}
+	return &dtos.Rulesheet{
+		ID:         1,
+		Name:       "Test",
+		Slug:       "test",
+		Version:    "3",
+		Parameters: &[]dtos.Parameter{{Name: "age", Type: "integer"}},
+		Features:   &[]dtos.Feature{},
+		Rules:      &rules,
+	}
+}
+
+// TestRulesheet_PatchRulesheet tests the partial updates of a rulesheet with a JSON Merge Patch or a JSON Patch.
+func TestRulesheet_PatchRulesheet(t *testing.T) {
+	// It tests that a merge patch changes only the given members and keeps the rest of the rulesheet.
+	t.Run("Merge patch flow", func(t *testing.T) {
 		gin.SetMode(gin.TestMode)
 
 		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
+		c := newPatchContext(w, "application/merge-patch+json", `{"description":"Discounts","rules":{"vip":{"value":true}}}`)
 
-		c.Request = &http.Request{
-			Header: make(http.Header),
-		}
+		srv := new(mock_services.Rulesheets)
+		srv.On("Get", mock.Anything, "1").Return(newPatchedRulesheet(), nil)
+		srv.On("Update", mock.Anything, mock.MatchedBy(func(dto dtos.Rulesheet) bool {
+			rules := *dto.Rules
+			return dto.ID == 1 && dto.Name == "Test" && dto.Slug == "test" && dto.Description == "Discounts" &&
+				len(rules) == 2 && len(rules["discount"].([]interface{})) == 2 && len(*dto.Parameters) == 1
+		})).Return(&dtos.Rulesheet{ID: 1, Name: "Test", Slug: "test", Version: "4"}, nil)
+		v1.NewRulesheets(srv).PatchRulesheet()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+		srv.AssertExpectations(t)
+	})
 
-		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+	// It tests that a JSON Patch changes a single condition of a rule.
+	t.Run("JSON Patch flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c := newPatchContext(w, "application/json-patch+json", `[
+			{"op":"test","path":"/rules/discount/0/condition","value":"$age > 60"},
+			{"op":"replace","path":"/rules/discount/0/condition","value":"$age >= 65"}
+		]`)
 
 		srv := new(mock_services.Rulesheets)
+		srv.On("Get", mock.Anything, "1").Return(newPatchedRulesheet(), nil)
+		srv.On("Update", mock.Anything, mock.MatchedBy(func(dto dtos.Rulesheet) bool {
+			first := (*dto.Rules)["discount"].([]interface{})[0].(*dtos.Rule)
+			return first.Condition == "$age >= 65" && first.Value == 0.2
+		})).Return(&dtos.Rulesheet{ID: 1, Name: "Test", Slug: "test", Version: "4"}, nil)
+		v1.NewRulesheets(srv).PatchRulesheet()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		srv.AssertExpectations(t)
+	})
 
-		srv.On("Delete", mock.Anything, "1").Return(true, nil)
-		v1.NewRulesheets(srv).DeleteRulesheet()(c)
+	// It tests that the patched rulesheet is validated like an update.
+	t.Run("Invalid patched rule flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c := newPatchContext(w, "application/json-patch+json", `[{"op":"replace","path":"/rules/discount/0/condition","value":"$unknown > 1"}]`)
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Get", mock.Anything, "1").Return(newPatchedRulesheet(), nil)
+		v1.NewRulesheets(srv).PatchRulesheet()(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		srv.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	// It tests the errors of the patches that can't be applied and of the patches that change the slug.
+	t.Run("Error flows", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		for _, testCase := range []struct {
+			contentType string
+			body        string
+			expected    int
+		}{
+			{"application/json", `{"name":"Other"}`, http.StatusUnsupportedMediaType},
+			{"application/merge-patch+json", `{"name":`, http.StatusBadRequest},
+			{"application/merge-patch+json", `{"slug":"other"}`, http.StatusBadRequest},
+			{"application/merge-patch+json", `{"name":null}`, http.StatusBadRequest},
+			{"application/json-patch+json", `[{"op":"unknown","path":"/name"}]`, http.StatusBadRequest},
+			{"application/json-patch+json", `[{"op":"remove","path":"/rules/unknown"}]`, http.StatusUnprocessableEntity},
+			{"application/json-patch+json", `[{"op":"test","path":"/name","value":"Other"}]`, http.StatusUnprocessableEntity},
+		} {
+			w := httptest.NewRecorder()
+			c := newPatchContext(w, testCase.contentType, testCase.body)
+
+			srv := new(mock_services.Rulesheets)
+			srv.On("Get", mock.Anything, "1").Return(newPatchedRulesheet(), nil)
+			v1.NewRulesheets(srv).PatchRulesheet()(c)
+			assert.Equal(t, testCase.expected, w.Code, testCase.body)
+			srv.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		}
+	})
+
+	// It tests that a patch over an outdated version is rejected with 409 Conflict.
+	t.Run("If-Match conflict flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c := newPatchContext(w, "application/merge-patch+json", `{"description":"Discounts"}`)
+		c.Request.Header.Set("If-Match", `"2"`)
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Get", mock.Anything, "1").Return(newPatchedRulesheet(), nil)
+		v1.NewRulesheets(srv).PatchRulesheet()(c)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		srv.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+// estRulesheet_DeleteRulesheet is a test function that tests the DeleteRulesheet function in the Rulesheets API endpoint,
+// covering different scenarios and expected HTTP status codes.
+func TestRulesheet_DeleteRulesheet(t *testing.T) {
+	// It tests is the normal flow of the DeleteRulesheet function in the v1 package of a Rulesheets service.
+	t.Run("Normal flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+
+		srv.On("Delete", mock.Anything, "1").Return(true, nil)
+		v1.NewRulesheets(srv).DeleteRulesheet()(c)
 		assert.Equal(t, http.StatusNoContent, w.Code)
 	})
 
@@ -904,3 +1421,1322 @@ func TestRulesheet_DeleteRulesheet(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 	})
 }
+
+// TestRulesheet_GetRulesheetVersions tests the GetRulesheetVersions function in the Rulesheets API endpoint, covering
+// the normal flow, the pagination parsing and the error handling.
+func TestRulesheet_GetRulesheetVersions(t *testing.T) {
+	// It tests the normal flow, where the service returns the versions of the rulesheet paginated with the
+	// query parameters and the endpoint responds with 200 OK and the list of versions.
+	t.Run("Normal flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.URL, _ = url.Parse("?limit=2&page=1")
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		findOpts := &services.FindOptions{
+			Limit: 2,
+			Page:  1,
+		}
+		versions := []*dtos.Version{
+			{Number: "2", Commit: "sha2"},
+			{Number: "1", Commit: "sha1"},
+		}
+		srv.On("History", mock.Anything, "1", findOpts).Return(versions, nil)
+		v1.NewRulesheets(srv).GetRulesheetVersions()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[{"version":"2","commit":"sha2"},{"version":"1","commit":"sha1"}]`, w.Body.String())
+	})
+
+	// It tests that an invalid pagination query parameter is rejected with 400 Bad Request.
+	t.Run("Error on parse limit Query Flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.URL, _ = url.Parse("?limit=abc")
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		v1.NewRulesheets(srv).GetRulesheetVersions()(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	// It tests that an error on the service is returned as 500 Internal Server Error.
+	t.Run("Error on fetch versions flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.URL, _ = url.Parse("")
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("History", mock.Anything, "1", &services.FindOptions{}).Return(nil, errors.New("error"))
+		v1.NewRulesheets(srv).GetRulesheetVersions()(c)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+// TestRulesheet_GetRulesheetVersion tests the GetRulesheetVersion function in the Rulesheets API endpoint.
+func TestRulesheet_GetRulesheetVersion(t *testing.T) {
+	// It tests the normal flow, where the service returns the rulesheet at the requested version.
+	t.Run("Normal flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}, gin.Param{Key: "version", Value: "3"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("GetVersion", mock.Anything, "1", "3").Return(&dtos.Rulesheet{ID: 1, Name: "Test", Version: "3"}, nil)
+		v1.NewRulesheets(srv).GetRulesheetVersion()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"id":1,"name":"Test","version":"3"}`, w.Body.String())
+	})
+
+	// It tests that an unknown version is returned as 404 Not Found.
+	t.Run("Version not found flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}, gin.Param{Key: "version", Value: "9"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("GetVersion", mock.Anything, "1", "9").Return(nil, services.ErrVersionNotFound)
+		v1.NewRulesheets(srv).GetRulesheetVersion()(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	// It tests that an error on the service is returned as 500 Internal Server Error.
+	t.Run("Error on fetch version flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}, gin.Param{Key: "version", Value: "3"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("GetVersion", mock.Anything, "1", "3").Return(nil, errors.New("error"))
+		v1.NewRulesheets(srv).GetRulesheetVersion()(c)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+// TestRulesheet_RollbackRulesheet tests the RollbackRulesheet function in the Rulesheets API endpoint.
+func TestRulesheet_RollbackRulesheet(t *testing.T) {
+	current := &dtos.Rulesheet{ID: 1, Name: "test", Slug: "test", Version: "4"}
+	rules := map[string]interface{}{
+		"feat": map[string]interface{}{"condition": "$a > 1", "value": true},
+	}
+	parameters := []dtos.Parameter{{Name: "a", Type: "integer"}}
+	target := &dtos.Rulesheet{ID: 1, Name: "test", Slug: "test", Version: "2", Parameters: &parameters, Rules: &rules}
+
+	// It tests the normal flow, where the content of the version is saved as an update based on the version
+	// of the If-Match header.
+	t.Run("Normal flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.Header.Set("If-Match", `"4"`)
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{"version":"2"}`)))
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Get", mock.Anything, "1").Return(current, nil)
+		srv.On("GetVersion", mock.Anything, "1", "2").Return(target, nil)
+		srv.On("Update", mock.Anything, mock.MatchedBy(func(dto dtos.Rulesheet) bool {
+			rule, ok := (*dto.Rules)["feat"].(*dtos.Rule)
+			return ok && rule.Condition == "$a > 1" && dto.BaseVersion == "4" &&
+				dto.CommitMessage == "[FEATWS BOT] Rollback to version 2"
+		})).Return(&dtos.Rulesheet{ID: 1, Version: "5"}, nil)
+		v1.NewRulesheets(srv).RollbackRulesheet()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"5"`, w.Header().Get("ETag"))
+	})
+
+	// It tests that a body without the version is rejected with 400 Bad Request.
+	t.Run("Error on validate required fields flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{}`)))
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Get", mock.Anything, "1").Return(current, nil)
+		v1.NewRulesheets(srv).RollbackRulesheet()(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	// It tests that an unknown version is returned as 404 Not Found.
+	t.Run("Version not found flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{"version":"9"}`)))
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Get", mock.Anything, "1").Return(current, nil)
+		srv.On("GetVersion", mock.Anything, "1", "9").Return(nil, services.ErrVersionNotFound)
+		v1.NewRulesheets(srv).RollbackRulesheet()(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	// It tests that a rollback with an `If-Match` header of an outdated version is rejected without saving.
+	t.Run("Error on outdated If-Match flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.Header.Set("If-Match", `"3"`)
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{"version":"2"}`)))
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Get", mock.Anything, "1").Return(current, nil)
+		v1.NewRulesheets(srv).RollbackRulesheet()(c)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		srv.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	// It tests that a version whose rules have an invalid expression is rejected like an update.
+	t.Run("Error on validate the rules flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{"version":"2"}`)))
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		invalid := map[string]interface{}{
+			"feat": map[string]interface{}{"condition": "$a >", "value": true},
+		}
+		srv := new(mock_services.Rulesheets)
+		srv.On("Get", mock.Anything, "1").Return(current, nil)
+		srv.On("GetVersion", mock.Anything, "1", "2").Return(&dtos.Rulesheet{ID: 1, Name: "test", Slug: "test", Version: "2", Parameters: &parameters, Rules: &invalid}, nil)
+		v1.NewRulesheets(srv).RollbackRulesheet()(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		srv.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	// It tests that a version with the string rules of rules.featws isn't restored over the structured rules.
+	t.Run("Error on legacy version flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{"version":"1"}`)))
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		legacy := map[string]interface{}{"feat": "$a > 1"}
+		srv := new(mock_services.Rulesheets)
+		srv.On("Get", mock.Anything, "1").Return(current, nil)
+		srv.On("GetVersion", mock.Anything, "1", "1").Return(&dtos.Rulesheet{ID: 1, Name: "test", Slug: "test", Version: "1", Rules: &legacy, HasStringRule: true}, nil)
+		v1.NewRulesheets(srv).RollbackRulesheet()(c)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		srv.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+// TestRulesheet_GetRulesheetDiff tests the GetRulesheetDiff function in the Rulesheets API endpoint.
+func TestRulesheet_GetRulesheetDiff(t *testing.T) {
+	// It tests the normal flow, where the service compares both versions.
+	t.Run("Normal flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{RawQuery: "from=1&to=2"},
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Diff", mock.Anything, "1", "1", "2").Return(&dtos.Diff{
+			From:  "1",
+			To:    "2",
+			Rules: []*dtos.Change{{Kind: dtos.ChangeRemoved, Name: "discount", Old: false}},
+		}, nil)
+		v1.NewRulesheets(srv).GetRulesheetDiff()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"from":"1","to":"2","features":[],"parameters":[],"rules":[{"kind":"removed","name":"discount","old":false}]}`, w.Body.String())
+	})
+
+	// It tests that a request without the versions is rejected with 400 Bad Request.
+	t.Run("Missing query param flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{RawQuery: "from=1"},
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		v1.NewRulesheets(srv).GetRulesheetDiff()(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	// It tests that an unknown version is returned as 404 Not Found.
+	t.Run("Version not found flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{RawQuery: "from=1&to=9"},
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Diff", mock.Anything, "1", "1", "9").Return(nil, services.ErrVersionNotFound)
+		v1.NewRulesheets(srv).GetRulesheetDiff()(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+// TestRulesheet_EvaluateRulesheet tests the EvaluateRulesheet function in the Rulesheets API endpoint.
+func TestRulesheet_EvaluateRulesheet(t *testing.T) {
+	// It tests the normal flow, where the service computes the features for the given parameters.
+	t.Run("Normal flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{"version":"2","parameters":{"age":30}}`)))
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Evaluate", mock.Anything, "1", "2", map[string]interface{}{"age": 30.0}).Return(&dtos.Evaluation{
+			Version:  "2",
+			Features: map[string]interface{}{"adult": true},
+			Rules:    []*dtos.RuleResult{{Path: "rules.adult", Condition: "$age >= 18", Matched: true}},
+		}, nil)
+		v1.NewRulesheets(srv).EvaluateRulesheet()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"version":"2","features":{"adult":true},"rules":[{"path":"rules.adult","condition":"$age >= 18","matched":true}]}`, w.Body.String())
+	})
+
+	// It tests that a rulesheet that doesn't exist is returned as 404 Not Found.
+	t.Run("Rulesheet not found flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{}`)))
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Evaluate", mock.Anything, "1", "", map[string]interface{}(nil)).Return(nil, nil)
+		v1.NewRulesheets(srv).EvaluateRulesheet()(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	// It tests that a rulesheet with only string rules is rejected with 422 Unprocessable Entity.
+	t.Run("String rules flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{}`)))
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Evaluate", mock.Anything, "1", "", map[string]interface{}(nil)).Return(nil, services.ErrStringRulesNotSupported)
+		v1.NewRulesheets(srv).EvaluateRulesheet()(c)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+}
+
+// TestRulesheet_RunRulesheetTests tests the RunRulesheetTests function in the Rulesheets API endpoint.
+func TestRulesheet_RunRulesheetTests(t *testing.T) {
+	// It tests the normal flow, where the service runs the test cases of the requested version.
+	t.Run("Normal flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{RawQuery: "version=2"},
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("RunTests", mock.Anything, "1", "2").Return(&dtos.TestRun{
+			Version: "2",
+			Failed:  1,
+			Results: []*dtos.TestResult{{
+				Name:     "small order",
+				Failures: []*dtos.TestFailure{{Feature: "discount", Expected: 0.05, Actual: 0.0}},
+			}},
+		}, nil)
+		v1.NewRulesheets(srv).RunRulesheetTests()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"version":"2","passed":0,"failed":1,"results":[{"name":"small order","passed":false,"failures":[{"feature":"discount","expected":0.05,"actual":0}]}]}`, w.Body.String())
+	})
+
+	// It tests that a rulesheet with only string rules is rejected with 422 Unprocessable Entity.
+	t.Run("String rules flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{},
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("RunTests", mock.Anything, "1", "").Return(nil, services.ErrStringRulesNotSupported)
+		v1.NewRulesheets(srv).RunRulesheetTests()(c)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+}
+
+// The function builds a multipart request that uploads the given files, with the given form fields.
+func newImportRequest(query string, fields map[string]string, files map[string]string) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for name, value := range fields {
+		writer.WriteField(name, value)
+	}
+	for name, content := range files {
+		part, _ := writer.CreateFormFile("files", name)
+		part.Write([]byte(content))
+	}
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/rulesheets/import?"+query, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+// TestRulesheet_ImportRulesheet tests the import of a rulesheet from uploaded files.
+func TestRulesheet_ImportRulesheet(t *testing.T) {
+	// It tests the dry run of an import of a legacy rules.featws file, which is validated but not created.
+	t.Run("Dry run flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = newImportRequest("dryRun=true", map[string]string{"name": "Test"}, map[string]string{
+			"rules.featws": "a = 10\nb = #a * 2\n",
+		})
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("GetBySlug", mock.Anything, "test").Return(nil, nil)
+		v1.NewRulesheets(srv).ImportRulesheet()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"rulesheet":{"name":"Test","slug":"test","rules":{"a":{"value":10},"b":{"dynamic":"#a * 2"}}},"created":true,"dryRun":true}`, w.Body.String())
+		srv.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	// It tests the import of a bundle over the rulesheet with the same slug, which is updated keeping its name.
+	t.Run("Update flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = newImportRequest("", map[string]string{"slug": "test"}, map[string]string{
+			"features.json":   `[{"name":"discount","type":"decimal"}]`,
+			"parameters.json": `[{"name":"total","type":"decimal"}]`,
+			"rules.json":      `{"discount":{"dynamic":"$total * 0.1"}}`,
+		})
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("GetBySlug", mock.Anything, "test").Return(&dtos.Rulesheet{ID: 1, Name: "Test", Slug: "test"}, nil)
+		srv.On("Update", mock.Anything, mock.MatchedBy(func(dto dtos.Rulesheet) bool {
+			return dto.ID == 1 && dto.Name == "Test" && len(*dto.Features) == 1
+		})).Return(&dtos.Rulesheet{ID: 1, Name: "Test", Slug: "test", Version: "2"}, nil)
+		v1.NewRulesheets(srv).ImportRulesheet()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"rulesheet":{"id":1,"name":"Test","slug":"test","version":"2"},"created":false,"dryRun":false}`, w.Body.String())
+	})
+
+	// It tests that an unknown file is rejected with 400 Bad Request.
+	t.Run("Unknown file flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = newImportRequest("", map[string]string{"name": "Test"}, map[string]string{
+			"rules.yaml": "a: 10",
+		})
+
+		srv := new(mock_services.Rulesheets)
+		v1.NewRulesheets(srv).ImportRulesheet()(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+// TestRulesheet_GetRulesheetGraph tests the dependency graph of a rulesheet on each format.
+func TestRulesheet_GetRulesheetGraph(t *testing.T) {
+	graph := &dtos.Graph{
+		Nodes: []*dtos.GraphNode{
+			{ID: "rule:discount", Kind: dtos.GraphNodeRule, Name: "discount"},
+			{ID: "parameter:age", Kind: dtos.GraphNodeParameter, Name: "age"},
+		},
+		Edges: []*dtos.GraphEdge{
+			{From: "rule:discount", To: "parameter:age", Paths: []string{"rules.discount.condition"}},
+		},
+		Cycles: [][]string{},
+	}
+
+	// It tests the normal flow, where the format defaults to JSON.
+	t.Run("Normal flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{RawQuery: "version=2"},
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Graph", mock.Anything, "1", "2").Return(graph, nil)
+		v1.NewRulesheets(srv).GetRulesheetGraph()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response responses.Graph
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, responses.NewGraph(graph), response)
+	})
+
+	// It tests that the graph is written as a Mermaid flowchart.
+	t.Run("Mermaid flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{RawQuery: "format=mermaid"},
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Graph", mock.Anything, "1", "").Return(graph, nil)
+		v1.NewRulesheets(srv).GetRulesheetGraph()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "flowchart LR\n    n0[\"discount\"]\n    n1[/\"age\"/]\n    n0 --> n1\n", w.Body.String())
+	})
+
+	// It tests that the graph is written on the Graphviz DOT language.
+	t.Run("DOT flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{RawQuery: "format=dot"},
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Graph", mock.Anything, "1", "").Return(graph, nil)
+		v1.NewRulesheets(srv).GetRulesheetGraph()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "\t\"rule:discount\" -> \"parameter:age\";\n")
+	})
+
+	// It tests that an unknown format is rejected with 400 Bad Request.
+	t.Run("Unknown format flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{RawQuery: "format=svg"},
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		v1.NewRulesheets(srv).GetRulesheetGraph()(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		srv.AssertNotCalled(t, "Graph", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	// It tests that an unknown rulesheet returns 404 Not Found.
+	t.Run("Not found flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{},
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Graph", mock.Anything, "1", "").Return(nil, nil)
+		v1.NewRulesheets(srv).GetRulesheetGraph()(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+// TestRulesheet_ExportRulesheet tests the export of the rules of a rulesheet to the rules.featws format.
+func TestRulesheet_ExportRulesheet(t *testing.T) {
+	// It tests the normal flow, where the format defaults to featws and the file is returned as an attachment.
+	t.Run("Normal flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{RawQuery: "version=2"},
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Export", mock.Anything, "1", "2", services.FormatFeatws).Return([]byte("a = $x > 1\n"), nil)
+		v1.NewRulesheets(srv).ExportRulesheet()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "a = $x > 1\n", w.Body.String())
+		assert.Equal(t, "attachment; filename=\"rules.featws\"", w.Header().Get("Content-Disposition"))
+	})
+
+	// It tests that the rules that can't be written are rejected with 422 Unprocessable Entity.
+	t.Run("Structured rules flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{RawQuery: "format=featws"},
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Export", mock.Anything, "1", "", services.FormatFeatws).Return(nil, services.ErrNotExportable)
+		v1.NewRulesheets(srv).ExportRulesheet()(c)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	// It tests that an unknown format is rejected with 400 Bad Request.
+	t.Run("Unknown format flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{RawQuery: "format=yaml"},
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Export", mock.Anything, "1", "", "yaml").Return(nil, services.ErrFormatNotSupported)
+		v1.NewRulesheets(srv).ExportRulesheet()(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+// TestRulesheet_ConvertRulesheet tests the conversion of the string rules of a legacy rulesheet.
+func TestRulesheet_ConvertRulesheet(t *testing.T) {
+	// It tests the normal flow, where the service commits the converted rules.
+	t.Run("Normal flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{RawQuery: "commit=true"},
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		rules := map[string]interface{}{"discount": &dtos.Rule{Dynamic: "$total * 0.1"}}
+		srv := new(mock_services.Rulesheets)
+		srv.On("ConvertLegacy", mock.Anything, "1", true).Return(&dtos.Conversion{
+			Rulesheet: &dtos.Rulesheet{ID: 1, Name: "test", Rules: &rules},
+			Errors:    []*dtos.RuleError{},
+			Committed: true,
+		}, nil)
+		v1.NewRulesheets(srv).ConvertRulesheet()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"rulesheet":{"id":1,"name":"test","rules":{"discount":{"dynamic":"$total * 0.1"}}},"committed":true}`, w.Body.String())
+	})
+
+	// It tests that a rulesheet without string rules is rejected with 409 Conflict.
+	t.Run("Structured rules flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{},
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("ConvertLegacy", mock.Anything, "1", false).Return(nil, services.ErrNotLegacy)
+		v1.NewRulesheets(srv).ConvertRulesheet()(c)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	// It tests that an invalid commit flag is rejected with 400 Bad Request.
+	t.Run("Invalid commit flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{RawQuery: "commit=maybe"},
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		v1.NewRulesheets(srv).ConvertRulesheet()(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		srv.AssertNotCalled(t, "ConvertLegacy", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// TestRulesheet_BlockFailingTests tests that the updates whose test cases fail are rejected when they're
+// configured to be blocked, also checking the test cases already committed when the payload omits them.
+func TestRulesheet_BlockFailingTests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	config.GetConfig().TestsBlockUpdates = true
+	defer func() {
+		config.GetConfig().TestsBlockUpdates = false
+	}()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = &http.Request{
+		Header: make(http.Header),
+	}
+	c.Request.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{"name":"Test","parameters":[{"name":"age","type":"integer"}],"rules":{"adult":{"condition":"$age >= 18","value":true}}}`)))
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+	srv := new(mock_services.Rulesheets)
+	srv.On("Get", mock.Anything, "1").Return(&dtos.Rulesheet{
+		ID:    uint(1),
+		Name:  "Test",
+		Tests: &[]dtos.TestCase{{Name: "child", Parameters: map[string]interface{}{"age": 10.0}, Expected: map[string]interface{}{"adult": true}}},
+	}, nil)
+	v1.NewRulesheets(srv).UpdateRulesheet()(c)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"tests[0].expected.adult"`)
+	srv.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestRulesheet_GetRulesheetChanges tests the GetRulesheetChanges function in the Rulesheets API endpoint.
+func TestRulesheet_GetRulesheetChanges(t *testing.T) {
+	// It tests the normal flow, where the service lists the pending changes.
+	t.Run("Normal flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("ListChanges", mock.Anything, "1").Return([]*dtos.ChangeRequest{{ID: 5, Title: "Update", Branch: "featws/change-2-1", State: "opened", ApprovedBy: []string{}}}, nil)
+		v1.NewRulesheets(srv).GetRulesheetChanges()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[{"id":5,"title":"Update","branch":"featws/change-2-1","state":"opened","approved":false,"approvedBy":[]}]`, w.Body.String())
+	})
+
+	// It tests the error flow, where the service fails to list the changes.
+	t.Run("Error on list flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("ListChanges", mock.Anything, "1").Return(nil, errors.New("error"))
+		v1.NewRulesheets(srv).GetRulesheetChanges()(c)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	// It tests that a storage backend without review mode is returned as 501 Not Implemented.
+	t.Run("Not supported flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("ListChanges", mock.Anything, "1").Return(nil, services.ErrNotSupported)
+		v1.NewRulesheets(srv).GetRulesheetChanges()(c)
+		assert.Equal(t, http.StatusNotImplemented, w.Code)
+	})
+}
+
+// TestRulesheet_PublishRulesheetChange tests the PublishRulesheetChange function in the Rulesheets API endpoint.
+func TestRulesheet_PublishRulesheetChange(t *testing.T) {
+	// It tests the normal flow, where the service publishes the change.
+	t.Run("Normal flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}, gin.Param{Key: "change", Value: "5"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("PublishChange", mock.Anything, "1", 5).Return(&dtos.Rulesheet{ID: 1, Version: "2"}, nil)
+		v1.NewRulesheets(srv).PublishRulesheetChange()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	// It tests that an invalid change param is rejected with 400 Bad Request.
+	t.Run("Invalid change param flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}, gin.Param{Key: "change", Value: "abc"}}
+
+		srv := new(mock_services.Rulesheets)
+		v1.NewRulesheets(srv).PublishRulesheetChange()(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	// It tests that a change that isn't pending is returned as 404 Not Found.
+	t.Run("Change not found flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}, gin.Param{Key: "change", Value: "5"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("PublishChange", mock.Anything, "1", 5).Return(nil, services.ErrChangeRequestNotFound)
+		v1.NewRulesheets(srv).PublishRulesheetChange()(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	// It tests that a change that isn't approved is returned as 409 Conflict.
+	t.Run("Change not approved flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}, gin.Param{Key: "change", Value: "5"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("PublishChange", mock.Anything, "1", 5).Return(nil, services.ErrChangeRequestNotApproved)
+		v1.NewRulesheets(srv).PublishRulesheetChange()(c)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+// TestRulesheet_DiscardRulesheetChange tests the DiscardRulesheetChange function in the Rulesheets API endpoint.
+func TestRulesheet_DiscardRulesheetChange(t *testing.T) {
+	// It tests the normal flow, where the service discards the change.
+	t.Run("Normal flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}, gin.Param{Key: "change", Value: "5"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("DiscardChange", mock.Anything, "1", 5).Return(nil)
+		v1.NewRulesheets(srv).DiscardRulesheetChange()(c)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+}
+
+// newItemContext creates the context of a request to an item of the rulesheet 1, like a rule, with the given
+// body, if any.
+func newItemContext(w *httptest.ResponseRecorder, method string, name string, body string) *gin.Context {
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, "/api/v1/rulesheets/1/items/"+name, bytes.NewReader([]byte(body)))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}, gin.Param{Key: "name", Value: name}}
+	return c
+}
+
+// withCommitMessage matches the rulesheets updated with the commit message.
+func withCommitMessage(message string) interface{} {
+	return mock.MatchedBy(func(dto dtos.Rulesheet) bool {
+		return dto.CommitMessage == message
+	})
+}
+
+// TestRulesheet_RulesheetItems tests the endpoints that read and change a single rule, feature or parameter
+// of a rulesheet.
+func TestRulesheet_RulesheetItems(t *testing.T) {
+	updated := &dtos.Rulesheet{ID: 1, Name: "Test", Slug: "test", Version: "4"}
+
+	// It tests that a rule, a feature and a parameter are returned by their names.
+	t.Run("Get flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Get", mock.Anything, "1").Return(newPatchedRulesheet(), nil)
+		controller := v1.NewRulesheets(srv)
+
+		w := httptest.NewRecorder()
+		controller.GetRulesheetRule()(newItemContext(w, http.MethodGet, "discount", ""))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[{"condition":"$age > 60","value":0.2},{"value":0}]`, w.Body.String())
+		assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+
+		w = httptest.NewRecorder()
+		controller.GetRulesheetParameter()(newItemContext(w, http.MethodGet, "age", ""))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"name":"age","type":"integer"}`, w.Body.String())
+
+		for _, handler := range []gin.HandlerFunc{controller.GetRulesheetRule(), controller.GetRulesheetFeature(), controller.GetRulesheetParameter()} {
+			w = httptest.NewRecorder()
+			handler(newItemContext(w, http.MethodGet, "unknown", ""))
+			assert.Equal(t, http.StatusNotFound, w.Code)
+		}
+	})
+
+	// It tests that a new rule is created and an existing one is updated, each one with the message of the change.
+	t.Run("Put rule flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Get", mock.Anything, "1").Return(newPatchedRulesheet(), nil)
+		srv.On("Update", mock.Anything, withCommitMessage("[FEATWS BOT] Update rule discount")).Return(updated, nil)
+		srv.On("Update", mock.Anything, withCommitMessage("[FEATWS BOT] Create rule senior")).Return(updated, nil)
+		controller := v1.NewRulesheets(srv)
+
+		w := httptest.NewRecorder()
+		controller.UpdateRulesheetRule()(newItemContext(w, http.MethodPut, "discount", `{"condition":"$age >= 65","value":0.3}`))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		controller.UpdateRulesheetRule()(newItemContext(w, http.MethodPut, "senior", `{"condition":"$age >= 65","value":true}`))
+		assert.Equal(t, http.StatusOK, w.Code)
+		srv.AssertExpectations(t)
+	})
+
+	// It tests that the changed rule is validated with the rest of the rulesheet.
+	t.Run("Put invalid rule flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Get", mock.Anything, "1").Return(newPatchedRulesheet(), nil)
+		controller := v1.NewRulesheets(srv)
+
+		for _, body := range []string{`{"condition":"$unknown > 1","value":1}`, `null`, `{"value":`} {
+			w := httptest.NewRecorder()
+			controller.UpdateRulesheetRule()(newItemContext(w, http.MethodPut, "discount", body))
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		}
+		srv.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	// It tests that a feature is declared with the name of the path, and that another name or type is rejected.
+	t.Run("Put feature flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Get", mock.Anything, "1").Return(newPatchedRulesheet(), nil)
+		srv.On("Update", mock.Anything, mock.MatchedBy(func(dto dtos.Rulesheet) bool {
+			return dto.CommitMessage == "[FEATWS BOT] Create feature vip" && len(*dto.Features) == 1 &&
+				(*dto.Features)[0].Name == "vip" && (*dto.Features)[0].Type == "boolean"
+		})).Return(updated, nil)
+		controller := v1.NewRulesheets(srv)
+
+		w := httptest.NewRecorder()
+		controller.UpdateRulesheetFeature()(newItemContext(w, http.MethodPut, "vip", `{"type":"boolean"}`))
+		assert.Equal(t, http.StatusOK, w.Code)
+		srv.AssertExpectations(t)
+
+		for _, body := range []string{`{"name":"other"}`, `{"type":"unknown"}`} {
+			w = httptest.NewRecorder()
+			controller.UpdateRulesheetFeature()(newItemContext(w, http.MethodPut, "vip", body))
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		}
+	})
+
+	// It tests that a rule is deleted with the message of the change, and that an unknown item returns a 404.
+	t.Run("Delete flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Get", mock.Anything, "1").Return(newPatchedRulesheet(), nil)
+		srv.On("Update", mock.Anything, mock.MatchedBy(func(dto dtos.Rulesheet) bool {
+			return dto.CommitMessage == "[FEATWS BOT] Delete rule discount" && (dto.Rules == nil || len(*dto.Rules) == 0)
+		})).Return(updated, nil)
+		controller := v1.NewRulesheets(srv)
+
+		w := httptest.NewRecorder()
+		controller.DeleteRulesheetRule()(newItemContext(w, http.MethodDelete, "discount", ""))
+		assert.Equal(t, http.StatusOK, w.Code)
+		srv.AssertExpectations(t)
+
+		w = httptest.NewRecorder()
+		controller.DeleteRulesheetFeature()(newItemContext(w, http.MethodDelete, "unknown", ""))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	// It tests that a parameter used by a rule can't be deleted.
+	t.Run("Delete used parameter flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Get", mock.Anything, "1").Return(newPatchedRulesheet(), nil)
+		controller := v1.NewRulesheets(srv)
+
+		w := httptest.NewRecorder()
+		controller.DeleteRulesheetParameter()(newItemContext(w, http.MethodDelete, "age", ""))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		srv.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+// TestRulesheet_ValidateRuleExpressions tests that the rules with broken expressions are rejected before
+// the rulesheet is saved, with the path of the expression and the position of the error.
+func TestRulesheet_ValidateRuleExpressions(t *testing.T) {
+	body := `{"name":"Test","parameters":[{"name":"total"}],"features":[{"name":"gold"}],"rules":{"discount":[{"value":1},{"value":2},{"condition":"$total >","value":3}],"vip":{"condition":"#gold","value":true}}}`
+
+	// It tests that a broken condition is rejected on the creation.
+	t.Run("Invalid expression on create flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader([]byte(body)))
+
+		srv := new(mock_services.Rulesheets)
+		v1.NewRulesheets(srv).CreateRulesheet()(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response struct {
+			ValidationErrors []map[string]interface{} `json:"validation_errors"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Len(t, response.ValidationErrors, 1)
+		assert.Equal(t, "rules.discount[2].condition", response.ValidationErrors[0]["field"])
+		assert.Equal(t, float64(9), response.ValidationErrors[0]["position"])
+		srv.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	// It tests that a broken condition is rejected on the update.
+	t.Run("Invalid expression on update flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader([]byte(body)))
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Get", mock.Anything, "1").Return(&dtos.Rulesheet{ID: 1, Name: "Test"}, nil)
+		v1.NewRulesheets(srv).UpdateRulesheet()(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		srv.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+// TestRulesheet_ValidateRuleReferences tests that the parameters and features referenced by the rules must
+// be declared, and that the parameters not used by any rule are returned as warnings.
+func TestRulesheet_ValidateRuleReferences(t *testing.T) {
+	// It tests that a reference to an undeclared parameter or feature is rejected.
+	t.Run("Undeclared reference flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{"name":"Test","parameters":[{"name":"total"}],"rules":{"discount":{"condition":"$totl > 10 && #vip","value":true},"vip":{"condition":"#gold","value":true}}}`)))
+
+		srv := new(mock_services.Rulesheets)
+		v1.NewRulesheets(srv).CreateRulesheet()(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response struct {
+			ValidationErrors []map[string]interface{} `json:"validation_errors"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Len(t, response.ValidationErrors, 2)
+		assert.Equal(t, "rules.discount.condition", response.ValidationErrors[0]["field"])
+		assert.Equal(t, "undeclared", response.ValidationErrors[0]["tag"])
+		assert.Equal(t, float64(1), response.ValidationErrors[0]["position"])
+		assert.Equal(t, "rules.vip.condition", response.ValidationErrors[1]["field"])
+	})
+
+	// It tests that the string values that only look like references are kept as text, with a warning,
+	// while a value that is a single reference to a declared parameter is resolved.
+	t.Run("Text reference flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{"name":"Test","parameters":[{"name":"total"}],"rules":{"color":{"value":"#FF0000"},"link":{"value":"https://x/#section"},"price":{"value":"US$ price"},"total":{"value":"$total"}}}`)))
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Create", mock.Anything, mock.Anything).Return(nil)
+		v1.NewRulesheets(srv).CreateRulesheet()(c)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response struct {
+			Warnings []map[string]interface{} `json:"warnings"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		if assert.Len(t, response.Warnings, 2) {
+			assert.Equal(t, "rules.color.value", response.Warnings[0]["field"])
+			assert.Equal(t, "reference", response.Warnings[0]["tag"])
+			assert.Equal(t, float64(1), response.Warnings[0]["position"])
+			assert.Equal(t, "rules.link.value", response.Warnings[1]["field"])
+			assert.Equal(t, "reference", response.Warnings[1]["tag"])
+			assert.Equal(t, float64(11), response.Warnings[1]["position"])
+		}
+	})
+
+	// It tests that an unused parameter doesn't prevent the creation, but is returned as a warning.
+	t.Run("Unused parameter flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{"name":"Test","parameters":[{"name":"total"},{"name":"age"}],"rules":{"discount":{"condition":"$total > 10","value":true}}}`)))
+
+		srv := new(mock_services.Rulesheets)
+		srv.On("Create", mock.Anything, mock.Anything).Return(nil)
+		v1.NewRulesheets(srv).CreateRulesheet()(c)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response struct {
+			Warnings []map[string]interface{} `json:"warnings"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Len(t, response.Warnings, 1)
+		assert.Equal(t, "parameters[1]", response.Warnings[0]["field"])
+		assert.Equal(t, "unused", response.Warnings[0]["tag"])
+	})
+}
+
+// TestRulesheet_ValidateRuleTypes tests that the values and expressions of the rules must match the types
+// declared on the rules and features.
+func TestRulesheet_ValidateRuleTypes(t *testing.T) {
+	// It tests that a mismatched value, dynamic value and condition are reported per rule.
+	t.Run("Type mismatch flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{"name":"Test",` +
+			`"parameters":[{"name":"age","type":"integer"},{"name":"name","type":"string"}],` +
+			`"features":[{"name":"vip","type":"boolean"}],` +
+			`"rules":{"vip":{"value":"yes"},"limit":{"dynamic":"$name * 2","value":0,"type":"decimal"},"adult":{"condition":"$age","value":true,"type":"boolean"},` +
+			`"since":{"value":"2020-01-31","type":"date"}}}`)))
+
+		srv := new(mock_services.Rulesheets)
+		v1.NewRulesheets(srv).CreateRulesheet()(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response struct {
+			ValidationErrors []map[string]interface{} `json:"validation_errors"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		fields := make([]interface{}, 0)
+		for _, validationErr := range response.ValidationErrors {
+			assert.Equal(t, "type", validationErr["tag"])
+			fields = append(fields, validationErr["field"])
+		}
+		assert.Equal(t, []interface{}{"rules.adult.condition", "rules.limit.dynamic", "rules.vip.value"}, fields)
+	})
+}
+
+// TestRulesheet_ValidateDeclarations tests that the features and parameters declared by a rulesheet must have
+// a unique name and a known type.
+func TestRulesheet_ValidateDeclarations(t *testing.T) {
+	cases := []struct {
+		name  string
+		body  string
+		field string
+		tag   string
+	}{
+		{"Missing name flow", `{"name":"Test","features":[{"type":"boolean"}]}`, "Name", "required"},
+		{"Unknown type flow", `{"name":"Test","parameters":[{"name":"age","type":"age"}]}`, "Type", "featws_type"},
+		{"Duplicated name flow", `{"name":"Test","features":[{"name":"gold"},{"name":"gold"}]}`, "Features", "unique"},
+	}
+
+	for _, tc := range cases {
+		// It tests that the invalid declaration is rejected before the creation.
+		t.Run(tc.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = &http.Request{
+				Header: make(http.Header),
+			}
+			c.Request.Body = ioutil.NopCloser(bytes.NewReader([]byte(tc.body)))
+
+			srv := new(mock_services.Rulesheets)
+			v1.NewRulesheets(srv).CreateRulesheet()(c)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response struct {
+				ValidationErrors []map[string]interface{} `json:"validation_errors"`
+			}
+			json.Unmarshal(w.Body.Bytes(), &response)
+			if assert.Len(t, response.ValidationErrors, 1) {
+				assert.Equal(t, tc.field, response.ValidationErrors[0]["field"])
+				assert.Equal(t, tc.tag, response.ValidationErrors[0]["tag"])
+			}
+			srv.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+
+	// It tests that a feature without a string name is rejected instead of breaking the storage backend.
+	t.Run("Invalid name flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{"name":"Test","features":[{"name":1}]}`)))
+
+		srv := new(mock_services.Rulesheets)
+		v1.NewRulesheets(srv).CreateRulesheet()(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		srv.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+// TestRulesheet_ValidateRuleCycles tests that the rules that depend on themselves are rejected.
+func TestRulesheet_ValidateRuleCycles(t *testing.T) {
+	// It tests that a circular dependency between derived features is rejected on the creation.
+	t.Run("Cycle flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{"name":"Test","rules":{"a":{"condition":"#c","value":true},"b":{"dynamic":"#a"},"c":[{"condition":"#b","value":true}],"d":{"value":"#d"}}}`)))
+
+		srv := new(mock_services.Rulesheets)
+		v1.NewRulesheets(srv).CreateRulesheet()(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response struct {
+			ValidationErrors []map[string]interface{} `json:"validation_errors"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		if assert.Len(t, response.ValidationErrors, 2) {
+			assert.Equal(t, "rules.a", response.ValidationErrors[0]["field"])
+			assert.Equal(t, "cycle", response.ValidationErrors[0]["tag"])
+			assert.Equal(t, "the rule 'a' depends on itself: a -> c -> b -> a", response.ValidationErrors[0]["error"])
+			assert.Equal(t, "rules.d", response.ValidationErrors[1]["field"])
+		}
+		srv.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,240 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/bancodobrasil/featws-api/models"
+	responses "github.com/bancodobrasil/featws-api/responses/v1"
+	"github.com/bancodobrasil/featws-api/services"
+	"github.com/gin-gonic/gin"
+)
+
+// Syncs defines the methods for handling the outbox of the changes of the rulesheets, so the operators can
+// follow the syncs with the storage backend and retry or discard the failed ones.
+//
+// Property:
+//   - GetSyncs: is a function that handles the HTTP GET request to list the syncs of the outbox, filtered by status, with pagination.
+//   - RetrySync: is a function that handles the HTTP POST request to retry a failed sync.
+//   - DiscardSync: is a function that handles the HTTP POST request to discard a failed sync, so the next changes of its rulesheet are pushed.
+type Syncs interface {
+	GetSyncs() gin.HandlerFunc
+	RetrySync() gin.HandlerFunc
+	DiscardSync() gin.HandlerFunc
+}
+
+// The type "syncs" contains the service of the outbox of the changes of the rulesheets.
+type syncs struct {
+	service services.Syncs
+}
+
+// NewSyncs creates a new instance of the Syncs controller with a given service.
+func NewSyncs(service services.Syncs) Syncs {
+	return &syncs{
+		service: service,
+	}
+}
+
+// GetSyncs godoc
+// @Summary 			Listar as Sincronizações das Folhas de Regra
+// @Description 		As alterações das folhas de regra são gravadas no banco de dados junto com uma sincronização pendente, que é enviada ao backend de armazenamento (*FEATWS_API_STORAGE*) em seguida. As sincronizações que falham são tentadas novamente, com um intervalo crescente (*FEATWS_API_SYNC_INTERVAL*), até o limite de tentativas (*FEATWS_API_SYNC_MAX_ATTEMPTS*), quando ficam com o status *failed*, e as que falham por conflito de versão ficam com esse status na primeira tentativa. Uma sincronização com o status *failed* aguarda ser tentada novamente ou descartada (*discarded*). Essa operação lista as sincronizações, com o status, o número de tentativas e o erro de cada uma. Utilize o parâmetro *status* para filtrar por *pending*, *synced*, *failed* ou *discarded*, e os parâmetros *limit* e *page* para paginar o resultado.
+// @Tags 				Sync
+// @Accept  			json
+// @Produce  			json
+// @Param				status query string false "Sync status" Enums(pending, synced, failed, discarded)
+// @Param				limit query integer false "Max length of the array returned"
+// @Param				page query integer false "Page number that is multiplied by 'limit' to calculate the offset"
+// @Success 			200 {array} responses.Sync
+// @Header 				200 {string} Authorization "token access"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/syncs [get]
+// GetSyncs returns a `gin.HandlerFunc` that lists the syncs of the outbox. The `status` query parameter
+// filters them, and a 400 status code is returned if it isn't a known status or the pagination is invalid.
+func (sc *syncs) GetSyncs() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+
+		query := c.Request.URL.Query()
+
+		status := query.Get("status")
+		switch status {
+		case "", models.SyncPending, models.SyncSynced, models.SyncFailed, models.SyncDiscarded:
+		default:
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: fmt.Sprintf("the query param 'status' must be '%s', '%s', '%s' or '%s'", models.SyncPending, models.SyncSynced, models.SyncFailed, models.SyncDiscarded),
+			})
+			log.Errorf("Error on check the sync status: %s", status)
+			return
+		}
+
+		opts, err := parseFindOptions(query)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on parse the pagination: %v", err)
+			return
+		}
+
+		dtos, err := sc.service.Find(ctx, status, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on fetch the syncs: %v", err)
+			return
+		}
+
+		var response = make([]responses.Sync, len(dtos))
+
+		for index, dto := range dtos {
+			response[index] = responses.NewSync(dto)
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// RetrySync godoc
+// @Summary 			Tentar Novamente uma Sincronização
+// @Description 		Marca uma sincronização que falhou como pendente, zerando as tentativas, e a envia ao backend de armazenamento imediatamente. As alterações seguintes da mesma folha de regra, que aguardavam essa sincronização, são enviadas na sequência. A sincronização mantém a versão em que a alteração se baseou, então uma sincronização que falhou por conflito de versão falha novamente; com *force=true*, ela é enviada sem essa versão, sobrescrevendo a versão atual com a alteração. Para não salvá-la, descarte a sincronização. Retorna a sincronização com o resultado da nova tentativa.
+// @Tags 				Sync
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Sync ID"
+// @Param				force query bool false "Save the change over the current version, even if it failed on a version conflict"
+// @Success 			200 {object} responses.Sync
+// @Header 				200 {string} Authorization "token access"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			404 {object} responses.Error "Not Found"
+// @Failure 			409 {object} responses.Error "Conflict"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/syncs/{id}/retry [post]
+// RetrySync returns a `gin.HandlerFunc` that retries the sync with the ID passed in the request, over the
+// current version when the "force" query parameter is set. If the sync doesn't exist, a 404 status code is
+// returned, and if it didn't fail, a 409 status code is returned.
+func (sc *syncs) RetrySync() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		id, exists := c.Params.Get("id")
+
+		if !exists {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: "Required param 'id'",
+			})
+			log.Error("Error on check if the sync exist")
+			return
+		}
+
+		force, err := parseBoolQuery(c.Request.URL.Query(), "force")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		dto, err := sc.service.Retry(ctx, id, force)
+		if err != nil {
+			if errors.Is(err, services.ErrSyncNotFound) {
+				c.JSON(http.StatusNotFound, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			if errors.Is(err, services.ErrSyncNotFailed) {
+				c.JSON(http.StatusConflict, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on retry the sync: %v", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, responses.NewSync(dto))
+	}
+}
+
+// DiscardSync godoc
+// @Summary 			Descartar uma Sincronização
+// @Description 		Marca uma sincronização que falhou como descartada (*discarded*), para que a alteração nunca seja salva, como uma alteração rejeitada por conflito de versão. As alterações seguintes da mesma folha de regra, que aguardavam essa sincronização, são enviadas na sequência. Retorna a sincronização descartada.
+// @Tags 				Sync
+// @Accept  			json
+// @Produce  			json
+// @Param				id path string true "Sync ID"
+// @Success 			200 {object} responses.Sync
+// @Header 				200 {string} Authorization "token access"
+// @Failure 			400 {object} responses.Error "Bad Format"
+// @Failure 			404 {object} responses.Error "Not Found"
+// @Failure 			409 {object} responses.Error "Conflict"
+// @Failure 			500 {object} responses.Error "Internal Server Error"
+// @Failure 			default {object} responses.Error
+// @Security 			Authentication Api Key
+// @Security 			Authentication Bearer Token
+// @Router 				/syncs/{id}/discard [post]
+// DiscardSync returns a `gin.HandlerFunc` that discards the sync with the ID passed in the request. If the
+// sync doesn't exist, a 404 status code is returned, and if it didn't fail, a 409 status code is returned.
+func (sc *syncs) DiscardSync() gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		id, exists := c.Params.Get("id")
+
+		if !exists {
+			c.JSON(http.StatusBadRequest, responses.Error{
+				Error: "Required param 'id'",
+			})
+			log.Error("Error on check if the sync exist")
+			return
+		}
+
+		dto, err := sc.service.Discard(ctx, id)
+		if err != nil {
+			if errors.Is(err, services.ErrSyncNotFound) {
+				c.JSON(http.StatusNotFound, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			if errors.Is(err, services.ErrSyncNotFailed) {
+				c.JSON(http.StatusConflict, responses.Error{
+					Error: err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, responses.Error{
+				Error: err.Error(),
+			})
+			log.Errorf("Error on discard the sync: %v", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, responses.NewSync(dto))
+	}
+}
@@ -0,0 +1,206 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	v1 "github.com/bancodobrasil/featws-api/controllers/v1"
+	"github.com/bancodobrasil/featws-api/dtos"
+	mock_services "github.com/bancodobrasil/featws-api/mocks/services"
+	"github.com/bancodobrasil/featws-api/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestSyncs_GetSyncs tests the GetSyncs function in the Syncs API endpoint.
+func TestSyncs_GetSyncs(t *testing.T) {
+	// It tests that the failed syncs are listed with their errors, so the operators can retry them.
+	t.Run("Success flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{RawQuery: "status=failed&limit=10"},
+		}
+
+		srv := new(mock_services.Syncs)
+		srv.On("Find", mock.Anything, "failed", &services.FindOptions{Limit: 10}).Return([]*dtos.Sync{{ID: 1, RulesheetID: 2, Status: "failed", Attempts: 5, Error: "error on save"}}, nil)
+		v1.NewSyncs(srv).GetSyncs()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var body []map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		assert.Len(t, body, 1)
+		assert.Equal(t, "failed", body[0]["status"])
+		assert.Equal(t, "error on save", body[0]["error"])
+	})
+
+	// It tests that an unknown status is rejected with 400 Bad Request.
+	t.Run("Invalid status flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{RawQuery: "status=unknown"},
+		}
+
+		srv := new(mock_services.Syncs)
+		v1.NewSyncs(srv).GetSyncs()(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+// TestSyncs_RetrySync tests the RetrySync function in the Syncs API endpoint.
+func TestSyncs_RetrySync(t *testing.T) {
+	// It tests that a failed sync is retried and returned with the result of the new attempt.
+	t.Run("Success flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{},
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Syncs)
+		srv.On("Retry", mock.Anything, "1", false).Return(&dtos.Sync{ID: 1, Status: "synced", Version: "3"}, nil)
+		v1.NewSyncs(srv).RetrySync()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	// It tests that an unknown sync is returned as 404 Not Found.
+	t.Run("Sync not found flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{},
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "9"}}
+
+		srv := new(mock_services.Syncs)
+		srv.On("Retry", mock.Anything, "9", false).Return(nil, services.ErrSyncNotFound)
+		v1.NewSyncs(srv).RetrySync()(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	// It tests that a sync that didn't fail is returned as 409 Conflict.
+	t.Run("Sync not failed flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{},
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Syncs)
+		srv.On("Retry", mock.Anything, "1", false).Return(nil, services.ErrSyncNotFailed)
+		v1.NewSyncs(srv).RetrySync()(c)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	// It tests that the retry over the current version is forced by the query param.
+	t.Run("Forced flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{RawQuery: "force=true"},
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Syncs)
+		srv.On("Retry", mock.Anything, "1", true).Return(&dtos.Sync{ID: 1, Status: "synced", Version: "3"}, nil)
+		v1.NewSyncs(srv).RetrySync()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	// It tests that an invalid force query param is returned as 400 Bad Request.
+	t.Run("Invalid force flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+			URL:    &url.URL{RawQuery: "force=maybe"},
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Syncs)
+		v1.NewSyncs(srv).RetrySync()(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		srv.AssertNotCalled(t, "Retry", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// TestSyncs_DiscardSync tests the DiscardSync function in the Syncs API endpoint.
+func TestSyncs_DiscardSync(t *testing.T) {
+	// It tests that a failed sync is discarded and returned.
+	t.Run("Success flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Syncs)
+		srv.On("Discard", mock.Anything, "1").Return(&dtos.Sync{ID: 1, Status: "discarded"}, nil)
+		v1.NewSyncs(srv).DiscardSync()(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	// It tests that an unknown sync is returned as 404 Not Found.
+	t.Run("Sync not found flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "9"}}
+
+		srv := new(mock_services.Syncs)
+		srv.On("Discard", mock.Anything, "9").Return(nil, services.ErrSyncNotFound)
+		v1.NewSyncs(srv).DiscardSync()(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	// It tests that a sync that didn't fail is returned as 409 Conflict.
+	t.Run("Sync not failed flow", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			Header: make(http.Header),
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		srv := new(mock_services.Syncs)
+		srv.On("Discard", mock.Anything, "1").Return(nil, services.ErrSyncNotFailed)
+		v1.NewSyncs(srv).DiscardSync()(c)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
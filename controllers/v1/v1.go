@@ -1,11 +1,40 @@
 package v1
 
 import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bancodobrasil/featws-api/config"
+	"github.com/bancodobrasil/featws-api/dtos"
+	"github.com/bancodobrasil/featws-api/expressions"
+	"github.com/gin-gonic/gin"
+
 	responses "github.com/bancodobrasil/featws-api/responses/v1"
+	"github.com/bancodobrasil/featws-api/services"
 	"github.com/go-playground/validator/v10"
 )
 
-var validate = validator.New()
+var validate = newValidator()
+
+// defaultPageLimit is the number of rulesheets of a page of the list when the "limit" isn't given.
+const defaultPageLimit = 10
+
+// newValidator creates the validator of the payloads, with the `featws_type` tag that checks if a string is
+// one of the types of the rule expressions, like `boolean`, `integer` or `decimal`.
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterValidation("featws_type", func(fl validator.FieldLevel) bool {
+		_, ok := expressions.NormalizeType(fl.Field().String())
+		return ok
+	})
+	return v
+}
 
 // validatePayload validates a payload using a validator library and returns any validation errors as a
 // custom error response.
@@ -35,3 +64,203 @@ func validatePayload(payload interface{}) *responses.Error {
 
 	return nil
 }
+
+// validateRules validates the expressions of the rules of a rulesheet and the parameters and features they
+// reference. It returns the errors as a custom error response, with the path of each broken expression and
+// the position of the error on it, and the warnings, which don't prevent the rulesheet from being saved.
+func validateRules(rulesheet *dtos.Rulesheet) (*responses.Error, []responses.ValidationError) {
+	errors := make([]responses.ValidationError, 0)
+	warnings := make([]responses.ValidationError, 0)
+
+	for _, ruleErr := range dtos.ValidateRules(rulesheet) {
+		validationErr := responses.ValidationError{
+			Field:    ruleErr.Path,
+			Tag:      ruleErr.Tag,
+			Error:    ruleErr.Message,
+			Position: ruleErr.Position,
+		}
+
+		if ruleErr.Warning {
+			warnings = append(warnings, validationErr)
+		} else {
+			errors = append(errors, validationErr)
+		}
+	}
+
+	if len(errors) > 0 {
+		return &responses.Error{
+			ValidationErrors: errors,
+		}, warnings
+	}
+
+	return nil, warnings
+}
+
+// checkTests runs the test cases of a rulesheet before it's saved, when the creations and updates whose test
+// cases fail are configured to be rejected. It returns the failures as a custom error response, with the
+// path of each expected feature that doesn't match, or nil if they're not checked or every case passed.
+func checkTests(rulesheet *dtos.Rulesheet) (*responses.Error, error) {
+	if !config.GetConfig().TestsBlockUpdates || rulesheet.Tests == nil || rulesheet.HasStringRule {
+		return nil, nil
+	}
+
+	run, err := dtos.RunTests(rulesheet)
+	if err != nil {
+		return nil, err
+	}
+
+	if run.Failed == 0 {
+		return nil, nil
+	}
+
+	errors := make([]responses.ValidationError, 0)
+	for index, result := range run.Results {
+		for _, failure := range result.Failures {
+			errors = append(errors, responses.ValidationError{
+				Field: fmt.Sprintf("tests[%d].expected.%s", index, failure.Feature),
+				Tag:   "test",
+				Error: fmt.Sprintf("the test case '%s' expected %v but got %v", result.Name, failure.Expected, failure.Actual),
+			})
+		}
+	}
+
+	return &responses.Error{
+		Error:            fmt.Sprintf("%d of %d test cases failed", run.Failed, run.Passed+run.Failed),
+		ValidationErrors: errors,
+	}, nil
+}
+
+// parseFindOptions reads the pagination query parameters "limit" and "page" and returns them as
+// find options. It returns an error if any of them isn't a positive integer.
+func parseFindOptions(query url.Values) (*services.FindOptions, error) {
+	opts := &services.FindOptions{}
+
+	for param, target := range map[string]*int{"limit": &opts.Limit, "page": &opts.Page} {
+		value, ok := query[param]
+		if !ok {
+			continue
+		}
+
+		valueInt, err := strconv.Atoi(value[0])
+		if err != nil || valueInt < 1 {
+			return nil, fmt.Errorf("the query param '%s' must be a positive integer", param)
+		}
+		*target = valueInt
+	}
+
+	return opts, nil
+}
+
+// parseRulesheetFilter reads the query parameters that filter the list of rulesheets: the "name" and "slug"
+// prefixes, the "description" text, the "q" text searched on the name, slug and description, the
+// "createdFrom", "createdTo", "updatedFrom" and "updatedTo" dates and the "deleted" state. It returns an
+// error if a date or the deleted state is invalid.
+func parseRulesheetFilter(query url.Values) (*services.RulesheetFilter, error) {
+	filter := &services.RulesheetFilter{
+		Name:        query.Get("name"),
+		Slug:        query.Get("slug"),
+		Description: query.Get("description"),
+		Search:      query.Get("q"),
+		Deleted:     query.Get("deleted"),
+	}
+
+	switch filter.Deleted {
+	case "", services.DeletedExclude, services.DeletedOnly, services.DeletedInclude:
+	default:
+		return nil, fmt.Errorf("the query param 'deleted' must be '%s', '%s' or '%s'", services.DeletedExclude, services.DeletedOnly, services.DeletedInclude)
+	}
+
+	for param, target := range map[string]**time.Time{
+		"createdFrom": &filter.CreatedFrom,
+		"createdTo":   &filter.CreatedTo,
+		"updatedFrom": &filter.UpdatedFrom,
+		"updatedTo":   &filter.UpdatedTo,
+	} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+
+		moment, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			day, dayErr := time.Parse("2006-01-02", value)
+			if dayErr != nil {
+				return nil, fmt.Errorf("the query param '%s' must be a date, like '2006-01-02' or '2006-01-02T15:04:05Z'", param)
+			}
+			// the end of the range of a date is the end of the day, so the range includes it
+			moment = day
+			if strings.HasSuffix(param, "To") {
+				moment = day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			}
+		}
+		*target = &moment
+	}
+
+	return filter, nil
+}
+
+// readUploadedFiles reads the content of the files uploaded on a multipart form, keyed by their names,
+// regardless of the fields they were sent on. It returns an error if a file is sent twice.
+func readUploadedFiles(form *multipart.Form) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+
+	for _, headers := range form.File {
+		for _, header := range headers {
+			name := filepath.Base(header.Filename)
+			if _, ok := files[name]; ok {
+				return nil, fmt.Errorf("the file '%s' was uploaded twice", name)
+			}
+
+			file, err := header.Open()
+			if err != nil {
+				return nil, err
+			}
+
+			content, err := io.ReadAll(file)
+			file.Close()
+			if err != nil {
+				return nil, err
+			}
+
+			files[name] = content
+		}
+	}
+
+	return files, nil
+}
+
+// parseBoolQuery reads a boolean query parameter, like "commit", which is false when it's omitted. It
+// returns an error if it isn't a boolean.
+func parseBoolQuery(query url.Values, param string) (bool, error) {
+	value, ok := query[param]
+	if !ok {
+		return false, nil
+	}
+
+	result, err := strconv.ParseBool(value[0])
+	if err != nil {
+		return false, fmt.Errorf("the query param '%s' must be a boolean", param)
+	}
+
+	return result, nil
+}
+
+// setRulesheetETag sets the `ETag` header of the response to the version of the rulesheet, which clients
+// send back on the `If-Match` header of an update.
+func setRulesheetETag(c *gin.Context, rulesheet *dtos.Rulesheet) {
+	if rulesheet.Version != "" {
+		c.Header("ETag", fmt.Sprintf("\"%s\"", rulesheet.Version))
+	}
+}
+
+// matchesETag checks if any of the entity tags of an `If-Match` header matches the version of a rulesheet.
+// The weak tags are compared like the strong ones, and `*` matches any version.
+func matchesETag(ifMatch string, version string) bool {
+	for _, tag := range strings.Split(ifMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || strings.Trim(tag, "\"") == version {
+			return true
+		}
+	}
+	return false
+}
@@ -35,7 +35,7 @@ const docTemplate = `{
                         "Authentication Bearer Token": []
                     }
                 ],
-                "description": "Nessa operação cria uma folha de regra no banco de dados do FeatWS. Para realizar a criação é necessário completar a folha de regra, com no mínimo:\n- **nome** no parâmetro *name*;\n- **slug** no parâmetro *slug*;\n- **descrição** no parâmetro *description*.\n\n` + "`" + `` + "`" + `` + "`" + `\n{\n\"description\": \"teste no Swagger da API do FeatWS\",\n\"name\": \"teste Swagger API\",\n\"slug\": \"teste_Swagger_API\"\n}\n` + "`" + `` + "`" + `` + "`" + `\nAmbos esses parâmetros devem ser uma string, ou seja, deve estar entre \"aspas\". Não é possível ter uma folha de regra com o mesmo nome de outra.\nPara criar uma folha de regra basta clicar em **Try it out** , complete a folha de regra com os dados desejados, em seguida, clique em **Execute**.",
+                "description": "Nessa operação cria uma folha de regra no banco de dados do FeatWS. Para realizar a criação é necessário completar a folha de regra, com no mínimo:\n- **nome** no parâmetro *name*;\n- **slug** no parâmetro *slug*;\n- **descrição** no parâmetro *description*.\n\n` + "`" + `` + "`" + `` + "`" + `\n{\n\"description\": \"teste no Swagger da API do FeatWS\",\n\"name\": \"teste Swagger API\",\n\"slug\": \"teste_Swagger_API\"\n}\n` + "`" + `` + "`" + `` + "`" + `\nAmbos esses parâmetros devem ser uma string, ou seja, deve estar entre \"aspas\". Não é possível ter uma folha de regra com o mesmo nome de outra.\nPara criar uma folha de regra basta clicar em **Try it out** , complete a folha de regra com os dados desejados, em seguida, clique em **Execute**.\nAs expressões *condition* e *dynamic* das regras são validadas antes de salvar. Uma expressão inválida, ou que referencia um parâmetro (*$nome*) ou uma feature (*#nome*) não declarados em *parameters* ou *features* nem definidos por outra regra, ou cujo tipo não corresponde ao tipo declarado na regra (*type*) ou na feature (*boolean*, *integer*, *decimal*, *string*, *date*, *list* ou *map*), retorna 400 com o caminho da regra (por exemplo, *rules.desconto[2].condition*) e a posição do erro na expressão em *validation_errors*. Um valor em texto só é uma referência quando é apenas a referência a um parâmetro ou feature declarado, como *#limite*; nos demais textos, como *#FF0000* ou *https://x/#secao*, o que parece uma referência é mantido como texto e retornado como aviso em *warnings*, assim como os parâmetros declarados que não são usados por nenhuma regra.\nOs casos de teste em *tests*, com os valores dos parâmetros (*parameters*) e os valores esperados das features (*expected*), são salvos no *tests.json*; quando omitidos na atualização, os casos já salvos são mantidos. Com *FEATWS_API_TESTS_BLOCK_UPDATES* habilitado, a folha de regra cujos casos de teste falham não é salva e retorna 422 com as features que não correspondem em *validation_errors*.",
                 "consumes": [
                     "application/json"
                 ],
@@ -53,7 +53,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.Rulesheet"
+                            "$ref": "#/definitions/github.com_bancodobrasil_featws-api_payloads_v1.Rulesheet"
                         }
                     }
                 ],
@@ -61,7 +61,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.Rulesheet"
+                            "$ref": "#/definitions/github.com_bancodobrasil_featws-api_payloads_v1.Rulesheet"
                         },
                         "headers": {
                             "Authorization": {
@@ -79,6 +79,12 @@ const docTemplate = `{
                     "404": {
                         "description": "Not Found"
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -104,7 +110,7 @@ const docTemplate = `{
                         "Authentication Bearer Token": []
                     }
                 ],
-                "description": "É possível listar as folhas de regra de algumas maneiras como veremos a seguir:\n\n- **Sem nenhum parâmetro:** Ao realizar a chamada do endpoint sem a passagem de parâmetros, todas as folhas de regra existentes serão retornadas, contendo informações como nome, ID, e caso estejam disponíveis, descrição e slug.\n- **Usando o *count*:** Ao habilitar o *count* para *True* será retornado do endpoint o número de Folhas de Regras existentes.\n- **Usando o *limit*:** Ao utilizar o parâmetro *limit* deve-se especificar o número máximo de respostas desejadas que serão retornadas pela array.\n- **Usando o *page*:** Ao utilizar o parâmetro *page*, serão retornadas as folhas de regra correspondentes a essa página, onde as folhas são ordenadas em ordem crescente pelo seu ID.\n\nPara listar as folhas de regra basta clicar em **Try it out** , complete com o formado desejados, em seguida, clique em **Execute**.",
+                "description": "É possível listar as folhas de regra de algumas maneiras como veremos a seguir:\n\n- **Sem nenhum parâmetro:** Ao realizar a chamada do endpoint sem a passagem de parâmetros, todas as folhas de regra existentes serão retornadas, contendo informações como nome, ID, e caso estejam disponíveis, descrição e slug.\n- **Paginação:** A resposta traz a página de folhas de regra em *items*, o número total de folhas de regra que atendem aos filtros em *total*, e os parâmetros *page* e *limit* usados. O parâmetro *limit* define o número máximo de folhas de regra da página, 10 por padrão, e *page* o número da página, a partir de 1.\n- **Usando o *count*:** Ao habilitar o *count* será retornado apenas o *total*, sem os *items*.\n- **Usando o *sort*:** Ordena as folhas de regra pelos campos informados, separados por vírgula, dentre *id*, *name*, *slug*, *createdAt* e *updatedAt*. Os campos precedidos por *-* são ordenados de forma decrescente, como em *name,-updatedAt*. Por padrão, e como desempate, as folhas de regra são ordenadas de forma crescente pelo ID.\n- **Usando o *cursor*:** Quando há mais folhas de regra, a resposta traz em *next* o cursor da próxima página, que pode ser passado no parâmetro *cursor*, com o mesmo *sort*, para buscar as folhas de regra seguintes sem calcular o deslocamento, o que é mais eficiente em tabelas grandes. O *cursor* não pode ser usado junto com o *page*.\n- **Usando os filtros:** Os parâmetros *name* e *slug* filtram as folhas de regra cujo nome ou slug começa com o texto informado, *description* filtra as que têm o texto na descrição e *q* busca o texto no nome, slug ou descrição, sem diferenciar maiúsculas e minúsculas. Os parâmetros *createdFrom*, *createdTo*, *updatedFrom* e *updatedTo* limitam as datas de criação e de atualização, como *2023-01-31* (o dia inteiro) ou *2023-01-31T10:00:00Z*. O parâmetro *deleted* aceita *exclude*, o padrão, que ignora as folhas de regra excluídas, *only*, que retorna só as excluídas, ou *include*, que retorna todas. Os filtros também valem para o *count*.\n\nPara listar as folhas de regra basta clicar em **Try it out** , complete com o formado desejados, em seguida, clique em **Execute**.",
                 "consumes": [
                     "application/json"
                 ],
@@ -133,6 +139,143 @@ const docTemplate = `{
                         "description": "Page number that is multiplied by 'limit' to calculate the offset",
                         "name": "page",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields to order by, like 'name,-updatedAt'",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page, from 'next'",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Prefix of the name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Prefix of the slug",
+                        "name": "slug",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text on the description",
+                        "name": "description",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text on the name, slug or description",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or after the date",
+                        "name": "createdFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or before the date",
+                        "name": "createdTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Updated at or after the date",
+                        "name": "updatedFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Updated at or before the date",
+                        "name": "updatedTo",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exclude",
+                            "only",
+                            "include"
+                        ],
+                        "type": "string",
+                        "description": "Deleted state",
+                        "name": "deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.RulesheetPage"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/rulesheets/convert": {
+            "post": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Converte as regras em texto de todas as folhas de regra legadas, carregadas do *rules.featws*, para regras estruturadas, da mesma forma que a conversão de uma folha de regra. As folhas de regra que já são estruturadas são ignoradas. Por padrão, a conversão é só uma prévia; com *commit=true*, cada folha de regra cujas regras convertidas não têm erros é salva no *rules.json*, removendo o *rules.featws*, e as demais retornam *committed* falso com os problemas em *errors*.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Converter as Regras em Texto das Folhas de Regra",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Save the converted rules",
+                        "name": "commit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -141,7 +284,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/v1.Rulesheet"
+                                "$ref": "#/definitions/v1.Conversion"
                             }
                         },
                         "headers": {
@@ -157,8 +300,111 @@ const docTemplate = `{
                             "$ref": "#/definitions/v1.Error"
                         }
                     },
-                    "404": {
-                        "description": "Not Found"
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/rulesheets/import": {
+            "post": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Cria ou atualiza uma folha de regra a partir de arquivos enviados em um formulário *multipart*, para migrar as folhas de regra de outros sistemas sem escrever as chamadas à API. Os arquivos, enviados no campo *files*, são identificados pelo nome: um arquivo *.featws* com as regras no formato legado, que são convertidas para regras estruturadas como na conversão das regras em texto, ou um pacote com *features.json*, *parameters.json*, *rules.json* e, opcionalmente, *tests.json*, como são salvos no repositório. Os campos *name*, *slug* e *description* do formulário identificam a folha de regra: se já existe uma com o mesmo *slug* (ou, sem ele, com o *slug* gerado a partir do *name*), ela é atualizada, mantendo o nome, a descrição e os casos de teste que não forem enviados; senão, é criada. O conteúdo é validado como na criação e na atualização. Com *dryRun=true*, a folha de regra é só validada e retornada, sem ser salva.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Importar Folha de Regra a partir de Arquivos",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Rulesheet files: a .featws file or features.json, parameters.json, rules.json and tests.json",
+                        "name": "files",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rulesheet name, required to create it",
+                        "name": "name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rulesheet slug",
+                        "name": "slug",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rulesheet description",
+                        "name": "description",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate the import",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Import"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Import"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -175,7 +421,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/rulesheets/{id}": {
+        "/rulesheets/slug/{slug}": {
             "get": {
                 "security": [
                     {
@@ -185,7 +431,7 @@ const docTemplate = `{
                         "Authentication Bearer Token": []
                     }
                 ],
-                "description": "Para se obter a folha de regra por ID, basta clicar em **Try it out** e colocar o ID desejado em *id*. Em seguida, clique em **Execute** e caso o ID exista retornará a folha de regra com o número de ID desejado.",
+                "description": "Para se obter a folha de regra pelo slug, que é a identidade usada pelos consumidores, pelo nome do projeto no GitLab e pelo ruller, basta clicar em **Try it out** e colocar o slug desejado em *slug*. Em seguida, clique em **Execute** e caso o slug exista retornará a folha de regra, como na busca por ID.\nA folha de regra também pode ser atualizada e excluída pelo slug, com os métodos *PUT*, *PATCH* e *DELETE* em */rulesheets/slug/{slug}*, que funcionam como os de */rulesheets/{id}*.",
                 "consumes": [
                     "application/json"
                 ],
@@ -195,12 +441,12 @@ const docTemplate = `{
                 "tags": [
                     "Rulesheet"
                 ],
-                "summary": "Obter Folha de Regra por ID",
+                "summary": "Obter Folha de Regra por Slug",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Rulesheet ID",
-                        "name": "id",
+                        "description": "Rulesheet slug",
+                        "name": "slug",
                         "in": "path",
                         "required": true
                     }
@@ -209,15 +455,16 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/v1.Rulesheet"
-                            }
+                            "$ref": "#/definitions/github.com_bancodobrasil_featws-api_responses_v1.Rulesheet"
                         },
                         "headers": {
                             "Authorization": {
                                 "type": "string",
                                 "description": "token access"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão da folha de regra"
                             }
                         }
                     },
@@ -243,8 +490,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/rulesheets/{id}": {
+            "get": {
                 "security": [
                     {
                         "Authentication Api Key": []
@@ -253,7 +502,7 @@ const docTemplate = `{
                         "Authentication Bearer Token": []
                     }
                 ],
-                "description": "Para atualizar ou editar uma folha de regra, é necessário enviar o ID da folha desejada no campo *id*, juntamente com os parâmetros da regra no corpo da solicitação no parâmetro *rulesheet*. Para realizar essa atualização clique no botão **Try it out** e preencher os campos com os dados desejados, em seguida, clicar em **Execute** para enviar a solicitação de atualização.",
+                "description": "Para se obter a folha de regra por ID, basta clicar em **Try it out** e colocar o ID desejado em *id*. Em seguida, clique em **Execute** e caso o ID exista retornará a folha de regra com o número de ID desejado.",
                 "consumes": [
                     "application/json"
                 ],
@@ -263,7 +512,7 @@ const docTemplate = `{
                 "tags": [
                     "Rulesheet"
                 ],
-                "summary": "Atualizar Folha de Regra por ID",
+                "summary": "Obter Folha de Regra por ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -271,15 +520,6 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Rulesheet body",
-                        "name": "rulesheet",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.Rulesheet"
-                        }
                     }
                 ],
                 "responses": {
@@ -288,13 +528,17 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/v1.Rulesheet"
+                                "$ref": "#/definitions/github.com_bancodobrasil_featws-api_payloads_v1.Rulesheet"
                             }
                         },
                         "headers": {
                             "Authorization": {
                                 "type": "string",
                                 "description": "token access"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão da folha de regra"
                             }
                         }
                     },
@@ -321,7 +565,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "put": {
                 "security": [
                     {
                         "Authentication Api Key": []
@@ -330,7 +574,7 @@ const docTemplate = `{
                         "Authentication Bearer Token": []
                     }
                 ],
-                "description": "Para excluir uma folha de regra, é necessário clicar no botão **Try it out** e preencher o campo *id* com o ID da folha de regra que se deseja excluir. Em seguida, clique em **Execute** para enviar a solicitação de exclusão.",
+                "description": "Para atualizar ou editar uma folha de regra, é necessário enviar o ID da folha desejada no campo *id*, juntamente com os parâmetros da regra no corpo da solicitação no parâmetro *rulesheet*. Para realizar essa atualização clique no botão **Try it out** e preencher os campos com os dados desejados, em seguida, clicar em **Execute** para enviar a solicitação de atualização.\nAs expressões *condition* e *dynamic* das regras são validadas antes de salvar. Uma expressão inválida, ou que referencia um parâmetro (*$nome*) ou uma feature (*#nome*) não declarados em *parameters* ou *features* nem definidos por outra regra, ou cujo tipo não corresponde ao tipo declarado na regra (*type*) ou na feature (*boolean*, *integer*, *decimal*, *string*, *date*, *list* ou *map*), retorna 400 com o caminho da regra (por exemplo, *rules.desconto[2].condition*) e a posição do erro na expressão em *validation_errors*. Um valor em texto só é uma referência quando é apenas a referência a um parâmetro ou feature declarado, como *#limite*; nos demais textos, como *#FF0000* ou *https://x/#secao*, o que parece uma referência é mantido como texto e retornado como aviso em *warnings*, assim como os parâmetros declarados que não são usados por nenhuma regra.\nOs casos de teste em *tests*, com os valores dos parâmetros (*parameters*) e os valores esperados das features (*expected*), são salvos no *tests.json*; quando omitidos na atualização, os casos já salvos são mantidos. Com *FEATWS_API_TESTS_BLOCK_UPDATES* habilitado, a folha de regra cujos casos de teste falham não é salva e retorna 422 com as features que não correspondem em *validation_errors*.",
                 "consumes": [
                     "application/json"
                 ],
@@ -340,7 +584,7 @@ const docTemplate = `{
                 "tags": [
                     "Rulesheet"
                 ],
-                "summary": "Deletar Folha de Regra por ID",
+                "summary": "Atualizar Folha de Regra por ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -348,18 +592,40 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Versão da folha de regra lida pelo cliente (ETag)",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Rulesheet body",
+                        "name": "rulesheet",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github.com_bancodobrasil_featws-api_payloads_v1.Rulesheet"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github.com_bancodobrasil_featws-api_payloads_v1.Rulesheet"
+                            }
                         },
                         "headers": {
                             "Authorization": {
                                 "type": "string",
                                 "description": "token access"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão da folha de regra"
                             }
                         }
                     },
@@ -372,6 +638,18 @@ const docTemplate = `{
                     "404": {
                         "description": "Not Found"
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -385,61 +663,2900 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "v1.Error": {
-            "type": "object",
-            "properties": {
-                "error": {},
-                "validation_errors": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v1.ValidationError"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Para excluir uma folha de regra, é necessário clicar no botão **Try it out** e preencher o campo *id* com o ID da folha de regra que se deseja excluir. Em seguida, clique em **Execute** para enviar a solicitação de exclusão.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Deletar Folha de Regra por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Altera parte de uma folha de regra sem enviar o documento inteiro. O documento alterado tem os campos *name*, *description*, *slug*, *features*, *parameters*, *rules* e *tests*, como no corpo da atualização, e a alteração pode ser enviada de duas formas, de acordo com o *Content-Type*:\n\n- **application/merge-patch+json** (RFC 7396): um objeto com os campos alterados, que são mesclados no documento. Os campos com *null* são removidos e as listas são substituídas inteiras, como em *{\"rules\": {\"desconto\": {\"value\": 0.2}}}*.\n- **application/json-patch+json** (RFC 6902): uma lista de operações (*add*, *remove*, *replace*, *move*, *copy* e *test*) com o caminho do valor alterado, como em *[{\"op\": \"replace\", \"path\": \"/rules/desconto/0/condition\", \"value\": \"$idade \u003e 60\"}]*.\n\nA folha de regra alterada é validada e salva como na atualização, em um único commit. Um patch inválido retorna 400, uma operação cujo caminho não existe ou cujo *test* falha retorna 422 e a alteração do slug não é permitida.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Alterar parte de uma Folha de Regra por ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Versão da folha de regra lida pelo cliente (ETag)",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "JSON Merge Patch or JSON Patch",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github.com_bancodobrasil_featws-api_responses_v1.Rulesheet"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão da folha de regra"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/rulesheets/{id}/changes": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "No modo de revisão (*FEATWS_API_GITLAB_REVIEW_MODE*), as alterações de uma folha de regra são salvas em um branch de rascunho e abrem um merge request no GitLab, que precisa ser aprovado antes de ser publicado. Enquanto pendente, a alteração não avança a versão: a folha de regra e a sincronização mantêm a versão publicada. Essa operação lista as alterações pendentes de uma folha de regra, com o título, o autor, o branch e as aprovações de cada uma.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Listar as Alterações Pendentes da Folha de Regra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.ChangeRequest"
+                            }
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/rulesheets/{id}/changes/{change}": {
+            "delete": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Descarta uma alteração pendente de uma folha de regra, fechando o seu merge request e removendo o branch de rascunho.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Descartar uma Alteração Pendente da Folha de Regra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Change request ID",
+                        "name": "change",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/rulesheets/{id}/changes/{change}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Aprova o merge request de uma alteração pendente de uma folha de regra. A aprovação é registrada no GitLab em nome do usuário do token configurado na API.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Aprovar uma Alteração Pendente da Folha de Regra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Change request ID",
+                        "name": "change",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ChangeRequest"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/rulesheets/{id}/changes/{change}/publish": {
+            "post": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Publica uma alteração pendente de uma folha de regra, fazendo o merge do seu merge request no branch padrão, avançando o número da versão (*VERSION*) e criando a tag da nova versão. A versão é definida na publicação, a partir da versão atual do branch padrão. A alteração precisa ter sido aprovada e não pode ter conflitos com outra alteração publicada antes dela.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Publicar uma Alteração Pendente da Folha de Regra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Change request ID",
+                        "name": "change",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github.com_bancodobrasil_featws-api_responses_v1.Rulesheet"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/rulesheets/{id}/convert": {
+            "post": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Converte as regras em texto de uma folha de regra legada, carregadas do *rules.featws*, para regras estruturadas. Cada texto é lido como uma expressão: um literal, como *10* ou *'ouro'*, vira o valor (*value*) da regra e as demais expressões viram o valor dinâmico (*dynamic*). As seções nomeadas viram mapas de regras e as seções *[[array]]* viram listas. Por padrão, a conversão é só uma prévia; com *commit=true*, as regras convertidas são validadas como numa atualização e, se não houver erros, salvas no *rules.json*, removendo o *rules.featws*. Os problemas encontrados são retornados em *errors*. Uma folha de regra que já é estruturada retorna 409.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Converter as Regras em Texto da Folha de Regra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Save the converted rules",
+                        "name": "commit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Conversion"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/rulesheets/{id}/diff": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Compara duas versões de uma folha de regra e lista as *features*, os *parameters* e as *rules* que foram adicionados (*added*), removidos (*removed*) ou alterados (*changed*), com os valores antigo (*old*) e novo (*new*) de cada um. As *features* e os *parameters* são identificados pelo nome e as *rules* pelo caminho, separado por pontos, dentro do *rules.json*. Os parâmetros *from* e *to* aceitam o número da versão (*VERSION*) ou o SHA de um commit.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Comparar Versões da Folha de Regra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Version number or commit SHA to compare from",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Version number or commit SHA to compare to",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Diff"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/rulesheets/{id}/evaluate": {
+            "post": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Calcula as *features* de uma folha de regra para os valores dos parâmetros informados em *parameters*, sem publicar a folha de regra nem aguardar a compilação e o deploy do ruller. Em cada lista de regras vale a primeira regra cuja condição (*condition*) é atendida, e a expressão *dynamic* tem precedência sobre o *value*. A resposta traz o valor de cada *feature*, as regras calculadas com o caminho (por exemplo, *rules.desconto[1]*) e se a condição foi atendida (*matched*), e os erros encontrados no cálculo em *errors*. O campo *version* aceita o número da versão (*VERSION*) ou o SHA de um commit; quando vazio, é avaliado o conteúdo atual. Apenas as regras estruturadas do *rules.json* são avaliadas: uma folha de regra só com as regras em texto do *rules.featws* retorna 422.\n\n` + "`" + `` + "`" + `` + "`" + `\n{\n\"version\": \"3\",\n\"parameters\": {\n\"idade\": 30\n}\n}\n` + "`" + `` + "`" + `` + "`" + `",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Avaliar a Folha de Regra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Evaluation body",
+                        "name": "evaluation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github.com_bancodobrasil_featws-api_payloads_v1.Evaluation"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github.com_bancodobrasil_featws-api_responses_v1.Evaluation"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/rulesheets/{id}/export": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Escreve as regras de uma folha de regra no formato *featws*, o formato INI do *rules.featws* lido pelo *go-featws*, para as equipes e ferramentas que só entendem esse formato. As regras em texto viram chaves da seção padrão, os mapas de regras viram seções nomeadas (*[secao]*) e as listas de mapas viram seções *[[lista]]*, em ordem alfabética; os valores que seriam alterados na leitura, como os que têm *;* ou estão entre aspas, são escritos entre crases. As regras estruturadas que só têm um valor (*value*) em texto, número ou booleano são escritas como o literal do valor, como *10* ou *'ouro'*, que a conversão das regras em texto lê de volta como a mesma regra. O arquivo gerado é lido de volta com as mesmas regras. O parâmetro *format* aceita só *featws*, que é o padrão, e o parâmetro *version* aceita o número da versão (*VERSION*) ou o SHA de um commit; quando vazio, é exportado o conteúdo atual. Uma folha de regra com regras que têm condição (*condition*) ou valor dinâmico (*dynamic*), ou que estão em uma lista de regras, que não podem ser escritas nesse formato, retorna 422 com o caminho dessas regras.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Exportar as Regras da Folha de Regra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "featws"
+                        ],
+                        "type": "string",
+                        "description": "Export format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Version number or commit SHA to export",
+                        "name": "version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "rules.featws",
+                        "schema": {
+                            "type": "string"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/rulesheets/{id}/features/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Retorna a feature com o nome *name* da folha de regra com o ID *id*, ou 404 caso a folha de regra ou a feature não existam.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Obter Feature de uma Folha de Regra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Feature name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github.com_bancodobrasil_featws-api_responses_v1.Feature"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão da folha de regra"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Cria ou substitui a feature com o nome *name* da folha de regra com o ID *id*, sem enviar a folha de regra inteira. O corpo é a feature, como *{\"type\": \"decimal\", \"description\": \"Desconto\"}*, com o nome do caminho quando *name* é omitido.\nA folha de regra alterada é validada e salva como na atualização, em um commit com a mensagem da alteração, como *[FEATWS BOT] Update feature nome*.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Criar ou Atualizar Feature de uma Folha de Regra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Feature name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Versão da folha de regra lida pelo cliente (ETag)",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Feature body",
+                        "name": "feature",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github.com_bancodobrasil_featws-api_payloads_v1.Feature"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github.com_bancodobrasil_featws-api_responses_v1.Rulesheet"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão da folha de regra"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Remove a feature com o nome *name* da folha de regra com o ID *id*. A folha de regra alterada é validada e salva como na atualização, em um commit com a mensagem *[FEATWS BOT] Delete feature nome*, e retorna 404 caso a feature não exista.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Deletar Feature de uma Folha de Regra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Feature name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Versão da folha de regra lida pelo cliente (ETag)",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github.com_bancodobrasil_featws-api_responses_v1.Rulesheet"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão da folha de regra"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/rulesheets/{id}/graph": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Retorna o grafo de dependências entre as regras, features e parâmetros de uma folha de regra: cada regra depende dos parâmetros (*$nome*) e features (*#nome*) referenciados nas suas condições, valores dinâmicos e valores em texto que são só uma referência a um parâmetro ou feature declarado, inclusive nos mapas e listas de regras, e as features definidas por outra regra apontam para essa regra. As dependências circulares entre as regras são listadas em *cycles*, da primeira regra de volta a ela mesma. O parâmetro *format* aceita *json*, que é o padrão, *dot*, a linguagem do Graphviz, ou *mermaid*, um fluxograma do Mermaid, em que as arestas dos ciclos são destacadas. O parâmetro *version* aceita o número da versão (*VERSION*) ou o SHA de um commit; quando vazio, é usado o conteúdo atual. As regras em texto do *rules.featws* são convertidas como na conversão, sem salvar.",
+                "produces": [
+                    "application/json",
+                    "text/plain"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Grafo de Dependências da Folha de Regra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "dot",
+                            "mermaid"
+                        ],
+                        "type": "string",
+                        "description": "Graph format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Version number or commit SHA",
+                        "name": "version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Graph"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/rulesheets/{id}/parameters/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Retorna o parâmetro com o nome *name* da folha de regra com o ID *id*, ou 404 caso a folha de regra ou o parâmetro não existam.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Obter Parâmetro de uma Folha de Regra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Parameter name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github.com_bancodobrasil_featws-api_responses_v1.Parameter"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão da folha de regra"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Cria ou substitui o parâmetro com o nome *name* da folha de regra com o ID *id*, sem enviar a folha de regra inteira. O corpo é o parâmetro, como *{\"type\": \"integer\", \"required\": true}*, com o nome do caminho quando *name* é omitido.\nA folha de regra alterada é validada e salva como na atualização, em um commit com a mensagem da alteração, como *[FEATWS BOT] Update parameter nome*.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Criar ou Atualizar Parâmetro de uma Folha de Regra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Parameter name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Versão da folha de regra lida pelo cliente (ETag)",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Parameter body",
+                        "name": "parameter",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github.com_bancodobrasil_featws-api_payloads_v1.Parameter"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github.com_bancodobrasil_featws-api_responses_v1.Rulesheet"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão da folha de regra"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Remove o parâmetro com o nome *name* da folha de regra com o ID *id*. A folha de regra alterada é validada e salva como na atualização, em um commit com a mensagem *[FEATWS BOT] Delete parameter nome*, e retorna 404 caso o parâmetro não exista.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Deletar Parâmetro de uma Folha de Regra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Parameter name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Versão da folha de regra lida pelo cliente (ETag)",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github.com_bancodobrasil_featws-api_responses_v1.Rulesheet"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão da folha de regra"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/rulesheets/{id}/rollback": {
+            "post": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Restaura as *features*, os *parameters* e as *rules* de uma folha de regra a partir de uma versão anterior, informada pelo número da versão (*VERSION*) ou pelo SHA de um commit no campo *version*. O conteúdo restaurado é validado e salvo como uma atualização, em um novo commit que avança o número da versão: as expressões inválidas retornam 400 e os casos de teste que falham, com *FEATWS_API_TESTS_BLOCK_UPDATES* habilitado, ou uma versão com as regras em texto do *rules.featws* retornam 422. Com o cabeçalho *If-Match*, a folha de regra só é restaurada se a versão atual for a informada; senão, retorna 409.\n\n` + "`" + `` + "`" + `` + "`" + `\n{\n\"version\": \"3\"\n}\n` + "`" + `` + "`" + `` + "`" + `",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Restaurar uma Versão da Folha de Regra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Versão da folha de regra lida pelo cliente (ETag)",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Rollback body",
+                        "name": "rollback",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.Rollback"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github.com_bancodobrasil_featws-api_responses_v1.Rulesheet"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão da folha de regra"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/rulesheets/{id}/rules/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Retorna a regra com o nome *name* da folha de regra com o ID *id*, ou 404 caso a folha de regra ou a regra não existam.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Obter Regra de uma Folha de Regra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rule name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão da folha de regra"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Cria ou substitui a regra com o nome *name* da folha de regra com o ID *id*, sem enviar a folha de regra inteira. O corpo é a regra no mesmo formato das *rules* da folha de regra: uma regra, como *{\"condition\": \"$idade \u003e 60\", \"value\": 0.2}*, uma lista de regras ou uma regra em texto.\nA folha de regra alterada é validada e salva como na atualização, em um commit com a mensagem da alteração, como *[FEATWS BOT] Update rule nome*.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Criar ou Atualizar Regra de uma Folha de Regra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rule name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Versão da folha de regra lida pelo cliente (ETag)",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Rule body",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github.com_bancodobrasil_featws-api_responses_v1.Rulesheet"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão da folha de regra"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Remove a regra com o nome *name* da folha de regra com o ID *id*. A folha de regra alterada é validada e salva como na atualização, em um commit com a mensagem *[FEATWS BOT] Delete rule nome*, e retorna 404 caso a regra não exista.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Deletar Regra de uma Folha de Regra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rule name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Versão da folha de regra lida pelo cliente (ETag)",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github.com_bancodobrasil_featws-api_responses_v1.Rulesheet"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão da folha de regra"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/rulesheets/{id}/tests/run": {
+            "post": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Executa os casos de teste salvos no *tests.json* de uma folha de regra, calculando as *features* para os parâmetros (*parameters*) de cada caso e comparando com os valores esperados (*expected*). A resposta traz a quantidade de casos que passaram (*passed*) e que falharam (*failed*) e, para cada caso, as features cujo valor calculado (*actual*) não corresponde ao esperado (*expected*). O parâmetro *version* aceita o número da versão (*VERSION*) ou o SHA de um commit; quando vazio, são executados os casos do conteúdo atual. Uma folha de regra só com as regras em texto do *rules.featws* retorna 422.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Executar os Casos de Teste da Folha de Regra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Version number or commit SHA to run the tests against",
+                        "name": "version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.TestRun"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/rulesheets/{id}/versions": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Lista as versões publicadas de uma folha de regra, da mais recente para a mais antiga. Cada versão contém o número da versão (*VERSION*), o SHA do commit, o autor, a data e a mensagem do commit. Utilize os parâmetros *limit* e *page* para paginar o resultado.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Listar as Versões de uma Folha de Regra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max length of the array returned",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number that is multiplied by 'limit' to calculate the offset",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.Version"
+                            }
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/rulesheets/{id}/versions/{version}": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Retorna a folha de regra com as *features*, os *parameters* e as *rules* exatamente como estavam em uma versão. O campo *version* aceita o número da versão (*VERSION*) ou o SHA de um commit do repositório da folha de regra.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rulesheet"
+                ],
+                "summary": "Obter uma Versão da Folha de Regra",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rulesheet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Version number or commit SHA",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github.com_bancodobrasil_featws-api_responses_v1.Rulesheet"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/syncs": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "As alterações das folhas de regra são gravadas no banco de dados junto com uma sincronização pendente, que é enviada ao backend de armazenamento (*FEATWS_API_STORAGE*) em seguida. As sincronizações que falham são tentadas novamente, com um intervalo crescente (*FEATWS_API_SYNC_INTERVAL*), até o limite de tentativas (*FEATWS_API_SYNC_MAX_ATTEMPTS*), quando ficam com o status *failed*, e as que falham por conflito de versão ficam com esse status na primeira tentativa. Uma sincronização com o status *failed* aguarda ser tentada novamente ou descartada (*discarded*). Essa operação lista as sincronizações, com o status, o número de tentativas e o erro de cada uma. Utilize o parâmetro *status* para filtrar por *pending*, *synced*, *failed* ou *discarded*, e os parâmetros *limit* e *page* para paginar o resultado.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sync"
+                ],
+                "summary": "Listar as Sincronizações das Folhas de Regra",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "synced",
+                            "failed",
+                            "discarded"
+                        ],
+                        "type": "string",
+                        "description": "Sync status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max length of the array returned",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number that is multiplied by 'limit' to calculate the offset",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.Sync"
+                            }
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/syncs/{id}/discard": {
+            "post": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Marca uma sincronização que falhou como descartada (*discarded*), para que a alteração nunca seja salva, como uma alteração rejeitada por conflito de versão. As alterações seguintes da mesma folha de regra, que aguardavam essa sincronização, são enviadas na sequência. Retorna a sincronização descartada.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sync"
+                ],
+                "summary": "Descartar uma Sincronização",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sync ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Sync"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/syncs/{id}/retry": {
+            "post": {
+                "security": [
+                    {
+                        "Authentication Api Key": []
+                    },
+                    {
+                        "Authentication Bearer Token": []
+                    }
+                ],
+                "description": "Marca uma sincronização que falhou como pendente, zerando as tentativas, e a envia ao backend de armazenamento imediatamente. As alterações seguintes da mesma folha de regra, que aguardavam essa sincronização, são enviadas na sequência. A sincronização mantém a versão em que a alteração se baseou, então uma sincronização que falhou por conflito de versão falha novamente; com *force=true*, ela é enviada sem essa versão, sobrescrevendo a versão atual com a alteração. Para não salvá-la, descarte a sincronização. Retorna a sincronização com o resultado da nova tentativa.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sync"
+                ],
+                "summary": "Tentar Novamente uma Sincronização",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sync ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Save the change over the current version, even if it failed on a version conflict",
+                        "name": "force",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Sync"
+                        },
+                        "headers": {
+                            "Authorization": {
+                                "type": "string",
+                                "description": "token access"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Format",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/v1.Error"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github.com_bancodobrasil_featws-api_payloads_v1.Evaluation": {
+            "type": "object",
+            "properties": {
+                "parameters": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "github.com_bancodobrasil_featws-api_payloads_v1.Feature": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "default": {},
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "github.com_bancodobrasil_featws-api_payloads_v1.Parameter": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "default": {},
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "resolver": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "github.com_bancodobrasil_featws-api_payloads_v1.Rulesheet": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "features": {
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "$ref": "#/definitions/github.com_bancodobrasil_featws-api_payloads_v1.Feature"
+                    }
+                },
+                "hasStringRule": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parameters": {
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "$ref": "#/definitions/github.com_bancodobrasil_featws-api_payloads_v1.Parameter"
+                    }
+                },
+                "rules": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "tests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github.com_bancodobrasil_featws-api_payloads_v1.TestCase"
+                    }
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "github.com_bancodobrasil_featws-api_payloads_v1.TestCase": {
+            "type": "object",
+            "required": [
+                "expected",
+                "name"
+            ],
+            "properties": {
+                "expected": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parameters": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "github.com_bancodobrasil_featws-api_responses_v1.Evaluation": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.ValidationError"
+                    }
+                },
+                "features": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.RuleResult"
+                    }
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "github.com_bancodobrasil_featws-api_responses_v1.Feature": {
+            "type": "object",
+            "properties": {
+                "default": {},
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "github.com_bancodobrasil_featws-api_responses_v1.Parameter": {
+            "type": "object",
+            "properties": {
+                "default": {},
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "resolver": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "github.com_bancodobrasil_featws-api_responses_v1.Rulesheet": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "features": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github.com_bancodobrasil_featws-api_responses_v1.Feature"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parameters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github.com_bancodobrasil_featws-api_responses_v1.Parameter"
+                    }
+                },
+                "rules": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "syncError": {
+                    "type": "string"
+                },
+                "syncStatus": {
+                    "type": "string"
+                },
+                "tests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github.com_bancodobrasil_featws-api_responses_v1.TestCase"
+                    }
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.ValidationError"
                     }
                 }
             }
         },
-        "v1.Rulesheet": {
+        "github.com_bancodobrasil_featws-api_responses_v1.TestCase": {
+            "type": "object",
+            "properties": {
+                "expected": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parameters": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "v1.Change": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "new": {},
+                "old": {}
+            }
+        },
+        "v1.ChangeRequest": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
+                "approved": {
+                    "type": "boolean"
+                },
+                "approvedBy": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "author": {
+                    "type": "string"
+                },
+                "branch": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "integer"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "webUrl": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.Conversion": {
+            "type": "object",
+            "properties": {
+                "committed": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.ValidationError"
+                    }
+                },
+                "rulesheet": {
+                    "$ref": "#/definitions/github.com_bancodobrasil_featws-api_responses_v1.Rulesheet"
+                }
+            }
+        },
+        "v1.Diff": {
+            "type": "object",
+            "properties": {
                 "features": {
                     "type": "array",
                     "items": {
-                        "type": "object",
-                        "additionalProperties": true
+                        "$ref": "#/definitions/v1.Change"
                     }
                 },
-                "hasStringRule": {
+                "from": {
+                    "type": "string"
+                },
+                "parameters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.Change"
+                    }
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.Change"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.Error": {
+            "type": "object",
+            "properties": {
+                "error": {},
+                "validation_errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.ValidationError"
+                    }
+                }
+            }
+        },
+        "v1.Graph": {
+            "type": "object",
+            "properties": {
+                "cycles": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "edges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.GraphEdge"
+                    }
+                },
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.GraphNode"
+                    }
+                }
+            }
+        },
+        "v1.GraphEdge": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "paths": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.GraphNode": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.Import": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "boolean"
+                },
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "rulesheet": {
+                    "$ref": "#/definitions/github.com_bancodobrasil_featws-api_responses_v1.Rulesheet"
+                }
+            }
+        },
+        "v1.Rollback": {
+            "type": "object",
+            "required": [
+                "version"
+            ],
+            "properties": {
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.RuleResult": {
+            "type": "object",
+            "properties": {
+                "condition": {
+                    "type": "string"
+                },
+                "matched": {
                     "type": "boolean"
                 },
+                "path": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.RulesheetPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github.com_bancodobrasil_featws-api_responses_v1.Rulesheet"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.Sync": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "commitMessage": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "name": {
+                "nextAttemptAt": {
                     "type": "string"
                 },
-                "parameters": {
+                "rulesheetId": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "syncedAt": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.TestFailure": {
+            "type": "object",
+            "properties": {
+                "actual": {},
+                "expected": {},
+                "feature": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.TestResult": {
+            "type": "object",
+            "properties": {
+                "errors": {
                     "type": "array",
                     "items": {
-                        "type": "object",
-                        "additionalProperties": true
+                        "$ref": "#/definitions/v1.ValidationError"
                     }
                 },
-                "rules": {
-                    "type": "object",
-                    "additionalProperties": true
+                "failures": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.TestFailure"
+                    }
                 },
-                "slug": {
+                "name": {
                     "type": "string"
                 },
+                "passed": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "v1.TestRun": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "passed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.TestResult"
+                    }
+                },
                 "version": {
                     "type": "string"
                 }
@@ -454,10 +3571,36 @@ const docTemplate = `{
                 "field": {
                     "type": "string"
                 },
+                "position": {
+                    "type": "integer"
+                },
                 "tag": {
                     "type": "string"
                 }
             }
+        },
+        "v1.Version": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "authorEmail": {
+                    "type": "string"
+                },
+                "commit": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -484,7 +3627,7 @@ var SwaggerInfo = &swag.Spec{
 	BasePath:         "/api/v1",
 	Schemes:          []string{},
 	Title:            "FeatWS API",
-	Description:      "Este projeto consiste em uma API cujo objetivo é fornecer operações para gerenciamento de repositórios e folhas de regra do sistema FeatWS. Através da API, é possível interagir entre a interface de usuário (UI) e o banco de dados, permitindo diversas interações, como as seguintes:\n- [Post] Criação da Folha de Regra;\n- [Get] Listar das Folhas de Regra;\n- [Get] Obter folha de regra por ID;\n- [Put] Atualizar uma folha de regra por ID;\n- [Delete] Deletar uma folha de regra por ID.\n\nAntes de realizar as requisições no Swagger, é necessário autorizar o acesso clicando no botão **Authorize**, ao lado, e inserindo a senha correspondente. Após inserir o campo **value** e clicar no botão **Authorize**, o Swagger estará disponível para ser utilizado.\n",
+	Description:      "Este projeto consiste em uma API cujo objetivo é fornecer operações para gerenciamento de repositórios e folhas de regra do sistema FeatWS. Através da API, é possível interagir entre a interface de usuário (UI) e o banco de dados, permitindo diversas interações, como as seguintes:\n- [Post] Criação da Folha de Regra;\n- [Post] Importar uma folha de regra a partir de arquivos (*.featws* ou *features.json*, *parameters.json* e *rules.json*);\n- [Get] Listar das Folhas de Regra, com filtros por nome, slug, descrição, datas e exclusão, ordenação e paginação por página ou cursor;\n- [Get] Obter folha de regra por ID ou por slug;\n- [Put] Atualizar uma folha de regra por ID ou por slug;\n- [Patch] Alterar parte de uma folha de regra por ID ou por slug, com JSON Merge Patch ou JSON Patch;\n- [Delete] Deletar uma folha de regra por ID ou por slug;\n- [Get, Put, Delete] Obter, criar ou atualizar e remover uma regra, feature ou parâmetro de uma folha de regra por ID, com uma mensagem de commit da alteração;\n- [Get] Listar as versões de uma folha de regra por ID;\n- [Get] Obter uma folha de regra por ID em uma versão;\n- [Post] Restaurar uma folha de regra por ID a partir de uma versão anterior;\n- [Get] Comparar duas versões de uma folha de regra por ID;\n- [Post] Avaliar as regras de uma folha de regra por ID para um conjunto de parâmetros;\n- [Post] Executar os casos de teste de uma folha de regra por ID;\n- [Get] Exportar as regras de uma folha de regra por ID no formato *featws*;\n- [Get] Grafo de dependências das regras de uma folha de regra por ID, em JSON, DOT ou Mermaid;\n- [Post] Converter as regras em texto (*rules.featws*) de uma folha de regra por ID para regras estruturadas;\n- [Post] Converter as regras em texto de todas as folhas de regra legadas;\n- [Get] Listar as alterações pendentes de uma folha de regra por ID;\n- [Post] Aprovar uma alteração pendente de uma folha de regra;\n- [Post] Publicar uma alteração pendente de uma folha de regra;\n- [Delete] Descartar uma alteração pendente de uma folha de regra;\n- [Get] Listar as sincronizações das folhas de regra com o backend de armazenamento;\n- [Post] Tentar novamente uma sincronização que falhou;\n- [Post] Descartar uma sincronização que falhou.\n\nAntes de realizar as requisições no Swagger, é necessário autorizar o acesso clicando no botão **Authorize**, ao lado, e inserindo a senha correspondente. Após inserir o campo **value** e clicar no botão **Authorize**, o Swagger estará disponível para ser utilizado.\n",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 }
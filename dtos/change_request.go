@@ -0,0 +1,30 @@
+package dtos
+
+import "time"
+
+// ChangeRequest represents a pending change of a rulesheet, committed to a draft branch and waiting on a
+// merge request to be published.
+//
+// Property:
+//   - ID: the internal ID (IID) of the merge request on the rulesheet project.
+//   - Title: the title of the merge request, i.e. the commit message of the change.
+//   - Description: the description of the merge request.
+//   - Branch: the draft branch that holds the change.
+//   - Author: the name of the author of the merge request.
+//   - State: the state of the merge request, e.g. `opened`, `merged` or `closed`.
+//   - Approved: whether the merge request has all the approvals it requires.
+//   - ApprovedBy: the names of the users that approved the merge request.
+//   - WebURL: the address of the merge request on GitLab.
+//   - CreatedAt: the moment the merge request was opened.
+type ChangeRequest struct {
+	ID          int
+	Title       string
+	Description string
+	Branch      string
+	Author      string
+	State       string
+	Approved    bool
+	ApprovedBy  []string
+	WebURL      string
+	CreatedAt   *time.Time
+}
@@ -0,0 +1,95 @@
+package dtos
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/bancodobrasil/featws-api/expressions"
+)
+
+// Conversion is the result of converting the string rules of a legacy rulesheet, loaded from its
+// rules.featws file, into structured rules.
+//
+// Property:
+//   - Rulesheet: the rulesheet with the converted rules, as it's saved on rules.json.
+//   - Errors: the problems found on the converted rules, ordered by path, like the expressions that can't be parsed. The conversion is only committed when there's no problem other than the warnings.
+//   - Committed: whether the converted rules were saved, replacing the rules.featws file.
+type Conversion struct {
+	Rulesheet *Rulesheet
+	Errors    []*RuleError
+	Committed bool
+}
+
+// ConvertStringRules converts the string rules of a legacy rulesheet into structured rules. Each string is
+// parsed as an expression: a literal, like `10` or `'gold'`, becomes the value of the rule and any other
+// expression becomes its dynamic value. The maps of the named sections and the lists of the `[[array]]`
+// sections keep their shape, with their items converted the same way. The strings that can't be parsed are
+// kept as values and returned as problems.
+func ConvertStringRules(rules map[string]interface{}) (map[string]interface{}, []*RuleError) {
+	result := make(map[string]interface{}, len(rules))
+	problems := make([]*RuleError, 0)
+
+	for name, rule := range rules {
+		var found []*RuleError
+		result[name], found = convertStringRule("rules."+name, rule)
+		problems = append(problems, found...)
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Path < problems[j].Path
+	})
+
+	return result, problems
+}
+
+// The function converts a string rule, or the string rules nested on a map or list.
+func convertStringRule(path string, v interface{}) (interface{}, []*RuleError) {
+	problems := make([]*RuleError, 0)
+
+	switch value := v.(type) {
+	case string:
+		source := strings.TrimSpace(value)
+		node, err := expressions.Parse(source)
+		if err != nil {
+			return &Rule{Value: value}, append(problems, newExpressionError(path, RuleErrorExpression, err))
+		}
+
+		// the zero values are kept as expressions, since they're omitted from the value of rules.json
+		literal, ok := node.(*expressions.Literal)
+		if !ok || literal.Value == nil || reflect.ValueOf(literal.Value).IsZero() {
+			return &Rule{Dynamic: source}, problems
+		}
+		return &Rule{Value: literal.Value}, problems
+
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			var found []*RuleError
+			result[key], found = convertStringRule(path+"."+key, item)
+			problems = append(problems, found...)
+		}
+		return result, problems
+
+	case []map[string]interface{}:
+		result := make([]interface{}, 0, len(value))
+		for index, item := range value {
+			converted, found := convertStringRule(fmt.Sprintf("%s[%d]", path, index), item)
+			result = append(result, converted)
+			problems = append(problems, found...)
+		}
+		return result, problems
+
+	case []interface{}:
+		result := make([]interface{}, 0, len(value))
+		for index, item := range value {
+			converted, found := convertStringRule(fmt.Sprintf("%s[%d]", path, index), item)
+			result = append(result, converted)
+			problems = append(problems, found...)
+		}
+		return result, problems
+	}
+
+	return v, problems
+}
@@ -0,0 +1,179 @@
+package dtos
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// The kinds of change reported by a Diff.
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// Change represents a single entry that differs between two versions of a rulesheet.
+//
+// Property:
+//   - Kind: one of `ChangeAdded`, `ChangeRemoved` or `ChangeChanged`.
+//   - Name: the name of the feature or parameter, or the dotted path of the rule (e.g. `discount.gold`).
+//   - Old: the value on the `from` version. It is nil when the entry was added.
+//   - New: the value on the `to` version. It is nil when the entry was removed.
+type Change struct {
+	Kind string
+	Name string
+	Old  interface{}
+	New  interface{}
+}
+
+// Diff represents the semantic difference between two versions of a rulesheet.
+//
+// Property:
+//   - From: the version the changes are computed from.
+//   - To: the version the changes are computed to.
+//   - Features: the features added, removed or changed, keyed by their name.
+//   - Parameters: the parameters added, removed or changed, keyed by their name.
+//   - Rules: the rules added, removed or changed, keyed by their dotted path. Nested maps of rules are walked, while rules and lists of rules are compared as a whole.
+type Diff struct {
+	From       string
+	To         string
+	Features   []*Change
+	Parameters []*Change
+	Rules      []*Change
+}
+
+// DiffRulesheets compares the features, parameters and rules of two rulesheets. The values are compared
+// by their JSON representation, so the order of the keys of the maps built by `buildRule` doesn't matter.
+func DiffRulesheets(from *Rulesheet, to *Rulesheet) (diff *Diff, err error) {
+	diff = &Diff{
+		From: from.Version,
+		To:   to.Version,
+	}
+
+	oldFeatures, err := indexByName(from.Features)
+	if err != nil {
+		return nil, err
+	}
+	newFeatures, err := indexByName(to.Features)
+	if err != nil {
+		return nil, err
+	}
+	diff.Features = diffEntries(oldFeatures, newFeatures)
+
+	oldParameters, err := indexByName(from.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	newParameters, err := indexByName(to.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	diff.Parameters = diffEntries(oldParameters, newParameters)
+
+	oldRules, err := flattenRules(from.Rules)
+	if err != nil {
+		return nil, err
+	}
+	newRules, err := flattenRules(to.Rules)
+	if err != nil {
+		return nil, err
+	}
+	diff.Rules = diffEntries(oldRules, newRules)
+
+	return
+}
+
+// The function indexes a list of features or parameters by their name.
+func indexByName(list interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+
+	var normalized []interface{}
+	if err := normalize(list, &normalized); err != nil {
+		return nil, err
+	}
+
+	for index, item := range normalized {
+		entry, _ := item.(map[string]interface{})
+		name, ok := entry["name"].(string)
+		if !ok {
+			name = fmt.Sprintf("[%d]", index)
+		}
+		result[name] = item
+	}
+
+	return result, nil
+}
+
+// The function flattens the rules of a rulesheet into a map keyed by the dotted path of each rule.
+func flattenRules(rules *map[string]interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	if rules == nil {
+		return result, nil
+	}
+
+	var normalized map[string]interface{}
+	if err := normalize(*rules, &normalized); err != nil {
+		return nil, err
+	}
+
+	flattenRule("", normalized, result)
+
+	return result, nil
+}
+
+// The function walks the nested maps of rules, the same way `buildRule` does, storing each leaf on the result.
+func flattenRule(path string, rule map[string]interface{}, result map[string]interface{}) {
+	for k, v := range rule {
+		name := k
+		if path != "" {
+			name = path + "." + k
+		}
+
+		if nested, ok := v.(map[string]interface{}); ok && !isRuleMap(nested) {
+			flattenRule(name, nested, result)
+			continue
+		}
+
+		result[name] = v
+	}
+}
+
+// The function converts a value to its generic JSON representation.
+func normalize(value interface{}, result interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
+}
+
+// The function compares two indexed sets of entries and returns the changes sorted by name.
+func diffEntries(from map[string]interface{}, to map[string]interface{}) []*Change {
+	changes := make([]*Change, 0)
+
+	for name, old := range from {
+		current, ok := to[name]
+		if !ok {
+			changes = append(changes, &Change{Kind: ChangeRemoved, Name: name, Old: old})
+			continue
+		}
+		if !reflect.DeepEqual(old, current) {
+			changes = append(changes, &Change{Kind: ChangeChanged, Name: name, Old: old, New: current})
+		}
+	}
+
+	for name, current := range to {
+		if _, ok := from[name]; !ok {
+			changes = append(changes, &Change{Kind: ChangeAdded, Name: name, New: current})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return strings.Compare(changes[i].Name, changes[j].Name) < 0
+	})
+
+	return changes
+}
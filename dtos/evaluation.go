@@ -0,0 +1,257 @@
+package dtos
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bancodobrasil/featws-api/expressions"
+)
+
+// RuleErrorEvaluation is the tag of the problems found while computing the rules of a rulesheet, like a
+// comparison between values of different types or a feature that references itself.
+const RuleErrorEvaluation = "evaluation"
+
+// RuleResult is a rule computed by an evaluation of a rulesheet.
+//
+// Property:
+//   - Path: the path of the rule on the rulesheet, like `rules.discount[1]`.
+//   - Condition: the condition of the rule. It's empty when the rule always applies.
+//   - Matched: whether the condition of the rule was met, so its value was used.
+type RuleResult struct {
+	Path      string
+	Condition string
+	Matched   bool
+}
+
+// Evaluation is the result of computing the features of a rulesheet for a set of parameters.
+//
+// Property:
+//   - Version: the version of the rulesheet that was evaluated.
+//   - Features: the computed value of each feature defined by a rule, by name. It's nil when none of the conditions of the rule were met.
+//   - Rules: the rules that were computed, in the order they were evaluated, and whether their conditions were met. The rules of a list after the first one that matches aren't computed.
+//   - Errors: the problems found while computing the rules. The features whose rules failed aren't on `Features`.
+type Evaluation struct {
+	Version  string
+	Features map[string]interface{}
+	Rules    []*RuleResult
+	Errors   []*RuleError
+}
+
+// The states of the features computed by an evaluation.
+const (
+	featurePending = iota
+	featureComputing
+	featureComputed
+)
+
+// evaluator computes the rules of a rulesheet, resolving the parameters and features referenced by their
+// expressions. The features are computed on demand, so a rule can reference a feature defined after it.
+type evaluator struct {
+	rules  map[string]interface{}
+	params map[string]interface{}
+	env    expressions.Env
+	states map[string]int
+	result *Evaluation
+}
+
+// EvaluateRulesheet computes the features defined by the structured rules of a rulesheet for the given
+// parameters, working like the ruller does: the first rule of a list whose condition is met gives the
+// value of the feature, and a condition that results nil, like a feature whose rules weren't matched, isn't
+// met. The dynamic expression of a rule takes precedence over its value, and the string values that are a
+// single reference to a declared parameter or feature, like `$age`, take the referenced value. The string rules of the legacy
+// rulesheets aren't evaluated.
+func EvaluateRulesheet(rulesheet *Rulesheet, params map[string]interface{}) *Evaluation {
+	e := &evaluator{
+		rules:  make(map[string]interface{}),
+		params: params,
+		env:    rulesheetEnv(rulesheet),
+		states: make(map[string]int),
+		result: &Evaluation{
+			Version:  rulesheet.Version,
+			Features: make(map[string]interface{}),
+			Rules:    make([]*RuleResult, 0),
+			Errors:   make([]*RuleError, 0),
+		},
+	}
+
+	if rulesheet.Rules != nil {
+		e.rules = *rulesheet.Rules
+	}
+
+	names := make([]string, 0, len(e.rules))
+	for name := range e.rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		// the failures are recorded on the errors of the evaluation
+		_, _ = e.Feature(name)
+	}
+
+	sort.SliceStable(e.result.Errors, func(i, j int) bool {
+		return e.result.Errors[i].Path < e.result.Errors[j].Path
+	})
+
+	return e.result
+}
+
+// Param returns the value of a parameter, or nil if it wasn't given.
+func (e *evaluator) Param(name string) interface{} {
+	return e.params[name]
+}
+
+// Feature returns the value of a feature, computing its rule if it wasn't computed yet. The features that
+// aren't defined by a rule are nil.
+func (e *evaluator) Feature(name string) (interface{}, error) {
+	rule, ok := e.rules[name]
+	if !ok {
+		return nil, nil
+	}
+
+	switch e.states[name] {
+	case featureComputing:
+		return nil, fmt.Errorf("the feature '%s' references itself", name)
+	case featureComputed:
+		if value, ok := e.result.Features[name]; ok {
+			return value, nil
+		}
+		return nil, fmt.Errorf("the feature '%s' can't be computed", name)
+	}
+
+	e.states[name] = featureComputing
+	value, _, problem := e.evaluate("rules."+name, rule, true)
+	e.states[name] = featureComputed
+
+	if problem != nil {
+		e.result.Errors = append(e.result.Errors, problem)
+		return nil, fmt.Errorf("the feature '%s' can't be computed", name)
+	}
+
+	e.result.Features[name] = value
+	return value, nil
+}
+
+// The function computes a rule, which can be nested on lists and maps, and returns its value and whether it
+// was matched. The value of a list is the value of its first item that matches, and the value of a map is
+// a map with the values of its items that match.
+func (e *evaluator) evaluate(path string, v interface{}, top bool) (interface{}, bool, *RuleError) {
+	switch value := v.(type) {
+	case *Rule:
+		matched := true
+		if value.Condition != "" {
+			result, problem := e.compute(path+".condition", value.Condition)
+			if problem != nil {
+				return nil, false, problem
+			}
+			// a condition referencing a feature whose rules weren't matched isn't met
+			b, ok := result.(bool)
+			if !ok && result != nil {
+				return nil, false, &RuleError{
+					Path:     path + ".condition",
+					Position: 1,
+					Tag:      RuleErrorEvaluation,
+					Message:  fmt.Sprintf("the condition must be boolean but it's %v", result),
+				}
+			}
+			matched = b
+		}
+
+		e.result.Rules = append(e.result.Rules, &RuleResult{Path: path, Condition: value.Condition, Matched: matched})
+
+		if !matched {
+			return nil, false, nil
+		}
+
+		if value.Dynamic != "" {
+			result, problem := e.compute(path+".dynamic", value.Dynamic)
+			return result, problem == nil, problem
+		}
+
+		result, problem := e.resolveValue(path+".value", value.Value)
+		return result, problem == nil, problem
+
+	case []interface{}:
+		for index, item := range value {
+			result, matched, problem := e.evaluate(fmt.Sprintf("%s[%d]", path, index), item, false)
+			if problem != nil || matched {
+				return result, matched, problem
+			}
+		}
+		return nil, false, nil
+
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		result := make(map[string]interface{})
+		for _, key := range keys {
+			item, matched, problem := e.evaluate(path+"."+key, value[key], false)
+			if problem != nil {
+				return nil, false, problem
+			}
+			if matched {
+				result[key] = item
+			}
+		}
+		return result, true, nil
+
+	case string:
+		if top {
+			return nil, false, &RuleError{Path: path, Tag: RuleErrorEvaluation, Message: "the string rules aren't evaluated, only the structured ones"}
+		}
+		result, problem := e.resolveValue(path, value)
+		return result, problem == nil, problem
+	}
+
+	return v, true, nil
+}
+
+// The function parses and computes an expression of a rule.
+func (e *evaluator) compute(path string, source string) (interface{}, *RuleError) {
+	node, err := expressions.Parse(source)
+	if err != nil {
+		return nil, newExpressionError(path, RuleErrorExpression, err)
+	}
+
+	result, err := expressions.Evaluate(node, e)
+	if err != nil {
+		return nil, newExpressionError(path, RuleErrorEvaluation, err)
+	}
+
+	return result, nil
+}
+
+// The function resolves the reference of a value of a rule. A string that is a single reference to a
+// declared parameter or feature, like `#limit`, takes the referenced value, and the other strings, like
+// `Hello $name` or `#FF0000`, are kept as they are.
+func (e *evaluator) resolveValue(path string, value interface{}) (interface{}, *RuleError) {
+	s, ok := value.(string)
+	if !ok || !isValueReference(s, e.env) {
+		return value, nil
+	}
+
+	return e.resolveReference(path, s, []int{0, len(s)})
+}
+
+// The function returns the value of a reference found on a string value of a rule.
+func (e *evaluator) resolveReference(path string, s string, loc []int) (interface{}, *RuleError) {
+	match := s[loc[0]:loc[1]]
+	if match[0] == '$' {
+		return e.Param(match[1:]), nil
+	}
+
+	value, err := e.Feature(match[1:])
+	if err != nil {
+		return nil, &RuleError{
+			Path:     path,
+			Position: len([]rune(s[:loc[0]])) + 1,
+			Tag:      RuleErrorEvaluation,
+			Message:  err.Error(),
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,353 @@
+package dtos
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The kinds of the nodes of the dependency graph of a rulesheet.
+const (
+	GraphNodeRule      = "rule"
+	GraphNodeFeature   = "feature"
+	GraphNodeParameter = "parameter"
+)
+
+// GraphNode is a rule, feature or parameter of the dependency graph of a rulesheet.
+//
+// Property:
+//   - ID: the unique identifier of the node, made of its kind and name, like `rule:discount` or `parameter:age`.
+//   - Kind: one of `GraphNodeRule`, for the features defined by the rules, `GraphNodeFeature`, for the features declared or referenced without a rule, or `GraphNodeParameter`.
+//   - Name: the name of the rule, feature or parameter.
+type GraphNode struct {
+	ID   string
+	Kind string
+	Name string
+}
+
+// GraphEdge is a dependency of a rule on a feature or parameter it references.
+//
+// Property:
+//   - From: the ID of the rule that depends on the referenced node.
+//   - To: the ID of the referenced rule, feature or parameter.
+//   - Paths: the paths of the conditions, dynamic values and values of the rule where the node is referenced, like `rules.discount[0].condition`.
+type GraphEdge struct {
+	From  string
+	To    string
+	Paths []string
+}
+
+// Graph is the dependency graph between the rules, features and parameters of a rulesheet.
+//
+// Property:
+//   - Nodes: the rules, features and parameters, ordered by kind and name. The declared features and parameters are included even when they aren't referenced.
+//   - Edges: the dependencies of the rules, ordered by the IDs of their nodes.
+//   - Cycles: the circular dependencies between the rules, each one as the names of the rules from the first one back to itself, like `[a b a]`.
+type Graph struct {
+	Nodes  []*GraphNode
+	Edges  []*GraphEdge
+	Cycles [][]string
+}
+
+// BuildGraph builds the dependency graph of a rulesheet with structured rules. Each rule depends on the
+// parameters and features referenced by its conditions, dynamic values and string values, nested on maps
+// and lists or not. The referenced features that are defined by another rule are edges to that rule.
+func BuildGraph(rulesheet *Rulesheet) *Graph {
+	graph := &Graph{
+		Nodes: make([]*GraphNode, 0),
+		Edges: make([]*GraphEdge, 0),
+	}
+
+	rules := make(map[string]interface{})
+	if rulesheet.Rules != nil {
+		rules = *rulesheet.Rules
+	}
+
+	nodes := make(map[string]*GraphNode)
+	addNode := func(kind string, name string) string {
+		if _, ok := rules[name]; ok && kind == GraphNodeFeature {
+			kind = GraphNodeRule
+		}
+		id := kind + ":" + name
+		if _, ok := nodes[id]; !ok {
+			nodes[id] = &GraphNode{ID: id, Kind: kind, Name: name}
+		}
+		return id
+	}
+
+	for name := range rules {
+		addNode(GraphNodeRule, name)
+	}
+	if rulesheet.Features != nil {
+		for _, feature := range *rulesheet.Features {
+			addNode(GraphNodeFeature, feature.Name)
+		}
+	}
+	if rulesheet.Parameters != nil {
+		for _, parameter := range *rulesheet.Parameters {
+			addNode(GraphNodeParameter, parameter.Name)
+		}
+	}
+
+	edges := make(map[string]*GraphEdge)
+	dependencies := make(map[string]map[string]bool)
+	env := rulesheetEnv(rulesheet)
+	for name, rule := range rules {
+		from := addNode(GraphNodeRule, name)
+
+		for _, expr := range collectExpressions(name, "rules."+name, rule) {
+			for _, ref := range expressionReferences(expr, env) {
+				kind := GraphNodeFeature
+				if ref.param {
+					kind = GraphNodeParameter
+				}
+				to := addNode(kind, ref.name)
+
+				if _, ok := rules[ref.name]; !ref.param && ok {
+					addDependency(dependencies, name, ref.name)
+				}
+
+				key := from + " " + to
+				if _, ok := edges[key]; !ok {
+					edges[key] = &GraphEdge{From: from, To: to, Paths: make([]string, 0)}
+				}
+				if paths := edges[key].Paths; len(paths) == 0 || paths[len(paths)-1] != expr.path {
+					edges[key].Paths = append(paths, expr.path)
+				}
+			}
+		}
+	}
+
+	for _, node := range nodes {
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		if graph.Nodes[i].Kind != graph.Nodes[j].Kind {
+			return graphKindOrder(graph.Nodes[i].Kind) < graphKindOrder(graph.Nodes[j].Kind)
+		}
+		return graph.Nodes[i].Name < graph.Nodes[j].Name
+	})
+
+	for _, edge := range edges {
+		sort.Strings(edge.Paths)
+		graph.Edges = append(graph.Edges, edge)
+	}
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].From != graph.Edges[j].From {
+			return graph.Edges[i].From < graph.Edges[j].From
+		}
+		return graph.Edges[i].To < graph.Edges[j].To
+	})
+
+	graph.Cycles = findCycles(dependencies)
+
+	return graph
+}
+
+// DOT writes the graph on the Graphviz DOT language. The rules are boxes, the features are ellipses and the
+// parameters are parallelograms, and the edges of the cycles are red.
+func (g *Graph) DOT() string {
+	inCycle := g.cycleEdges()
+
+	var b strings.Builder
+	b.WriteString("digraph rulesheet {\n")
+	b.WriteString("\trankdir=LR;\n")
+	for _, node := range g.Nodes {
+		shape := "box"
+		switch node.Kind {
+		case GraphNodeFeature:
+			shape = "ellipse"
+		case GraphNodeParameter:
+			shape = "parallelogram"
+		}
+		fmt.Fprintf(&b, "\t%s [label=%s, shape=%s];\n", quoteDOT(node.ID), quoteDOT(node.Name), shape)
+	}
+	for _, edge := range g.Edges {
+		attributes := ""
+		if inCycle[edge.From+" "+edge.To] {
+			attributes = " [color=red]"
+		}
+		fmt.Fprintf(&b, "\t%s -> %s%s;\n", quoteDOT(edge.From), quoteDOT(edge.To), attributes)
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// Mermaid writes the graph as a Mermaid flowchart. The rules are rectangles, the features are stadiums and
+// the parameters are parallelograms, and the edges of the cycles are thick.
+func (g *Graph) Mermaid() string {
+	inCycle := g.cycleEdges()
+
+	ids := make(map[string]string, len(g.Nodes))
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for index, node := range g.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", index)
+		label := quoteMermaid(node.Name)
+		switch node.Kind {
+		case GraphNodeFeature:
+			fmt.Fprintf(&b, "    %s([%s])\n", ids[node.ID], label)
+		case GraphNodeParameter:
+			fmt.Fprintf(&b, "    %s[/%s/]\n", ids[node.ID], label)
+		default:
+			fmt.Fprintf(&b, "    %s[%s]\n", ids[node.ID], label)
+		}
+	}
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		if inCycle[edge.From+" "+edge.To] {
+			arrow = "==>"
+		}
+		fmt.Fprintf(&b, "    %s %s %s\n", ids[edge.From], arrow, ids[edge.To])
+	}
+
+	return b.String()
+}
+
+// The function returns the edges of the cycles of the graph, keyed by the IDs of their nodes.
+func (g *Graph) cycleEdges() map[string]bool {
+	result := make(map[string]bool)
+	for _, cycle := range g.Cycles {
+		for index := 1; index < len(cycle); index++ {
+			result[GraphNodeRule+":"+cycle[index-1]+" "+GraphNodeRule+":"+cycle[index]] = true
+		}
+	}
+	return result
+}
+
+// The function returns the position of a kind of node on the ordered graph.
+func graphKindOrder(kind string) int {
+	switch kind {
+	case GraphNodeRule:
+		return 0
+	case GraphNodeFeature:
+		return 1
+	}
+	return 2
+}
+
+// The function quotes an ID or label of the DOT language.
+func quoteDOT(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// The function quotes a label of a Mermaid flowchart, escaping the quotes as entities.
+func quoteMermaid(s string) string {
+	return `"` + strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(s) + `"`
+}
+
+// The function records that a rule depends on another rule.
+func addDependency(dependencies map[string]map[string]bool, from string, to string) {
+	if _, ok := dependencies[from]; !ok {
+		dependencies[from] = make(map[string]bool)
+	}
+	dependencies[from][to] = true
+}
+
+// The function finds the circular dependencies between the rules, one for each group of rules that depend
+// on each other, with Tarjan's algorithm. Each cycle is the shortest path from the first rule of the group,
+// by name, back to itself.
+func findCycles(dependencies map[string]map[string]bool) [][]string {
+	cycles := make([][]string, 0)
+
+	index := make(map[string]int)
+	low := make(map[string]int)
+	onStack := make(map[string]bool)
+	stack := make([]string, 0)
+
+	var connect func(name string)
+	connect = func(name string) {
+		index[name] = len(index)
+		low[name] = index[name]
+		stack = append(stack, name)
+		onStack[name] = true
+
+		for _, next := range sortedNames(dependencies[name]) {
+			if _, visited := index[next]; !visited {
+				connect(next)
+				if low[next] < low[name] {
+					low[name] = low[next]
+				}
+			} else if onStack[next] && index[next] < low[name] {
+				low[name] = index[next]
+			}
+		}
+
+		if low[name] != index[name] {
+			return
+		}
+
+		group := make(map[string]bool)
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			group[top] = true
+			if top == name {
+				break
+			}
+		}
+
+		if len(group) > 1 || dependencies[name][name] {
+			cycles = append(cycles, shortestCycle(sortedNames(group)[0], group, dependencies))
+		}
+	}
+
+	names := make(map[string]bool, len(dependencies))
+	for name := range dependencies {
+		names[name] = true
+	}
+	for _, name := range sortedNames(names) {
+		if _, visited := index[name]; !visited {
+			connect(name)
+		}
+	}
+
+	sort.Slice(cycles, func(i, j int) bool {
+		return cycles[i][0] < cycles[j][0]
+	})
+
+	return cycles
+}
+
+// The function returns the shortest path from a rule back to itself through the rules of its group.
+func shortestCycle(start string, group map[string]bool, dependencies map[string]map[string]bool) []string {
+	parents := make(map[string]string)
+	queue := []string{start}
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		for _, next := range sortedNames(dependencies[name]) {
+			if next == start {
+				cycle := []string{start}
+				for current := name; current != start; current = parents[current] {
+					cycle = append(cycle, current)
+				}
+				// the path was built backwards, from the last rule to the first one
+				for i, j := 1, len(cycle)-1; i < j; i, j = i+1, j-1 {
+					cycle[i], cycle[j] = cycle[j], cycle[i]
+				}
+				return append(cycle, start)
+			}
+			if _, visited := parents[next]; !visited && group[next] {
+				parents[next] = name
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return []string{start, start}
+}
+
+// The function returns the names of a set, ordered.
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -2,6 +2,7 @@ package dtos
 
 import (
 	"encoding/json"
+	"time"
 
 	v1 "github.com/bancodobrasil/featws-api/payloads/v1"
 )
@@ -29,19 +30,32 @@ type Rule struct {
 //   - Slug: is a string that represents a unique identifier for the Rulesheet. It is typically used in URLs to identify and access a specific Rulesheet.
 //   - HasStringRule - HasStringRule is a boolean property that indicates whether or not the rulesheet contains a string rule. A string rule is a rule that involves comparing or manipulating strings.
 //   - Version - The version of the rulesheet. It could be a string or a number that represents the version number of the rulesheet. This is useful for tracking changes and updates to the rulesheet over time.
-//   - Features - Features is a pointer to a slice of maps, where each map represents a feature of the rulesheet. Each map contains key-value pairs where the key is a string representing the name of the feature and the value is an interface{} representing the value of the feature.
-//   - Parameters: a pointer to a slice of maps, where each map represents a parameter that can be used in the rules defined in the `Rules` property. Each mapcontains key-value pairs where the key is a string representing the name of the parameter and the value is an interface{}
+//   - BaseVersion - The version the changes of the rulesheet were based on, e.g. from the `If-Match` header of an update. When it's set, the storage backend refuses to save the rulesheet over any other version.
+//   - CommitMessage - The message of the commit of the changes of the rulesheet, like `[FEATWS BOT] Update rule discount`. The default message of the update is used when it's empty.
+//   - RemoveLegacyRules - Whether the legacy rules.featws file is removed when the rulesheet is saved. It's only set by the conversion of its string rules to rules.json, so the other changes keep the file.
+//   - Features - the features declared by the rulesheet, committed on its features.json file.
+//   - Parameters: the parameters declared by the rulesheet, that can be used in the rules defined in the `Rules` property, committed on its parameters.json file.
+//   - Tests: the test cases of the rulesheet, committed on its tests.json file. It's nil when the test cases weren't given, so the ones already committed are kept.
+//   - CreatedAt, UpdatedAt: the moments the rulesheet was created and last updated on the repository. They're nil when the rulesheet wasn't loaded from it.
+//   - Sync: the status of the last change of the rulesheet written on the outbox, pushed to the storage backend by the sync worker. It's nil when the status wasn't loaded or the rulesheet has no changes on the outbox.
 //   - Rules: property is a pointer to a map of string keys and interface values. This map represents the set of rules that are associated with the rulesheet. Each key in the map represents a unique rule identifier, and the corresponding value is an interface that can be usedto store any type of data. The use of `interface` allows for flexibility in the type of data that can be stored in the map.
 type Rulesheet struct {
-	ID            uint
-	Name          string
-	Description   string
-	Slug          string
-	HasStringRule bool
-	Version       string
-	Features      *[]map[string]interface{}
-	Parameters    *[]map[string]interface{}
-	Rules         *map[string]interface{}
+	ID                uint
+	Name              string
+	Description       string
+	Slug              string
+	HasStringRule     bool
+	Version           string
+	BaseVersion       string
+	CommitMessage     string
+	RemoveLegacyRules bool
+	Features          *[]Feature
+	Parameters        *[]Parameter
+	Rules             *map[string]interface{}
+	Tests             *[]TestCase
+	Sync              *Sync
+	CreatedAt         *time.Time
+	UpdatedAt         *time.Time
 }
 
 // NewRulesheetV1 takes in a payload of rulesheet and returns a DTO with the rules converted to a
@@ -54,8 +68,9 @@ func NewRulesheetV1(payload v1.Rulesheet) (dto Rulesheet, err error) {
 		Description: payload.Description,
 		Slug:        payload.Slug,
 		Version:     payload.Version,
-		Features:    payload.Features,
-		Parameters:  payload.Parameters,
+		Features:    newFeaturesV1(payload.Features),
+		Parameters:  newParametersV1(payload.Parameters),
+		Tests:       newTestCasesV1(payload.Tests),
 	}
 
 	isRule := false
@@ -71,7 +86,8 @@ func NewRulesheetV1(payload v1.Rulesheet) (dto Rulesheet, err error) {
 		}
 	}
 
-	dto.HasStringRule = !isRule
+	// a rulesheet without rules has no string rules either
+	dto.HasStringRule = !isRule && len(*payload.Rules) > 0
 
 	// FIXME - Remover restricao de exclusividade entre regras string e complexas
 	if dto.HasStringRule {
@@ -91,6 +107,74 @@ func NewRulesheetV1(payload v1.Rulesheet) (dto Rulesheet, err error) {
 	return
 }
 
+// The function copies the features of a payload.
+func newFeaturesV1(payload *[]v1.Feature) *[]Feature {
+	if payload == nil {
+		return nil
+	}
+
+	features := make([]Feature, len(*payload))
+	for index, feature := range *payload {
+		features[index] = Feature{
+			Name:        feature.Name,
+			Type:        feature.Type,
+			Description: feature.Description,
+			Default:     feature.Default,
+			Extra:       feature.Extra,
+		}
+	}
+
+	return &features
+}
+
+// The function copies the parameters of a payload.
+func newParametersV1(payload *[]v1.Parameter) *[]Parameter {
+	if payload == nil {
+		return nil
+	}
+
+	parameters := make([]Parameter, len(*payload))
+	for index, parameter := range *payload {
+		parameters[index] = Parameter{
+			Name:        parameter.Name,
+			Type:        parameter.Type,
+			Description: parameter.Description,
+			Default:     parameter.Default,
+			Required:    parameter.Required,
+			Resolver:    parameter.Resolver,
+			Extra:       parameter.Extra,
+		}
+	}
+
+	return &parameters
+}
+
+// The function copies the test cases of a payload.
+func newTestCasesV1(payload *[]v1.TestCase) *[]TestCase {
+	if payload == nil {
+		return nil
+	}
+
+	tests := make([]TestCase, len(*payload))
+	for index, testCase := range *payload {
+		tests[index] = TestCase{
+			Name:       testCase.Name,
+			Parameters: testCase.Parameters,
+			Expected:   testCase.Expected,
+		}
+	}
+
+	return &tests
+}
+
+// The function tells if a map of the rules of a rulesheet is a rule, which has a `value` or a `dynamic`,
+// rather than a map of nested rules.
+func isRuleMap(value map[string]interface{}) bool {
+	_, hasValue := value["value"]
+	_, hasDynamic := value["dynamic"]
+	return hasValue || hasDynamic
+}
+
 // The function takes in an interface and recursively builds a rule based on its type.
 func buildRule(v interface{}) (interface{}, error) {
 	switch value := v.(type) {
@@ -108,7 +192,7 @@ func buildRule(v interface{}) (interface{}, error) {
 	case map[string]interface{}:
 		//fmt.Println("MAP INTERFACE", value)
 
-		if _, ok := value["value"]; !ok {
+		if !isRuleMap(value) {
 			mapp := make(map[string]interface{}, 0)
 			for k, item := range value {
 				itemRule, err := buildRule(item)
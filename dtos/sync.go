@@ -0,0 +1,30 @@
+package dtos
+
+import "time"
+
+// Sync represents the sync of a change of a rulesheet with the storage backend, written on the outbox when
+// the rulesheet is changed and pushed by the sync worker.
+//
+// Property:
+//   - ID: the ID of the sync on the outbox.
+//   - RulesheetID: the ID of the rulesheet that was changed.
+//   - CommitMessage: the commit message used when the change is saved on the storage backend.
+//   - Status: the status of the sync, `pending`, `synced`, `failed` or `discarded`.
+//   - Attempts: the number of attempts to push the change that failed.
+//   - Error: the error of the last failed attempt.
+//   - Version: the version published on the storage backend when the change was synced.
+//   - NextAttemptAt: the moment after which a pending sync is attempted again.
+//   - CreatedAt: the moment the change was written on the outbox.
+//   - SyncedAt: the moment the change was synced.
+type Sync struct {
+	ID            uint
+	RulesheetID   uint
+	CommitMessage string
+	Status        string
+	Attempts      int
+	Error         string
+	Version       string
+	NextAttemptAt *time.Time
+	CreatedAt     *time.Time
+	SyncedAt      *time.Time
+}
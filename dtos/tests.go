@@ -0,0 +1,126 @@
+package dtos
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// TestCase is a test case of a rulesheet, committed on its tests.json file.
+//
+// Property:
+//   - Name: the name of the test case.
+//   - Parameters: the values of the parameters the rules are evaluated with, by name.
+//   - Expected: the expected values of the features, by name. The features that aren't listed aren't checked.
+type TestCase struct {
+	Name       string                 `json:"name"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Expected   map[string]interface{} `json:"expected"`
+}
+
+// TestFailure is a feature whose computed value doesn't match the value expected by a test case.
+//
+// Property:
+//   - Feature: the name of the feature.
+//   - Expected: the value expected by the test case.
+//   - Actual: the value computed by the rules. It's nil when the rules of the feature weren't matched or failed.
+type TestFailure struct {
+	Feature  string
+	Expected interface{}
+	Actual   interface{}
+}
+
+// TestResult is the result of running a test case of a rulesheet.
+//
+// Property:
+//   - Name: the name of the test case.
+//   - Passed: whether every expected feature matched the computed value.
+//   - Failures: the features whose computed values don't match the expected ones, ordered by name.
+//   - Errors: the problems found while computing the rules for the parameters of the test case.
+type TestResult struct {
+	Name     string
+	Passed   bool
+	Failures []*TestFailure
+	Errors   []*RuleError
+}
+
+// TestRun is the result of running the test cases of a rulesheet.
+//
+// Property:
+//   - Version: the version of the rulesheet the test cases were run against.
+//   - Passed: the number of test cases that passed.
+//   - Failed: the number of test cases that failed.
+//   - Results: the result of each test case, in the order they're declared.
+type TestRun struct {
+	Version string
+	Passed  int
+	Failed  int
+	Results []*TestResult
+}
+
+// RunTests runs the test cases of a rulesheet, evaluating its rules with the parameters of each case using
+// the `EvaluateRulesheet` function and comparing the computed features with the expected ones. The values
+// are compared by their JSON representation, so a number matches regardless of the Go type it was decoded
+// as.
+func RunTests(rulesheet *Rulesheet) (*TestRun, error) {
+	run := &TestRun{
+		Version: rulesheet.Version,
+		Results: make([]*TestResult, 0),
+	}
+
+	if rulesheet.Tests == nil {
+		return run, nil
+	}
+
+	for _, testCase := range *rulesheet.Tests {
+		evaluation := EvaluateRulesheet(rulesheet, testCase.Parameters)
+
+		result := &TestResult{
+			Name:     testCase.Name,
+			Passed:   true,
+			Failures: make([]*TestFailure, 0),
+			Errors:   evaluation.Errors,
+		}
+
+		features := make([]string, 0, len(testCase.Expected))
+		for feature := range testCase.Expected {
+			features = append(features, feature)
+		}
+		sort.Strings(features)
+
+		for _, feature := range features {
+			expected := testCase.Expected[feature]
+			actual := evaluation.Features[feature]
+
+			equal, err := equalJSON(expected, actual)
+			if err != nil {
+				return nil, err
+			}
+			if !equal {
+				result.Passed = false
+				result.Failures = append(result.Failures, &TestFailure{Feature: feature, Expected: expected, Actual: actual})
+			}
+		}
+
+		if result.Passed {
+			run.Passed++
+		} else {
+			run.Failed++
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	return run, nil
+}
+
+// The function checks if two values have the same JSON representation.
+func equalJSON(a interface{}, b interface{}) (bool, error) {
+	aJSON, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	bJSON, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return string(aJSON) == string(bJSON), nil
+}
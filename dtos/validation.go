@@ -0,0 +1,415 @@
+package dtos
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bancodobrasil/featws-api/expressions"
+)
+
+// The tags of the problems found on the rules of a rulesheet.
+const (
+	RuleErrorExpression = "expression"
+	RuleErrorUndeclared = "undeclared"
+	RuleErrorUnused     = "unused"
+	RuleErrorType       = "type"
+	RuleErrorCycle      = "cycle"
+	RuleErrorReference  = "reference"
+)
+
+// referencePattern matches the string values of the rules, which aren't expressions, that are a reference
+// to a parameter or feature, like `#limit`. A string value is a reference only when it's a single reference
+// to a declared parameter or feature.
+var referencePattern = regexp.MustCompile(`^[$#][\pL_][\pL\pN_]*$`)
+
+// textReferencePattern matches what looks like a reference among other text on a string value, like
+// `Hello $name`, which is kept as text.
+var textReferencePattern = regexp.MustCompile(`[$#][\pL_][\pL\pN_]*`)
+
+// RuleError is a problem found on the rules of a rulesheet.
+//
+// Property:
+//   - Path: the path of the problem on the rulesheet, like `rules.discount[2].condition` or `parameters[0]`.
+//   - Position: the position of the problem on the expression, counted in characters from 1, or zero when it isn't on an expression.
+//   - Tag: the kind of the problem: `expression` for a syntax error, `undeclared` for a reference to a parameter or feature that isn't declared, `unused` for a parameter that isn't referenced by any rule, `type` for a value or expression whose type doesn't match the declared one, `cycle` for a rule that depends on itself through the features it references and `reference` for what looks like a reference on a string value that is kept as text, like `#FF0000` or `https://host/#section`.
+//   - Message: the description of the problem.
+//   - Warning: whether the problem is only a warning, which doesn't prevent the rulesheet from being saved.
+type RuleError struct {
+	Path     string
+	Position int
+	Tag      string
+	Message  string
+	Warning  bool
+}
+
+// The kinds of the expressions found on the rules of a rulesheet.
+const (
+	expressionCondition = "condition"
+	expressionDynamic   = "dynamic"
+	expressionValue     = "value"
+)
+
+// ruleExpression is an expression, or a value, found on the rules of a rulesheet.
+//
+// Property:
+//   - path: the path of the expression on the rulesheet.
+//   - kind: whether it's the condition, the dynamic value or the value of the rule.
+//   - feature: the name of the rule where it's found, which is the feature it defines.
+//   - rule: the rule where it's found.
+type ruleExpression struct {
+	path    string
+	kind    string
+	feature string
+	rule    *Rule
+}
+
+// ValidateRules checks the rules of a rulesheet and returns the problems found, ordered by path. The
+// condition and dynamic expressions must be valid, and every parameter or feature they reference must be
+// declared on `Parameters` or `Features` or be defined by another rule. A string value references a
+// parameter or feature only when it's a single reference to a declared one, and what looks like a reference
+// on the other strings is returned as a warning, since it's kept as text. The type of each value or
+// expression must match the type declared on the rule or on its feature, and the conditions must be
+// boolean. The rules can't depend on themselves, directly or through the rules of the features they
+// reference. The parameters that aren't referenced by any rule are returned as warnings.
+func ValidateRules(rulesheet *Rulesheet) []*RuleError {
+	result := make([]*RuleError, 0)
+
+	if rulesheet.Rules == nil {
+		return result
+	}
+
+	env := rulesheetEnv(rulesheet)
+	parameters, features := env.Params, env.Features
+
+	used := make(map[string]bool)
+	dependencies := make(map[string]map[string]bool)
+
+	found := make([]ruleExpression, 0)
+	for name, rule := range *rulesheet.Rules {
+		found = append(found, collectExpressions(name, "rules."+name, rule)...)
+	}
+
+	for _, expr := range found {
+		problems, refs := checkExpression(expr, env)
+		result = append(result, problems...)
+
+		for _, ref := range refs {
+			if ref.param {
+				used[ref.name] = true
+			}
+			if _, ok := (*rulesheet.Rules)[ref.name]; !ref.param && ok {
+				addDependency(dependencies, expr.feature, ref.name)
+			}
+			if _, ok := parameters[ref.name]; ref.param && !ok {
+				result = append(result, &RuleError{
+					Path:     expr.path,
+					Position: ref.position,
+					Tag:      RuleErrorUndeclared,
+					Message:  fmt.Sprintf("the parameter '%s' isn't declared", ref.name),
+				})
+			}
+			if _, ok := features[ref.name]; !ref.param && !ok {
+				result = append(result, &RuleError{
+					Path:     expr.path,
+					Position: ref.position,
+					Tag:      RuleErrorUndeclared,
+					Message:  fmt.Sprintf("the feature '%s' isn't declared nor defined by a rule", ref.name),
+				})
+			}
+		}
+	}
+
+	for _, cycle := range findCycles(dependencies) {
+		result = append(result, &RuleError{
+			Path:    "rules." + cycle[0],
+			Tag:     RuleErrorCycle,
+			Message: fmt.Sprintf("the rule '%s' depends on itself: %s", cycle[0], strings.Join(cycle, " -> ")),
+		})
+	}
+
+	if rulesheet.Parameters != nil {
+		for index, parameter := range *rulesheet.Parameters {
+			name := parameter.Name
+			if name != "" && !used[name] {
+				result = append(result, &RuleError{
+					Path:    fmt.Sprintf("parameters[%d]", index),
+					Tag:     RuleErrorUnused,
+					Message: fmt.Sprintf("the parameter '%s' isn't used by any rule", name),
+					Warning: true,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Path != result[j].Path {
+			return result[i].Path < result[j].Path
+		}
+		return result[i].Position < result[j].Position
+	})
+
+	return result
+}
+
+// The function returns the types of the parameters and features of a rulesheet, where the features are the
+// declared ones and the ones defined by its rules.
+func rulesheetEnv(rulesheet *Rulesheet) expressions.Env {
+	parameters := declaredTypes(rulesheet.Parameters)
+	features := declaredTypes(rulesheet.Features)
+	if rulesheet.Rules != nil {
+		for name, rule := range *rulesheet.Rules {
+			if _, ok := features[name]; !ok || features[name] == expressions.TypeAny {
+				features[name] = ruleType(rule)
+			}
+		}
+	}
+	return expressions.Env{Params: parameters, Features: features}
+}
+
+// The function returns the types of the declared parameters or features, by name. The ones declared without
+// a known type are of the `any` type.
+func declaredTypes(declarations interface{}) map[string]string {
+	types := make(map[string]string)
+
+	declare := func(name, declared string) {
+		types[name] = expressions.TypeAny
+		if t, ok := expressions.NormalizeType(declared); ok {
+			types[name] = t
+		}
+	}
+
+	switch list := declarations.(type) {
+	case *[]Feature:
+		if list != nil {
+			for _, feature := range *list {
+				declare(feature.Name, feature.Type)
+			}
+		}
+	case *[]Parameter:
+		if list != nil {
+			for _, parameter := range *list {
+				declare(parameter.Name, parameter.Type)
+			}
+		}
+	}
+
+	return types
+}
+
+// The function returns the type declared on a rule, or on the first of its nested rules that declares one,
+// or `any` if there's none.
+func ruleType(v interface{}) string {
+	switch value := v.(type) {
+	case *Rule:
+		if t, ok := expressions.NormalizeType(value.Type); ok {
+			return t
+		}
+	case []interface{}:
+		for _, item := range value {
+			if t := ruleType(item); t != expressions.TypeAny {
+				return t
+			}
+		}
+	}
+	return expressions.TypeAny
+}
+
+// The function recursively collects the expressions and values of a rule, which can be nested on lists and
+// maps.
+func collectExpressions(feature string, path string, v interface{}) []ruleExpression {
+	result := make([]ruleExpression, 0)
+
+	switch value := v.(type) {
+	case *Rule:
+		if value.Condition != "" {
+			result = append(result, ruleExpression{path: path + ".condition", kind: expressionCondition, feature: feature, rule: value})
+		}
+		if value.Dynamic != "" {
+			result = append(result, ruleExpression{path: path + ".dynamic", kind: expressionDynamic, feature: feature, rule: value})
+		} else if value.Value != nil {
+			result = append(result, ruleExpression{path: path + ".value", kind: expressionValue, feature: feature, rule: value})
+		}
+	case []interface{}:
+		for index, item := range value {
+			result = append(result, collectExpressions(feature, fmt.Sprintf("%s[%d]", path, index), item)...)
+		}
+	case map[string]interface{}:
+		for key, item := range value {
+			result = append(result, collectExpressions(feature, path+"."+key, item)...)
+		}
+	}
+
+	return result
+}
+
+// reference is a reference to a parameter or feature found on an expression.
+type reference struct {
+	name     string
+	param    bool
+	position int
+}
+
+// The function checks an expression, or a value, of a rule and returns its problems and the parameters and
+// features it references. The expressions are parsed and their types are checked, and the string values,
+// which aren't expressions, reference a parameter or feature only when they're a single reference to a
+// declared one. What looks like a reference on the other strings is reported as a warning.
+func checkExpression(expr ruleExpression, env expressions.Env) ([]*RuleError, []reference) {
+	refs := make([]reference, 0)
+
+	expected, ok := expressions.NormalizeType(expr.rule.Type)
+	if !ok {
+		expected = lookupFeatureType(env, expr.feature)
+	}
+
+	if expr.kind == expressionValue {
+		var problems []*RuleError
+		actual := expressions.TypeOf(expr.rule.Value)
+
+		if s, ok := expr.rule.Value.(string); ok {
+			refs = valueReferences(s, env)
+
+			// a value that is a single reference has the type of the referenced parameter or feature
+			if len(refs) == 1 {
+				actual = env.Features[refs[0].name]
+				if refs[0].param {
+					actual = env.Params[refs[0].name]
+				}
+			} else {
+				problems = textReferenceWarnings(expr.path, s, env)
+				if expected == expressions.TypeDate && expressions.IsDate(s) {
+					actual = expressions.TypeDate
+				}
+			}
+		}
+
+		if actual != "" && !expressions.IsAssignable(expected, actual) {
+			problems = append(problems, &RuleError{Path: expr.path, Tag: RuleErrorType, Message: fmt.Sprintf("the value must be %s but it's %s", expected, actual)})
+		}
+		return problems, refs
+	}
+
+	source := expr.rule.Condition
+	if expr.kind == expressionDynamic {
+		source = expr.rule.Dynamic
+	} else {
+		expected = expressions.TypeBoolean
+	}
+
+	node, err := expressions.Parse(source)
+	if err != nil {
+		return []*RuleError{newExpressionError(expr.path, RuleErrorExpression, err)}, nil
+	}
+
+	refs = nodeReferences(node)
+
+	actual, err := expressions.Check(node, env)
+	if err != nil {
+		return []*RuleError{newExpressionError(expr.path, RuleErrorType, err)}, refs
+	}
+
+	if !expressions.IsAssignable(expected, actual) {
+		return []*RuleError{{Path: expr.path, Position: 1, Tag: RuleErrorType, Message: fmt.Sprintf("the %s must be %s but it's %s", expr.kind, expected, actual)}}, refs
+	}
+
+	return nil, refs
+}
+
+// The function returns the reference of a string value, which isn't an expression, if it's a single
+// reference to a declared parameter or feature, like `#limit`. The other strings, even with references among
+// other text, are kept as text, so they have no references.
+func valueReferences(s string, env expressions.Env) []reference {
+	refs := make([]reference, 0)
+	if isValueReference(s, env) {
+		refs = append(refs, reference{
+			name:     s[1:],
+			param:    s[0] == '$',
+			position: 1,
+		})
+	}
+	return refs
+}
+
+// The function checks whether a string value is a single reference to a declared parameter or feature.
+func isValueReference(s string, env expressions.Env) bool {
+	if !referencePattern.MatchString(s) {
+		return false
+	}
+	declared := env.Features
+	if s[0] == '$' {
+		declared = env.Params
+	}
+	_, ok := declared[s[1:]]
+	return ok
+}
+
+// The function returns a warning for each text that looks like a reference on a string value that is kept as
+// text, like `#section` on `https://host/#section` or `#FF0000`, which isn't a declared feature.
+func textReferenceWarnings(path string, s string, env expressions.Env) []*RuleError {
+	var result []*RuleError
+	for _, loc := range textReferencePattern.FindAllStringIndex(s, -1) {
+		result = append(result, &RuleError{
+			Path:     path,
+			Position: len([]rune(s[:loc[0]])) + 1,
+			Tag:      RuleErrorReference,
+			Message:  fmt.Sprintf("'%s' is kept as text, since only a value that is a single reference to a declared parameter or feature is resolved", s[loc[0]:loc[1]]),
+			Warning:  true,
+		})
+	}
+	return result
+}
+
+// The function returns the references to parameters and features found on a parsed expression.
+func nodeReferences(node expressions.Node) []reference {
+	refs := make([]reference, 0)
+	expressions.Walk(node, func(n expressions.Node) {
+		switch ref := n.(type) {
+		case *expressions.Param:
+			refs = append(refs, reference{name: ref.Name, param: true, position: ref.Position})
+		case *expressions.Feature:
+			refs = append(refs, reference{name: ref.Name, position: ref.Position})
+		}
+	})
+	return refs
+}
+
+// The function returns the references of an expression, or a value, of a rule without checking it. The
+// expressions that can't be parsed have no references.
+func expressionReferences(expr ruleExpression, env expressions.Env) []reference {
+	if expr.kind == expressionValue {
+		if s, ok := expr.rule.Value.(string); ok {
+			return valueReferences(s, env)
+		}
+		return nil
+	}
+
+	source := expr.rule.Condition
+	if expr.kind == expressionDynamic {
+		source = expr.rule.Dynamic
+	}
+
+	node, err := expressions.Parse(source)
+	if err != nil {
+		return nil
+	}
+	return nodeReferences(node)
+}
+
+// The function returns the type of a feature, or `any` if it isn't declared.
+func lookupFeatureType(env expressions.Env, name string) string {
+	if t, ok := env.Features[name]; ok {
+		return t
+	}
+	return expressions.TypeAny
+}
+
+// The function returns the problem of an expression from the error of its parsing or type checking.
+func newExpressionError(path string, tag string, err error) *RuleError {
+	var exprErr *expressions.Error
+	if errors.As(err, &exprErr) {
+		return &RuleError{Path: path, Position: exprErr.Position, Tag: tag, Message: exprErr.Message}
+	}
+	return &RuleError{Path: path, Tag: tag, Message: err.Error()}
+}
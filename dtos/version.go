@@ -0,0 +1,21 @@
+package dtos
+
+import "time"
+
+// Version represents a published version of a rulesheet, as recorded by the commit that bumped its VERSION file.
+//
+// Property:
+//   - Number: the content of the VERSION file at the commit, i.e. the sequential version number of the rulesheet.
+//   - Commit: the SHA of the commit that published the version.
+//   - Author: the name of the author of the commit.
+//   - AuthorEmail: the email of the author of the commit.
+//   - Date: the moment the commit was authored.
+//   - Message: the commit message used when the version was published.
+type Version struct {
+	Number      string
+	Commit      string
+	Author      string
+	AuthorEmail string
+	Date        *time.Time
+	Message     string
+}
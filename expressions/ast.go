@@ -0,0 +1,135 @@
+package expressions
+
+// Node is a node of the syntax tree of an expression. Pos is the position of the node on the expression,
+// counted in characters from 1.
+type Node interface {
+	Pos() int
+}
+
+// Literal is a number, string, boolean or nil written on the expression. The numbers are parsed as `int64`
+// when they have no decimal part and as `float64` otherwise.
+type Literal struct {
+	Position int
+	Value    interface{}
+}
+
+// Param is a reference to a parameter of the rulesheet, written as `$name`.
+type Param struct {
+	Position int
+	Name     string
+}
+
+// Feature is a reference to a feature of the rulesheet, written as `#name`.
+type Feature struct {
+	Position int
+	Name     string
+}
+
+// Ident is a bare identifier, like the name of a function.
+type Ident struct {
+	Position int
+	Name     string
+}
+
+// Unary is an operation with a single operand, like `!a` or `-a`.
+type Unary struct {
+	Position int
+	Op       string
+	X        Node
+}
+
+// Binary is an operation with two operands, like `a && b` or `a + b`.
+type Binary struct {
+	Position int
+	Op       string
+	X        Node
+	Y        Node
+}
+
+// Call is a call of a function or method, like `f(a, b)` or `$list.contains(a)`.
+type Call struct {
+	Position int
+	Fun      Node
+	Args     []Node
+}
+
+// Member is the access to a member of a value, like `$customer.name`.
+type Member struct {
+	Position int
+	X        Node
+	Name     string
+}
+
+// Index is the access to an item of a list or map, like `$list[0]` or `$map["key"]`.
+type Index struct {
+	Position int
+	X        Node
+	Index    Node
+}
+
+// List is a list written on the expression, like `[1, 2, 3]`.
+type List struct {
+	Position int
+	Items    []Node
+}
+
+// Pos returns the position of the literal.
+func (n *Literal) Pos() int { return n.Position }
+
+// Pos returns the position of the parameter.
+func (n *Param) Pos() int { return n.Position }
+
+// Pos returns the position of the feature.
+func (n *Feature) Pos() int { return n.Position }
+
+// Pos returns the position of the identifier.
+func (n *Ident) Pos() int { return n.Position }
+
+// Pos returns the position of the operator.
+func (n *Unary) Pos() int { return n.Position }
+
+// Pos returns the position of the operator.
+func (n *Binary) Pos() int { return n.Position }
+
+// Pos returns the position of the opening parenthesis.
+func (n *Call) Pos() int { return n.Position }
+
+// Pos returns the position of the name of the member.
+func (n *Member) Pos() int { return n.Position }
+
+// Pos returns the position of the opening bracket.
+func (n *Index) Pos() int { return n.Position }
+
+// Pos returns the position of the opening bracket.
+func (n *List) Pos() int { return n.Position }
+
+// Walk calls the function for the node and each of its descendants, in depth-first order.
+func Walk(node Node, fn func(Node)) {
+	if node == nil {
+		return
+	}
+
+	fn(node)
+
+	switch n := node.(type) {
+	case *Unary:
+		Walk(n.X, fn)
+	case *Binary:
+		Walk(n.X, fn)
+		Walk(n.Y, fn)
+	case *Call:
+		Walk(n.Fun, fn)
+		for _, arg := range n.Args {
+			Walk(arg, fn)
+		}
+	case *Member:
+		Walk(n.X, fn)
+	case *Index:
+		Walk(n.X, fn)
+		Walk(n.Index, fn)
+	case *List:
+		for _, item := range n.Items {
+			Walk(item, fn)
+		}
+	}
+}
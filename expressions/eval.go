@@ -0,0 +1,412 @@
+package expressions
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Resolver resolves the values of the parameters and features referenced by an expression.
+//
+// Property:
+//   - Param: returns the value of a parameter, or nil if it wasn't given.
+//   - Feature: returns the value of a feature, computing its rule if needed.
+type Resolver interface {
+	Param(name string) interface{}
+	Feature(name string) (interface{}, error)
+}
+
+// functions are the functions that can be called on the expressions, like `max($a, $b)`.
+var functions = map[string]func(args []interface{}) (interface{}, error){
+	"len": func(args []interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("len expects 1 argument but got %d", len(args))
+		}
+		return length(args[0])
+	},
+	"min": func(args []interface{}) (interface{}, error) {
+		return reduceNumbers("min", args, math.Min)
+	},
+	"max": func(args []interface{}) (interface{}, error) {
+		return reduceNumbers("max", args, math.Max)
+	},
+	"abs": func(args []interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("abs expects 1 argument but got %d", len(args))
+		}
+		n, ok := toNumber(args[0])
+		if !ok {
+			return nil, fmt.Errorf("abs expects a number but got %v", args[0])
+		}
+		return math.Abs(n), nil
+	},
+	"lower": stringFunction("lower", strings.ToLower),
+	"upper": stringFunction("upper", strings.ToUpper),
+}
+
+// Evaluate computes the value of an expression, resolving its parameters and features. The numbers are
+// computed as `float64`. The error is an `*Error` with the position of the operation that failed.
+func Evaluate(node Node, resolver Resolver) (interface{}, error) {
+	switch n := node.(type) {
+	case *Literal:
+		if i, ok := n.Value.(int64); ok {
+			return float64(i), nil
+		}
+		return n.Value, nil
+
+	case *Param:
+		return normalize(resolver.Param(n.Name)), nil
+
+	case *Feature:
+		value, err := resolver.Feature(n.Name)
+		if err != nil {
+			return nil, &Error{Position: n.Position, Message: err.Error()}
+		}
+		return normalize(value), nil
+
+	case *List:
+		items := make([]interface{}, 0, len(n.Items))
+		for _, item := range n.Items {
+			value, err := Evaluate(item, resolver)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+		}
+		return items, nil
+
+	case *Unary:
+		x, err := Evaluate(n.X, resolver)
+		if err != nil {
+			return nil, err
+		}
+		if n.Op == "!" {
+			b, ok := x.(bool)
+			if !ok {
+				return nil, evalError(n, "the operand of '!' must be boolean but it's %v", x)
+			}
+			return !b, nil
+		}
+		f, ok := toNumber(x)
+		if !ok {
+			return nil, evalError(n, "the operand of '%s' must be a number but it's %v", n.Op, x)
+		}
+		if n.Op == "-" {
+			return -f, nil
+		}
+		return f, nil
+
+	case *Binary:
+		return evaluateBinary(n, resolver)
+
+	case *Call:
+		return evaluateCall(n, resolver)
+
+	case *Member:
+		x, err := Evaluate(n.X, resolver)
+		if err != nil {
+			return nil, err
+		}
+		m, ok := x.(map[string]interface{})
+		if !ok {
+			return nil, evalError(n, "can't read '%s' of %v", n.Name, x)
+		}
+		return normalize(m[n.Name]), nil
+
+	case *Index:
+		x, err := Evaluate(n.X, resolver)
+		if err != nil {
+			return nil, err
+		}
+		index, err := Evaluate(n.Index, resolver)
+		if err != nil {
+			return nil, err
+		}
+		switch v := x.(type) {
+		case []interface{}:
+			f, ok := toNumber(index)
+			if !ok || f != math.Trunc(f) || f < 0 || int(f) >= len(v) {
+				return nil, evalError(n, "invalid index %v of a list of %d items", index, len(v))
+			}
+			return normalize(v[int(f)]), nil
+		case map[string]interface{}:
+			return normalize(v[fmt.Sprint(index)]), nil
+		}
+		return nil, evalError(n, "only a list or map can be indexed but it's %v", x)
+
+	case *Ident:
+		return nil, evalError(n, "unknown name '%s'", n.Name)
+	}
+
+	return nil, fmt.Errorf("unknown expression node %T", node)
+}
+
+// The function computes a binary operation. The `&&` and `||` operators don't compute their right operand
+// when the left one decides the result.
+func evaluateBinary(n *Binary, resolver Resolver) (interface{}, error) {
+	x, err := Evaluate(n.X, resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.Op == "&&" || n.Op == "||" {
+		bx, ok := x.(bool)
+		if !ok {
+			return nil, evalError(n, "the operands of '%s' must be boolean but found %v", n.Op, x)
+		}
+		if (n.Op == "&&" && !bx) || (n.Op == "||" && bx) {
+			return bx, nil
+		}
+		y, err := Evaluate(n.Y, resolver)
+		if err != nil {
+			return nil, err
+		}
+		by, ok := y.(bool)
+		if !ok {
+			return nil, evalError(n, "the operands of '%s' must be boolean but found %v", n.Op, y)
+		}
+		return by, nil
+	}
+
+	y, err := Evaluate(n.Y, resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.Op {
+	case "==":
+		return reflect.DeepEqual(x, y), nil
+	case "!=":
+		return !reflect.DeepEqual(x, y), nil
+	case "<", "<=", ">", ">=":
+		cmp, err := compare(x, y)
+		if err != nil {
+			return nil, evalError(n, "%s", err.Error())
+		}
+		switch n.Op {
+		case "<":
+			return cmp < 0, nil
+		case "<=":
+			return cmp <= 0, nil
+		case ">":
+			return cmp > 0, nil
+		}
+		return cmp >= 0, nil
+	}
+
+	if n.Op == "+" {
+		sx, xIsString := x.(string)
+		sy, yIsString := y.(string)
+		if xIsString || yIsString {
+			if !xIsString {
+				sx = fmt.Sprint(x)
+			}
+			if !yIsString {
+				sy = fmt.Sprint(y)
+			}
+			return sx + sy, nil
+		}
+	}
+
+	fx, okX := toNumber(x)
+	fy, okY := toNumber(y)
+	if !okX || !okY {
+		return nil, evalError(n, "the operands of '%s' must be numbers but they're %v and %v", n.Op, x, y)
+	}
+
+	switch n.Op {
+	case "+":
+		return fx + fy, nil
+	case "-":
+		return fx - fy, nil
+	case "*":
+		return fx * fy, nil
+	case "/":
+		if fy == 0 {
+			return nil, evalError(n, "division by zero")
+		}
+		return fx / fy, nil
+	}
+
+	if fy == 0 {
+		return nil, evalError(n, "division by zero")
+	}
+	return math.Mod(fx, fy), nil
+}
+
+// The function calls a function, like `max($a, $b)`, or a method of a value, like `$tags.contains('a')`.
+func evaluateCall(n *Call, resolver Resolver) (interface{}, error) {
+	args := make([]interface{}, 0, len(n.Args))
+	for _, arg := range n.Args {
+		value, err := Evaluate(arg, resolver)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, value)
+	}
+
+	switch fun := n.Fun.(type) {
+	case *Ident:
+		function, ok := functions[fun.Name]
+		if !ok {
+			return nil, evalError(fun, "unknown function '%s'", fun.Name)
+		}
+		result, err := function(args)
+		if err != nil {
+			return nil, evalError(n, "%s", err.Error())
+		}
+		return result, nil
+
+	case *Member:
+		receiver, err := Evaluate(fun.X, resolver)
+		if err != nil {
+			return nil, err
+		}
+		result, err := callMethod(receiver, fun.Name, args)
+		if err != nil {
+			return nil, evalError(fun, "%s", err.Error())
+		}
+		return result, nil
+	}
+
+	return nil, evalError(n, "only a function or method can be called")
+}
+
+// The function calls a method of a list or string.
+func callMethod(receiver interface{}, name string, args []interface{}) (interface{}, error) {
+	switch name {
+	case "contains", "startsWith", "endsWith":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s expects 1 argument but got %d", name, len(args))
+		}
+		if list, ok := receiver.([]interface{}); ok && name == "contains" {
+			for _, item := range list {
+				if reflect.DeepEqual(normalize(item), args[0]) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		s, ok := receiver.(string)
+		sub, okSub := args[0].(string)
+		if !ok || !okSub {
+			return nil, fmt.Errorf("%s expects strings but got %v and %v", name, receiver, args[0])
+		}
+		switch name {
+		case "startsWith":
+			return strings.HasPrefix(s, sub), nil
+		case "endsWith":
+			return strings.HasSuffix(s, sub), nil
+		}
+		return strings.Contains(s, sub), nil
+	case "length":
+		return length(receiver)
+	}
+	return nil, fmt.Errorf("unknown method '%s'", name)
+}
+
+// The function compares two numbers or strings, returning a negative number if the first one is the lesser,
+// zero if they're equal and a positive number otherwise. The dates are compared as strings, so they must be
+// written on the same layout.
+func compare(x interface{}, y interface{}) (int, error) {
+	if fx, ok := toNumber(x); ok {
+		if fy, ok := toNumber(y); ok {
+			switch {
+			case fx < fy:
+				return -1, nil
+			case fx > fy:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+
+	sx, okX := x.(string)
+	sy, okY := y.(string)
+	if okX && okY {
+		return strings.Compare(sx, sy), nil
+	}
+
+	return 0, fmt.Errorf("can't compare %v with %v", x, y)
+}
+
+// The function returns the length of a list, map or string.
+func length(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case []interface{}:
+		return float64(len(v)), nil
+	case map[string]interface{}:
+		return float64(len(v)), nil
+	case string:
+		return float64(len([]rune(v))), nil
+	}
+	return nil, fmt.Errorf("can't get the length of %v", value)
+}
+
+// The function reduces the numbers of the arguments of a function, like `min` and `max`.
+func reduceNumbers(name string, args []interface{}, reduce func(float64, float64) float64) (interface{}, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%s expects at least 1 argument", name)
+	}
+
+	result := 0.0
+	for index, arg := range args {
+		n, ok := toNumber(arg)
+		if !ok {
+			return nil, fmt.Errorf("%s expects numbers but got %v", name, arg)
+		}
+		if index == 0 {
+			result = n
+			continue
+		}
+		result = reduce(result, n)
+	}
+
+	return result, nil
+}
+
+// The function returns a function of a single string argument.
+func stringFunction(name string, fn func(string) string) func(args []interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s expects 1 argument but got %d", name, len(args))
+		}
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s expects a string but got %v", name, args[0])
+		}
+		return fn(s), nil
+	}
+}
+
+// The function converts a number of any Go type to `float64`.
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// The function converts the numbers of a value to `float64`, so they're compared as equal regardless of the
+// Go type they were decoded as.
+func normalize(value interface{}) interface{} {
+	if n, ok := toNumber(value); ok {
+		return n
+	}
+	return value
+}
+
+// The function returns the error of an operation that failed.
+func evalError(n Node, format string, args ...interface{}) error {
+	return &Error{Position: n.Pos(), Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,92 @@
+package expressions_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bancodobrasil/featws-api/expressions"
+	"github.com/stretchr/testify/assert"
+)
+
+// resolver resolves the parameters and features used by the evaluation tests.
+type resolver struct {
+	params   map[string]interface{}
+	features map[string]interface{}
+}
+
+func (r resolver) Param(name string) interface{} {
+	return r.params[name]
+}
+
+func (r resolver) Feature(name string) (interface{}, error) {
+	value, ok := r.features[name]
+	if !ok {
+		return nil, fmt.Errorf("the feature '%s' can't be computed", name)
+	}
+	return value, nil
+}
+
+// The function returns the values of the parameters and features used by the evaluation tests.
+func SetupResolver() resolver {
+	return resolver{
+		params: map[string]interface{}{
+			"age":   30,
+			"total": 150.5,
+			"name":  "Ana",
+			"birth": "1990-05-01",
+			"tags":  []interface{}{"a", "b"},
+		},
+		features: map[string]interface{}{
+			"vip": true,
+		},
+	}
+}
+
+// This tests the values computed by the expressions.
+func TestEvaluateSuccess(t *testing.T) {
+	for expression, expected := range map[string]interface{}{
+		"$age >= 18 && #vip":             true,
+		"$age * 2":                       60.0,
+		"$total > 100 || $unknown > 1":   true,
+		"'Mr. ' + $name":                 "Mr. Ana",
+		"$birth < '2000-01-01'":          true,
+		"$tags[1]":                       "b",
+		"$tags.contains('a') && !#vip":   false,
+		"max($age, 40) % 7":              5.0,
+		"$age == 30":                     true,
+		"[1, 2] == [1, 2]":               true,
+		"$unknown == nil":                true,
+		"upper($name).startsWith('AN')":  true,
+		"-(len($tags) + $name.length())": -5.0,
+	} {
+		node, err := expressions.Parse(expression)
+		assert.NoError(t, err, expression)
+
+		actual, err := expressions.Evaluate(node, SetupResolver())
+		assert.NoError(t, err, expression)
+		assert.Equal(t, expected, actual, expression)
+	}
+}
+
+// This tests that the operations that fail are reported with their position.
+func TestEvaluateWithError(t *testing.T) {
+	for expression, position := range map[string]int{
+		"$age / 0":         6,
+		"$name * 2":        7,
+		"$unknown > 1":     10,
+		"$tags[5]":         6,
+		"foo($age)":        1,
+		"#missing && true": 1,
+	} {
+		node, err := expressions.Parse(expression)
+		assert.NoError(t, err, expression)
+
+		_, err = expressions.Evaluate(node, SetupResolver())
+
+		var evalErr *expressions.Error
+		if assert.True(t, errors.As(err, &evalErr), expression) {
+			assert.Equal(t, position, evalErr.Position, expression)
+		}
+	}
+}
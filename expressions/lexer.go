@@ -0,0 +1,153 @@
+package expressions
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenKind is the kind of a token of an expression.
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenParam
+	tokenFeature
+	tokenIdent
+	tokenOperator
+)
+
+// operators are the operators and punctuation of the expressions, the longest ones first, so `<=` is read
+// before `<`.
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "!", "<", ">", "+", "-", "*", "/", "%", "(", ")", "[", "]", ",", "."}
+
+// token is a token of an expression.
+//
+// Property:
+//   - kind: the kind of the token.
+//   - text: the text of the token. For strings, it's the value without the quotes and escapes; for parameters and features, it's the name without the prefix.
+//   - pos: the position where the token starts, counted in characters from 1.
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// The function describes the token on the error messages.
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return fmt.Sprintf("string %q", t.text)
+	case tokenParam:
+		return "$" + t.text
+	case tokenFeature:
+		return "#" + t.text
+	}
+	return fmt.Sprintf("'%s'", t.text)
+}
+
+// The function splits an expression into tokens.
+func tokenize(expression string) ([]token, error) {
+	src := []rune(expression)
+	tokens := make([]token, 0)
+
+	for i := 0; i < len(src); {
+		r := src[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(src) && unicode.IsDigit(src[i]) {
+				i++
+			}
+			if i+1 < len(src) && src[i] == '.' && unicode.IsDigit(src[i+1]) {
+				i++
+				for i < len(src) && unicode.IsDigit(src[i]) {
+					i++
+				}
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(src[start:i]), pos: start + 1})
+
+		case r == '"' || r == '\'':
+			start := i
+			var value strings.Builder
+			i++
+			for ; i < len(src) && src[i] != r; i++ {
+				if src[i] == '\\' && i+1 < len(src) {
+					i++
+					switch src[i] {
+					case 'n':
+						value.WriteRune('\n')
+					case 't':
+						value.WriteRune('\t')
+					default:
+						value.WriteRune(src[i])
+					}
+					continue
+				}
+				value.WriteRune(src[i])
+			}
+			if i >= len(src) {
+				return nil, &Error{Position: start + 1, Message: "unterminated string"}
+			}
+			i++
+			tokens = append(tokens, token{kind: tokenString, text: value.String(), pos: start + 1})
+
+		case r == '$' || r == '#' || isIdentStart(r):
+			start := i
+			kind := tokenIdent
+			if r == '$' {
+				kind = tokenParam
+				i++
+			} else if r == '#' {
+				kind = tokenFeature
+				i++
+			}
+			nameStart := i
+			for i < len(src) && isIdentPart(src[i]) {
+				i++
+			}
+			if i == nameStart || !isIdentStart(src[nameStart]) {
+				return nil, &Error{Position: start + 1, Message: fmt.Sprintf("expected a name after '%c'", r)}
+			}
+			tokens = append(tokens, token{kind: kind, text: string(src[nameStart:i]), pos: start + 1})
+
+		default:
+			end := i + 2
+			if end > len(src) {
+				end = len(src)
+			}
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(string(src[i:end]), candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, &Error{Position: i + 1, Message: fmt.Sprintf("unexpected character '%c'", r)}
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i + 1})
+			i += len(op)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(src) + 1}), nil
+}
+
+// The function checks if the character can start a name.
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+// The function checks if the character can be part of a name.
+func isIdentPart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,261 @@
+package expressions
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Error is a syntax error of an expression.
+//
+// Property:
+//   - Position: the position of the error on the expression, counted in characters from 1.
+//   - Message: the description of the error.
+type Error struct {
+	Position int
+	Message  string
+}
+
+// Error returns the description of the error with its position.
+func (e *Error) Error() string {
+	return fmt.Sprintf("position %d: %s", e.Position, e.Message)
+}
+
+// precedences are the precedences of the binary operators, from the lowest to the highest.
+var precedences = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+// parser reads the syntax tree of an expression from its tokens.
+type parser struct {
+	tokens []token
+	next   int
+}
+
+// Parse parses an expression of a rule, like a condition or a dynamic value, and returns its syntax tree.
+// The expressions reference the parameters as `$name` and the features as `#name`. The error is an `*Error`
+// with the position where the expression is broken.
+func Parse(expression string) (Node, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	if p.peek().kind == tokenEOF {
+		return nil, &Error{Position: 1, Message: "empty expression"}
+	}
+
+	node, err := p.parseBinary(1)
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, &Error{Position: t.pos, Message: fmt.Sprintf("unexpected %s", t)}
+	}
+
+	return node, nil
+}
+
+// The function returns the current token without consuming it.
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+// The function consumes the current token.
+func (p *parser) advance() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEOF {
+		p.next++
+	}
+	return t
+}
+
+// The function checks if the current token is the given operator and consumes it if so.
+func (p *parser) accept(op string) bool {
+	if t := p.peek(); t.kind == tokenOperator && t.text == op {
+		p.next++
+		return true
+	}
+	return false
+}
+
+// The function consumes the given operator, failing if it isn't the current token.
+func (p *parser) expect(op string) error {
+	if !p.accept(op) {
+		t := p.peek()
+		return &Error{Position: t.pos, Message: fmt.Sprintf("expected '%s' but found %s", op, t)}
+	}
+	return nil
+}
+
+// The function parses the binary operations whose operators have at least the given precedence.
+func (p *parser) parseBinary(precedence int) (Node, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		prec, ok := precedences[t.text]
+		if t.kind != tokenOperator || !ok || prec < precedence {
+			return x, nil
+		}
+		p.advance()
+
+		y, err := p.parseBinary(prec + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		x = &Binary{Position: t.pos, Op: t.text, X: x, Y: y}
+	}
+}
+
+// The function parses the unary operations.
+func (p *parser) parseUnary() (Node, error) {
+	t := p.peek()
+	if t.kind == tokenOperator && (t.text == "!" || t.text == "-" || t.text == "+") {
+		p.advance()
+
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &Unary{Position: t.pos, Op: t.text, X: x}, nil
+	}
+
+	return p.parsePostfix()
+}
+
+// The function parses the member accesses, calls and indexes that follow an operand.
+func (p *parser) parsePostfix() (Node, error) {
+	x, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+
+		switch {
+		case p.accept("."):
+			name := p.advance()
+			if name.kind != tokenIdent {
+				return nil, &Error{Position: name.pos, Message: fmt.Sprintf("expected a name after '.' but found %s", name)}
+			}
+			x = &Member{Position: name.pos, X: x, Name: name.text}
+
+		case p.accept("("):
+			args, err := p.parseList(")")
+			if err != nil {
+				return nil, err
+			}
+			x = &Call{Position: t.pos, Fun: x, Args: args}
+
+		case p.accept("["):
+			index, err := p.parseBinary(1)
+			if err != nil {
+				return nil, err
+			}
+			if err = p.expect("]"); err != nil {
+				return nil, err
+			}
+			x = &Index{Position: t.pos, X: x, Index: index}
+
+		default:
+			return x, nil
+		}
+	}
+}
+
+// The function parses a list of expressions separated by commas, until the closing operator.
+func (p *parser) parseList(closing string) ([]Node, error) {
+	items := make([]Node, 0)
+
+	if p.accept(closing) {
+		return items, nil
+	}
+
+	for {
+		item, err := p.parseBinary(1)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+
+		if p.accept(closing) {
+			return items, nil
+		}
+		if !p.accept(",") {
+			t := p.peek()
+			return nil, &Error{Position: t.pos, Message: fmt.Sprintf("expected ',' or '%s' but found %s", closing, t)}
+		}
+	}
+}
+
+// The function parses an operand: a literal, a reference, a list or an expression between parentheses.
+func (p *parser) parseOperand() (Node, error) {
+	t := p.advance()
+
+	switch t.kind {
+	case tokenNumber:
+		if value, err := strconv.ParseInt(t.text, 10, 64); err == nil {
+			return &Literal{Position: t.pos, Value: value}, nil
+		}
+		value, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, &Error{Position: t.pos, Message: fmt.Sprintf("invalid number %s", t.text)}
+		}
+		return &Literal{Position: t.pos, Value: value}, nil
+
+	case tokenString:
+		return &Literal{Position: t.pos, Value: t.text}, nil
+
+	case tokenParam:
+		return &Param{Position: t.pos, Name: t.text}, nil
+
+	case tokenFeature:
+		return &Feature{Position: t.pos, Name: t.text}, nil
+
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			return &Literal{Position: t.pos, Value: true}, nil
+		case "false":
+			return &Literal{Position: t.pos, Value: false}, nil
+		case "nil", "null":
+			return &Literal{Position: t.pos, Value: nil}, nil
+		}
+		return &Ident{Position: t.pos, Name: t.text}, nil
+
+	case tokenOperator:
+		switch t.text {
+		case "(":
+			x, err := p.parseBinary(1)
+			if err != nil {
+				return nil, err
+			}
+			if err = p.expect(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		case "[":
+			items, err := p.parseList("]")
+			if err != nil {
+				return nil, err
+			}
+			return &List{Position: t.pos, Items: items}, nil
+		}
+	}
+
+	return nil, &Error{Position: t.pos, Message: fmt.Sprintf("unexpected %s", t)}
+}
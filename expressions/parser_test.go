@@ -0,0 +1,59 @@
+package expressions_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bancodobrasil/featws-api/expressions"
+	"github.com/stretchr/testify/assert"
+)
+
+// This tests that the valid expressions of the rules are parsed, with the precedence of the operators.
+func TestParseSuccess(t *testing.T) {
+	for _, expression := range []string{
+		"$age >= 18",
+		"#vip && ($total > 100.5 || $coupon == 'FREE')",
+		"!#blocked",
+		"-$a * (2 + $b) % 3",
+		`$tags.contains("new")`,
+		"$items[0].price <= 10",
+		"[1, 2, $a] != []",
+		"max($a, $b, 3) != nil",
+	} {
+		_, err := expressions.Parse(expression)
+		assert.NoError(t, err, expression)
+	}
+
+	node, err := expressions.Parse("$a || $b && $c")
+	assert.NoError(t, err)
+	or := node.(*expressions.Binary)
+	assert.Equal(t, "||", or.Op)
+	assert.Equal(t, "&&", or.Y.(*expressions.Binary).Op)
+}
+
+// This tests that the syntax errors are returned with the position where the expression is broken.
+func TestParseWithSyntaxError(t *testing.T) {
+	for expression, position := range map[string]int{
+		"$age >=":        8,
+		"$age > 18 )":    11,
+		"($a && $b":      10,
+		"$a @ 1":         4,
+		"'unterminated":  1,
+		"#vip && $":      9,
+		"max($a $b)":     8,
+		"":               1,
+		"$customer.":     11,
+		"$a == == $b":    7,
+		"$a && ($b ||)":  13,
+		"[1, 2":          6,
+		"$items[0":       9,
+		"$price * 1.5 +": 15,
+	} {
+		_, err := expressions.Parse(expression)
+
+		var syntaxErr *expressions.Error
+		if assert.True(t, errors.As(err, &syntaxErr), expression) {
+			assert.Equal(t, position, syntaxErr.Position, expression)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package expressions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The types of the values of the rules, parameters and features. A value of the `any` type, like the
+// result of a function, isn't checked.
+const (
+	TypeAny     = "any"
+	TypeBoolean = "boolean"
+	TypeInteger = "integer"
+	TypeDecimal = "decimal"
+	TypeString  = "string"
+	TypeDate    = "date"
+	TypeList    = "list"
+	TypeMap     = "map"
+)
+
+// typeAliases are the names accepted for each type on the declarations of the rulesheets.
+var typeAliases = map[string]string{
+	"boolean": TypeBoolean, "bool": TypeBoolean,
+	"integer": TypeInteger, "int": TypeInteger, "long": TypeInteger,
+	"decimal": TypeDecimal, "float": TypeDecimal, "double": TypeDecimal, "number": TypeDecimal,
+	"string": TypeString, "text": TypeString,
+	"date": TypeDate, "datetime": TypeDate, "time": TypeDate,
+	"list": TypeList, "array": TypeList, "slice": TypeList,
+	"map": TypeMap, "object": TypeMap,
+	"any": TypeAny,
+}
+
+// dateLayouts are the layouts accepted for the dates written as strings.
+var dateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"}
+
+// NormalizeType returns the type of a declared type name, ignoring the case and accepting the usual aliases,
+// like `bool` or `float`. The lists may declare the type of their items, like `list<string>` or
+// `[]string`, which isn't checked. It returns false if the name isn't a known type.
+func NormalizeType(name string) (string, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	if strings.HasPrefix(name, "[]") || strings.HasPrefix(name, "list<") || strings.HasPrefix(name, "array<") {
+		return TypeList, true
+	}
+	if strings.HasPrefix(name, "map<") || strings.HasPrefix(name, "map[") {
+		return TypeMap, true
+	}
+
+	t, ok := typeAliases[name]
+	return t, ok
+}
+
+// IsAssignable checks if a value of the actual type can be used where the expected type is declared. The
+// integers can be used as decimals, and the `any` type matches every type.
+func IsAssignable(expected string, actual string) bool {
+	return expected == TypeAny || actual == TypeAny || expected == actual ||
+		(expected == TypeDecimal && actual == TypeInteger)
+}
+
+// IsDate checks if a string holds a date, like `2023-01-31` or `2023-01-31T10:00:00Z`.
+func IsDate(value string) bool {
+	for _, layout := range dateLayouts {
+		if _, err := time.Parse(layout, value); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// TypeOf returns the type of a value of a rule, as decoded from JSON.
+func TypeOf(value interface{}) string {
+	switch v := value.(type) {
+	case bool:
+		return TypeBoolean
+	case int, int32, int64:
+		return TypeInteger
+	case float32:
+		return TypeDecimal
+	case float64:
+		if v == float64(int64(v)) {
+			return TypeInteger
+		}
+		return TypeDecimal
+	case string:
+		return TypeString
+	case []interface{}:
+		return TypeList
+	case map[string]interface{}:
+		return TypeMap
+	}
+	return TypeAny
+}
+
+// Env holds the types of the parameters and features referenced by the expressions. The ones that aren't
+// on the maps are of the `any` type.
+type Env struct {
+	Params   map[string]string
+	Features map[string]string
+}
+
+// Check infers the type of an expression, checking the types of the operands of each operation. The error
+// is an `*Error` with the position of the operation whose operands don't match.
+func Check(node Node, env Env) (string, error) {
+	switch n := node.(type) {
+	case *Literal:
+		if n.Value == nil {
+			return TypeAny, nil
+		}
+		return TypeOf(n.Value), nil
+
+	case *Param:
+		return lookupType(env.Params, n.Name), nil
+
+	case *Feature:
+		return lookupType(env.Features, n.Name), nil
+
+	case *List:
+		for _, item := range n.Items {
+			if _, err := Check(item, env); err != nil {
+				return "", err
+			}
+		}
+		return TypeList, nil
+
+	case *Unary:
+		x, err := Check(n.X, env)
+		if err != nil {
+			return "", err
+		}
+		if n.Op == "!" {
+			return TypeBoolean, expectType(n, x, TypeBoolean)
+		}
+		if !isNumeric(x) {
+			return "", mismatch(n, "the operand of '%s' must be a number but it's %s", n.Op, x)
+		}
+		return x, nil
+
+	case *Binary:
+		return checkBinary(n, env)
+
+	case *Call:
+		for _, arg := range n.Args {
+			if _, err := Check(arg, env); err != nil {
+				return "", err
+			}
+		}
+		return TypeAny, nil
+
+	case *Member:
+		if _, err := Check(n.X, env); err != nil {
+			return "", err
+		}
+		return TypeAny, nil
+
+	case *Index:
+		x, err := Check(n.X, env)
+		if err != nil {
+			return "", err
+		}
+		if x != TypeAny && x != TypeList && x != TypeMap {
+			return "", mismatch(n, "only a list or map can be indexed but it's %s", x)
+		}
+		if _, err = Check(n.Index, env); err != nil {
+			return "", err
+		}
+		return TypeAny, nil
+	}
+
+	return TypeAny, nil
+}
+
+// The function checks the types of the operands of a binary operation and returns the type of its result.
+func checkBinary(n *Binary, env Env) (string, error) {
+	x, err := Check(n.X, env)
+	if err != nil {
+		return "", err
+	}
+	y, err := Check(n.Y, env)
+	if err != nil {
+		return "", err
+	}
+
+	switch n.Op {
+	case "&&", "||":
+		if err = expectType(n, x, TypeBoolean); err != nil {
+			return "", err
+		}
+		return TypeBoolean, expectType(n, y, TypeBoolean)
+
+	case "==", "!=":
+		if !isComparable(x, y) {
+			return "", mismatch(n, "can't compare %s with %s", x, y)
+		}
+		return TypeBoolean, nil
+
+	case "<", "<=", ">", ">=":
+		if (isNumeric(x) && isNumeric(y)) || (isOrdered(x) && isOrdered(y) && isComparable(x, y)) {
+			return TypeBoolean, nil
+		}
+		return "", mismatch(n, "can't compare %s with %s using '%s'", x, y, n.Op)
+
+	case "+":
+		if (x == TypeString || x == TypeAny) && (y == TypeString || y == TypeAny) && (x == TypeString || y == TypeString) {
+			return TypeString, nil
+		}
+		fallthrough
+
+	default:
+		if !isNumeric(x) || !isNumeric(y) {
+			return "", mismatch(n, "the operands of '%s' must be numbers but they're %s and %s", n.Op, x, y)
+		}
+		if x == TypeAny || y == TypeAny {
+			return TypeAny, nil
+		}
+		if x == TypeDecimal || y == TypeDecimal {
+			return TypeDecimal, nil
+		}
+		return TypeInteger, nil
+	}
+}
+
+// The function returns the declared type of a name, or `any` if it isn't declared.
+func lookupType(types map[string]string, name string) string {
+	if t, ok := types[name]; ok && t != "" {
+		return t
+	}
+	return TypeAny
+}
+
+// The function checks if the operand of an operation has the expected type.
+func expectType(n Node, actual string, expected string) error {
+	if !IsAssignable(expected, actual) {
+		return mismatch(n, "expected %s but found %s", expected, actual)
+	}
+	return nil
+}
+
+// The function checks if the type is a number, or may be one.
+func isNumeric(t string) bool {
+	return t == TypeInteger || t == TypeDecimal || t == TypeAny
+}
+
+// The function checks if the values of two types can be compared. The dates can be compared with the
+// strings, since they're written as strings on the expressions.
+func isComparable(x string, y string) bool {
+	return IsAssignable(x, y) || IsAssignable(y, x) ||
+		(x == TypeDate && y == TypeString) || (x == TypeString && y == TypeDate)
+}
+
+// The function checks if the values of the type can be ordered.
+func isOrdered(t string) bool {
+	return t == TypeString || t == TypeDate || t == TypeAny
+}
+
+// The function returns the error of an operation whose operands don't match.
+func mismatch(n Node, format string, args ...interface{}) error {
+	return &Error{Position: n.Pos(), Message: fmt.Sprintf(format, args...)}
+}
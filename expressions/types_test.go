@@ -0,0 +1,89 @@
+package expressions_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bancodobrasil/featws-api/expressions"
+	"github.com/stretchr/testify/assert"
+)
+
+// The function returns the types of the parameters and features used by the type checking tests.
+func SetupEnv() expressions.Env {
+	return expressions.Env{
+		Params: map[string]string{
+			"age":   expressions.TypeInteger,
+			"total": expressions.TypeDecimal,
+			"name":  expressions.TypeString,
+			"birth": expressions.TypeDate,
+			"tags":  expressions.TypeList,
+		},
+		Features: map[string]string{
+			"vip": expressions.TypeBoolean,
+		},
+	}
+}
+
+// This tests that the type of the expressions is inferred from their operands.
+func TestCheckSuccess(t *testing.T) {
+	for expression, expected := range map[string]string{
+		"$age >= 18 && #vip":           expressions.TypeBoolean,
+		"$age * 2":                     expressions.TypeInteger,
+		"$total * 0.9 + $age":          expressions.TypeDecimal,
+		"'Mr. ' + $name":               expressions.TypeString,
+		"$birth < '2000-01-01'":        expressions.TypeBoolean,
+		"$tags[0]":                     expressions.TypeAny,
+		"$unknown + 1":                 expressions.TypeAny,
+		"!#vip || $tags.contains('a')": expressions.TypeBoolean,
+	} {
+		node, err := expressions.Parse(expression)
+		assert.NoError(t, err, expression)
+
+		actual, err := expressions.Check(node, SetupEnv())
+		assert.NoError(t, err, expression)
+		assert.Equal(t, expected, actual, expression)
+	}
+}
+
+// This tests that the operations whose operands don't match are reported with their position.
+func TestCheckWithMismatch(t *testing.T) {
+	for expression, position := range map[string]int{
+		"$age && #vip":     6,
+		"$name * 2":        7,
+		"!$age":            1,
+		"$age == 'x'":      6,
+		"#vip > 1":         6,
+		"$total[0]":        7,
+		"-$name":           1,
+		"$age > 1 || $age": 10,
+	} {
+		node, err := expressions.Parse(expression)
+		assert.NoError(t, err, expression)
+
+		_, err = expressions.Check(node, SetupEnv())
+
+		var typeErr *expressions.Error
+		if assert.True(t, errors.As(err, &typeErr), expression) {
+			assert.Equal(t, position, typeErr.Position, expression)
+		}
+	}
+}
+
+// This tests that the declared type names accept the usual aliases.
+func TestNormalizeType(t *testing.T) {
+	for name, expected := range map[string]string{
+		"Boolean":      expressions.TypeBoolean,
+		"int":          expressions.TypeInteger,
+		"float":        expressions.TypeDecimal,
+		"list<string>": expressions.TypeList,
+		"[]integer":    expressions.TypeList,
+		"object":       expressions.TypeMap,
+	} {
+		actual, ok := expressions.NormalizeType(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, expected, actual, name)
+	}
+
+	_, ok := expressions.NormalizeType("unknown")
+	assert.False(t, ok)
+}
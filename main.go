@@ -38,10 +38,30 @@ func setupLog() {
 // @version 1.0
 // @Description Este projeto consiste em uma API cujo objetivo é fornecer operações para gerenciamento de repositórios e folhas de regra do sistema FeatWS. Através da API, é possível interagir entre a interface de usuário (UI) e o banco de dados, permitindo diversas interações, como as seguintes:
 // @Description - [Post] Criação da Folha de Regra;
-// @Description - [Get] Listar das Folhas de Regra;
-// @Description - [Get] Obter folha de regra por ID;
-// @Description - [Put] Atualizar uma folha de regra por ID;
-// @Description - [Delete] Deletar uma folha de regra por ID.
+// @Description - [Post] Importar uma folha de regra a partir de arquivos (*.featws* ou *features.json*, *parameters.json* e *rules.json*);
+// @Description - [Get] Listar das Folhas de Regra, com filtros por nome, slug, descrição, datas e exclusão, ordenação e paginação por página ou cursor;
+// @Description - [Get] Obter folha de regra por ID ou por slug;
+// @Description - [Put] Atualizar uma folha de regra por ID ou por slug;
+// @Description - [Patch] Alterar parte de uma folha de regra por ID ou por slug, com JSON Merge Patch ou JSON Patch;
+// @Description - [Delete] Deletar uma folha de regra por ID ou por slug;
+// @Description - [Get, Put, Delete] Obter, criar ou atualizar e remover uma regra, feature ou parâmetro de uma folha de regra por ID, com uma mensagem de commit da alteração;
+// @Description - [Get] Listar as versões de uma folha de regra por ID;
+// @Description - [Get] Obter uma folha de regra por ID em uma versão;
+// @Description - [Post] Restaurar uma folha de regra por ID a partir de uma versão anterior;
+// @Description - [Get] Comparar duas versões de uma folha de regra por ID;
+// @Description - [Post] Avaliar as regras de uma folha de regra por ID para um conjunto de parâmetros;
+// @Description - [Post] Executar os casos de teste de uma folha de regra por ID;
+// @Description - [Get] Exportar as regras de uma folha de regra por ID no formato *featws*;
+// @Description - [Get] Grafo de dependências das regras de uma folha de regra por ID, em JSON, DOT ou Mermaid;
+// @Description - [Post] Converter as regras em texto (*rules.featws*) de uma folha de regra por ID para regras estruturadas;
+// @Description - [Post] Converter as regras em texto de todas as folhas de regra legadas;
+// @Description - [Get] Listar as alterações pendentes de uma folha de regra por ID;
+// @Description - [Post] Aprovar uma alteração pendente de uma folha de regra;
+// @Description - [Post] Publicar uma alteração pendente de uma folha de regra;
+// @Description - [Delete] Descartar uma alteração pendente de uma folha de regra;
+// @Description - [Get] Listar as sincronizações das folhas de regra com o backend de armazenamento;
+// @Description - [Post] Tentar novamente uma sincronização que falhou;
+// @Description - [Post] Descartar uma sincronização que falhou.
 // @Description
 // @Description Antes de realizar as requisições no Swagger, é necessário autorizar o acesso clicando no botão **Authorize**, ao lado, e inserindo a senha correspondente. Após inserir o campo **value** e clicar no botão **Authorize**, o Swagger estará disponível para ser utilizado.
 // @Description
@@ -17,20 +17,20 @@ type Repository[T interface{}] struct {
 	mock.Mock
 }
 
-// Count provides a mock function with given fields: ctx, entity
-func (_m *Repository[T]) Count(ctx context.Context, entity interface{}) (int64, error) {
-	ret := _m.Called(ctx, entity)
+// Count provides a mock function with given fields: ctx, criteria
+func (_m *Repository[T]) Count(ctx context.Context, criteria *repository.Criteria) (int64, error) {
+	ret := _m.Called(ctx, criteria)
 
 	var r0 int64
-	if rf, ok := ret.Get(0).(func(context.Context, interface{}) int64); ok {
-		r0 = rf(ctx, entity)
+	if rf, ok := ret.Get(0).(func(context.Context, *repository.Criteria) int64); ok {
+		r0 = rf(ctx, criteria)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, interface{}) error); ok {
-		r1 = rf(ctx, entity)
+	if rf, ok := ret.Get(1).(func(context.Context, *repository.Criteria) error); ok {
+		r1 = rf(ctx, criteria)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -38,20 +38,20 @@ func (_m *Repository[T]) Count(ctx context.Context, entity interface{}) (int64,
 	return r0, r1
 }
 
-// CountInTransaction provides a mock function with given fields: ctx, db, entity
-func (_m *Repository[T]) CountInTransaction(ctx context.Context, db *gorm.DB, entity interface{}) (int64, error) {
-	ret := _m.Called(ctx, db, entity)
+// CountInTransaction provides a mock function with given fields: ctx, db, criteria
+func (_m *Repository[T]) CountInTransaction(ctx context.Context, db *gorm.DB, criteria *repository.Criteria) (int64, error) {
+	ret := _m.Called(ctx, db, criteria)
 
 	var r0 int64
-	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, interface{}) int64); ok {
-		r0 = rf(ctx, db, entity)
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.Criteria) int64); ok {
+		r0 = rf(ctx, db, criteria)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, interface{}) error); ok {
-		r1 = rf(ctx, db, entity)
+	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, *repository.Criteria) error); ok {
+		r1 = rf(ctx, db, criteria)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -129,13 +129,13 @@ func (_m *Repository[T]) DeleteInTransaction(ctx context.Context, db *gorm.DB, i
 	return r0, r1
 }
 
-// Find provides a mock function with given fields: ctx, entity, options
-func (_m *Repository[T]) Find(ctx context.Context, entity interface{}, options *repository.FindOptions) ([]*T, error) {
-	ret := _m.Called(ctx, entity, options)
+// Find provides a mock function with given fields: ctx, criteria, options
+func (_m *Repository[T]) Find(ctx context.Context, criteria *repository.Criteria, options *repository.FindOptions) ([]*T, error) {
+	ret := _m.Called(ctx, criteria, options)
 
 	var r0 []*T
-	if rf, ok := ret.Get(0).(func(context.Context, interface{}, *repository.FindOptions) []*T); ok {
-		r0 = rf(ctx, entity, options)
+	if rf, ok := ret.Get(0).(func(context.Context, *repository.Criteria, *repository.FindOptions) []*T); ok {
+		r0 = rf(ctx, criteria, options)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*T)
@@ -143,8 +143,8 @@ func (_m *Repository[T]) Find(ctx context.Context, entity interface{}, options *
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, interface{}, *repository.FindOptions) error); ok {
-		r1 = rf(ctx, entity, options)
+	if rf, ok := ret.Get(1).(func(context.Context, *repository.Criteria, *repository.FindOptions) error); ok {
+		r1 = rf(ctx, criteria, options)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -152,13 +152,13 @@ func (_m *Repository[T]) Find(ctx context.Context, entity interface{}, options *
 	return r0, r1
 }
 
-// FindInTransaction provides a mock function with given fields: ctx, db, entity, options
-func (_m *Repository[T]) FindInTransaction(ctx context.Context, db *gorm.DB, entity interface{}, options *repository.FindOptions) ([]*T, error) {
-	ret := _m.Called(ctx, db, entity, options)
+// FindInTransaction provides a mock function with given fields: ctx, db, criteria, options
+func (_m *Repository[T]) FindInTransaction(ctx context.Context, db *gorm.DB, criteria *repository.Criteria, options *repository.FindOptions) ([]*T, error) {
+	ret := _m.Called(ctx, db, criteria, options)
 
 	var r0 []*T
-	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, interface{}, *repository.FindOptions) []*T); ok {
-		r0 = rf(ctx, db, entity, options)
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.Criteria, *repository.FindOptions) []*T); ok {
+		r0 = rf(ctx, db, criteria, options)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*T)
@@ -166,8 +166,8 @@ func (_m *Repository[T]) FindInTransaction(ctx context.Context, db *gorm.DB, ent
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, interface{}, *repository.FindOptions) error); ok {
-		r1 = rf(ctx, db, entity, options)
+	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, *repository.Criteria, *repository.FindOptions) error); ok {
+		r1 = rf(ctx, db, criteria, options)
 	} else {
 		r1 = ret.Error(1)
 	}
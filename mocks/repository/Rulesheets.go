@@ -19,20 +19,20 @@ type Rulesheets struct {
 	mock.Mock
 }
 
-// Count provides a mock function with given fields: ctx, entity
-func (_m *Rulesheets) Count(ctx context.Context, entity interface{}) (int64, error) {
-	ret := _m.Called(ctx, entity)
+// Count provides a mock function with given fields: ctx, criteria
+func (_m *Rulesheets) Count(ctx context.Context, criteria *repository.Criteria) (int64, error) {
+	ret := _m.Called(ctx, criteria)
 
 	var r0 int64
-	if rf, ok := ret.Get(0).(func(context.Context, interface{}) int64); ok {
-		r0 = rf(ctx, entity)
+	if rf, ok := ret.Get(0).(func(context.Context, *repository.Criteria) int64); ok {
+		r0 = rf(ctx, criteria)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, interface{}) error); ok {
-		r1 = rf(ctx, entity)
+	if rf, ok := ret.Get(1).(func(context.Context, *repository.Criteria) error); ok {
+		r1 = rf(ctx, criteria)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -40,20 +40,20 @@ func (_m *Rulesheets) Count(ctx context.Context, entity interface{}) (int64, err
 	return r0, r1
 }
 
-// CountInTransaction provides a mock function with given fields: ctx, db, entity
-func (_m *Rulesheets) CountInTransaction(ctx context.Context, db *gorm.DB, entity interface{}) (int64, error) {
-	ret := _m.Called(ctx, db, entity)
+// CountInTransaction provides a mock function with given fields: ctx, db, criteria
+func (_m *Rulesheets) CountInTransaction(ctx context.Context, db *gorm.DB, criteria *repository.Criteria) (int64, error) {
+	ret := _m.Called(ctx, db, criteria)
 
 	var r0 int64
-	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, interface{}) int64); ok {
-		r0 = rf(ctx, db, entity)
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.Criteria) int64); ok {
+		r0 = rf(ctx, db, criteria)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, interface{}) error); ok {
-		r1 = rf(ctx, db, entity)
+	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, *repository.Criteria) error); ok {
+		r1 = rf(ctx, db, criteria)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -131,13 +131,13 @@ func (_m *Rulesheets) DeleteInTransaction(ctx context.Context, db *gorm.DB, id s
 	return r0, r1
 }
 
-// Find provides a mock function with given fields: ctx, entity, options
-func (_m *Rulesheets) Find(ctx context.Context, entity interface{}, options *repository.FindOptions) ([]*models.Rulesheet, error) {
-	ret := _m.Called(ctx, entity, options)
+// Find provides a mock function with given fields: ctx, criteria, options
+func (_m *Rulesheets) Find(ctx context.Context, criteria *repository.Criteria, options *repository.FindOptions) ([]*models.Rulesheet, error) {
+	ret := _m.Called(ctx, criteria, options)
 
 	var r0 []*models.Rulesheet
-	if rf, ok := ret.Get(0).(func(context.Context, interface{}, *repository.FindOptions) []*models.Rulesheet); ok {
-		r0 = rf(ctx, entity, options)
+	if rf, ok := ret.Get(0).(func(context.Context, *repository.Criteria, *repository.FindOptions) []*models.Rulesheet); ok {
+		r0 = rf(ctx, criteria, options)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*models.Rulesheet)
@@ -145,8 +145,8 @@ func (_m *Rulesheets) Find(ctx context.Context, entity interface{}, options *rep
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, interface{}, *repository.FindOptions) error); ok {
-		r1 = rf(ctx, entity, options)
+	if rf, ok := ret.Get(1).(func(context.Context, *repository.Criteria, *repository.FindOptions) error); ok {
+		r1 = rf(ctx, criteria, options)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -154,13 +154,13 @@ func (_m *Rulesheets) Find(ctx context.Context, entity interface{}, options *rep
 	return r0, r1
 }
 
-// FindInTransaction provides a mock function with given fields: ctx, db, entity, options
-func (_m *Rulesheets) FindInTransaction(ctx context.Context, db *gorm.DB, entity interface{}, options *repository.FindOptions) ([]*models.Rulesheet, error) {
-	ret := _m.Called(ctx, db, entity, options)
+// FindInTransaction provides a mock function with given fields: ctx, db, criteria, options
+func (_m *Rulesheets) FindInTransaction(ctx context.Context, db *gorm.DB, criteria *repository.Criteria, options *repository.FindOptions) ([]*models.Rulesheet, error) {
+	ret := _m.Called(ctx, db, criteria, options)
 
 	var r0 []*models.Rulesheet
-	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, interface{}, *repository.FindOptions) []*models.Rulesheet); ok {
-		r0 = rf(ctx, db, entity, options)
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, *repository.Criteria, *repository.FindOptions) []*models.Rulesheet); ok {
+		r0 = rf(ctx, db, criteria, options)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*models.Rulesheet)
@@ -168,8 +168,8 @@ func (_m *Rulesheets) FindInTransaction(ctx context.Context, db *gorm.DB, entity
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, interface{}, *repository.FindOptions) error); ok {
-		r1 = rf(ctx, db, entity, options)
+	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, *repository.Criteria, *repository.FindOptions) error); ok {
+		r1 = rf(ctx, db, criteria, options)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -200,6 +200,29 @@ func (_m *Rulesheets) Get(ctx context.Context, id string) (*models.Rulesheet, er
 	return r0, r1
 }
 
+// GetBySlug provides a mock function with given fields: ctx, slug
+func (_m *Rulesheets) GetBySlug(ctx context.Context, slug string) (*models.Rulesheet, error) {
+	ret := _m.Called(ctx, slug)
+
+	var r0 *models.Rulesheet
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Rulesheet); ok {
+		r0 = rf(ctx, slug)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Rulesheet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, slug)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDB provides a mock function with given fields:
 func (_m *Rulesheets) GetDB() *gorm.DB {
 	ret := _m.Called()
@@ -7,6 +7,8 @@ import (
 	gitlab "github.com/xanzy/go-gitlab"
 
 	mock "github.com/stretchr/testify/mock"
+
+	services "github.com/bancodobrasil/featws-api/services"
 )
 
 // Gitlab is an autogenerated mock type for the Gitlab type
@@ -14,6 +16,29 @@ type Gitlab struct {
 	mock.Mock
 }
 
+// ApproveChangeRequest provides a mock function with given fields: rulesheet, id
+func (_m *Gitlab) ApproveChangeRequest(rulesheet *dtos.Rulesheet, id int) (*dtos.ChangeRequest, error) {
+	ret := _m.Called(rulesheet, id)
+
+	var r0 *dtos.ChangeRequest
+	if rf, ok := ret.Get(0).(func(*dtos.Rulesheet, int) *dtos.ChangeRequest); ok {
+		r0 = rf(rulesheet, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.ChangeRequest)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*dtos.Rulesheet, int) error); ok {
+		r1 = rf(rulesheet, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Connect provides a mock function with given fields:
 func (_m *Gitlab) Connect() (*gitlab.Client, error) {
 	ret := _m.Called()
@@ -37,6 +62,20 @@ func (_m *Gitlab) Connect() (*gitlab.Client, error) {
 	return r0, r1
 }
 
+// DiscardChangeRequest provides a mock function with given fields: rulesheet, id
+func (_m *Gitlab) DiscardChangeRequest(rulesheet *dtos.Rulesheet, id int) error {
+	ret := _m.Called(rulesheet, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dtos.Rulesheet, int) error); ok {
+		r0 = rf(rulesheet, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Fill provides a mock function with given fields: rulesheet
 func (_m *Gitlab) Fill(rulesheet *dtos.Rulesheet) error {
 	ret := _m.Called(rulesheet)
@@ -51,6 +90,101 @@ func (_m *Gitlab) Fill(rulesheet *dtos.Rulesheet) error {
 	return r0
 }
 
+// FillRef provides a mock function with given fields: rulesheet, ref
+func (_m *Gitlab) FillRef(rulesheet *dtos.Rulesheet, ref string) error {
+	ret := _m.Called(rulesheet, ref)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dtos.Rulesheet, string) error); ok {
+		r0 = rf(rulesheet, ref)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// History provides a mock function with given fields: rulesheet, options
+func (_m *Gitlab) History(rulesheet *dtos.Rulesheet, options *services.FindOptions) ([]*dtos.Version, error) {
+	ret := _m.Called(rulesheet, options)
+
+	var r0 []*dtos.Version
+	if rf, ok := ret.Get(0).(func(*dtos.Rulesheet, *services.FindOptions) []*dtos.Version); ok {
+		r0 = rf(rulesheet, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dtos.Version)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*dtos.Rulesheet, *services.FindOptions) error); ok {
+		r1 = rf(rulesheet, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListChangeRequests provides a mock function with given fields: rulesheet
+func (_m *Gitlab) ListChangeRequests(rulesheet *dtos.Rulesheet) ([]*dtos.ChangeRequest, error) {
+	ret := _m.Called(rulesheet)
+
+	var r0 []*dtos.ChangeRequest
+	if rf, ok := ret.Get(0).(func(*dtos.Rulesheet) []*dtos.ChangeRequest); ok {
+		r0 = rf(rulesheet)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dtos.ChangeRequest)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*dtos.Rulesheet) error); ok {
+		r1 = rf(rulesheet)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublishChangeRequest provides a mock function with given fields: rulesheet, id
+func (_m *Gitlab) PublishChangeRequest(rulesheet *dtos.Rulesheet, id int) error {
+	ret := _m.Called(rulesheet, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dtos.Rulesheet, int) error); ok {
+		r0 = rf(rulesheet, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResolveVersion provides a mock function with given fields: rulesheet, version
+func (_m *Gitlab) ResolveVersion(rulesheet *dtos.Rulesheet, version string) (string, error) {
+	ret := _m.Called(rulesheet, version)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(*dtos.Rulesheet, string) string); ok {
+		r0 = rf(rulesheet, version)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*dtos.Rulesheet, string) error); ok {
+		r1 = rf(rulesheet, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Save provides a mock function with given fields: rulesheet, commitMessage
 func (_m *Gitlab) Save(rulesheet *dtos.Rulesheet, commitMessage string) error {
 	ret := _m.Called(rulesheet, commitMessage)
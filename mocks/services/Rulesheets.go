@@ -16,20 +16,89 @@ type Rulesheets struct {
 	mock.Mock
 }
 
-// Count provides a mock function with given fields: ctx, entity
-func (_m *Rulesheets) Count(ctx context.Context, entity interface{}) (int64, error) {
-	ret := _m.Called(ctx, entity)
+// ApproveChange provides a mock function with given fields: ctx, id, change
+func (_m *Rulesheets) ApproveChange(ctx context.Context, id string, change int) (*dtos.ChangeRequest, error) {
+	ret := _m.Called(ctx, id, change)
+
+	var r0 *dtos.ChangeRequest
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) *dtos.ChangeRequest); ok {
+		r0 = rf(ctx, id, change)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.ChangeRequest)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, id, change)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConvertAllLegacy provides a mock function with given fields: ctx, commit
+func (_m *Rulesheets) ConvertAllLegacy(ctx context.Context, commit bool) ([]*dtos.Conversion, error) {
+	ret := _m.Called(ctx, commit)
+
+	var r0 []*dtos.Conversion
+	if rf, ok := ret.Get(0).(func(context.Context, bool) []*dtos.Conversion); ok {
+		r0 = rf(ctx, commit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dtos.Conversion)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, bool) error); ok {
+		r1 = rf(ctx, commit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConvertLegacy provides a mock function with given fields: ctx, id, commit
+func (_m *Rulesheets) ConvertLegacy(ctx context.Context, id string, commit bool) (*dtos.Conversion, error) {
+	ret := _m.Called(ctx, id, commit)
+
+	var r0 *dtos.Conversion
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) *dtos.Conversion); ok {
+		r0 = rf(ctx, id, commit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.Conversion)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool) error); ok {
+		r1 = rf(ctx, id, commit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Count provides a mock function with given fields: ctx, filter
+func (_m *Rulesheets) Count(ctx context.Context, filter *services.RulesheetFilter) (int64, error) {
+	ret := _m.Called(ctx, filter)
 
 	var r0 int64
-	if rf, ok := ret.Get(0).(func(context.Context, interface{}) int64); ok {
-		r0 = rf(ctx, entity)
+	if rf, ok := ret.Get(0).(func(context.Context, *services.RulesheetFilter) int64); ok {
+		r0 = rf(ctx, filter)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, interface{}) error); ok {
-		r1 = rf(ctx, entity)
+	if rf, ok := ret.Get(1).(func(context.Context, *services.RulesheetFilter) error); ok {
+		r1 = rf(ctx, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -72,12 +141,95 @@ func (_m *Rulesheets) Delete(ctx context.Context, id string) (bool, error) {
 	return r0, r1
 }
 
+// Diff provides a mock function with given fields: ctx, id, from, to
+func (_m *Rulesheets) Diff(ctx context.Context, id string, from string, to string) (*dtos.Diff, error) {
+	ret := _m.Called(ctx, id, from, to)
+
+	var r0 *dtos.Diff
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *dtos.Diff); ok {
+		r0 = rf(ctx, id, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.Diff)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, id, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DiscardChange provides a mock function with given fields: ctx, id, change
+func (_m *Rulesheets) DiscardChange(ctx context.Context, id string, change int) error {
+	ret := _m.Called(ctx, id, change)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = rf(ctx, id, change)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Evaluate provides a mock function with given fields: ctx, id, version, params
+func (_m *Rulesheets) Evaluate(ctx context.Context, id string, version string, params map[string]interface{}) (*dtos.Evaluation, error) {
+	ret := _m.Called(ctx, id, version, params)
+
+	var r0 *dtos.Evaluation
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, map[string]interface{}) *dtos.Evaluation); ok {
+		r0 = rf(ctx, id, version, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.Evaluation)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, map[string]interface{}) error); ok {
+		r1 = rf(ctx, id, version, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Export provides a mock function with given fields: ctx, id, version, format
+func (_m *Rulesheets) Export(ctx context.Context, id string, version string, format string) ([]byte, error) {
+	ret := _m.Called(ctx, id, version, format)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) []byte); ok {
+		r0 = rf(ctx, id, version, format)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, id, version, format)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Find provides a mock function with given fields: ctx, filter, options
-func (_m *Rulesheets) Find(ctx context.Context, filter interface{}, options *services.FindOptions) ([]*dtos.Rulesheet, error) {
+func (_m *Rulesheets) Find(ctx context.Context, filter *services.RulesheetFilter, options *services.FindOptions) ([]*dtos.Rulesheet, error) {
 	ret := _m.Called(ctx, filter, options)
 
 	var r0 []*dtos.Rulesheet
-	if rf, ok := ret.Get(0).(func(context.Context, interface{}, *services.FindOptions) []*dtos.Rulesheet); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *services.RulesheetFilter, *services.FindOptions) []*dtos.Rulesheet); ok {
 		r0 = rf(ctx, filter, options)
 	} else {
 		if ret.Get(0) != nil {
@@ -86,7 +238,7 @@ func (_m *Rulesheets) Find(ctx context.Context, filter interface{}, options *ser
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, interface{}, *services.FindOptions) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *services.RulesheetFilter, *services.FindOptions) error); ok {
 		r1 = rf(ctx, filter, options)
 	} else {
 		r1 = ret.Error(1)
@@ -118,6 +270,167 @@ func (_m *Rulesheets) Get(ctx context.Context, id string) (*dtos.Rulesheet, erro
 	return r0, r1
 }
 
+// GetBySlug provides a mock function with given fields: ctx, slug
+func (_m *Rulesheets) GetBySlug(ctx context.Context, slug string) (*dtos.Rulesheet, error) {
+	ret := _m.Called(ctx, slug)
+
+	var r0 *dtos.Rulesheet
+	if rf, ok := ret.Get(0).(func(context.Context, string) *dtos.Rulesheet); ok {
+		r0 = rf(ctx, slug)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.Rulesheet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, slug)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVersion provides a mock function with given fields: ctx, id, version
+func (_m *Rulesheets) GetVersion(ctx context.Context, id string, version string) (*dtos.Rulesheet, error) {
+	ret := _m.Called(ctx, id, version)
+
+	var r0 *dtos.Rulesheet
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *dtos.Rulesheet); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.Rulesheet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, id, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Graph provides a mock function with given fields: ctx, id, version
+func (_m *Rulesheets) Graph(ctx context.Context, id string, version string) (*dtos.Graph, error) {
+	ret := _m.Called(ctx, id, version)
+
+	var r0 *dtos.Graph
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *dtos.Graph); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.Graph)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, id, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// History provides a mock function with given fields: ctx, id, options
+func (_m *Rulesheets) History(ctx context.Context, id string, options *services.FindOptions) ([]*dtos.Version, error) {
+	ret := _m.Called(ctx, id, options)
+
+	var r0 []*dtos.Version
+	if rf, ok := ret.Get(0).(func(context.Context, string, *services.FindOptions) []*dtos.Version); ok {
+		r0 = rf(ctx, id, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dtos.Version)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *services.FindOptions) error); ok {
+		r1 = rf(ctx, id, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListChanges provides a mock function with given fields: ctx, id
+func (_m *Rulesheets) ListChanges(ctx context.Context, id string) ([]*dtos.ChangeRequest, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []*dtos.ChangeRequest
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*dtos.ChangeRequest); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dtos.ChangeRequest)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublishChange provides a mock function with given fields: ctx, id, change
+func (_m *Rulesheets) PublishChange(ctx context.Context, id string, change int) (*dtos.Rulesheet, error) {
+	ret := _m.Called(ctx, id, change)
+
+	var r0 *dtos.Rulesheet
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) *dtos.Rulesheet); ok {
+		r0 = rf(ctx, id, change)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.Rulesheet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, id, change)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RunTests provides a mock function with given fields: ctx, id, version
+func (_m *Rulesheets) RunTests(ctx context.Context, id string, version string) (*dtos.TestRun, error) {
+	ret := _m.Called(ctx, id, version)
+
+	var r0 *dtos.TestRun
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *dtos.TestRun); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.TestRun)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, id, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, entity
 func (_m *Rulesheets) Update(ctx context.Context, entity dtos.Rulesheet) (*dtos.Rulesheet, error) {
 	ret := _m.Called(ctx, entity)
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// The statuses of a sync of a rulesheet with the storage backend.
+const (
+	SyncPending   = "pending"
+	SyncSynced    = "synced"
+	SyncFailed    = "failed"
+	SyncDiscarded = "discarded"
+)
+
+// Sync represents an entry of the outbox of the changes of the rulesheets: it's written on the same
+// transaction that changes a rulesheet and is pushed to the storage backend, like GitLab, by the sync worker.
+//
+// Property:
+//   - `gorm.Model`: This is a struct that provides some common fields for db models such as `ID`, `CreatedAt`, `UpdatedAt`, and `DeletedAt`.
+//   - RulesheetID: the ID of the rulesheet that was changed. The syncs of a rulesheet are pushed in the order they were written.
+//   - CommitMessage: the commit message used when the change is saved on the storage backend. It has no length limit, since it includes the names given on the routes of the items.
+//   - Payload: the content of the rulesheet to be saved, encoded as JSON.
+//   - Status: the status of the sync, `pending`, `synced`, `failed` or `discarded`, when a failed sync is dropped by an operator.
+//   - Attempts: the number of attempts to push the change that failed.
+//   - Error: the error of the last failed attempt.
+//   - Version: the version published on the storage backend when the change was synced.
+//   - NextAttemptAt: the moment after which a pending sync can be attempted again.
+//   - LockedUntil: the moment until which a sync is being pushed by a worker, so no other worker pushes it too.
+//   - SyncedAt: the moment the change was synced.
+type Sync struct {
+	gorm.Model
+	RulesheetID   uint   `gorm:"index"`
+	CommitMessage string `gorm:"type:text"`
+	Payload       string `gorm:"type:longtext"`
+	Status        string `gorm:"type:varchar(16);index"`
+	Attempts      int
+	Error         string `gorm:"type:text"`
+	Version       string `gorm:"type:varchar(32)"`
+	NextAttemptAt *time.Time
+	LockedUntil   *time.Time
+	SyncedAt      *time.Time
+}
@@ -0,0 +1,412 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// The media types of the patches.
+const (
+	MediaTypeMergePatch = "application/merge-patch+json"
+	MediaTypeJSONPatch  = "application/json-patch+json"
+)
+
+// ErrInvalidPatch is returned when the patch isn't a valid JSON document, or a JSON Patch has an unknown
+// operation or an operation without its members.
+var ErrInvalidPatch = errors.New("the patch is invalid")
+
+// ErrPathNotFound is returned when an operation of a JSON Patch references a member or item that doesn't
+// exist.
+var ErrPathNotFound = errors.New("the path doesn't exist")
+
+// ErrTestFailed is returned when the value of a `test` operation of a JSON Patch doesn't match the document.
+var ErrTestFailed = errors.New("the test failed")
+
+// Operation is an operation of a JSON Patch.
+//
+// Property:
+//   - Op: one of `add`, `remove`, `replace`, `move`, `copy` and `test`.
+//   - Path: the JSON Pointer of the value changed or tested, like `/rules/discount/0/condition`.
+//   - From: the JSON Pointer of the value moved or copied.
+//   - Value: the value added, replaced or tested. It's nil when it isn't given, unlike the JSON `null`.
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Merge applies a JSON Merge Patch (RFC 7396) to a JSON document: the members of the objects of the patch
+// are merged on the document recursively, the `null` members are removed and any other value, like a list,
+// replaces the one of the document.
+func Merge(document []byte, patch []byte) ([]byte, error) {
+	target, err := decode(document)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := decode(patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPatch, err)
+	}
+
+	return json.Marshal(merge(target, changes))
+}
+
+// Apply applies a JSON Patch (RFC 6902) to a JSON document. The operations are applied in order and none of
+// them is applied if any fails, with `ErrInvalidPatch`, `ErrPathNotFound` or `ErrTestFailed`.
+func Apply(document []byte, patch []byte) ([]byte, error) {
+	target, err := decode(document)
+	if err != nil {
+		return nil, err
+	}
+
+	var operations []Operation
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPatch, err)
+	}
+
+	for index, operation := range operations {
+		target, err = operation.apply(target)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", index, err)
+		}
+	}
+
+	return json.Marshal(target)
+}
+
+// The function decodes a JSON document keeping its numbers as they were written.
+func decode(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+
+	return value, nil
+}
+
+// The function merges the changes of a merge patch on a value.
+func merge(target interface{}, changes interface{}) interface{} {
+	members, ok := changes.(map[string]interface{})
+	if !ok {
+		return changes
+	}
+
+	object, ok := target.(map[string]interface{})
+	if !ok {
+		object = make(map[string]interface{})
+	}
+
+	for key, value := range members {
+		if value == nil {
+			delete(object, key)
+			continue
+		}
+		object[key] = merge(object[key], value)
+	}
+
+	return object
+}
+
+// The function applies the operation to the document and returns the changed document.
+func (o Operation) apply(document interface{}) (interface{}, error) {
+	if o.Path == nil {
+		return nil, fmt.Errorf("%w: the operation '%s' has no path", ErrInvalidPatch, o.Op)
+	}
+	path, err := parsePointer(*o.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch o.Op {
+	case "add", "replace", "test":
+		if o.Value == nil {
+			return nil, fmt.Errorf("%w: the operation '%s' has no value", ErrInvalidPatch, o.Op)
+		}
+		value, err := decode(o.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPatch, err)
+		}
+
+		switch o.Op {
+		case "add":
+			return add(document, path, value)
+		case "replace":
+			return replace(document, path, value)
+		}
+
+		current, err := get(document, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(current, value) {
+			return nil, fmt.Errorf("%w: the value of '%s' is different", ErrTestFailed, *o.Path)
+		}
+		return document, nil
+
+	case "remove":
+		document, _, err = remove(document, path)
+		return document, err
+
+	case "move", "copy":
+		if o.From == nil {
+			return nil, fmt.Errorf("%w: the operation '%s' has no from", ErrInvalidPatch, o.Op)
+		}
+		from, err := parsePointer(*o.From)
+		if err != nil {
+			return nil, err
+		}
+
+		if o.Op == "copy" {
+			value, err := get(document, from)
+			if err != nil {
+				return nil, err
+			}
+			return add(document, path, clone(value))
+		}
+
+		if strings.HasPrefix(*o.Path, *o.From+"/") {
+			return nil, fmt.Errorf("%w: '%s' can't be moved into itself", ErrInvalidPatch, *o.From)
+		}
+		document, value, err := remove(document, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(document, path, value)
+	}
+
+	return nil, fmt.Errorf("%w: unknown operation '%s'", ErrInvalidPatch, o.Op)
+}
+
+// The function parses a JSON Pointer (RFC 6901) into its reference tokens. The empty pointer references the
+// whole document.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: the path '%s' doesn't start with '/'", ErrInvalidPatch, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for index, token := range tokens {
+		tokens[index] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+// The function returns the value referenced by the tokens of a pointer.
+func get(document interface{}, path []string) (interface{}, error) {
+	current := document
+	for _, token := range path {
+		var err error
+		current, err = child(current, token)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return current, nil
+}
+
+// The function returns the member or item of a value referenced by a token.
+func child(value interface{}, token string) (interface{}, error) {
+	switch node := value.(type) {
+	case map[string]interface{}:
+		member, ok := node[token]
+		if !ok {
+			return nil, fmt.Errorf("%w: the member '%s'", ErrPathNotFound, token)
+		}
+		return member, nil
+	case []interface{}:
+		index, err := parseIndex(token, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		return node[index], nil
+	}
+	return nil, fmt.Errorf("%w: '%s' isn't on an object or list", ErrPathNotFound, token)
+}
+
+// The function changes the parent of the value referenced by a pointer, replacing it on its own parent, since
+// the lists are copied when an item is added or removed.
+func change(document interface{}, path []string, fn func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return fn(document, path[0])
+	}
+
+	current, err := child(document, path[0])
+	if err != nil {
+		return nil, err
+	}
+	changed, err := change(current, path[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+
+	switch node := document.(type) {
+	case map[string]interface{}:
+		node[path[0]] = changed
+	case []interface{}:
+		index, _ := parseIndex(path[0], len(node)-1)
+		node[index] = changed
+	}
+	return document, nil
+}
+
+// The function adds a value to an object, replacing the member with the same name, or inserts it on a list,
+// at the index or at the end for the `-` token.
+func add(document interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return change(document, path, func(parent interface{}, token string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			node[token] = value
+			return node, nil
+		case []interface{}:
+			index := len(node)
+			if token != "-" {
+				var err error
+				index, err = parseIndex(token, len(node))
+				if err != nil {
+					return nil, err
+				}
+			}
+			node = append(node, nil)
+			copy(node[index+1:], node[index:])
+			node[index] = value
+			return node, nil
+		}
+		return nil, fmt.Errorf("%w: '%s' isn't on an object or list", ErrPathNotFound, token)
+	})
+}
+
+// The function replaces the value referenced by a pointer, which must exist.
+func replace(document interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return change(document, path, func(parent interface{}, token string) (interface{}, error) {
+		if _, err := child(parent, token); err != nil {
+			return nil, err
+		}
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			node[token] = value
+		case []interface{}:
+			index, _ := parseIndex(token, len(node)-1)
+			node[index] = value
+		}
+		return parent, nil
+	})
+}
+
+// The function removes the value referenced by a pointer, which must exist, returning it.
+func remove(document interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("%w: the whole document can't be removed", ErrInvalidPatch)
+	}
+
+	var removed interface{}
+	document, err := change(document, path, func(parent interface{}, token string) (interface{}, error) {
+		var err error
+		removed, err = child(parent, token)
+		if err != nil {
+			return nil, err
+		}
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			delete(node, token)
+		case []interface{}:
+			index, _ := parseIndex(token, len(node)-1)
+			return append(node[:index], node[index+1:]...), nil
+		}
+		return parent, nil
+	})
+
+	return document, removed, err
+}
+
+// The function parses the index of a list item, from zero up to the max index.
+func parseIndex(token string, max int) (int, error) {
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: '%s' isn't an index", ErrPathNotFound, token)
+	}
+	if index > max {
+		return 0, fmt.Errorf("%w: the index %d is out of range", ErrPathNotFound, index)
+	}
+	return index, nil
+}
+
+// The function copies the objects and lists of a value, so the copy can be changed without changing it.
+func clone(value interface{}) interface{} {
+	switch node := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(node))
+		for key, member := range node {
+			result[key] = clone(member)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(node))
+		for index, item := range node {
+			result[index] = clone(item)
+		}
+		return result
+	}
+	return value
+}
+
+// The function compares two JSON values, with the numbers compared by their values, like `1` and `1.0`.
+func equal(a interface{}, b interface{}) bool {
+	switch x := a.(type) {
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for key, member := range x {
+			other, ok := y[key]
+			if !ok || !equal(member, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for index := range x {
+			if !equal(x[index], y[index]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		m, _, errX := big.ParseFloat(string(x), 10, 256, big.ToNearestEven)
+		n, _, errY := big.ParseFloat(string(y), 10, 256, big.ToNearestEven)
+		return errX == nil && errY == nil && m.Cmp(n) == 0
+	}
+	return a == b
+}
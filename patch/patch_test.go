@@ -0,0 +1,94 @@
+package patch_test
+
+import (
+	"testing"
+
+	"github.com/bancodobrasil/featws-api/patch"
+	"github.com/stretchr/testify/assert"
+)
+
+// This tests the merge patches, with the examples of the RFC 7396.
+func TestMerge(t *testing.T) {
+	for _, testCase := range []struct {
+		document string
+		patch    string
+		expected string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		{`{"price":12345678901234567890}`, `{"name":"x"}`, `{"name":"x","price":12345678901234567890}`},
+	} {
+		result, err := patch.Merge([]byte(testCase.document), []byte(testCase.patch))
+		assert.NoError(t, err, testCase.patch)
+		assert.JSONEq(t, testCase.expected, string(result), testCase.patch)
+	}
+
+	_, err := patch.Merge([]byte(`{}`), []byte(`{"a":`))
+	assert.ErrorIs(t, err, patch.ErrInvalidPatch)
+}
+
+// This tests the operations of the JSON Patches, with examples of the RFC 6902.
+func TestApply(t *testing.T) {
+	for _, testCase := range []struct {
+		document string
+		patch    string
+		expected string
+	}{
+		{`{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{`{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{`{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{`{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{`{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{`{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{`{"foo":{"bar":[1]}}`, `[{"op":"copy","from":"/foo/bar","path":"/baz"},{"op":"add","path":"/baz/-","value":2}]`, `{"foo":{"bar":[1]},"baz":[1,2]}`},
+		{`{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2.0}]`, `{"baz":"qux","foo":["a",2,"c"]}`},
+		{`{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10},{"op":"replace","path":"/~1","value":null}]`, `{"/":null,"~1":10}`},
+		{`{"foo":"bar"}`, `[{"op":"replace","path":"","value":{"baz":"qux"}}]`, `{"baz":"qux"}`},
+		{`{"a":{"b":[{"c":1}]}}`, `[{"op":"replace","path":"/a/b/0/c","value":2}]`, `{"a":{"b":[{"c":2}]}}`},
+	} {
+		result, err := patch.Apply([]byte(testCase.document), []byte(testCase.patch))
+		assert.NoError(t, err, testCase.patch)
+		assert.JSONEq(t, testCase.expected, string(result), testCase.patch)
+	}
+}
+
+// This tests that the JSON Patches that can't be applied return the error of the operation that failed.
+func TestApplyWithError(t *testing.T) {
+	for _, testCase := range []struct {
+		document string
+		patch    string
+		expected error
+	}{
+		{`{}`, `{"op":"add"}`, patch.ErrInvalidPatch},
+		{`{}`, `[{"op":"unknown","path":"/a"}]`, patch.ErrInvalidPatch},
+		{`{}`, `[{"op":"add","path":"/a"}]`, patch.ErrInvalidPatch},
+		{`{}`, `[{"op":"add","value":1}]`, patch.ErrInvalidPatch},
+		{`{}`, `[{"op":"add","path":"a","value":1}]`, patch.ErrInvalidPatch},
+		{`{"a":{"b":1}}`, `[{"op":"move","from":"/a","path":"/a/c"}]`, patch.ErrInvalidPatch},
+		{`{}`, `[{"op":"remove","path":""}]`, patch.ErrInvalidPatch},
+		{`{"baz":"qux"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`, patch.ErrPathNotFound},
+		{`{"foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, patch.ErrPathNotFound},
+		{`{"foo":"bar"}`, `[{"op":"replace","path":"/baz","value":1}]`, patch.ErrPathNotFound},
+		{`{"foo":[1,2]}`, `[{"op":"add","path":"/foo/3","value":3}]`, patch.ErrPathNotFound},
+		{`{"foo":[1,2]}`, `[{"op":"remove","path":"/foo/01"}]`, patch.ErrPathNotFound},
+		{`{"foo":[1,2]}`, `[{"op":"copy","from":"/bar","path":"/baz"}]`, patch.ErrPathNotFound},
+		{`{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`, patch.ErrTestFailed},
+		{`{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":null}]`, patch.ErrTestFailed},
+	} {
+		_, err := patch.Apply([]byte(testCase.document), []byte(testCase.patch))
+		assert.ErrorIs(t, err, testCase.expected, testCase.patch)
+	}
+}
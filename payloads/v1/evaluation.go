@@ -0,0 +1,11 @@
+package v1
+
+// Evaluation contains the input of a rulesheet evaluation.
+//
+// Property:
+//   - Version: the version number or commit SHA of the rulesheet to evaluate. The current content is evaluated when it's empty.
+//   - Parameters: the values of the parameters referenced by the rules, by name.
+type Evaluation struct {
+	Version    string                 `json:"version,omitempty"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
@@ -0,0 +1,9 @@
+package v1
+
+// Rollback contains the input of a rulesheet rollback.
+//
+// Property:
+//   - Version: the version number or commit SHA whose content will be restored on the rulesheet.
+type Rollback struct {
+	Version string `json:"version,omitempty" validate:"required"`
+}
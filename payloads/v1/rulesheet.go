@@ -15,17 +15,19 @@ package v1
 //   - Slug: is a string property in the Rulesheet struct that represents a unique identifier for the rulesheet. It is typically used in URLs to identify and access a specific rulesheet.
 //   - Version: Represents the version number of the rulesheet.
 //   - HasStringRule - The HasStringRule property is a boolean value that indicates whether the rulesheet contains a string rule or not. If it is true, it means that the rulesheet has at least one rule that involves a string value. If it is false, it means that all the rules in the rulesheet involve
-//   - Features: It is a pointer to a slice of maps that represent the features of the rulesheet. Each map contains key-value pairs, where the key represents the feature name as a string, and the value is an interface that allows for flexibility in defining different types of feature values.
-//   - Parameters: It is a pointer to a slice of maps, where each map represents a parameter used in the rules defined within the "Rules" property. Each map consists of key-value pairs, with the key being a string representing the parameter name, and the value being an interface. This design allows for flexibility in defining various types of parameter values.
+//   - Features: the features declared by the rulesheet, each one with a unique name.
+//   - Parameters: the parameters declared by the rulesheet and used by the rules defined within the "Rules" property, each one with a unique name.
 //   - Rules: a pointer to a map of string keys and interface values. This is likely where the actual rules for the rulesheet are stored. The keys in the map would likely correspond to some sort of rule identifier or name, and the values would contain the logic or conditions for.
+//   - Tests: the test cases of the rulesheet, each one with the values of the parameters and the expected values of the features. When it's omitted on an update, the test cases already committed are kept.
 type Rulesheet struct {
-	ID            uint                      `json:"id,omitempty"`
-	Name          string                    `json:"name,omitempty" validate:"required"`
-	Description   string                    `json:"description,omitempty"`
-	Slug          string                    `json:"slug,omitempty"`
-	Version       string                    `json:"version,omitempty"`
-	HasStringRule bool                      `json:"hasStringRule,omitempty"`
-	Features      *[]map[string]interface{} `json:"features,omitempty"`
-	Parameters    *[]map[string]interface{} `json:"parameters,omitempty"`
-	Rules         *map[string]interface{}   `json:"rules,omitempty"`
+	ID            uint                    `json:"id,omitempty"`
+	Name          string                  `json:"name,omitempty" validate:"required"`
+	Description   string                  `json:"description,omitempty"`
+	Slug          string                  `json:"slug,omitempty"`
+	Version       string                  `json:"version,omitempty"`
+	HasStringRule bool                    `json:"hasStringRule,omitempty"`
+	Features      *[]Feature              `json:"features,omitempty" validate:"omitempty,unique=Name,dive"`
+	Parameters    *[]Parameter            `json:"parameters,omitempty" validate:"omitempty,unique=Name,dive"`
+	Rules         *map[string]interface{} `json:"rules,omitempty"`
+	Tests         *[]TestCase             `json:"tests,omitempty" validate:"omitempty,dive"`
 }
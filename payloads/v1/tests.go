@@ -0,0 +1,13 @@
+package v1
+
+// TestCase contains a test case of a rulesheet.
+//
+// Property:
+//   - Name: the name of the test case.
+//   - Parameters: the values of the parameters the rules are evaluated with, by name.
+//   - Expected: the expected values of the features, by name. The features that aren't listed aren't checked.
+type TestCase struct {
+	Name       string                 `json:"name" validate:"required"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Expected   map[string]interface{} `json:"expected" validate:"required"`
+}
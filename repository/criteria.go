@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// The operators of the conditions of a Criteria.
+const (
+	OperatorEqual    = "equal"
+	OperatorPrefix   = "prefix"
+	OperatorContains = "contains"
+	OperatorFrom     = "from"
+	OperatorTo       = "to"
+)
+
+// The states of the soft deleted entities matched by a Criteria.
+const (
+	DeletedExclude = "exclude"
+	DeletedOnly    = "only"
+	DeletedInclude = "include"
+)
+
+// Condition is a condition on the columns of the entities found by the repository.
+//
+// Property:
+//   - Fields: the names of the columns the condition is checked on. The condition is met when any of them matches.
+//   - Operator: one of `OperatorEqual`, `OperatorPrefix` and `OperatorContains`, for the text columns, whose prefix and content are matched ignoring the case, or `OperatorFrom` and `OperatorTo`, for the inclusive bounds of the columns of dates.
+//   - Value: the value the columns are compared to.
+type Condition struct {
+	Fields   []string
+	Operator string
+	Value    interface{}
+}
+
+// Criteria is the criteria of the entities found or counted by the repository.
+//
+// Property:
+//   - Conditions: the conditions that must all be met by the entities.
+//   - Deleted: the state of the soft deleted entities: `DeletedExclude`, which is the default, to ignore them, `DeletedOnly` to find only them or `DeletedInclude` to find them along the others.
+type Criteria struct {
+	Conditions []Condition
+	Deleted    string
+}
+
+// Equal creates a condition that matches the entities whose column is equal to the value.
+func Equal(field string, value interface{}) Condition {
+	return Condition{Fields: []string{field}, Operator: OperatorEqual, Value: value}
+}
+
+// Prefix creates a condition that matches the entities whose column starts with the prefix, ignoring the case.
+func Prefix(field string, prefix string) Condition {
+	return Condition{Fields: []string{field}, Operator: OperatorPrefix, Value: prefix}
+}
+
+// Contains creates a condition that matches the entities where any of the columns contains the text,
+// ignoring the case.
+func Contains(text string, fields ...string) Condition {
+	return Condition{Fields: fields, Operator: OperatorContains, Value: text}
+}
+
+// From creates a condition that matches the entities whose column of dates is at or after the moment.
+func From(field string, moment time.Time) Condition {
+	return Condition{Fields: []string{field}, Operator: OperatorFrom, Value: moment}
+}
+
+// To creates a condition that matches the entities whose column of dates is at or before the moment.
+func To(field string, moment time.Time) Condition {
+	return Condition{Fields: []string{field}, Operator: OperatorTo, Value: moment}
+}
+
+// likeEscaper escapes the wildcards of the LIKE patterns with `!`, which is escaped the same way on every
+// database, unlike the backslash, so the values are matched literally.
+var likeEscaper = strings.NewReplacer(`!`, `!!`, `%`, `!%`, `_`, `!_`)
+
+// apply adds the criteria to a query. A nil criteria finds every entity that isn't deleted.
+func (c *Criteria) apply(db *gorm.DB) *gorm.DB {
+	if c == nil {
+		return db
+	}
+
+	switch c.Deleted {
+	case DeletedOnly:
+		db = db.Unscoped().Where("deleted_at IS NOT NULL")
+	case DeletedInclude:
+		db = db.Unscoped()
+	}
+
+	for _, condition := range c.Conditions {
+		expressions := make([]clause.Expression, 0, len(condition.Fields))
+		for _, field := range condition.Fields {
+			expressions = append(expressions, condition.expression(clause.Column{Name: field}))
+		}
+		// a single expression is added as it is, since gorm joins a lone OR to the previous conditions
+		if len(expressions) == 1 {
+			db = db.Where(expressions[0])
+			continue
+		}
+		db = db.Where(clause.Or(expressions...))
+	}
+
+	return db
+}
+
+// The function returns the expression of the condition on a column.
+func (c Condition) expression(column clause.Column) clause.Expression {
+	switch c.Operator {
+	case OperatorPrefix, OperatorContains:
+		pattern := likeEscaper.Replace(strings.ToLower(c.Value.(string))) + "%"
+		if c.Operator == OperatorContains {
+			pattern = "%" + pattern
+		}
+		return clause.Expr{SQL: `LOWER(?) LIKE ? ESCAPE '!'`, Vars: []interface{}{column, pattern}}
+	case OperatorFrom:
+		return clause.Gte{Column: column, Value: c.Value}
+	case OperatorTo:
+		return clause.Lte{Column: column, Value: c.Value}
+	}
+	return clause.Eq{Column: column, Value: c.Value}
+}
@@ -10,17 +10,32 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// FindOptions type defines options for limiting and paging search results.
+// FindOptions type defines options for sorting, limiting and paging search results.
 //
 // Property:
 //
 //   - Limit: an integer that specifies the maximum number of results to be returned by a search or query. It's often used in conjunction with the Page property to implement pagination.
 //   - Page:  an integer that represents the current page number in a paginated result set. It's often used in combination with the Limit property to determine which subset of data to return.
+//   - Sort: the columns the results are ordered by, in order of precedence. The last one should be unique, like the ID, so the order is stable.
+//   - After: the values of the Sort columns of the last result of the previous page, for the keyset pagination, which finds the results after it instead of skipping the ones of the previous pages. The Page is ignored when it's set.
 type FindOptions struct {
 	Limit int
 	Page  int
+	Sort  []Sort
+	After []interface{}
+}
+
+// Sort is a column the results of a search are ordered by.
+//
+// Property:
+//   - Column: the name of the column.
+//   - Desc: whether the results are ordered from the greatest value to the lowest one.
+type Sort struct {
+	Column string
+	Desc   bool
 }
 
 // Repository defines a set of methods for interacting with a database using GORM in Go.
@@ -30,10 +45,10 @@ type FindOptions struct {
 //   - GetDB: returns a pointer to a gorm.DB instance that allows performing database operations.
 //   - Create: creates a new entity of type T in the database.
 //   - CreateInTransaction: creates a new entity in the database within a transaction. It takes a context.Context and a *gorm.DB as parameters, along with a pointer to the entity to be created. It returns an error if the creation fails.
-//   - Find: retrieves a list of entities from the repository based on specified criteria in the FindOptions parameter. It returns a slice of pointers to the type T and an error if any occurred during the operation.
-//   - FindInTransaction: finds multiple entities in a transactional context. It takes a context.Context object, a *gorm.DB object representing the transaction, an interface object representing the entity to be found, and a *FindOptions object representing the options for the find operation. It returns a slice of pointers to the found entities.
-//   - Count: returns the number of entities in the repository.
-//   - CountInTransaction: : counts the number of entities in the database within a transaction. It takes a context.Context and a *gorm.DB as parameters, along with an entity interface representing the type of entity to count. It returns the count as an int64 and an error if any occurred.
+//   - Find: retrieves a list of entities from the repository that meet the given Criteria, limited and paged by the FindOptions parameter. It returns a slice of pointers to the type T and an error if any occurred during the operation.
+//   - FindInTransaction: finds multiple entities in a transactional context. It takes a context.Context object, a *gorm.DB object representing the transaction, the Criteria of the entities to be found, and a *FindOptions object representing the options for the find operation. It returns a slice of pointers to the found entities.
+//   - Count: returns the number of entities in the repository that meet the given Criteria.
+//   - CountInTransaction: : counts the number of entities in the database within a transaction. It takes a context.Context and a *gorm.DB as parameters, along with the Criteria of the entities to count. It returns the count as an int64 and an error if any occurred.
 //   - Get: retrieves a single entity of type T from the repository based on the provided ID. It returns the retrieved entity and an error if any occurred during the retrieval process.
 //   - GetInTransaction: retrieves a single entity of type T from the database within a transaction. It takes a context.Context and a *gorm.DB as parameters and returns a pointer to the retrieved entity of type T and an error if any.
 //   - Update: updates an existing entity in the repository. It takes a context.Context object and an entity of type T as input and returns the updated entity of type T and an error. If the update is successful, the updated entity is returned; otherwise, an error is returned.
@@ -44,10 +59,10 @@ type Repository[T any] interface {
 	GetDB() *gorm.DB
 	Create(ctx context.Context, entity *T) error
 	CreateInTransaction(ctx context.Context, db *gorm.DB, entity *T) error
-	Find(ctx context.Context, entity interface{}, options *FindOptions) (list []*T, err error)
-	FindInTransaction(ctx context.Context, db *gorm.DB, entity interface{}, options *FindOptions) (list []*T, err error)
-	Count(ctx context.Context, entity interface{}) (count int64, err error)
-	CountInTransaction(ctx context.Context, db *gorm.DB, entity interface{}) (count int64, err error)
+	Find(ctx context.Context, criteria *Criteria, options *FindOptions) (list []*T, err error)
+	FindInTransaction(ctx context.Context, db *gorm.DB, criteria *Criteria, options *FindOptions) (list []*T, err error)
+	Count(ctx context.Context, criteria *Criteria) (count int64, err error)
+	CountInTransaction(ctx context.Context, db *gorm.DB, criteria *Criteria) (count int64, err error)
 	Get(ctx context.Context, id string) (entity *T, err error)
 	GetInTransaction(ctx context.Context, db *gorm.DB, id string) (entity *T, err error)
 	Update(ctx context.Context, entity T) (updated *T, err error)
@@ -115,21 +130,21 @@ func (r *repository[T]) CreateInTransaction(ctx context.Context, db *gorm.DB, en
 }
 
 // Find is a method of the `repository` struct that finds a list of entities from the
-// repository based on specified criteria in the `FindOptions` parameter. It takes a `context.Context`
-// object, an `interface{}` object representing the entity to be found, and a pointer to a
+// repository that meet the given `Criteria`, limited and paged by the `FindOptions` parameter. It takes a
+// `context.Context` object, the `Criteria` of the entities to be found, and a pointer to a
 // `FindOptions` object representing the options for the find operation as input parameters. It returns
 // a slice of pointers to the type `T` and an error if any occurred during the operation.
-func (r *repository[T]) Find(ctx context.Context, entity interface{}, options *FindOptions) (list []*T, err error) {
+func (r *repository[T]) Find(ctx context.Context, criteria *Criteria, options *FindOptions) (list []*T, err error) {
 	db := r.newSession(ctx)
-	return r.FindInTransaction(ctx, db, entity, options)
+	return r.FindInTransaction(ctx, db, criteria, options)
 }
 
 // FindInTransaction is a method of a generic repository that finds a list of entities in a database
-// transaction using GORM. It takes in a context, a GORM database instance, an entity interface, and
-// optional find options. It starts a new span on the context for tracing purposes, applies any
+// transaction using GORM. It takes in a context, a GORM database instance, the criteria of the entities,
+// and optional find options. It starts a new span on the context for tracing purposes, applies any
 // specified find options to the database query, executes the query using GORM's Find method, and
 // returns the resulting list of entities or an error if there was one.
-func (r *repository[T]) FindInTransaction(ctx context.Context, db *gorm.DB, entity interface{}, options *FindOptions) (list []*T, err error) {
+func (r *repository[T]) FindInTransaction(ctx context.Context, db *gorm.DB, criteria *Criteria, options *FindOptions) (list []*T, err error) {
 	// add the span of database query on the root span of the context
 	tracer := telemetry.GetTracer(ctx)
 	ctx, span := tracer.Start(ctx, "repo-find", trace.WithSpanKind(trace.SpanKindInternal))
@@ -144,14 +159,22 @@ func (r *repository[T]) FindInTransaction(ctx context.Context, db *gorm.DB, enti
 			limit = options.Limit
 		}
 		db = db.Limit(limit)
-		if options.Page != 0 {
+		if options.Page != 0 && len(options.After) == 0 {
 			db = db.Offset((options.Page - 1) * limit)
 		}
+
+		for _, sort := range options.Sort {
+			db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: sort.Column}, Desc: sort.Desc})
+		}
+
+		if len(options.After) > 0 {
+			db = db.Where(keyset(options.Sort, options.After))
+		}
 	}
 
-	// The code below queries the database using the Find method, searching for parameters corresponding
-	// to entity, and the results are returned in list.
-	result := db.Find(&list, entity)
+	// The code below queries the database using the Find method, searching for the entities that meet
+	// the criteria, and the results are returned in list.
+	result := criteria.apply(db).Find(&list)
 
 	err = result.Error
 	if err != nil {
@@ -162,20 +185,53 @@ func (r *repository[T]) FindInTransaction(ctx context.Context, db *gorm.DB, enti
 	return
 }
 
-// Count is a method that counts the number of entities in a db table. It takes a context and an entity as input parameters and returns the
+// keyset returns the condition of the results after the given values of the sort columns: the ones whose
+// first column is after its value, or whose first column is equal to it and the second one is after its
+// value, and so on.
+func keyset(sort []Sort, after []interface{}) clause.Expression {
+	alternatives := make([]clause.Expression, 0, len(sort))
+
+	for index := range sort {
+		if index >= len(after) {
+			break
+		}
+
+		conditions := make([]clause.Expression, 0, index+1)
+		for previous := 0; previous < index; previous++ {
+			conditions = append(conditions, clause.Eq{Column: clause.Column{Name: sort[previous].Column}, Value: after[previous]})
+		}
+
+		column := clause.Column{Name: sort[index].Column}
+		if sort[index].Desc {
+			conditions = append(conditions, clause.Lt{Column: column, Value: after[index]})
+		} else {
+			conditions = append(conditions, clause.Gt{Column: column, Value: after[index]})
+		}
+
+		alternatives = append(alternatives, clause.And(conditions...))
+	}
+
+	// a single alternative is returned as it is, since gorm joins a lone OR to the previous conditions
+	if len(alternatives) == 1 {
+		return alternatives[0]
+	}
+	return clause.Or(alternatives...)
+}
+
+// Count is a method that counts the number of entities in a db table. It takes a context and a criteria as input parameters and returns the
 // count of entities and an error if any. It creates a new database session and calls the
-// CountInTransaction method passing the context, db session, and entity as parameters to count
-// the number of entities in the table.
-func (r *repository[T]) Count(ctx context.Context, entity interface{}) (count int64, err error) {
+// CountInTransaction method passing the context, db session, and criteria as parameters to count
+// the number of entities in the table that meet it.
+func (r *repository[T]) Count(ctx context.Context, criteria *Criteria) (count int64, err error) {
 	db := r.newSession(ctx)
-	return r.CountInTransaction(ctx, db, entity)
+	return r.CountInTransaction(ctx, db, criteria)
 }
 
-// CountInTransaction is a method that counts the number of records in a db table that match a given
-// entity. It takes a context, a db connection, and an entity as input parameters. It uses the OpenTelemetry
+// CountInTransaction is a method that counts the number of records in a db table that meet a given
+// criteria. It takes a context, a db connection, and a criteria as input parameters. It uses the OpenTelemetry
 // library to create a span for the db query and logs any errors that occur during the query. The method
 // returns the count of matching records and any errors encountered during the query.
-func (r *repository[T]) CountInTransaction(ctx context.Context, db *gorm.DB, entity interface{}) (count int64, err error) {
+func (r *repository[T]) CountInTransaction(ctx context.Context, db *gorm.DB, criteria *Criteria) (count int64, err error) {
 	// add the span of database query on the root span of the context
 	tracer := telemetry.GetTracer(ctx)
 	ctx, span := tracer.Start(ctx, "repo-count", trace.WithSpanKind(trace.SpanKindInternal))
@@ -183,9 +239,9 @@ func (r *repository[T]) CountInTransaction(ctx context.Context, db *gorm.DB, ent
 
 	count = 0
 
-	// The code is used to query a database using the Where method with a specific entity as a filter. It's
-	// used to determine the number of records that match the provided entity, and the result is stored in the count variable.
-	result := db.Where(entity).Count(&count)
+	// The code is used to query a database with the criteria as a filter. It's used to determine the number
+	// of records that meet the provided criteria, and the result is stored in the count variable.
+	result := criteria.apply(db).Count(&count)
 
 	err = result.Error
 	if err != nil {
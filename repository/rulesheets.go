@@ -1,14 +1,25 @@
 package repository
 
 import (
+	"context"
+	"errors"
+
 	"github.com/bancodobrasil/featws-api/database"
 	"github.com/bancodobrasil/featws-api/models"
+	telemetry "github.com/bancodobrasil/gin-telemetry"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
 )
 
-// Rulesheets is defining an interface that has a single method signature `Repository[models.Rulesheet]` that is defined in repository.go.
+// Rulesheets is defining an interface with the generic `Repository[models.Rulesheet]` methods, that are defined in repository.go,
+// and the queries of the rulesheets.
+//
+// Property:
+//   - GetBySlug: returns the rulesheet with the given slug, or nil if there's none.
 type Rulesheets interface {
 	Repository[models.Rulesheet]
+	GetBySlug(ctx context.Context, slug string) (*models.Rulesheet, error)
 }
 
 // rulesheets contains an array of "Rulesheet" objects within a "repository" field.
@@ -51,3 +62,25 @@ func NewRulesheetsWithDB(db *gorm.DB) (Rulesheets, error) {
 		},
 	}, err
 }
+
+// GetBySlug retrieves the rulesheet with the given slug, which is the identity used by the consumers and the
+// storage backend. It returns nil if there's no rulesheet with the slug, or if it's deleted.
+func (r *rulesheets) GetBySlug(ctx context.Context, slug string) (*models.Rulesheet, error) {
+	// add the span of database query on the root span of the context
+	tracer := telemetry.GetTracer(ctx)
+	ctx, span := tracer.Start(ctx, "repo-get-by-slug", trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	var entity models.Rulesheet
+
+	result := r.newSession(ctx).Where("slug = ?", slug).First(&entity)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.WithContext(ctx).Errorf("Error on find rulesheet by slug: %v", result.Error)
+		return nil, result.Error
+	}
+
+	return &entity, nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bancodobrasil/featws-api/database"
+	"github.com/bancodobrasil/featws-api/models"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Syncs defines the repository of the outbox of the changes of the rulesheets, with the generic
+// `Repository[models.Sync]` methods and the queries used by the sync worker.
+//
+// Property:
+//   - GetLast: returns the last sync written for a rulesheet, or nil if there's none.
+//   - GetFirstUnsynced: returns the oldest sync of a rulesheet that isn't synced nor discarded yet, or nil if there's none.
+//   - FindDue: returns the IDs of the rulesheets with pending syncs that can be attempted at the given moment, from the oldest, leaving out the ones blocked by a failed sync.
+//   - Claim: locks a pending sync until the given moment, so no other worker pushes it meanwhile. It returns false if the sync is already locked or isn't pending anymore.
+type Syncs interface {
+	Repository[models.Sync]
+	GetLast(ctx context.Context, rulesheetID uint) (*models.Sync, error)
+	GetFirstUnsynced(ctx context.Context, rulesheetID uint) (*models.Sync, error)
+	FindDue(ctx context.Context, now time.Time, limit int) ([]uint, error)
+	Claim(ctx context.Context, id uint, now time.Time, until time.Time) (bool, error)
+}
+
+// syncs implements the `Syncs` repository over the generic repository of `models.Sync`.
+type syncs struct {
+	repository[models.Sync]
+}
+
+var instanceSyncs Syncs
+
+// GetSyncs returns an instance of the Syncs struct, creating it if it doesn't already exist.
+func GetSyncs() Syncs {
+	if instanceSyncs == nil {
+		i, err := NewSyncsWithDB(database.GetConn())
+		if err != nil {
+			panic(err)
+		}
+		instanceSyncs = i
+	}
+	return instanceSyncs
+}
+
+// NewSyncsWithDB creates a new instance of Syncs with a given db connection and performs db migration.
+func NewSyncsWithDB(db *gorm.DB) (Syncs, error) {
+	err := db.AutoMigrate(&models.Sync{})
+	if err != nil {
+		return nil, err
+	}
+	return &syncs{
+		repository[models.Sync]{
+			db: db,
+		},
+	}, nil
+}
+
+// GetLast returns the last sync written for a rulesheet, which holds its current sync status.
+func (r *syncs) GetLast(ctx context.Context, rulesheetID uint) (*models.Sync, error) {
+	return r.first(ctx, r.newSession(ctx).Where("rulesheet_id = ?", rulesheetID).Order("id DESC"))
+}
+
+// GetFirstUnsynced returns the oldest sync of a rulesheet that is pending or failed. The syncs of a rulesheet
+// are pushed in order, so it's the next one to be pushed. The discarded syncs are never pushed.
+func (r *syncs) GetFirstUnsynced(ctx context.Context, rulesheetID uint) (*models.Sync, error) {
+	return r.first(ctx, r.newSession(ctx).Where("rulesheet_id = ? AND status IN ?", rulesheetID, []string{models.SyncPending, models.SyncFailed}).Order("id ASC"))
+}
+
+// FindDue returns the IDs of the rulesheets with pending syncs whose next attempt is due and that aren't
+// locked by a worker, from the one with the oldest pending sync. The rulesheets with a failed sync are left
+// out, since their syncs wait for it to be retried or discarded, so they don't fill the batch of the worker.
+func (r *syncs) FindDue(ctx context.Context, now time.Time, limit int) (ids []uint, err error) {
+	result := r.newSession(ctx).
+		Where("status = ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?) AND (locked_until IS NULL OR locked_until < ?)", models.SyncPending, now, now).
+		Where("NOT EXISTS (SELECT 1 FROM syncs AS failed WHERE failed.rulesheet_id = syncs.rulesheet_id AND failed.status = ? AND failed.deleted_at IS NULL)", models.SyncFailed).
+		Group("rulesheet_id").
+		Order("MIN(created_at), rulesheet_id").
+		Limit(limit).
+		Pluck("rulesheet_id", &ids)
+
+	err = result.Error
+	if err != nil {
+		log.WithContext(ctx).Errorf("Error on find due syncs: %v", err)
+		return
+	}
+
+	return
+}
+
+// Claim locks a pending sync until the given moment with a conditional update, so only one worker succeeds
+// when many try to claim it at once.
+func (r *syncs) Claim(ctx context.Context, id uint, now time.Time, until time.Time) (bool, error) {
+	result := r.newSession(ctx).
+		Where("id = ? AND status = ? AND (locked_until IS NULL OR locked_until < ?)", id, models.SyncPending, now).
+		Update("locked_until", until)
+
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("Error on claim sync: %v", result.Error)
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// first returns the first sync of the query, or nil if there's none.
+func (r *syncs) first(ctx context.Context, db *gorm.DB) (*models.Sync, error) {
+	var entity models.Sync
+
+	result := db.First(&entity)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.WithContext(ctx).Errorf("Error on find sync: %v", result.Error)
+		return nil, result.Error
+	}
+
+	return &entity, nil
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/bancodobrasil/featws-api/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// This tests the query of the due rulesheets: the ones blocked by a failed sync are left out by the query,
+// before its limit, so more blocked rulesheets than the batch of the worker can't fill it and starve the
+// others, which are returned from the one with the oldest pending sync.
+func TestSyncs_FindDueSkipsBlockedRulesheets(t *testing.T) {
+	conn, mocks, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DriverName:                "mysql",
+		Conn:                      conn,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{})
+	assert.NoError(t, err)
+
+	now := time.Now()
+	mocks.ExpectQuery(regexp.QuoteMeta("SELECT `rulesheet_id` FROM `syncs` WHERE (status = ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?) AND (locked_until IS NULL OR locked_until < ?)) AND (NOT EXISTS (SELECT 1 FROM syncs AS failed WHERE failed.rulesheet_id = syncs.rulesheet_id AND failed.status = ? AND failed.deleted_at IS NULL)) AND `syncs`.`deleted_at` IS NULL GROUP BY `rulesheet_id` ORDER BY MIN(created_at), rulesheet_id LIMIT 2")).
+		WithArgs(models.SyncPending, now, now, models.SyncFailed).
+		WillReturnRows(sqlmock.NewRows([]string{"rulesheet_id"}).AddRow(5).AddRow(4))
+
+	repository := &syncs{repository[models.Sync]{db: db}}
+	ids, err := repository.FindDue(context.Background(), now, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{5, 4}, ids)
+	assert.NoError(t, mocks.ExpectationsWereMet())
+}
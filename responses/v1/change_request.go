@@ -0,0 +1,49 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/bancodobrasil/featws-api/dtos"
+)
+
+// ChangeRequest represents a pending change of a rulesheet, waiting on a merge request to be published.
+//
+// Property:
+//   - ID: the internal ID of the merge request on the rulesheet project, used to approve, publish or discard the change.
+//   - Title: the title of the merge request, i.e. the commit message of the change.
+//   - Description: the description of the merge request.
+//   - Branch: the draft branch that holds the change.
+//   - Author: the name of the author of the merge request.
+//   - State: the state of the merge request.
+//   - Approved: whether the change has all the approvals it requires to be published.
+//   - ApprovedBy: the names of the users that approved the change.
+//   - WebURL: the address of the merge request on GitLab.
+//   - CreatedAt: the moment the change was opened.
+type ChangeRequest struct {
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Branch      string     `json:"branch"`
+	Author      string     `json:"author,omitempty"`
+	State       string     `json:"state"`
+	Approved    bool       `json:"approved"`
+	ApprovedBy  []string   `json:"approvedBy"`
+	WebURL      string     `json:"webUrl,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+}
+
+// NewChangeRequest creates a new ChangeRequest object by copying data from a DTO object.
+func NewChangeRequest(dto *dtos.ChangeRequest) ChangeRequest {
+	return ChangeRequest{
+		ID:          dto.ID,
+		Title:       dto.Title,
+		Description: dto.Description,
+		Branch:      dto.Branch,
+		Author:      dto.Author,
+		State:       dto.State,
+		Approved:    dto.Approved,
+		ApprovedBy:  dto.ApprovedBy,
+		WebURL:      dto.WebURL,
+		CreatedAt:   dto.CreatedAt,
+	}
+}
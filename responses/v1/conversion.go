@@ -0,0 +1,24 @@
+package v1
+
+import "github.com/bancodobrasil/featws-api/dtos"
+
+// Conversion represents the result of converting the string rules of a legacy rulesheet into structured rules.
+//
+// Property:
+//   - Rulesheet: the rulesheet with the converted rules.
+//   - Errors: the problems found on the converted rules, with the path of the rule and the position on the expression.
+//   - Committed: whether the converted rules were saved, replacing the rules.featws file.
+type Conversion struct {
+	Rulesheet Rulesheet         `json:"rulesheet"`
+	Errors    []ValidationError `json:"errors,omitempty"`
+	Committed bool              `json:"committed"`
+}
+
+// NewConversion creates a new Conversion object by copying data from a DTO object.
+func NewConversion(dto *dtos.Conversion) Conversion {
+	return Conversion{
+		Rulesheet: NewRulesheet(dto.Rulesheet),
+		Errors:    newRuleErrors(dto.Errors),
+		Committed: dto.Committed,
+	}
+}
@@ -0,0 +1,58 @@
+package v1
+
+import "github.com/bancodobrasil/featws-api/dtos"
+
+// Change represents an entry that differs between two versions of a rulesheet.
+//
+// Property:
+//   - Kind: the kind of the change, one of `added`, `removed` or `changed`.
+//   - Name: the name of the feature or parameter, or the dotted path of the rule.
+//   - Old: the value on the `from` version.
+//   - New: the value on the `to` version.
+type Change struct {
+	Kind string      `json:"kind"`
+	Name string      `json:"name"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// Diff represents the semantic difference between two versions of a rulesheet.
+//
+// Property:
+//   - From: the version the changes are computed from.
+//   - To: the version the changes are computed to.
+//   - Features: the features added, removed or changed.
+//   - Parameters: the parameters added, removed or changed.
+//   - Rules: the rules added, removed or changed.
+type Diff struct {
+	From       string   `json:"from"`
+	To         string   `json:"to"`
+	Features   []Change `json:"features"`
+	Parameters []Change `json:"parameters"`
+	Rules      []Change `json:"rules"`
+}
+
+// NewDiff creates a new Diff object by copying data from a DTO object.
+func NewDiff(dto *dtos.Diff) Diff {
+	return Diff{
+		From:       dto.From,
+		To:         dto.To,
+		Features:   newChanges(dto.Features),
+		Parameters: newChanges(dto.Parameters),
+		Rules:      newChanges(dto.Rules),
+	}
+}
+
+// The function copies a list of changes from the DTO objects.
+func newChanges(dtos []*dtos.Change) []Change {
+	changes := make([]Change, len(dtos))
+	for index, dto := range dtos {
+		changes[index] = Change{
+			Kind: dto.Kind,
+			Name: dto.Name,
+			Old:  dto.Old,
+			New:  dto.New,
+		}
+	}
+	return changes
+}
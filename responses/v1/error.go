@@ -19,8 +19,10 @@ type Error struct {
 //   - Field: is a string property that represents the name of the field that has a validation error.
 //   - Tag: a string property representing the violated validation rule. For instance, if a required field is missing, the Tag property would be "required".
 //   - Error: a string property that describes the error message related to the validation error. It provides details about what went wrong during the validation process.
+//   - Position: the position of the error on an expression of a rule, counted in characters from 1, when the field is an expression.
 type ValidationError struct {
-	Field string `json:"field"`
-	Tag   string `json:"tag"`
-	Error string `json:"error"`
+	Field    string `json:"field"`
+	Tag      string `json:"tag"`
+	Error    string `json:"error"`
+	Position int    `json:"position,omitempty"`
 }
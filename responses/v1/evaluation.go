@@ -0,0 +1,62 @@
+package v1
+
+import "github.com/bancodobrasil/featws-api/dtos"
+
+// RuleResult represents a rule computed by an evaluation of a rulesheet.
+//
+// Property:
+//   - Path: the path of the rule on the rulesheet, like `rules.discount[1]`.
+//   - Condition: the condition of the rule, empty when the rule always applies.
+//   - Matched: whether the condition of the rule was met, so its value was used.
+type RuleResult struct {
+	Path      string `json:"path"`
+	Condition string `json:"condition,omitempty"`
+	Matched   bool   `json:"matched"`
+}
+
+// Evaluation represents the features of a rulesheet computed for a set of parameters.
+//
+// Property:
+//   - Version: the version of the rulesheet that was evaluated.
+//   - Features: the computed value of each feature, by name.
+//   - Rules: the rules that were computed and whether their conditions were met.
+//   - Errors: the problems found while computing the rules, with the path of the rule and the position on the expression.
+type Evaluation struct {
+	Version  string                 `json:"version"`
+	Features map[string]interface{} `json:"features"`
+	Rules    []RuleResult           `json:"rules"`
+	Errors   []ValidationError      `json:"errors,omitempty"`
+}
+
+// NewEvaluation creates a new Evaluation object by copying data from a DTO object.
+func NewEvaluation(dto *dtos.Evaluation) Evaluation {
+	rules := make([]RuleResult, len(dto.Rules))
+	for index, rule := range dto.Rules {
+		rules[index] = RuleResult{
+			Path:      rule.Path,
+			Condition: rule.Condition,
+			Matched:   rule.Matched,
+		}
+	}
+
+	return Evaluation{
+		Version:  dto.Version,
+		Features: dto.Features,
+		Rules:    rules,
+		Errors:   newRuleErrors(dto.Errors),
+	}
+}
+
+// The function copies a list of problems found on the rules from the DTO objects.
+func newRuleErrors(dtos []*dtos.RuleError) []ValidationError {
+	errors := make([]ValidationError, len(dtos))
+	for index, dto := range dtos {
+		errors[index] = ValidationError{
+			Field:    dto.Path,
+			Tag:      dto.Tag,
+			Error:    dto.Message,
+			Position: dto.Position,
+		}
+	}
+	return errors
+}
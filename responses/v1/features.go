@@ -0,0 +1,134 @@
+package v1
+
+import (
+	"encoding/json"
+
+	"github.com/bancodobrasil/featws-api/dtos"
+	"github.com/bancodobrasil/featws-api/utils"
+)
+
+// Feature represents a feature declared by a rulesheet.
+//
+// Property:
+//   - Name: the name of the feature.
+//   - Type: the type of the value of the feature.
+//   - Description: the description of the feature.
+//   - Default: the value of the feature when none of its rules are met.
+//   - Extra: the members of the feature that aren't modeled by the other properties, kept as they are.
+type Feature struct {
+	Name        string                     `json:"name"`
+	Type        string                     `json:"type,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Default     interface{}                `json:"default,omitempty"`
+	Extra       map[string]json.RawMessage `json:"-"`
+}
+
+// Parameter represents a parameter declared by a rulesheet.
+//
+// Property:
+//   - Name: the name of the parameter.
+//   - Type: the type of the value of the parameter.
+//   - Description: the description of the parameter.
+//   - Default: the value of the parameter when it isn't given.
+//   - Required: whether the parameter must be given to evaluate the rulesheet.
+//   - Resolver: the name of the resolver that loads the value of the parameter.
+//   - Extra: the members of the parameter that aren't modeled by the other properties, kept as they are.
+type Parameter struct {
+	Name        string                     `json:"name"`
+	Type        string                     `json:"type,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Default     interface{}                `json:"default,omitempty"`
+	Required    bool                       `json:"required,omitempty"`
+	Resolver    string                     `json:"resolver,omitempty"`
+	Extra       map[string]json.RawMessage `json:"-"`
+}
+
+// The type has the fields of a feature, without its custom JSON encoding.
+type featureFields Feature
+
+// MarshalJSON encodes the feature with its extra members.
+func (f Feature) MarshalJSON() ([]byte, error) {
+	return utils.MarshalWithExtra(featureFields(f), f.Extra)
+}
+
+// UnmarshalJSON decodes the feature, keeping the members that aren't its fields on `Extra`.
+func (f *Feature) UnmarshalJSON(data []byte) error {
+	var fields featureFields
+	extra, err := utils.UnmarshalWithExtra(data, &fields)
+	if err != nil {
+		return err
+	}
+	*f = Feature(fields)
+	f.Extra = extra
+	return nil
+}
+
+// The type has the fields of a parameter, without its custom JSON encoding.
+type parameterFields Parameter
+
+// MarshalJSON encodes the parameter with its extra members.
+func (p Parameter) MarshalJSON() ([]byte, error) {
+	return utils.MarshalWithExtra(parameterFields(p), p.Extra)
+}
+
+// UnmarshalJSON decodes the parameter, keeping the members that aren't its fields on `Extra`.
+func (p *Parameter) UnmarshalJSON(data []byte) error {
+	var fields parameterFields
+	extra, err := utils.UnmarshalWithExtra(data, &fields)
+	if err != nil {
+		return err
+	}
+	*p = Parameter(fields)
+	p.Extra = extra
+	return nil
+}
+
+// NewFeature creates a Feature response from the DTO object.
+func NewFeature(dto dtos.Feature) Feature {
+	return Feature{
+		Name:        dto.Name,
+		Type:        dto.Type,
+		Description: dto.Description,
+		Default:     dto.Default,
+		Extra:       dto.Extra,
+	}
+}
+
+// NewParameter creates a Parameter response from the DTO object.
+func NewParameter(dto dtos.Parameter) Parameter {
+	return Parameter{
+		Name:        dto.Name,
+		Type:        dto.Type,
+		Description: dto.Description,
+		Default:     dto.Default,
+		Required:    dto.Required,
+		Resolver:    dto.Resolver,
+		Extra:       dto.Extra,
+	}
+}
+
+// The function copies a list of features from the DTO objects.
+func newFeatures(dtos *[]dtos.Feature) *[]Feature {
+	if dtos == nil {
+		return nil
+	}
+
+	features := make([]Feature, len(*dtos))
+	for index, dto := range *dtos {
+		features[index] = NewFeature(dto)
+	}
+	return &features
+}
+
+// The function copies a list of parameters from the DTO objects.
+func newParameters(dtos *[]dtos.Parameter) *[]Parameter {
+	if dtos == nil {
+		return nil
+	}
+
+	parameters := make([]Parameter, len(*dtos))
+	for index, dto := range *dtos {
+		parameters[index] = NewParameter(dto)
+	}
+	return &parameters
+}
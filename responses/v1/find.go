@@ -1,9 +1,17 @@
 package v1
 
-// FindResult is the result of a find request.
+// RulesheetPage is a page of the list of rulesheets.
 //
 // Property:
-//   - Count: is a field of the FindResult struct that represents the number of occurrences found during a search operation. It is of type int64, which means it can hold integervalues up to 64 bits in size. The `json:"count,omitempty"` tag is used to specify the field name in JSON.
-type FindResult struct {
-	Count int64 `json:"count,omitempty"`
+//   - Items: the rulesheets of the page.
+//   - Total: the number of rulesheets that match the filters, on every page.
+//   - Page: the number of the page, from 1, or zero when the page is found by a cursor.
+//   - Limit: the maximum number of rulesheets of the page.
+//   - Next: the cursor of the next page, passed on the `cursor` query parameter to find the rulesheets after the last one of this page. It's empty when there are no more rulesheets.
+type RulesheetPage struct {
+	Items []Rulesheet `json:"items"`
+	Total int64       `json:"total"`
+	Page  int         `json:"page"`
+	Limit int         `json:"limit"`
+	Next  string      `json:"next,omitempty"`
 }
@@ -0,0 +1,66 @@
+package v1
+
+import "github.com/bancodobrasil/featws-api/dtos"
+
+// GraphNode represents a rule, feature or parameter of the dependency graph of a rulesheet.
+//
+// Property:
+//   - ID: the unique identifier of the node, like `rule:discount` or `parameter:age`.
+//   - Kind: the kind of the node, one of `rule`, `feature` or `parameter`.
+//   - Name: the name of the rule, feature or parameter.
+type GraphNode struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// GraphEdge represents a dependency of a rule on a feature or parameter it references.
+//
+// Property:
+//   - From: the ID of the rule.
+//   - To: the ID of the referenced rule, feature or parameter.
+//   - Paths: the paths of the expressions of the rule where the node is referenced.
+type GraphEdge struct {
+	From  string   `json:"from"`
+	To    string   `json:"to"`
+	Paths []string `json:"paths"`
+}
+
+// Graph represents the dependency graph between the rules, features and parameters of a rulesheet.
+//
+// Property:
+//   - Nodes: the rules, features and parameters.
+//   - Edges: the dependencies of the rules.
+//   - Cycles: the circular dependencies between the rules, each one from the first rule back to itself.
+type Graph struct {
+	Nodes  []GraphNode `json:"nodes"`
+	Edges  []GraphEdge `json:"edges"`
+	Cycles [][]string  `json:"cycles"`
+}
+
+// NewGraph creates a new Graph object by copying data from a DTO object.
+func NewGraph(dto *dtos.Graph) Graph {
+	graph := Graph{
+		Nodes:  make([]GraphNode, len(dto.Nodes)),
+		Edges:  make([]GraphEdge, len(dto.Edges)),
+		Cycles: dto.Cycles,
+	}
+
+	for index, node := range dto.Nodes {
+		graph.Nodes[index] = GraphNode{
+			ID:   node.ID,
+			Kind: node.Kind,
+			Name: node.Name,
+		}
+	}
+
+	for index, edge := range dto.Edges {
+		graph.Edges[index] = GraphEdge{
+			From:  edge.From,
+			To:    edge.To,
+			Paths: edge.Paths,
+		}
+	}
+
+	return graph
+}
@@ -0,0 +1,13 @@
+package v1
+
+// Import represents the result of importing a rulesheet from uploaded files.
+//
+// Property:
+//   - Rulesheet: the imported rulesheet, with the warnings found on its rules.
+//   - Created: whether the rulesheet was created, or would be on a dry run, instead of updating the one with the same slug.
+//   - DryRun: whether the import was only validated, without saving the rulesheet.
+type Import struct {
+	Rulesheet Rulesheet `json:"rulesheet"`
+	Created   bool      `json:"created"`
+	DryRun    bool      `json:"dryRun"`
+}
@@ -0,0 +1,49 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/bancodobrasil/featws-api/dtos"
+)
+
+// Sync represents the sync of a change of a rulesheet with the storage backend.
+//
+// Property:
+//   - ID: the ID of the sync, used to retry it when it failed.
+//   - RulesheetID: the ID of the rulesheet that was changed.
+//   - CommitMessage: the commit message used when the change is saved on the storage backend.
+//   - Status: the status of the sync, `pending`, `synced`, `failed` or `discarded`.
+//   - Attempts: the number of attempts to push the change that failed.
+//   - Error: the error of the last failed attempt.
+//   - Version: the version published when the change was synced.
+//   - NextAttemptAt: the moment after which a pending sync is attempted again.
+//   - CreatedAt: the moment the rulesheet was changed.
+//   - SyncedAt: the moment the change was synced.
+type Sync struct {
+	ID            uint       `json:"id"`
+	RulesheetID   uint       `json:"rulesheetId"`
+	CommitMessage string     `json:"commitMessage,omitempty"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	Error         string     `json:"error,omitempty"`
+	Version       string     `json:"version,omitempty"`
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"`
+	CreatedAt     *time.Time `json:"createdAt,omitempty"`
+	SyncedAt      *time.Time `json:"syncedAt,omitempty"`
+}
+
+// NewSync creates a new Sync object by copying data from a DTO object.
+func NewSync(dto *dtos.Sync) Sync {
+	return Sync{
+		ID:            dto.ID,
+		RulesheetID:   dto.RulesheetID,
+		CommitMessage: dto.CommitMessage,
+		Status:        dto.Status,
+		Attempts:      dto.Attempts,
+		Error:         dto.Error,
+		Version:       dto.Version,
+		NextAttemptAt: dto.NextAttemptAt,
+		CreatedAt:     dto.CreatedAt,
+		SyncedAt:      dto.SyncedAt,
+	}
+}
@@ -0,0 +1,97 @@
+package v1
+
+import "github.com/bancodobrasil/featws-api/dtos"
+
+// TestCase represents a test case of a rulesheet.
+//
+// Property:
+//   - Name: the name of the test case.
+//   - Parameters: the values of the parameters the rules are evaluated with.
+//   - Expected: the expected values of the features.
+type TestCase struct {
+	Name       string                 `json:"name"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Expected   map[string]interface{} `json:"expected"`
+}
+
+// TestFailure represents a feature whose computed value doesn't match the expected one.
+//
+// Property:
+//   - Feature: the name of the feature.
+//   - Expected: the value expected by the test case.
+//   - Actual: the value computed by the rules.
+type TestFailure struct {
+	Feature  string      `json:"feature"`
+	Expected interface{} `json:"expected"`
+	Actual   interface{} `json:"actual"`
+}
+
+// TestResult represents the result of a test case of a rulesheet.
+//
+// Property:
+//   - Name: the name of the test case.
+//   - Passed: whether every expected feature matched the computed value.
+//   - Failures: the features whose computed values don't match the expected ones.
+//   - Errors: the problems found while computing the rules, with the path of the rule and the position on the expression.
+type TestResult struct {
+	Name     string            `json:"name"`
+	Passed   bool              `json:"passed"`
+	Failures []TestFailure     `json:"failures,omitempty"`
+	Errors   []ValidationError `json:"errors,omitempty"`
+}
+
+// TestRun represents the result of running the test cases of a rulesheet.
+//
+// Property:
+//   - Version: the version of the rulesheet the test cases were run against.
+//   - Passed: the number of test cases that passed.
+//   - Failed: the number of test cases that failed.
+//   - Results: the result of each test case.
+type TestRun struct {
+	Version string       `json:"version"`
+	Passed  int          `json:"passed"`
+	Failed  int          `json:"failed"`
+	Results []TestResult `json:"results"`
+}
+
+// NewTestRun creates a new TestRun object by copying data from a DTO object.
+func NewTestRun(dto *dtos.TestRun) TestRun {
+	results := make([]TestResult, len(dto.Results))
+	for index, result := range dto.Results {
+		failures := make([]TestFailure, len(result.Failures))
+		for i, failure := range result.Failures {
+			failures[i] = TestFailure{
+				Feature:  failure.Feature,
+				Expected: failure.Expected,
+				Actual:   failure.Actual,
+			}
+		}
+
+		results[index] = TestResult{
+			Name:     result.Name,
+			Passed:   result.Passed,
+			Failures: failures,
+			Errors:   newRuleErrors(result.Errors),
+		}
+	}
+
+	return TestRun{
+		Version: dto.Version,
+		Passed:  dto.Passed,
+		Failed:  dto.Failed,
+		Results: results,
+	}
+}
+
+// The function copies a list of test cases from the DTO objects.
+func newTestCases(dtos []dtos.TestCase) []TestCase {
+	tests := make([]TestCase, len(dtos))
+	for index, dto := range dtos {
+		tests[index] = TestCase{
+			Name:       dto.Name,
+			Parameters: dto.Parameters,
+			Expected:   dto.Expected,
+		}
+	}
+	return tests
+}
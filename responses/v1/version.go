@@ -0,0 +1,37 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/bancodobrasil/featws-api/dtos"
+)
+
+// Version represents a published version of a rulesheet.
+//
+// Property:
+//   - Version: the sequential version number stored on the VERSION file of the rulesheet.
+//   - Commit: the SHA of the commit that published the version.
+//   - Author: the name of the author of the commit.
+//   - AuthorEmail: the email of the author of the commit.
+//   - Date: the moment the version was published.
+//   - Message: the commit message used when the version was published.
+type Version struct {
+	Version     string     `json:"version"`
+	Commit      string     `json:"commit"`
+	Author      string     `json:"author,omitempty"`
+	AuthorEmail string     `json:"authorEmail,omitempty"`
+	Date        *time.Time `json:"date,omitempty"`
+	Message     string     `json:"message,omitempty"`
+}
+
+// NewVersion creates a new Version object by copying data from a DTO object.
+func NewVersion(dto *dtos.Version) Version {
+	return Version{
+		Version:     dto.Number,
+		Commit:      dto.Commit,
+		Author:      dto.Author,
+		AuthorEmail: dto.AuthorEmail,
+		Date:        dto.Date,
+		Message:     dto.Message,
+	}
+}
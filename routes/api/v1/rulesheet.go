@@ -1,7 +1,6 @@
 package v1
 
 import (
-	"github.com/bancodobrasil/featws-api/config"
 	v1 "github.com/bancodobrasil/featws-api/controllers/v1"
 	"github.com/bancodobrasil/featws-api/repository"
 	"github.com/bancodobrasil/featws-api/services"
@@ -9,20 +8,15 @@ import (
 )
 
 // rulesheetsRouter sets up the routing for CRUD operations on rulesheets using Gin framework
-func rulesheetsRouter(router *gin.RouterGroup) {
-
-	cfg := config.GetConfig()
+func rulesheetsRouter(router *gin.RouterGroup, backend services.Backend, syncs services.Syncs) {
 
 	// Repository enables the code to access the data repository and perform CRUD operations on the rulesheets.
 	repository := repository.GetRulesheets()
 
-	// The project connects to GitLab to access the rulesheets and perform CRUD operations
-	gitlabService := services.NewGitlab(cfg)
-
 	// The service variable is creating a new instance of the Rulesheets service from the services package,
-	// it takes the parameters: repository and gitlabService. These parameters allow the service to access
-	// the data repository and the GitLab service
-	service := services.NewRulesheets(repository, gitlabService)
+	// it takes the parameters: repository, backend and syncs. These parameters allow the service to access
+	// the data repository, the storage backend and the outbox of the changes pushed to it
+	service := services.NewRulesheets(repository, backend, syncs)
 
 	// The controller is creating a new instance of the "Rulesheets" controller from the "v1"
 	// package and passing an instance of the service as a parameter. This allows the controller
@@ -32,7 +26,36 @@ func rulesheetsRouter(router *gin.RouterGroup) {
 	// These are the API endpoints
 	router.POST("/", controller.CreateRulesheet())
 	router.GET("/", controller.GetRulesheets())
+	router.POST("/import", controller.ImportRulesheet())
+	router.POST("/convert", controller.ConvertRulesheets())
+	router.GET("/slug/:slug", controller.GetRulesheetBySlug())
+	router.PUT("/slug/:slug", controller.ResolveRulesheetSlug(), controller.UpdateRulesheet())
+	router.PATCH("/slug/:slug", controller.ResolveRulesheetSlug(), controller.PatchRulesheet())
+	router.DELETE("/slug/:slug", controller.ResolveRulesheetSlug(), controller.DeleteRulesheet())
 	router.GET("/:id", controller.GetRulesheet())
 	router.PUT("/:id", controller.UpdateRulesheet())
+	router.PATCH("/:id", controller.PatchRulesheet())
 	router.DELETE("/:id", controller.DeleteRulesheet())
+	router.GET("/:id/versions", controller.GetRulesheetVersions())
+	router.GET("/:id/versions/:version", controller.GetRulesheetVersion())
+	router.POST("/:id/rollback", controller.RollbackRulesheet())
+	router.GET("/:id/diff", controller.GetRulesheetDiff())
+	router.POST("/:id/evaluate", controller.EvaluateRulesheet())
+	router.POST("/:id/tests/run", controller.RunRulesheetTests())
+	router.GET("/:id/graph", controller.GetRulesheetGraph())
+	router.GET("/:id/export", controller.ExportRulesheet())
+	router.POST("/:id/convert", controller.ConvertRulesheet())
+	router.GET("/:id/changes", controller.GetRulesheetChanges())
+	router.POST("/:id/changes/:change/approve", controller.ApproveRulesheetChange())
+	router.POST("/:id/changes/:change/publish", controller.PublishRulesheetChange())
+	router.DELETE("/:id/changes/:change", controller.DiscardRulesheetChange())
+	router.GET("/:id/rules/:name", controller.GetRulesheetRule())
+	router.PUT("/:id/rules/:name", controller.UpdateRulesheetRule())
+	router.DELETE("/:id/rules/:name", controller.DeleteRulesheetRule())
+	router.GET("/:id/features/:name", controller.GetRulesheetFeature())
+	router.PUT("/:id/features/:name", controller.UpdateRulesheetFeature())
+	router.DELETE("/:id/features/:name", controller.DeleteRulesheetFeature())
+	router.GET("/:id/parameters/:name", controller.GetRulesheetParameter())
+	router.PUT("/:id/parameters/:name", controller.UpdateRulesheetParameter())
+	router.DELETE("/:id/parameters/:name", controller.DeleteRulesheetParameter())
 }
@@ -0,0 +1,19 @@
+package v1
+
+import (
+	v1 "github.com/bancodobrasil/featws-api/controllers/v1"
+	"github.com/bancodobrasil/featws-api/services"
+	"github.com/gin-gonic/gin"
+)
+
+// syncsRouter sets up the routing of the outbox of the changes of the rulesheets, so the operators can
+// follow the syncs with the storage backend and retry or discard the failed ones
+func syncsRouter(router *gin.RouterGroup, syncs services.Syncs) {
+
+	controller := v1.NewSyncs(syncs)
+
+	// These are the API endpoints
+	router.GET("/", controller.GetSyncs())
+	router.POST("/:id/retry", controller.RetrySync())
+	router.POST("/:id/discard", controller.DiscardSync())
+}
@@ -1,15 +1,35 @@
 package v1
 
 import (
+	"context"
+
+	"github.com/bancodobrasil/featws-api/config"
+	"github.com/bancodobrasil/featws-api/repository"
+	"github.com/bancodobrasil/featws-api/services"
 	goauthgin "github.com/bancodobrasil/goauth-gin"
 	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
 )
 
 // Router define routes the API V1
 func Router(router *gin.RouterGroup) {
 
+	cfg := config.GetConfig()
+
+	// The project stores the content of the rulesheets on the backend selected by the configuration, like GitLab
+	backend, err := services.NewBackend(cfg)
+	if err != nil {
+		log.Fatalf("Error on create the storage backend: %v", err)
+	}
+
+	// The changes of the rulesheets are written on an outbox and pushed to the storage backend, retrying the
+	// failed attempts on the background
+	syncs := services.NewSyncs(cfg, repository.GetSyncs(), backend)
+	go services.RunSyncWorker(context.Background(), syncs, cfg.SyncInterval)
+
 	// This code is defining the routes for the API v1.
 	router.Use(goauthgin.Authenticate())
-	rulesheetsRouter(router.Group("/rulesheets"))
+	rulesheetsRouter(router.Group("/rulesheets"), backend, syncs)
+	syncsRouter(router.Group("/syncs"), syncs)
 	//rpcRouter(router.Group("/"))
 }
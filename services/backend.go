@@ -0,0 +1,455 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bancodobrasil/featws-api/config"
+	"github.com/bancodobrasil/featws-api/dtos"
+	"github.com/bancodobrasil/go-featws"
+	log "github.com/sirupsen/logrus"
+)
+
+// Backend interface defines the methods of a storage for the content of the rulesheets: the VERSION, the
+// features, the parameters and the rules, together with the history of the versions published.
+//
+// Property:
+//   - Save: A method that stores the content of a `Rulesheet` as a new version, bumping its VERSION, with the provided commit message. When the `BaseVersion` of the `Rulesheet` is set and the current version is another one, it returns `ErrVersionConflict` without saving. The legacy rules.featws file is removed only when `RemoveLegacyRules` is set.
+//   - Fill: The method fills a `Rulesheet` with its current content.
+//   - FillRef: The method works like `Fill`, but reads the content of the `Rulesheet` at the given ref, as returned by `ResolveVersion`.
+//   - ResolveVersion: The method resolves a version number or a commit ID of a `Rulesheet` to the ref that holds it. It returns `ErrVersionNotFound` if there's no such version.
+//   - History: The method lists the versions published for a `Rulesheet`, from the newest to the oldest. The options parameter controls the pagination of the result.
+type Backend interface {
+	Save(rulesheet *dtos.Rulesheet, commitMessage string) error
+	Fill(rulesheet *dtos.Rulesheet) error
+	FillRef(rulesheet *dtos.Rulesheet, ref string) error
+	ResolveVersion(rulesheet *dtos.Rulesheet, version string) (string, error)
+	History(rulesheet *dtos.Rulesheet, options *FindOptions) ([]*dtos.Version, error)
+}
+
+// ChangeRequests interface defines the methods of a `Backend` that supports the review mode, where the changes
+// of a rulesheet wait on a merge request to be published.
+//
+// Property:
+//   - ListChangeRequests: The method lists the pending changes of a `Rulesheet`, committed to draft branches on review mode and waiting on a merge request.
+//   - ApproveChangeRequest: The method approves the merge request of a pending change.
+//   - PublishChangeRequest: The method merges an approved pending change into the default branch, bumping the VERSION and tagging the new version.
+//   - DiscardChangeRequest: The method closes the merge request of a pending change and deletes its draft branch.
+type ChangeRequests interface {
+	ListChangeRequests(rulesheet *dtos.Rulesheet) ([]*dtos.ChangeRequest, error)
+	ApproveChangeRequest(rulesheet *dtos.Rulesheet, id int) (*dtos.ChangeRequest, error)
+	PublishChangeRequest(rulesheet *dtos.Rulesheet, id int) error
+	DiscardChangeRequest(rulesheet *dtos.Rulesheet, id int) error
+}
+
+// ErrNotSupported is returned when an operation isn't supported by the configured backend.
+var ErrNotSupported = errors.New("operation not supported by the storage backend")
+
+// ErrVersionConflict is returned when a rulesheet is saved over a version other than the one its changes were based on.
+var ErrVersionConflict = errors.New("the rulesheet was changed by another update")
+
+// The storage backends that can be configured on `FEATWS_API_STORAGE`.
+const (
+	StorageGitlab = "gitlab"
+	StorageGithub = "github"
+	StorageGitea  = "gitea"
+	StorageLocal  = "local"
+)
+
+// NewBackend creates the storage backend selected by the configuration.
+func NewBackend(cfg *config.Config) (Backend, error) {
+	switch cfg.Storage {
+	case StorageGitlab, "":
+		if cfg.GitlabToken == "" {
+			log.Warn("FEATWS_API_GITLAB_TOKEN is empty: the features, parameters and rules of the rulesheets won't be stored. Set FEATWS_API_STORAGE=local to store them on the local filesystem")
+		}
+		return NewGitlab(cfg), nil
+	case StorageGithub:
+		if cfg.GithubToken == "" {
+			return nil, errors.New("FEATWS_API_GITHUB_TOKEN is required by the github storage backend")
+		}
+		return NewGithub(cfg), nil
+	case StorageGitea:
+		if cfg.GiteaToken == "" || cfg.GiteaURL == "" {
+			return nil, errors.New("FEATWS_API_GITEA_TOKEN and FEATWS_API_GITEA_URL are required by the gitea storage backend")
+		}
+		return NewGitea(cfg), nil
+	case StorageLocal:
+		return NewLocal(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend '%s'", cfg.Storage)
+	}
+}
+
+// checkBaseVersion checks that the current version of a rulesheet, read by the backend right before saving
+// it, is the version its changes were based on. There's nothing to check when the `BaseVersion` isn't set.
+func checkBaseVersion(rulesheet *dtos.Rulesheet, current string) error {
+	if rulesheet.BaseVersion == "" || rulesheet.BaseVersion == current {
+		return nil
+	}
+	return fmt.Errorf("%w: the changes are based on version %s, but the current version is %s", ErrVersionConflict, rulesheet.BaseVersion, current)
+}
+
+// marshalRulesheetFiles builds the content of the features.json, parameters.json and rules.json files of a
+// rulesheet. The features and the parameters are sorted by name, so the files don't change when only the
+// order of the payload does.
+func marshalRulesheetFiles(rulesheet *dtos.Rulesheet) (features []byte, parameters []byte, rules []byte, err error) {
+	// FEATURES
+	if rulesheet.Features == nil {
+		empty := make([]dtos.Feature, 0)
+		rulesheet.Features = &empty
+	}
+
+	sort.Slice(*rulesheet.Features, func(i, j int) bool {
+		return (*rulesheet.Features)[i].Name < (*rulesheet.Features)[j].Name
+	})
+
+	features, err = json.MarshalIndent(rulesheet.Features, "", "  ")
+	if err != nil {
+		log.Errorf("Failed to marshal features: %v", err)
+		return
+	}
+
+	// PARAMETERS
+	if rulesheet.Parameters == nil {
+		empty := make([]dtos.Parameter, 0)
+		rulesheet.Parameters = &empty
+	}
+
+	sort.Slice(*rulesheet.Parameters, func(i, j int) bool {
+		return (*rulesheet.Parameters)[i].Name < (*rulesheet.Parameters)[j].Name
+	})
+
+	parameters, err = json.MarshalIndent(rulesheet.Parameters, "", "  ")
+	if err != nil {
+		log.Errorf("Failed to marshal parameters: %v", err)
+		return
+	}
+
+	// Rules
+	if rulesheet.Rules == nil {
+		empty := make(map[string]interface{}, 0)
+		rulesheet.Rules = &empty
+	}
+
+	rules, err = json.MarshalIndent(rulesheet.Rules, "", "  ")
+	if err != nil {
+		log.Errorf("Failed to marshal rules: %v", err)
+		return
+	}
+
+	return
+}
+
+// marshalRulesheetTests builds the content of the tests.json file of a rulesheet. It returns nil when the
+// test cases weren't given, so the file already committed is kept.
+func marshalRulesheetTests(rulesheet *dtos.Rulesheet) ([]byte, error) {
+	if rulesheet.Tests == nil {
+		return nil, nil
+	}
+
+	tests, err := json.MarshalIndent(rulesheet.Tests, "", "  ")
+	if err != nil {
+		log.Errorf("Failed to marshal tests: %v", err)
+		return nil, err
+	}
+
+	return tests, nil
+}
+
+// loadFeatwsRules parses the content of a legacy rules.featws file into the rules of a rulesheet: the keys of
+// the default section are rules, the named sections are maps of rules and the `[[array]]` sections are lists.
+func loadFeatwsRules(bRules []byte) (*map[string]interface{}, error) {
+	rulesFile, err := featws.Load(bRules)
+	if err != nil {
+		log.Errorf("Failed to load featws file: %v", err)
+		return nil, err
+	}
+
+	rules := make(map[string]interface{})
+
+	s := rulesFile.Section("")
+
+	for _, k := range s.Keys() {
+		rules[k.Name()] = k.Value()
+	}
+
+	for _, sname := range rulesFile.SectionStrings() {
+		if sname == featws.DefaultSection {
+			continue
+		}
+		if sname[:1] == "[" {
+			continue
+		}
+		sec := make(map[string]interface{})
+		for _, k := range rulesFile.Section(sname).Keys() {
+			sec[k.Name()] = k.Value()
+		}
+		rules[sname] = sec
+	}
+
+	for _, aname := range rulesFile.ArrayStrings() {
+		a := rulesFile.Array(aname)
+
+		arr := make([]map[string]interface{}, 0)
+
+		for _, s := range a.Sections() {
+			sec := make(map[string]interface{})
+			for _, k := range s.Keys() {
+				sec[k.Name()] = k.Value()
+			}
+			arr = append(arr, sec)
+		}
+		rules[aname[1:len(aname)-1]] = arr
+	}
+
+	return &rules, nil
+}
+
+// encodeFeatwsRules writes the rules of a rulesheet on the legacy rules.featws format, the inverse of
+// `loadFeatwsRules`: the string rules are keys of the default section, the maps of string rules are named
+// sections and the lists of maps of string rules are `[[array]]` sections. The keys are written in
+// alphabetical order and the values that the parser would change, like the ones with a `;` or surrounded by
+// quotes, are written between backticks. When the rules are structured, the rules with only a plain value, a
+// string, number or boolean, are written as the literal of their value, like `10` or `'gold'`, which
+// `dtos.ConvertStringRules` reads back as the same rule. It returns `ErrNotExportable` with the paths of the
+// rules that can't be written, like the ones with a condition or a dynamic value.
+func encodeFeatwsRules(rules map[string]interface{}, structured bool) ([]byte, error) {
+	var defaults, sections, arrays []string
+	problems := make([]string, 0)
+
+	// the maps of the structured rules are rules when they have a value or a dynamic value, like buildRule
+	asRule := func(v interface{}) (*dtos.Rule, bool) {
+		if !structured {
+			return nil, false
+		}
+		return featwsStructuredRule(v)
+	}
+
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, ok := asRule(rules[name]); ok {
+			defaults = append(defaults, name)
+			continue
+		}
+
+		switch rule := rules[name].(type) {
+		case string:
+			defaults = append(defaults, name)
+		case map[string]interface{}:
+			sections = append(sections, name)
+			if !validFeatwsSection(name) {
+				problems = append(problems, fmt.Sprintf("rules.%s: invalid section name", name))
+			}
+		case []interface{}, []map[string]interface{}:
+			arrays = append(arrays, name)
+			if !validFeatwsSection(name) {
+				problems = append(problems, fmt.Sprintf("rules.%s: invalid section name", name))
+			}
+			if featwsListLen(rule) == 0 {
+				problems = append(problems, fmt.Sprintf("rules.%s: an empty list can't be written", name))
+			}
+		default:
+			problems = append(problems, fmt.Sprintf("rules.%s: only the string rules and the rules with a plain value can be written", name))
+		}
+	}
+
+	var buf strings.Builder
+
+	writeKeys := func(path string, keys map[string]interface{}) {
+		names := make([]string, 0, len(keys))
+		for name := range keys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			value, ok := keys[name].(string)
+			if rule, isRule := asRule(keys[name]); isRule {
+				var err error
+				value, err = featwsRuleValue(rule)
+				if err != nil {
+					problems = append(problems, fmt.Sprintf("%s.%s: %v", path, name, err))
+					continue
+				}
+			} else if !ok {
+				problems = append(problems, fmt.Sprintf("%s.%s: only the string rules and the rules with a plain value can be written", path, name))
+				continue
+			}
+			key, err := quoteFeatwsKey(name)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s.%s: %v", path, name, err))
+				continue
+			}
+			value, err = quoteFeatwsValue(value)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s.%s: %v", path, name, err))
+				continue
+			}
+			fmt.Fprintf(&buf, "%s = %s\n", key, value)
+		}
+	}
+
+	defaultKeys := make(map[string]interface{}, len(defaults))
+	for _, name := range defaults {
+		defaultKeys[name] = rules[name]
+	}
+	writeKeys("rules", defaultKeys)
+
+	for _, name := range sections {
+		fmt.Fprintf(&buf, "\n[%s]\n", name)
+		writeKeys("rules."+name, rules[name].(map[string]interface{}))
+	}
+
+	for _, name := range arrays {
+		items := make([]interface{}, 0)
+		switch list := rules[name].(type) {
+		case []interface{}:
+			items = list
+		case []map[string]interface{}:
+			for _, item := range list {
+				items = append(items, item)
+			}
+		}
+
+		for index, item := range items {
+			path := fmt.Sprintf("rules.%s[%d]", name, index)
+			if rule, ok := asRule(item); ok {
+				// a list of rules gives the value of its first matching rule, which a list of sections can't hold
+				if _, err := featwsRuleValue(rule); err != nil {
+					problems = append(problems, fmt.Sprintf("%s: %v", path, err))
+				} else {
+					problems = append(problems, fmt.Sprintf("%s: only the maps of rules can be written on a list", path))
+				}
+				continue
+			}
+			keys, ok := item.(map[string]interface{})
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s: only the maps of string rules can be written on a list", path))
+				continue
+			}
+			fmt.Fprintf(&buf, "\n[[%s]]\n", name)
+			writeKeys(path, keys)
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotExportable, strings.Join(problems, "; "))
+	}
+
+	return []byte(strings.TrimLeft(buf.String(), "\n")), nil
+}
+
+// The function returns a structured rule, given as a `dtos.Rule` or as a map read from rules.json with a
+// value or a dynamic value.
+func featwsStructuredRule(v interface{}) (*dtos.Rule, bool) {
+	switch value := v.(type) {
+	case *dtos.Rule:
+		return value, value != nil
+	case map[string]interface{}:
+		_, hasValue := value["value"]
+		_, hasDynamic := value["dynamic"]
+		if !hasValue && !hasDynamic {
+			return nil, false
+		}
+		rule := &dtos.Rule{Value: value["value"]}
+		rule.Condition, _ = value["condition"].(string)
+		rule.Dynamic, _ = value["dynamic"].(string)
+		return rule, true
+	}
+	return nil, false
+}
+
+// The function writes the value of a structured rule as the literal read back by `dtos.ConvertStringRules`.
+// Only the rules without a condition and a dynamic value, whose value is a string, number or boolean, can
+// be written.
+func featwsRuleValue(rule *dtos.Rule) (string, error) {
+	if rule.Condition != "" || rule.Dynamic != "" {
+		return "", errors.New("the rules with a condition or a dynamic value can't be written")
+	}
+
+	switch value := rule.Value.(type) {
+	case string:
+		replacer := strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\t", `\t`)
+		return "'" + replacer.Replace(value) + "'", nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case int:
+		return strconv.Itoa(value), nil
+	case int64:
+		return strconv.FormatInt(value, 10), nil
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	}
+
+	return "", errors.New("only the rules with a string, number or boolean value can be written")
+}
+
+// The function returns the length of a list of rules.
+func featwsListLen(list interface{}) int {
+	switch l := list.(type) {
+	case []interface{}:
+		return len(l)
+	case []map[string]interface{}:
+		return len(l)
+	}
+	return 0
+}
+
+// The function checks if a name can be written as the name of a section, which is read up to its last `]`.
+func validFeatwsSection(name string) bool {
+	return name != "" && name != featws.DefaultSection && !strings.HasPrefix(name, "[") &&
+		!strings.ContainsAny(name, "\r\n") && strings.TrimSpace(name) == name
+}
+
+// The function writes a key, between backticks when it has a delimiter or would be read as something else.
+func quoteFeatwsKey(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, "\r\n") {
+		return "", errors.New("invalid key name")
+	}
+
+	if strings.ContainsAny(name, "=:`\"") || strings.ContainsAny(name[:1], "[;#") || strings.TrimSpace(name) != name {
+		if strings.Contains(name, "`") {
+			return "", errors.New("invalid key name")
+		}
+		return "`" + name + "`", nil
+	}
+
+	return name, nil
+}
+
+// The function writes a value, between backticks, or triple quotes when it has a backtick, when the parser
+// would change it: it's trimmed, cut on a `;`, continued on a trailing `\` and unquoted when it's
+// surrounded by quotes.
+func quoteFeatwsValue(value string) (string, error) {
+	plain := strings.TrimSpace(value) == value &&
+		!strings.ContainsAny(value, ";\r\n") &&
+		!strings.HasSuffix(value, "\\") &&
+		!strings.HasPrefix(value, "`") &&
+		!strings.HasPrefix(value, `"""`) &&
+		!(len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[0] == value[len(value)-1])
+	if plain {
+		return value, nil
+	}
+
+	if strings.Contains(value, "\r") {
+		return "", errors.New("the value can't have a carriage return")
+	}
+	if !strings.Contains(value, "`") {
+		return "`" + value + "`", nil
+	}
+	if !strings.Contains(value, `"""`) && !strings.HasSuffix(value, `"`) {
+		return `"""` + value + `"""`, nil
+	}
+
+	return "", errors.New("the value can't be quoted")
+}
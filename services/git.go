@@ -0,0 +1,504 @@
+package services
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bancodobrasil/featws-api/config"
+	"github.com/bancodobrasil/featws-api/dtos"
+	log "github.com/sirupsen/logrus"
+)
+
+// gitCommit represents a commit of a repository on a git host.
+//
+// Property:
+//   - SHA: the SHA of the commit.
+//   - Author: the name of the author of the commit.
+//   - AuthorEmail: the email of the author of the commit.
+//   - Date: the moment the commit was authored.
+//   - Message: the commit message.
+type gitCommit struct {
+	SHA         string
+	Author      string
+	AuthorEmail string
+	Date        *time.Time
+	Message     string
+}
+
+// errRepositoryNotFound is returned by a `gitHost` when the repository of a rulesheet doesn't exist.
+var errRepositoryNotFound = errors.New("repository not found")
+
+// gitHost defines the operations of a git hosting service, like GitHub or Gitea, used by `gitBackend` to store
+// the rulesheets. The repositories are identified by their name, under the owner configured for the host.
+//
+// Property:
+//   - findRepository: checks that the repository exists. It returns `errRepositoryNotFound` if it doesn't.
+//   - createRepository: creates the repository, initialized with the given default branch.
+//   - readFile: reads a file of the repository at the given ref. It returns nil if the file doesn't exist.
+//   - readVersion: reads the VERSION file at the head of the branch, like `readFile`, and returns the base the next commit must be made on, like the SHA of the head commit or of the VERSION file, or an empty base if the branch doesn't exist.
+//   - commitFiles: commits the files, keyed by their path, on a single commit on the given branch, removing the ones on `removed` that exist. The commit is made on the base returned by `readVersion`, so it returns `ErrVersionConflict` if the branch changed since the VERSION was read.
+//   - createTag: creates an annotated tag on the given ref.
+//   - getTag: returns the SHA of the commit of a tag. It returns `ErrVersionNotFound` if the tag doesn't exist.
+//   - getCommit: returns a commit by its SHA, or a prefix of it. It returns `ErrVersionNotFound` if the commit doesn't exist.
+//   - listCommits: lists the commits of the branch that changed the given path, from the newest to the oldest, and whether there's a next page.
+type gitHost interface {
+	findRepository(repo string) error
+	createRepository(repo string, branch string) error
+	readFile(repo string, ref string, path string) ([]byte, error)
+	readVersion(repo string, branch string) ([]byte, string, error)
+	commitFiles(repo string, branch string, base string, message string, files map[string]string, removed []string) (*gitCommit, error)
+	createTag(repo string, name string, ref string, message string) error
+	getTag(repo string, name string) (string, error)
+	getCommit(repo string, sha string) (*gitCommit, error)
+	listCommits(repo string, branch string, path string, page int, perPage int) ([]*gitCommit, bool, error)
+}
+
+// gitBackend stores the rulesheets on the repositories of a git host, with the same file layout and the same
+// versioning used on GitLab: VERSION, the CI file, features.json, parameters.json, rules.json and tests.json, committed
+// to the default branch with a tag for each version.
+//
+// Property:
+//   - cfg: The configuration, where `GitlabPrefix`, `GitlabDefaultBranch` and `GitlabTagFormat` define the name of the repositories, their branch and their tags on any git host.
+//   - host: The git host that keeps the repositories.
+//   - ciFile: The path of the CI file on the repositories.
+//   - ciScript: The content of the CI file. The file isn't committed when it's empty.
+type gitBackend struct {
+	cfg      *config.Config
+	host     gitHost
+	ciFile   string
+	ciScript string
+}
+
+// Save commits the content of the rulesheet to the default branch of its repository, creating the repository
+// if it doesn't exist, and bumps its VERSION. The new version is tagged when the tag format is configured.
+func (gb *gitBackend) Save(rulesheet *dtos.Rulesheet, commitMessage string) error {
+	cfg := gb.cfg
+	repo := gb.repository(rulesheet)
+
+	err := gb.host.findRepository(repo)
+	if err == errRepositoryNotFound {
+		err = gb.host.createRepository(repo, cfg.GitlabDefaultBranch)
+	}
+	if err != nil {
+		log.Errorf("Failed to fetch repository: %v", err)
+		return err
+	}
+
+	// the commit is pinned to the VERSION it bumps, so concurrent saves can't publish the same version
+	bVersion, base, err := gb.host.readVersion(repo, cfg.GitlabDefaultBranch)
+	if err != nil {
+		log.Errorf("Failed to resolve version: %v", err)
+		return err
+	}
+
+	rulesheet.Version = "0"
+	if bVersion != nil {
+		rulesheet.Version = strings.Replace(string(bVersion), "\n", "", -1)
+	}
+
+	err = checkBaseVersion(rulesheet, rulesheet.Version)
+	if err != nil {
+		return err
+	}
+
+	version, err := strconv.Atoi(rulesheet.Version)
+	if err != nil {
+		log.Errorf("Failed to parse version: %v", err)
+		return err
+	}
+	rulesheet.Version = fmt.Sprintf("%d", version+1)
+
+	features, parameters, rules, err := marshalRulesheetFiles(rulesheet)
+	if err != nil {
+		return err
+	}
+
+	files := map[string]string{
+		"VERSION":         rulesheet.Version + "\n",
+		"features.json":   string(features),
+		"parameters.json": string(parameters),
+		"rules.json":      string(rules),
+	}
+	if gb.ciScript != "" {
+		files[gb.ciFile] = gb.ciScript
+	}
+
+	tests, err := marshalRulesheetTests(rulesheet)
+	if err != nil {
+		return err
+	}
+	if tests != nil {
+		files["tests.json"] = string(tests)
+	}
+
+	// the legacy rules are replaced by rules.json only when they're converted
+	removed := []string{}
+	if rulesheet.RemoveLegacyRules {
+		removed = append(removed, "rules.featws")
+	}
+
+	commit, err := gb.host.commitFiles(repo, cfg.GitlabDefaultBranch, base, commitMessage, files, removed)
+	if err != nil {
+		log.Errorf("Failed to create commit: %v", err)
+		return err
+	}
+
+	if cfg.GitlabTagFormat == "" {
+		return nil
+	}
+
+	// the version is already published by the commit, so failing the save would publish it again
+	err = gb.host.createTag(repo, versionTagName(cfg, rulesheet.Version), commit.SHA, fmt.Sprintf("%s\n\nAuthor: %s <%s>", commitMessage, commit.Author, commit.AuthorEmail))
+	if err != nil {
+		log.Warnf("The version %s was published without its tag: %v", rulesheet.Version, err)
+	}
+
+	return nil
+}
+
+// Fill fills the rulesheet with the content of the default branch of its repository.
+func (gb *gitBackend) Fill(rulesheet *dtos.Rulesheet) error {
+	return gb.FillRef(rulesheet, gb.cfg.GitlabDefaultBranch)
+}
+
+// FillRef fills the rulesheet with the content of its repository at the given ref. As on GitLab, the legacy
+// rules.featws file is read when there's no rules.json.
+func (gb *gitBackend) FillRef(rulesheet *dtos.Rulesheet, ref string) error {
+	repo := gb.repository(rulesheet)
+
+	bVersion, err := gb.host.readFile(repo, ref, "VERSION")
+	if err != nil {
+		log.Errorf("Failed to fetch version: %v", err)
+		return err
+	}
+
+	rulesheet.Version = strings.Replace(string(bVersion), "\n", "", -1)
+
+	for fileName, target := range map[string]interface{}{"features.json": &rulesheet.Features, "parameters.json": &rulesheet.Parameters, "tests.json": &rulesheet.Tests} {
+		content, err := gb.host.readFile(repo, ref, fileName)
+		if err != nil {
+			log.Errorf("Failed to fetch %s: %v", fileName, err)
+			return err
+		}
+		if content == nil {
+			continue
+		}
+		err = json.Unmarshal(content, target)
+		if err != nil {
+			log.Errorf("Failed to decode %s: %v", fileName, err)
+			return err
+		}
+	}
+
+	bRulesJSON, err := gb.host.readFile(repo, ref, "rules.json")
+	if err != nil {
+		log.Errorf("Failed to fetch rules: %v", err)
+		return err
+	}
+
+	if len(bRulesJSON) > 0 {
+		err = json.Unmarshal(bRulesJSON, &rulesheet.Rules)
+		if err != nil {
+			log.Errorf("Failed to decode rules: %v", err)
+			return err
+		}
+		return nil
+	}
+
+	bRules, err := gb.host.readFile(repo, ref, "rules.featws")
+	if err != nil {
+		log.Errorf("Failed to fetch rules.featws: %v", err)
+		return err
+	}
+
+	rulesheet.Rules, err = loadFeatwsRules(bRules)
+	if err != nil {
+		return err
+	}
+
+	rulesheet.HasStringRule = len(bRules) > 0
+	return nil
+}
+
+// ResolveVersion resolves a version of a rulesheet to the SHA of the commit that published it, the same way
+// as on GitLab: a numeric version is looked up on its tag, falling back to the commits that changed the
+// VERSION file, and any other value is handled as a commit SHA.
+func (gb *gitBackend) ResolveVersion(rulesheet *dtos.Rulesheet, version string) (string, error) {
+	repo := gb.repository(rulesheet)
+
+	number, err := strconv.Atoi(version)
+	if err != nil {
+		commit, err := gb.host.getCommit(repo, version)
+		if err != nil {
+			if err != ErrVersionNotFound {
+				log.Errorf("Failed to fetch commit: %v", err)
+			}
+			return "", err
+		}
+		return commit.SHA, nil
+	}
+
+	if gb.cfg.GitlabTagFormat != "" {
+		sha, err := gb.host.getTag(repo, versionTagName(gb.cfg, version))
+		if err == nil {
+			return sha, nil
+		}
+		if err != ErrVersionNotFound {
+			log.Errorf("Failed to fetch tag: %v", err)
+			return "", err
+		}
+	}
+
+	for page := 1; ; page++ {
+		commits, next, err := gb.host.listCommits(repo, gb.cfg.GitlabDefaultBranch, "VERSION", page, 100)
+		if err != nil {
+			log.Errorf("Failed to list commits: %v", err)
+			return "", err
+		}
+
+		for _, commit := range commits {
+			bVersion, err := gb.host.readFile(repo, commit.SHA, "VERSION")
+			if err != nil {
+				log.Errorf("Failed to fetch version: %v", err)
+				return "", err
+			}
+
+			commitNumber, err := strconv.Atoi(strings.Replace(string(bVersion), "\n", "", -1))
+			if err != nil {
+				continue
+			}
+
+			if commitNumber == number {
+				return commit.SHA, nil
+			}
+
+			// the versions only grow, so an older commit can't hold it anymore
+			if commitNumber < number {
+				return "", ErrVersionNotFound
+			}
+		}
+
+		if !next {
+			return "", ErrVersionNotFound
+		}
+	}
+}
+
+// History lists the versions published for a rulesheet, walking the commits of the default branch that
+// changed the VERSION file, from the newest to the oldest.
+func (gb *gitBackend) History(rulesheet *dtos.Rulesheet, options *FindOptions) (result []*dtos.Version, err error) {
+	result = make([]*dtos.Version, 0)
+	repo := gb.repository(rulesheet)
+
+	page, perPage := 1, 20
+	if options != nil {
+		if options.Page > 0 {
+			page = options.Page
+		}
+		if options.Limit > 0 {
+			perPage = options.Limit
+		}
+	}
+
+	commits, _, err := gb.host.listCommits(repo, gb.cfg.GitlabDefaultBranch, "VERSION", page, perPage)
+	if err != nil {
+		log.Errorf("Failed to list commits: %v", err)
+		return
+	}
+
+	for _, commit := range commits {
+		bVersion, err := gb.host.readFile(repo, commit.SHA, "VERSION")
+		if err != nil {
+			log.Errorf("Failed to fetch version: %v", err)
+			return nil, err
+		}
+
+		result = append(result, &dtos.Version{
+			Number:      strings.Replace(string(bVersion), "\n", "", -1),
+			Commit:      commit.SHA,
+			Author:      commit.Author,
+			AuthorEmail: commit.AuthorEmail,
+			Date:        commit.Date,
+			Message:     commit.Message,
+		})
+	}
+
+	return
+}
+
+// repository returns the name of the repository of a rulesheet.
+func (gb *gitBackend) repository(rulesheet *dtos.Rulesheet) string {
+	return gb.cfg.GitlabPrefix + rulesheet.Slug
+}
+
+// restClient is a minimal client of the JSON REST APIs of the git hosts.
+//
+// Property:
+//   - baseURL: the base URL of the API.
+//   - token: the access token sent on the Authorization header.
+//   - client: the HTTP client used on the requests.
+type restClient struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// restError is returned by `restClient` when the API answers with a status code other than 2xx.
+//
+// Property:
+//   - StatusCode: the status code of the response.
+//   - Message: the body of the response.
+type restError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *restError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.StatusCode, e.Message)
+}
+
+// newRestClient creates a new REST client for the API on the base URL.
+func newRestClient(baseURL string, token string) *restClient {
+	return &restClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// do sends a request with the body encoded as JSON and decodes the JSON response into the result, if any.
+func (rc *restClient) do(method string, path string, query url.Values, body interface{}, result interface{}) error {
+	endpoint := rc.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	if rc.token != "" {
+		req.Header.Set("Authorization", "token "+rc.token)
+	}
+
+	resp, err := rc.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &restError{StatusCode: resp.StatusCode, Message: string(data)}
+	}
+
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(data, result)
+}
+
+// isRestStatus checks if the error was returned by the API with one of the status codes.
+func isRestStatus(err error, statusCodes ...int) bool {
+	restErr, ok := err.(*restError)
+	if !ok {
+		return false
+	}
+	for _, statusCode := range statusCodes {
+		if restErr.StatusCode == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// restPath escapes and joins the segments of a path of the API.
+func restPath(segments ...string) string {
+	escaped := make([]string, len(segments))
+	for i, segment := range segments {
+		escaped[i] = url.PathEscape(segment)
+	}
+	return "/" + strings.Join(escaped, "/")
+}
+
+// restCommit represents a commit on the APIs of GitHub and Gitea, which share the same format.
+type restCommit struct {
+	SHA    string `json:"sha"`
+	Commit struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name  string     `json:"name"`
+			Email string     `json:"email"`
+			Date  *time.Time `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+}
+
+// restGitCommit represents a git commit object on the APIs of GitHub and Gitea, as returned when a commit is created.
+type restGitCommit struct {
+	SHA     string `json:"sha"`
+	Message string `json:"message"`
+	Author  struct {
+		Name  string     `json:"name"`
+		Email string     `json:"email"`
+		Date  *time.Time `json:"date"`
+	} `json:"author"`
+}
+
+// restContents represents a file on the contents APIs of GitHub and Gitea.
+type restContents struct {
+	SHA     string `json:"sha"`
+	Content string `json:"content"`
+}
+
+// decode returns the content of the file, which the APIs encode on base64.
+func (rc *restContents) decode() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(strings.ReplaceAll(rc.Content, "\n", ""))
+}
+
+// The function converts a commit of the APIs of GitHub and Gitea.
+func newGitCommit(commit *restCommit) *gitCommit {
+	return &gitCommit{
+		SHA:         commit.SHA,
+		Author:      commit.Commit.Author.Name,
+		AuthorEmail: commit.Commit.Author.Email,
+		Date:        commit.Commit.Author.Date,
+		Message:     commit.Commit.Message,
+	}
+}
+
+// The function converts a git commit object of the APIs of GitHub and Gitea.
+func newGitCommitFromObject(commit *restGitCommit) *gitCommit {
+	return &gitCommit{
+		SHA:         commit.SHA,
+		Author:      commit.Author.Name,
+		AuthorEmail: commit.Author.Email,
+		Date:        commit.Author.Date,
+		Message:     commit.Message,
+	}
+}
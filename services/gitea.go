@@ -0,0 +1,239 @@
+package services
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/bancodobrasil/featws-api/config"
+)
+
+// giteaCIFile is the path of the Gitea Actions workflow committed to the repositories of the rulesheets.
+const giteaCIFile = ".gitea/workflows/featws.yml"
+
+// giteaHost implements the `gitHost` operations on the REST API of Gitea. The files are committed through
+// the endpoint that changes multiple files at once, available since Gitea 1.20, so each version is published
+// on a single commit.
+//
+// Property:
+//   - owner: The organization that owns the repositories.
+//   - client: The client of the Gitea API.
+type giteaHost struct {
+	owner  string
+	client *restClient
+}
+
+// NewGitea creates a new instance of the Gitea storage backend using the provided configuration.
+func NewGitea(cfg *config.Config) Backend {
+	return &gitBackend{
+		cfg: cfg,
+		host: &giteaHost{
+			owner:  cfg.GiteaOwner,
+			client: newRestClient(cfg.GiteaURL, cfg.GiteaToken),
+		},
+		ciFile:   giteaCIFile,
+		ciScript: cfg.GiteaCIScript,
+	}
+}
+
+func (gt *giteaHost) findRepository(repo string) error {
+	err := gt.client.do(http.MethodGet, restPath("repos", gt.owner, repo), nil, nil, nil)
+	if isRestStatus(err, http.StatusNotFound) {
+		return errRepositoryNotFound
+	}
+	return err
+}
+
+func (gt *giteaHost) createRepository(repo string, branch string) error {
+	path := restPath("orgs", gt.owner, "repos")
+	if gt.owner == "" {
+		path = restPath("user", "repos")
+	}
+
+	return gt.client.do(http.MethodPost, path, nil, map[string]interface{}{
+		"name":           repo,
+		"private":        true,
+		"auto_init":      true,
+		"default_branch": branch,
+	}, nil)
+}
+
+func (gt *giteaHost) readFile(repo string, ref string, path string) ([]byte, error) {
+	contents, err := gt.contents(repo, ref, path)
+	if err != nil || contents == nil {
+		return nil, err
+	}
+
+	return contents.decode()
+}
+
+// readVersion reads the VERSION file at the head of the branch, whose SHA is the base of the next commit.
+func (gt *giteaHost) readVersion(repo string, branch string) ([]byte, string, error) {
+	contents, err := gt.contents(repo, branch, "VERSION")
+	if err != nil || contents == nil {
+		return nil, "", err
+	}
+
+	content, err := contents.decode()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return content, contents.SHA, nil
+}
+
+func (gt *giteaHost) commitFiles(repo string, branch string, base string, message string, files map[string]string, removed []string) (*gitCommit, error) {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	changes := make([]map[string]string, 0, len(files))
+	for _, path := range paths {
+		change := map[string]string{
+			"operation": "create",
+			"path":      path,
+			"content":   base64.StdEncoding.EncodeToString([]byte(files[path])),
+		}
+
+		// the VERSION is written over the one it was read from, so Gitea refuses the commit if it changed
+		// since then
+		if path == "VERSION" {
+			if base != "" {
+				change["operation"] = "update"
+				change["sha"] = base
+			}
+			changes = append(changes, change)
+			continue
+		}
+
+		current, err := gt.contents(repo, branch, path)
+		if err != nil {
+			return nil, err
+		}
+		if current != nil {
+			change["operation"] = "update"
+			change["sha"] = current.SHA
+		}
+
+		changes = append(changes, change)
+	}
+
+	for _, path := range removed {
+		current, err := gt.contents(repo, branch, path)
+		if err != nil {
+			return nil, err
+		}
+		if current == nil {
+			continue
+		}
+
+		changes = append(changes, map[string]string{
+			"operation": "delete",
+			"path":      path,
+			"sha":       current.SHA,
+		})
+	}
+
+	var result struct {
+		Commit restGitCommit `json:"commit"`
+	}
+
+	err := gt.client.do(http.MethodPost, restPath("repos", gt.owner, repo, "contents"), nil, map[string]interface{}{
+		"branch":  branch,
+		"message": message,
+		"files":   changes,
+	}, &result)
+	if isRestStatus(err, http.StatusConflict, http.StatusUnprocessableEntity) {
+		return nil, fmt.Errorf("%w: the VERSION changed while saving", ErrVersionConflict)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newGitCommitFromObject(&result.Commit), nil
+}
+
+func (gt *giteaHost) createTag(repo string, name string, ref string, message string) error {
+	return gt.client.do(http.MethodPost, restPath("repos", gt.owner, repo, "tags"), nil, map[string]interface{}{
+		"tag_name": name,
+		"target":   ref,
+		"message":  message,
+	}, nil)
+}
+
+func (gt *giteaHost) getTag(repo string, name string) (string, error) {
+	var tag struct {
+		Commit struct {
+			SHA string `json:"sha"`
+		} `json:"commit"`
+	}
+
+	err := gt.client.do(http.MethodGet, restPath("repos", gt.owner, repo, "tags", name), nil, nil, &tag)
+	if err != nil {
+		if isRestStatus(err, http.StatusNotFound) {
+			return "", ErrVersionNotFound
+		}
+		return "", err
+	}
+
+	return tag.Commit.SHA, nil
+}
+
+func (gt *giteaHost) getCommit(repo string, sha string) (*gitCommit, error) {
+	var commit restCommit
+
+	err := gt.client.do(http.MethodGet, restPath("repos", gt.owner, repo, "git", "commits", sha), nil, nil, &commit)
+	if err != nil {
+		if isRestStatus(err, http.StatusNotFound, http.StatusUnprocessableEntity) {
+			return nil, ErrVersionNotFound
+		}
+		return nil, err
+	}
+
+	return newGitCommit(&commit), nil
+}
+
+func (gt *giteaHost) listCommits(repo string, branch string, path string, page int, perPage int) ([]*gitCommit, bool, error) {
+	commits := make([]*restCommit, 0)
+
+	err := gt.client.do(http.MethodGet, restPath("repos", gt.owner, repo, "commits"), url.Values{
+		"sha":   {branch},
+		"path":  {path},
+		"page":  {strconv.Itoa(page)},
+		"limit": {strconv.Itoa(perPage)},
+	}, nil, &commits)
+	if err != nil {
+		// the repository doesn't exist or is empty
+		if isRestStatus(err, http.StatusNotFound, http.StatusConflict) {
+			return make([]*gitCommit, 0), false, nil
+		}
+		return nil, false, err
+	}
+
+	result := make([]*gitCommit, 0, len(commits))
+	for _, commit := range commits {
+		result = append(result, newGitCommit(commit))
+	}
+
+	return result, len(commits) == perPage, nil
+}
+
+// contents fetches a file of the repository at the given ref. It returns nil if the file doesn't exist.
+func (gt *giteaHost) contents(repo string, ref string, path string) (*restContents, error) {
+	var contents restContents
+
+	err := gt.client.do(http.MethodGet, restPath("repos", gt.owner, repo, "contents")+"/"+path, url.Values{"ref": {ref}}, nil, &contents)
+	if err != nil {
+		if isRestStatus(err, http.StatusNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &contents, nil
+}
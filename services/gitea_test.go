@@ -0,0 +1,198 @@
+package services_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bancodobrasil/featws-api/config"
+	"github.com/bancodobrasil/featws-api/services"
+	"github.com/stretchr/testify/assert"
+)
+
+// The function returns a pointer to a configuration object with predefined values for a Gitea server.
+func SetupGiteaConfig(url *httptest.Server) *config.Config {
+	return &config.Config{
+		GiteaURL:            url.URL + "/api/v1",
+		GiteaOwner:          "test",
+		GiteaToken:          "test",
+		GitlabPrefix:        "prefix-",
+		GitlabDefaultBranch: "main",
+		GitlabTagFormat:     "v{version}",
+	}
+}
+
+// This is a test function that checks if the files of a rulesheet are committed to an existing Gitea
+// repository on a single commit, bumping its VERSION, and if the new version is tagged.
+func TestGiteaSaveAndUpdateRepository(t *testing.T) {
+	var commit map[string]interface{}
+	var tag map[string]interface{}
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token test", r.Header.Get("Authorization"))
+
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/repos/test/prefix-test":
+			w.Write([]byte(`{"name":"prefix-test"}`))
+		case r.Method == "GET" && r.URL.Path == "/api/v1/repos/test/prefix-test/contents/VERSION":
+			assert.Equal(t, "main", r.URL.Query().Get("ref"))
+			w.Write([]byte(`{"sha":"version-sha","content":"` + base64.StdEncoding.EncodeToString([]byte("1\n")) + `"}`))
+		case r.Method == "GET" && r.URL.Path == "/api/v1/repos/test/prefix-test/contents/rules.json":
+			w.Write([]byte(`{"sha":"rules-sha","content":""}`))
+		case r.Method == "GET" && r.URL.Path == "/api/v1/repos/test/prefix-test/contents/rules.featws":
+			w.Write([]byte(`{"sha":"featws-sha","content":""}`))
+		case r.Method == "POST" && r.URL.Path == "/api/v1/repos/test/prefix-test/contents":
+			json.NewDecoder(r.Body).Decode(&commit)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"commit":{"sha":"sha2","message":"test","author":{"name":"bot","email":"bot@test"}}}`))
+		case r.Method == "POST" && r.URL.Path == "/api/v1/repos/test/prefix-test/tags":
+			json.NewDecoder(r.Body).Decode(&tag)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+
+	backend := services.NewGitea(SetupGiteaConfig(s))
+
+	dto := SetupRulesheet()
+	dto.RemoveLegacyRules = true
+	err := backend.Save(dto, "test")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", dto.Version)
+
+	assert.Equal(t, "main", commit["branch"])
+	assert.Equal(t, "test", commit["message"])
+
+	files := make(map[string]map[string]interface{})
+	for _, file := range commit["files"].([]interface{}) {
+		f := file.(map[string]interface{})
+		files[f["path"].(string)] = f
+	}
+	// no workflow is committed without a CI script, and the legacy rules are removed by the conversion
+	assert.Len(t, files, 5)
+	assert.Equal(t, "update", files["VERSION"]["operation"])
+	assert.Equal(t, "version-sha", files["VERSION"]["sha"])
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("2\n")), files["VERSION"]["content"])
+	assert.Equal(t, "update", files["rules.json"]["operation"])
+	assert.Equal(t, "create", files["features.json"]["operation"])
+	assert.Nil(t, files["features.json"]["sha"])
+	assert.Equal(t, "delete", files["rules.featws"]["operation"])
+	assert.Equal(t, "featws-sha", files["rules.featws"]["sha"])
+
+	assert.Equal(t, "v2", tag["tag_name"])
+	assert.Equal(t, "sha2", tag["target"])
+	assert.Equal(t, "test\n\nAuthor: bot <bot@test>", tag["message"])
+}
+
+// This is a test function that checks if a tag that fails on Gitea doesn't fail the save, since the version
+// is already published by the commit.
+func TestGiteaSaveWithTagFailure(t *testing.T) {
+	commits := 0
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/repos/test/prefix-test":
+			w.Write([]byte(`{"name":"prefix-test"}`))
+		case r.Method == "GET" && r.URL.Path == "/api/v1/repos/test/prefix-test/contents/VERSION":
+			w.Write([]byte(`{"sha":"version-sha","content":"` + base64.StdEncoding.EncodeToString([]byte("1\n")) + `"}`))
+		case r.Method == "POST" && r.URL.Path == "/api/v1/repos/test/prefix-test/contents":
+			commits++
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"commit":{"sha":"sha2","message":"test","author":{"name":"bot","email":"bot@test"}}}`))
+		case r.Method == "POST" && r.URL.Path == "/api/v1/repos/test/prefix-test/tags":
+			w.WriteHeader(http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+
+	backend := services.NewGitea(SetupGiteaConfig(s))
+
+	dto := SetupRulesheet()
+	err := backend.Save(dto, "test")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", dto.Version)
+	assert.Equal(t, 1, commits)
+}
+
+// This is a test function that checks if a save fails with a version conflict on Gitea when the VERSION
+// changed since it was read, and if no tag is created for the version.
+func TestGiteaSaveWithChangedVersion(t *testing.T) {
+	tagged := false
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/repos/test/prefix-test":
+			w.Write([]byte(`{"name":"prefix-test"}`))
+		case r.Method == "GET" && r.URL.Path == "/api/v1/repos/test/prefix-test/contents/VERSION":
+			w.Write([]byte(`{"sha":"version-sha","content":"` + base64.StdEncoding.EncodeToString([]byte("1\n")) + `"}`))
+		case r.Method == "POST" && r.URL.Path == "/api/v1/repos/test/prefix-test/contents":
+			var commit map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&commit)
+			for _, file := range commit["files"].([]interface{}) {
+				f := file.(map[string]interface{})
+				if f["path"] == "VERSION" {
+					assert.Equal(t, "version-sha", f["sha"])
+				}
+			}
+			// another save changed the VERSION after it was read
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message":"sha does not match"}`))
+		case r.Method == "POST" && r.URL.Path == "/api/v1/repos/test/prefix-test/tags":
+			tagged = true
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+
+	backend := services.NewGitea(SetupGiteaConfig(s))
+
+	err := backend.Save(SetupRulesheet(), "test")
+	assert.ErrorIs(t, err, services.ErrVersionConflict)
+	assert.False(t, tagged)
+}
+
+// This is a test function that checks if the versions of a rulesheet are listed from the commits of the
+// Gitea repository that changed the VERSION file, and resolved from their tags.
+func TestGiteaHistoryAndResolveVersion(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/repos/test/prefix-test/commits":
+			assert.Equal(t, "main", r.URL.Query().Get("sha"))
+			assert.Equal(t, "VERSION", r.URL.Query().Get("path"))
+			assert.Equal(t, "2", r.URL.Query().Get("page"))
+			assert.Equal(t, "5", r.URL.Query().Get("limit"))
+			w.Write([]byte(`[{"sha":"sha1","commit":{"message":"first","author":{"name":"bot","email":"bot@test","date":"2023-01-02T03:04:05Z"}}}]`))
+		case "/api/v1/repos/test/prefix-test/contents/VERSION":
+			assert.Equal(t, "sha1", r.URL.Query().Get("ref"))
+			w.Write([]byte(`{"content":"` + base64.StdEncoding.EncodeToString([]byte("1\n")) + `"}`))
+		case "/api/v1/repos/test/prefix-test/tags/v1":
+			w.Write([]byte(`{"name":"v1","commit":{"sha":"sha1"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+
+	backend := services.NewGitea(SetupGiteaConfig(s))
+
+	history, err := backend.History(SetupRulesheet(), &services.FindOptions{Page: 2, Limit: 5})
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+	assert.Equal(t, "1", history[0].Number)
+	assert.Equal(t, "sha1", history[0].Commit)
+	assert.Equal(t, "bot", history[0].Author)
+	assert.Equal(t, "first", history[0].Message)
+	assert.Equal(t, 2023, history[0].Date.Year())
+
+	ref, err := backend.ResolveVersion(SetupRulesheet(), "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "sha1", ref)
+}
@@ -0,0 +1,293 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bancodobrasil/featws-api/config"
+)
+
+// githubCIFile is the path of the GitHub Actions workflow committed to the repositories of the rulesheets.
+const githubCIFile = ".github/workflows/featws.yml"
+
+// githubHost implements the `gitHost` operations on the REST API of GitHub, or GitHub Enterprise. The files
+// are committed through the git data API, so each version is published on a single commit.
+//
+// Property:
+//   - owner: The organization that owns the repositories.
+//   - client: The client of the GitHub API.
+type githubHost struct {
+	owner  string
+	client *restClient
+}
+
+// githubRef is a reference of a repository on GitHub, like a branch, with the SHA of the object it points to.
+type githubRef struct {
+	Object struct {
+		SHA string `json:"sha"`
+	} `json:"object"`
+}
+
+// NewGithub creates a new instance of the GitHub storage backend using the provided configuration.
+func NewGithub(cfg *config.Config) Backend {
+	return &gitBackend{
+		cfg: cfg,
+		host: &githubHost{
+			owner:  cfg.GithubOwner,
+			client: newRestClient(cfg.GithubURL, cfg.GithubToken),
+		},
+		ciFile:   githubCIFile,
+		ciScript: cfg.GithubCIScript,
+	}
+}
+
+func (gh *githubHost) findRepository(repo string) error {
+	err := gh.client.do(http.MethodGet, restPath("repos", gh.owner, repo), nil, nil, nil)
+	if isRestStatus(err, http.StatusNotFound) {
+		return errRepositoryNotFound
+	}
+	return err
+}
+
+func (gh *githubHost) createRepository(repo string, branch string) error {
+	path := restPath("orgs", gh.owner, "repos")
+	if gh.owner == "" {
+		path = restPath("user", "repos")
+	}
+
+	// the repository is initialized, since the git data API doesn't work on an empty repository
+	return gh.client.do(http.MethodPost, path, nil, map[string]interface{}{
+		"name":      repo,
+		"private":   true,
+		"auto_init": true,
+	}, nil)
+}
+
+func (gh *githubHost) readFile(repo string, ref string, path string) ([]byte, error) {
+	var contents restContents
+
+	err := gh.client.do(http.MethodGet, restPath("repos", gh.owner, repo, "contents")+"/"+path, url.Values{"ref": {ref}}, nil, &contents)
+	if err != nil {
+		if isRestStatus(err, http.StatusNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return contents.decode()
+}
+
+// readVersion reads the VERSION file at the head commit of the branch, which is the base of the next commit.
+func (gh *githubHost) readVersion(repo string, branch string) ([]byte, string, error) {
+	var ref githubRef
+
+	err := gh.client.do(http.MethodGet, restPath("repos", gh.owner, repo, "git", "ref", "heads")+"/"+branch, nil, nil, &ref)
+	if err != nil {
+		if isRestStatus(err, http.StatusNotFound) {
+			return nil, "", nil
+		}
+		return nil, "", err
+	}
+
+	content, err := gh.readFile(repo, ref.Object.SHA, "VERSION")
+	if err != nil {
+		return nil, "", err
+	}
+
+	return content, ref.Object.SHA, nil
+}
+
+func (gh *githubHost) commitFiles(repo string, branch string, base string, message string, files map[string]string, removed []string) (*gitCommit, error) {
+	var ref githubRef
+	ref.Object.SHA = base
+
+	// the branch doesn't exist yet when it isn't the default branch of the new repository
+	createRef := false
+	var err error
+	if base == "" {
+		var repository struct {
+			DefaultBranch string `json:"default_branch"`
+		}
+		err = gh.client.do(http.MethodGet, restPath("repos", gh.owner, repo), nil, nil, &repository)
+		if err != nil {
+			return nil, err
+		}
+
+		createRef = true
+		err = gh.client.do(http.MethodGet, restPath("repos", gh.owner, repo, "git", "ref", "heads")+"/"+repository.DefaultBranch, nil, nil, &ref)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var parent struct {
+		Tree struct {
+			SHA string `json:"sha"`
+		} `json:"tree"`
+	}
+	err = gh.client.do(http.MethodGet, restPath("repos", gh.owner, repo, "git", "commits", ref.Object.SHA), nil, nil, &parent)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]map[string]interface{}, 0, len(files))
+	for path, content := range files {
+		entries = append(entries, map[string]interface{}{
+			"path":    path,
+			"mode":    "100644",
+			"type":    "blob",
+			"content": content,
+		})
+	}
+
+	// a tree entry without a SHA removes the file, which must exist on the base tree
+	for _, path := range removed {
+		current, err := gh.readFile(repo, ref.Object.SHA, path)
+		if err != nil {
+			return nil, err
+		}
+		if current == nil {
+			continue
+		}
+		entries = append(entries, map[string]interface{}{
+			"path": path,
+			"mode": "100644",
+			"type": "blob",
+			"sha":  nil,
+		})
+	}
+
+	var tree struct {
+		SHA string `json:"sha"`
+	}
+	err = gh.client.do(http.MethodPost, restPath("repos", gh.owner, repo, "git", "trees"), nil, map[string]interface{}{
+		"base_tree": parent.Tree.SHA,
+		"tree":      entries,
+	}, &tree)
+	if err != nil {
+		return nil, err
+	}
+
+	var commit restGitCommit
+	err = gh.client.do(http.MethodPost, restPath("repos", gh.owner, repo, "git", "commits"), nil, map[string]interface{}{
+		"message": message,
+		"tree":    tree.SHA,
+		"parents": []string{ref.Object.SHA},
+	}, &commit)
+	if err != nil {
+		return nil, err
+	}
+
+	if createRef {
+		// the creation fails if the branch was created since the VERSION was read
+		err = gh.client.do(http.MethodPost, restPath("repos", gh.owner, repo, "git", "refs"), nil, map[string]interface{}{
+			"ref": "refs/heads/" + branch,
+			"sha": commit.SHA,
+		}, nil)
+	} else {
+		// the update isn't forced, so GitHub refuses it if the branch moved from the commit the VERSION was
+		// read from
+		err = gh.client.do(http.MethodPatch, restPath("repos", gh.owner, repo, "git", "refs", "heads")+"/"+branch, nil, map[string]interface{}{
+			"sha": commit.SHA,
+		}, nil)
+	}
+	if isRestStatus(err, http.StatusUnprocessableEntity) {
+		return nil, fmt.Errorf("%w: the branch changed while saving", ErrVersionConflict)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newGitCommitFromObject(&commit), nil
+}
+
+func (gh *githubHost) createTag(repo string, name string, ref string, message string) error {
+	var tag struct {
+		SHA string `json:"sha"`
+	}
+
+	err := gh.client.do(http.MethodPost, restPath("repos", gh.owner, repo, "git", "tags"), nil, map[string]interface{}{
+		"tag":     name,
+		"message": message,
+		"object":  ref,
+		"type":    "commit",
+	}, &tag)
+	if err != nil {
+		return err
+	}
+
+	return gh.client.do(http.MethodPost, restPath("repos", gh.owner, repo, "git", "refs"), nil, map[string]interface{}{
+		"ref": "refs/tags/" + name,
+		"sha": tag.SHA,
+	}, nil)
+}
+
+func (gh *githubHost) getTag(repo string, name string) (string, error) {
+	var ref struct {
+		Object struct {
+			Type string `json:"type"`
+			SHA  string `json:"sha"`
+		} `json:"object"`
+	}
+
+	err := gh.client.do(http.MethodGet, restPath("repos", gh.owner, repo, "git", "ref", "tags", name), nil, nil, &ref)
+	if err != nil {
+		if isRestStatus(err, http.StatusNotFound) {
+			return "", ErrVersionNotFound
+		}
+		return "", err
+	}
+
+	if ref.Object.Type != "tag" {
+		return ref.Object.SHA, nil
+	}
+
+	// an annotated tag points to a tag object, which points to the commit
+	err = gh.client.do(http.MethodGet, restPath("repos", gh.owner, repo, "git", "tags", ref.Object.SHA), nil, nil, &ref)
+	if err != nil {
+		return "", err
+	}
+
+	return ref.Object.SHA, nil
+}
+
+func (gh *githubHost) getCommit(repo string, sha string) (*gitCommit, error) {
+	var commit restCommit
+
+	err := gh.client.do(http.MethodGet, restPath("repos", gh.owner, repo, "commits", sha), nil, nil, &commit)
+	if err != nil {
+		if isRestStatus(err, http.StatusNotFound, http.StatusUnprocessableEntity) {
+			return nil, ErrVersionNotFound
+		}
+		return nil, err
+	}
+
+	return newGitCommit(&commit), nil
+}
+
+func (gh *githubHost) listCommits(repo string, branch string, path string, page int, perPage int) ([]*gitCommit, bool, error) {
+	commits := make([]*restCommit, 0)
+
+	err := gh.client.do(http.MethodGet, restPath("repos", gh.owner, repo, "commits"), url.Values{
+		"sha":      {branch},
+		"path":     {path},
+		"page":     {strconv.Itoa(page)},
+		"per_page": {strconv.Itoa(perPage)},
+	}, nil, &commits)
+	if err != nil {
+		// the repository doesn't exist or is empty
+		if isRestStatus(err, http.StatusNotFound, http.StatusConflict) {
+			return make([]*gitCommit, 0), false, nil
+		}
+		return nil, false, err
+	}
+
+	result := make([]*gitCommit, 0, len(commits))
+	for _, commit := range commits {
+		result = append(result, newGitCommit(commit))
+	}
+
+	return result, len(commits) == perPage, nil
+}
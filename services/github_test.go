@@ -0,0 +1,243 @@
+package services_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bancodobrasil/featws-api/config"
+	"github.com/bancodobrasil/featws-api/dtos"
+	"github.com/bancodobrasil/featws-api/services"
+	"github.com/stretchr/testify/assert"
+)
+
+// The function returns a pointer to a configuration object with predefined values for a GitHub server.
+func SetupGithubConfig(url *httptest.Server) *config.Config {
+	return &config.Config{
+		GithubURL:           url.URL,
+		GithubOwner:         "test",
+		GithubToken:         "test",
+		GithubCIScript:      "test ci-script",
+		GitlabPrefix:        "prefix-",
+		GitlabDefaultBranch: "main",
+		GitlabTagFormat:     "v{version}",
+	}
+}
+
+// The function writes a file of the GitHub contents API.
+func writeGithubContents(w http.ResponseWriter, content string) {
+	data, _ := json.Marshal(map[string]string{
+		"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+		"encoding": "base64",
+	})
+	w.Write(data)
+}
+
+// This is a test function that checks if a new repository is created on GitHub and the files of the
+// rulesheet are committed on a single commit, tagged with the new version.
+func TestGithubSaveAndCreateRepository(t *testing.T) {
+	created := false
+	var tree map[string]interface{}
+	var tag map[string]interface{}
+	refs := make([]string, 0)
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token test", r.Header.Get("Authorization"))
+
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/repos/test/prefix-test":
+			if !created {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(`{"default_branch":"main"}`))
+		case r.Method == "POST" && r.URL.Path == "/orgs/test/repos":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			assert.Equal(t, "prefix-test", body["name"])
+			assert.Equal(t, true, body["auto_init"])
+			created = true
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == "GET" && r.URL.Path == "/repos/test/prefix-test/contents/VERSION":
+			assert.Equal(t, "head", r.URL.Query().Get("ref"))
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == "GET" && r.URL.Path == "/repos/test/prefix-test/git/ref/heads/main":
+			w.Write([]byte(`{"object":{"sha":"head"}}`))
+		case r.Method == "GET" && r.URL.Path == "/repos/test/prefix-test/git/commits/head":
+			w.Write([]byte(`{"sha":"head","tree":{"sha":"tree0"}}`))
+		case r.Method == "POST" && r.URL.Path == "/repos/test/prefix-test/git/trees":
+			json.NewDecoder(r.Body).Decode(&tree)
+			w.Write([]byte(`{"sha":"tree1"}`))
+		case r.Method == "POST" && r.URL.Path == "/repos/test/prefix-test/git/commits":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			assert.Equal(t, "tree1", body["tree"])
+			assert.Equal(t, []interface{}{"head"}, body["parents"])
+			w.Write([]byte(`{"sha":"sha1","message":"test","author":{"name":"bot","email":"bot@test"}}`))
+		case r.Method == "PATCH" && r.URL.Path == "/repos/test/prefix-test/git/refs/heads/main":
+			refs = append(refs, "refs/heads/main")
+			w.Write([]byte(`{}`))
+		case r.Method == "POST" && r.URL.Path == "/repos/test/prefix-test/git/tags":
+			json.NewDecoder(r.Body).Decode(&tag)
+			w.Write([]byte(`{"sha":"tag1"}`))
+		case r.Method == "POST" && r.URL.Path == "/repos/test/prefix-test/git/refs":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			assert.Equal(t, "tag1", body["sha"])
+			refs = append(refs, body["ref"].(string))
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+
+	backend := services.NewGithub(SetupGithubConfig(s))
+
+	dto := SetupRulesheet()
+	err := backend.Save(dto, "test")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", dto.Version)
+	assert.True(t, created)
+
+	files := make(map[string]string)
+	for _, entry := range tree["tree"].([]interface{}) {
+		e := entry.(map[string]interface{})
+		files[e["path"].(string)] = e["content"].(string)
+	}
+	assert.Equal(t, "tree0", tree["base_tree"])
+	assert.Equal(t, map[string]string{
+		"VERSION":                      "1\n",
+		".github/workflows/featws.yml": "test ci-script",
+		"features.json":                "[]",
+		"parameters.json":              "[]",
+		"rules.json":                   "{}",
+	}, files)
+
+	assert.Equal(t, "v1", tag["tag"])
+	assert.Equal(t, "sha1", tag["object"])
+	assert.Equal(t, "test\n\nAuthor: bot <bot@test>", tag["message"])
+	assert.Equal(t, []string{"refs/heads/main", "refs/tags/v1"}, refs)
+}
+
+// This is a test function that checks if a save fails with a version conflict on GitHub when the branch
+// moved from the commit the VERSION was read from, and if no tag is created for the version.
+func TestGithubSaveWithMovedBranch(t *testing.T) {
+	tagged := false
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/repos/test/prefix-test":
+			w.Write([]byte(`{"default_branch":"main"}`))
+		case r.Method == "GET" && r.URL.Path == "/repos/test/prefix-test/git/ref/heads/main":
+			w.Write([]byte(`{"object":{"sha":"head"}}`))
+		case r.Method == "GET" && r.URL.Path == "/repos/test/prefix-test/contents/VERSION":
+			writeGithubContents(w, "1\n")
+		case r.Method == "GET" && r.URL.Path == "/repos/test/prefix-test/git/commits/head":
+			w.Write([]byte(`{"sha":"head","tree":{"sha":"tree0"}}`))
+		case r.Method == "POST" && r.URL.Path == "/repos/test/prefix-test/git/trees":
+			w.Write([]byte(`{"sha":"tree1"}`))
+		case r.Method == "POST" && r.URL.Path == "/repos/test/prefix-test/git/commits":
+			w.Write([]byte(`{"sha":"sha2","message":"test","author":{"name":"bot","email":"bot@test"}}`))
+		case r.Method == "PATCH" && r.URL.Path == "/repos/test/prefix-test/git/refs/heads/main":
+			// another save moved the branch after the VERSION was read
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message":"Update is not a fast forward"}`))
+		case r.Method == "POST" && r.URL.Path == "/repos/test/prefix-test/git/tags":
+			tagged = true
+			w.Write([]byte(`{"sha":"tag2"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+
+	backend := services.NewGithub(SetupGithubConfig(s))
+
+	err := backend.Save(SetupRulesheet(), "test")
+	assert.ErrorIs(t, err, services.ErrVersionConflict)
+	assert.False(t, tagged)
+}
+
+// This is a test function that checks if the content of a rulesheet is read from GitHub at the given ref.
+func TestGithubFillRef(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("ref") != "sha1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/repos/test/prefix-test/contents/VERSION":
+			writeGithubContents(w, "1\n")
+		case "/repos/test/prefix-test/contents/features.json":
+			writeGithubContents(w, `[{"name":"a"}]`)
+		case "/repos/test/prefix-test/contents/rules.json":
+			writeGithubContents(w, `{"a":"#a"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+
+	backend := services.NewGithub(SetupGithubConfig(s))
+
+	dto := SetupRulesheet()
+	err := backend.FillRef(dto, "sha1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", dto.Version)
+	assert.Equal(t, []dtos.Feature{{Name: "a"}}, *dto.Features)
+	assert.Nil(t, dto.Parameters)
+	assert.Equal(t, map[string]interface{}{"a": "#a"}, *dto.Rules)
+}
+
+// This is a test function that checks if a version is resolved from its annotated tag on GitHub, falling
+// back to the commits that changed the VERSION file when the tag doesn't exist.
+func TestGithubResolveVersion(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/test/prefix-test/git/ref/tags/v2":
+			w.Write([]byte(`{"object":{"type":"tag","sha":"tag2"}}`))
+		case "/repos/test/prefix-test/git/tags/tag2":
+			w.Write([]byte(`{"object":{"type":"commit","sha":"sha2"}}`))
+		case "/repos/test/prefix-test/commits":
+			assert.Equal(t, "VERSION", r.URL.Query().Get("path"))
+			w.Write([]byte(`[{"sha":"sha2","commit":{"message":"second"}},{"sha":"sha1","commit":{"message":"first"}}]`))
+		case "/repos/test/prefix-test/contents/VERSION":
+			writeGithubContents(w, map[string]string{"sha1": "1\n", "sha2": "2\n"}[r.URL.Query().Get("ref")])
+		case "/repos/test/prefix-test/commits/abc1234":
+			w.Write([]byte(`{"sha":"abc1234def","commit":{"message":"first"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+
+	backend := services.NewGithub(SetupGithubConfig(s))
+
+	ref, err := backend.ResolveVersion(SetupRulesheet(), "2")
+	assert.NoError(t, err)
+	assert.Equal(t, "sha2", ref)
+
+	ref, err = backend.ResolveVersion(SetupRulesheet(), "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "sha1", ref)
+
+	ref, err = backend.ResolveVersion(SetupRulesheet(), "abc1234")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc1234def", ref)
+
+	_, err = backend.ResolveVersion(SetupRulesheet(), "3")
+	assert.ErrorIs(t, err, services.ErrVersionNotFound)
+
+	_, err = backend.ResolveVersion(SetupRulesheet(), "unknown")
+	assert.ErrorIs(t, err, services.ErrVersionNotFound)
+
+	history, err := backend.History(SetupRulesheet(), nil)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, "2", history[0].Number)
+	assert.Equal(t, "second", history[0].Message)
+}
@@ -3,32 +3,35 @@ package services
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bancodobrasil/featws-api/config"
 	"github.com/bancodobrasil/featws-api/dtos"
 	"github.com/xanzy/go-gitlab"
 
-	"github.com/bancodobrasil/go-featws"
 	log "github.com/sirupsen/logrus"
 )
 
-// Gitlab interface defines methods for saving, filling, and connecting to a Gitlab client.
+// Gitlab interface defines the GitLab storage backend, which also supports the review mode and connecting to a Gitlab client.
 //
 // Property:
-//   - Save: A method that takes a pointer to a Rulesheet DTO (Data Transfer Object) and a commit message as input parameters and returns an error. This method is responsible for saving the Rulesheet to Gitlab repository with the provided commit message.
-//   - Fill: The method is a function that takes a pointer to a `Rulesheet` DTO and fills it with data from a GitLab repository. It returns an error if there's any issue while filling the `Rulesheet`.
+//   - Backend: The methods to save and fill the content of a `Rulesheet` and to browse its versions. On GitLab, `Save` also creates an annotated tag for the new version when the tag format is configured, and on review mode commits the changes to a draft branch with a merge request instead, keeping the published version.
+//   - ChangeRequests: The methods to list, approve, publish and discard the pending changes of a `Rulesheet` on review mode.
 //   - Connect: Connect is a method that returns a pointer to a gitlab.Client and an error. It's used to establish a connection to the GitLab server.
 type Gitlab interface {
-	Save(rulesheet *dtos.Rulesheet, commitMessage string) error
-	Fill(rulesheet *dtos.Rulesheet) error
+	Backend
+	ChangeRequests
 	Connect() (*gitlab.Client, error)
 }
 
+// ErrVersionNotFound is returned when a version number or commit SHA doesn't exist on the repository of a rulesheet.
+var ErrVersionNotFound = errors.New("version not found")
+
 // gitlabService struct holds a pointer to a config.Config object.
 //
 // Property:
@@ -84,7 +87,7 @@ func (gs *gitlabService) Save(rulesheet *dtos.Rulesheet, commitMessage string) e
 	// projData, _ := json.Marshal(proj)
 	// fmt.Println(string(projData))
 
-	_, resp, err = git.RepositoryFiles.GetFile(proj.ID, "VERSION", &gitlab.GetFileOptions{
+	versionFile, resp, err := git.RepositoryFiles.GetFile(proj.ID, "VERSION", &gitlab.GetFileOptions{
 		Ref: gitlab.String(cfg.GitlabDefaultBranch),
 	})
 	if err != nil {
@@ -105,23 +108,40 @@ func (gs *gitlabService) Save(rulesheet *dtos.Rulesheet, commitMessage string) e
 		rulesheet.Version = strings.Replace(string(bVersion), "\n", "", -1)
 	}
 
+	err = checkBaseVersion(rulesheet, rulesheet.Version)
+	if err != nil {
+		return err
+	}
+
 	actions := []*gitlab.CommitActionOptions{}
 	var commitAction *gitlab.CommitActionOptions
-	var content []byte
 
 	// VERSION
 	version, err := strconv.Atoi(rulesheet.Version)
-	rulesheet.Version = fmt.Sprintf("%d", version+1)
 	if err != nil {
 		log.Errorf("Failed to parse version: %v", err)
 		return err
 	}
-	commitAction, err = createOrUpdateGitlabFileCommitAction(git, proj, cfg.GitlabDefaultBranch, "VERSION", rulesheet.Version+"\n")
-	if err != nil {
-		log.Errorf("Failed to commit version: %v", err)
-		return err
+
+	// On review mode the changes go to a draft branch, except for the first version of the project, as
+	// there's no default branch to open a merge request against yet. The draft keeps the published
+	// VERSION, which is bumped only when the change is published.
+	review := cfg.GitlabReviewMode && version > 0
+	if !review {
+		rulesheet.Version = fmt.Sprintf("%d", version+1)
+
+		commitAction, err = createOrUpdateGitlabFileCommitAction(git, proj, cfg.GitlabDefaultBranch, "VERSION", rulesheet.Version+"\n")
+		if err != nil {
+			log.Errorf("Failed to commit version: %v", err)
+			return err
+		}
+		// GitLab refuses the commit if the VERSION changed since it was read, so concurrent saves can't
+		// overwrite each other
+		if versionFile != nil && versionFile.LastCommitID != "" {
+			commitAction.LastCommitID = gitlab.String(versionFile.LastCommitID)
+		}
+		actions = append(actions, commitAction)
 	}
-	actions = append(actions, commitAction)
 
 	ci := cfg.GitlabCIScript
 	commitAction, err = createOrUpdateGitlabFileCommitAction(git, proj, cfg.GitlabDefaultBranch, ".gitlab-ci.yml", ci)
@@ -131,88 +151,108 @@ func (gs *gitlabService) Save(rulesheet *dtos.Rulesheet, commitMessage string) e
 	}
 	actions = append(actions, commitAction)
 
-	// FEATURES
-	if rulesheet.Features == nil {
-		empty := make([]map[string]interface{}, 0)
-		rulesheet.Features = &empty
-	}
-
-	sort.Slice(*rulesheet.Features, func(i, j int) bool {
-		a := (*rulesheet.Features)[i]
-		b := (*rulesheet.Features)[j]
-		aValue := a["name"].(string)
-		bValue := b["name"].(string)
-		return aValue < bValue
-
-	})
-
-	content, err = json.MarshalIndent(rulesheet.Features, "", "  ")
+	features, parameters, rules, err := marshalRulesheetFiles(rulesheet)
 	if err != nil {
-		log.Errorf("Failed to marshal features: %v", err)
 		return err
 	}
-	commitAction, err = createOrUpdateGitlabFileCommitAction(git, proj, cfg.GitlabDefaultBranch, "features.json", string(content))
+
+	commitAction, err = createOrUpdateGitlabFileCommitAction(git, proj, cfg.GitlabDefaultBranch, "features.json", string(features))
 	if err != nil {
 		log.Errorf("Failed to commit features: %v", err)
 		return err
 	}
 	actions = append(actions, commitAction)
 
-	// PARAMETERS
-	if rulesheet.Parameters == nil {
-		empty := make([]map[string]interface{}, 0)
-		rulesheet.Parameters = &empty
+	commitAction, err = createOrUpdateGitlabFileCommitAction(git, proj, cfg.GitlabDefaultBranch, "parameters.json", string(parameters))
+	if err != nil {
+		log.Errorf("Failed to commit parameters: %v", err)
+		return err
 	}
+	actions = append(actions, commitAction)
 
-	sort.Slice(*rulesheet.Parameters, func(i, j int) bool {
-		a := (*rulesheet.Parameters)[i]
-		b := (*rulesheet.Parameters)[j]
-		aValue := a["name"].(string)
-		bValue := b["name"].(string)
-		return aValue < bValue
-	})
-
-	content, err = json.MarshalIndent(rulesheet.Parameters, "", "  ")
+	commitAction, err = createOrUpdateGitlabFileCommitAction(git, proj, cfg.GitlabDefaultBranch, "rules.json", string(rules))
 	if err != nil {
-		log.Errorf("Failed to marshal parameters: %v", err)
+		log.Errorf("Failed to commit rules: %v", err)
 		return err
 	}
-	commitAction, err = createOrUpdateGitlabFileCommitAction(git, proj, cfg.GitlabDefaultBranch, "parameters.json", string(content))
+	actions = append(actions, commitAction)
+
+	tests, err := marshalRulesheetTests(rulesheet)
 	if err != nil {
-		log.Errorf("Failed to commit parameters: %v", err)
 		return err
 	}
-	actions = append(actions, commitAction)
 
-	// Rules
-	if rulesheet.Rules == nil {
-		empty := make(map[string]interface{}, 0)
-		rulesheet.Rules = &empty
+	if tests != nil {
+		commitAction, err = createOrUpdateGitlabFileCommitAction(git, proj, cfg.GitlabDefaultBranch, "tests.json", string(tests))
+		if err != nil {
+			log.Errorf("Failed to commit tests: %v", err)
+			return err
+		}
+		actions = append(actions, commitAction)
 	}
 
-	content, err = json.MarshalIndent(rulesheet.Rules, "", "  ")
-	if err != nil {
-		log.Errorf("Failed to marshal parameters: %v", err)
-		return err
+	// the legacy rules are replaced by rules.json only when they're converted
+	if rulesheet.RemoveLegacyRules {
+		_, resp, err = git.RepositoryFiles.GetFile(proj.ID, "rules.featws", &gitlab.GetFileOptions{
+			Ref: gitlab.String(cfg.GitlabDefaultBranch),
+		})
+		if err == nil {
+			actions = append(actions, &gitlab.CommitActionOptions{
+				Action:   gitlab.FileAction(gitlab.FileDelete),
+				FilePath: gitlab.String("rules.featws"),
+			})
+		} else if resp == nil || resp.StatusCode != http.StatusNotFound {
+			log.Errorf("Failed to fetch rules.featws: %v", err)
+			return err
+		}
 	}
-	commitAction, err = createOrUpdateGitlabFileCommitAction(git, proj, cfg.GitlabDefaultBranch, "rules.json", string(content))
-	if err != nil {
-		log.Errorf("Failed to commit parameters: %v", err)
-		return err
+
+	branch := cfg.GitlabDefaultBranch
+	if review {
+		branch = fmt.Sprintf("%s%s-%d", gitlabChangeBranchPrefix, rulesheet.Version, time.Now().Unix())
+
+		_, _, err = git.Branches.CreateBranch(proj.ID, &gitlab.CreateBranchOptions{
+			Branch: gitlab.String(branch),
+			Ref:    gitlab.String(cfg.GitlabDefaultBranch),
+		})
+		if err != nil {
+			log.Errorf("Failed to create branch: %v", err)
+			return err
+		}
 	}
-	actions = append(actions, commitAction)
 
-	_, _, err = git.Commits.CreateCommit(proj.ID, &gitlab.CreateCommitOptions{
-		Branch:        &cfg.GitlabDefaultBranch,
+	commit, resp, err := git.Commits.CreateCommit(proj.ID, &gitlab.CreateCommitOptions{
+		Branch:        &branch,
 		CommitMessage: gitlab.String(commitMessage),
 		Actions:       actions,
 	})
 	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusBadRequest && strings.Contains(err.Error(), "has changed since") {
+			return fmt.Errorf("%w: the VERSION changed while saving", ErrVersionConflict)
+		}
 		log.Errorf("Failed to create commit: %v", err)
 		return err
 	}
 
-	return err
+	if review {
+		_, _, err = git.MergeRequests.CreateMergeRequest(proj.ID, &gitlab.CreateMergeRequestOptions{
+			Title:              gitlab.String(commitMessage),
+			Description:        gitlab.String(fmt.Sprintf("Change of the rulesheet %s, based on the version %s. The version is bumped when it's published.", rulesheet.Name, rulesheet.Version)),
+			SourceBranch:       gitlab.String(branch),
+			TargetBranch:       gitlab.String(cfg.GitlabDefaultBranch),
+			RemoveSourceBranch: gitlab.Bool(true),
+		})
+		if err != nil {
+			log.Errorf("Failed to create merge request: %v", err)
+			return err
+		}
+		return nil
+	}
+
+	// TAG
+	gitlabCreateTag(git, cfg, proj, rulesheet.Version, commit.ID, fmt.Sprintf("%s\n\nAuthor: %s <%s>", commitMessage, commit.AuthorName, commit.AuthorEmail))
+
+	return nil
 }
 
 // func printRule(rule interface{}, rulesBuffer *bytes.Buffer, ruleName string, isSliceItem bool) error {
@@ -281,12 +321,18 @@ func defineCreateOrUpdateGitlabFileAction(git *gitlab.Client, proj *gitlab.Proje
 	return gitlab.FileAction(gitlab.FileUpdate), nil
 }
 
-// Fill is a method in a GitLab service that fills a `Rulesheet` struct with data from GitLab. It first
+// Fill is a method in a GitLab service that fills a `Rulesheet` struct with data from GitLab. It reads
+// the content of the project's default branch using the `FillRef` method.
+func (gs *gitlabService) Fill(rulesheet *dtos.Rulesheet) error {
+	return gs.FillRef(rulesheet, gs.cfg.GitlabDefaultBranch)
+}
+
+// FillRef is a method in a GitLab service that fills a `Rulesheet` struct with data from GitLab. It first
 // checks if a GitLab token is provided, and if not, it returns nil. It then connects to GitLab using the
 // provided token and fetches the namespace and project associated with the provided GitLab namespace and
-// prefix. It fetches the version, features, parameters, and rules data from the project's default branch
+// prefix. It fetches the version, features, parameters, tests and rules data from the given ref of the project
 // and populates the corresponding fields in the `Rulesheet` struct.
-func (gs *gitlabService) Fill(rulesheet *dtos.Rulesheet) (err error) {
+func (gs *gitlabService) FillRef(rulesheet *dtos.Rulesheet, ref string) (err error) {
 	if gs.cfg.GitlabToken == "" {
 		return nil
 	}
@@ -297,19 +343,12 @@ func (gs *gitlabService) Fill(rulesheet *dtos.Rulesheet) (err error) {
 		return
 	}
 
-	ns, _, err := git.Namespaces.GetNamespace(gs.cfg.GitlabNamespace)
-	if err != nil {
-		log.Errorf("Failed to fetch namespace: %v", err)
-		return
-	}
-
-	proj, _, err := git.Projects.GetProject(fmt.Sprintf("%s/%s%s", ns.FullPath, gs.cfg.GitlabPrefix, rulesheet.Slug), &gitlab.GetProjectOptions{})
+	proj, err := gitlabFetchProject(git, gs.cfg, rulesheet)
 	if err != nil {
-		log.Errorf("Failed to fetch project: %v", err)
 		return
 	}
 
-	bVersion, err := gitlabLoadString(git, proj, gs.cfg.GitlabDefaultBranch, "VERSION")
+	bVersion, err := gitlabLoadString(git, proj, ref, "VERSION")
 	if err != nil {
 		log.Errorf("Failed to fetch version: %v", err)
 		return
@@ -317,81 +356,197 @@ func (gs *gitlabService) Fill(rulesheet *dtos.Rulesheet) (err error) {
 
 	rulesheet.Version = strings.Replace(string(bVersion), "\n", "", -1)
 
-	err = gitlabLoadJSON(git, proj, gs.cfg.GitlabDefaultBranch, "features.json", &rulesheet.Features)
+	err = gitlabLoadJSON(git, proj, ref, "features.json", &rulesheet.Features)
 	if err != nil {
 		log.Errorf("Failed to fetch features: %v", err)
 		return
 	}
 
-	err = gitlabLoadJSON(git, proj, gs.cfg.GitlabDefaultBranch, "parameters.json", &rulesheet.Parameters)
+	err = gitlabLoadJSON(git, proj, ref, "parameters.json", &rulesheet.Parameters)
 	if err != nil {
 		log.Errorf("Failed to fetch parameters: %v", err)
 		return
 	}
 
-	bRulesJSON, err := gitlabLoadString(git, proj, gs.cfg.GitlabDefaultBranch, "rules.json")
+	err = gitlabLoadJSON(git, proj, ref, "tests.json", &rulesheet.Tests)
+	if err != nil {
+		log.Errorf("Failed to fetch tests: %v", err)
+		return
+	}
+
+	bRulesJSON, err := gitlabLoadString(git, proj, ref, "rules.json")
 	if err != nil {
 		log.Errorf("Failed to check rules JSON: %v", err)
 		return
 	}
 
 	if string(bRulesJSON) != "" {
-		err = gitlabLoadJSON(git, proj, gs.cfg.GitlabDefaultBranch, "rules.json", &rulesheet.Rules)
+		err = gitlabLoadJSON(git, proj, ref, "rules.json", &rulesheet.Rules)
 		if err != nil {
 			log.Errorf("Failed to fetch parameters: %v", err)
 			return
 		}
 	} else {
-		bRules, err := gitlabLoadString(git, proj, gs.cfg.GitlabDefaultBranch, "rules.featws")
+		bRules, err := gitlabLoadString(git, proj, ref, "rules.featws")
 		if err != nil {
 			log.Errorf("Failed to fetch parameters: %v", err)
 			return err
 		}
 
-		rulesFile, err := featws.Load(bRules)
+		rulesheet.Rules, err = loadFeatwsRules(bRules)
 		if err != nil {
-			log.Errorf("Failed to load featws file: %v", err)
 			return err
 		}
 
-		rules := make(map[string]interface{})
+		rulesheet.HasStringRule = len(bRules) > 0
+	}
 
-		s := rulesFile.Section("")
+	return
+}
 
-		for _, k := range s.Keys() {
-			rules[k.Name()] = k.Value()
+// ResolveVersion resolves a version of a rulesheet to the SHA of the commit that published it. A numeric
+// version is looked up on the tag created by `Save`, if the tags are enabled, or by walking the commits of the default branch that changed the VERSION file, from
+// the newest to the oldest, until the VERSION content matches it. Any other value is handled as a commit
+// SHA and checked on the project. It returns `ErrVersionNotFound` if the version doesn't exist.
+func (gs *gitlabService) ResolveVersion(rulesheet *dtos.Rulesheet, version string) (string, error) {
+	if gs.cfg.GitlabToken == "" {
+		return "", ErrVersionNotFound
+	}
+
+	git, err := gs.Connect()
+	if err != nil {
+		log.Errorf("Error on connect the gitlab client: %v", err)
+		return "", err
+	}
+
+	proj, err := gitlabFetchProject(git, gs.cfg, rulesheet)
+	if err != nil {
+		return "", err
+	}
+
+	number, err := strconv.Atoi(version)
+	if err != nil {
+		commit, resp, err := git.Commits.GetCommit(proj.ID, version)
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				return "", ErrVersionNotFound
+			}
+			log.Errorf("Failed to fetch commit: %v", err)
+			return "", err
+		}
+		return commit.ID, nil
+	}
+
+	if gs.cfg.GitlabTagFormat != "" {
+		tag, resp, err := git.Tags.GetTag(proj.ID, versionTagName(gs.cfg, version))
+		if err == nil && tag.Commit != nil {
+			return tag.Commit.ID, nil
+		}
+		// versions published before the tags were enabled are still found on the commits
+		if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+			log.Errorf("Failed to fetch tag: %v", err)
+			return "", err
 		}
+	}
 
-		for _, sname := range rulesFile.SectionStrings() {
-			if sname == featws.DefaultSection {
-				continue
+	listOptions := &gitlab.ListCommitsOptions{
+		ListOptions: gitlab.ListOptions{
+			Page:    1,
+			PerPage: 100,
+		},
+		RefName: gitlab.String(gs.cfg.GitlabDefaultBranch),
+		Path:    gitlab.String("VERSION"),
+	}
+
+	for {
+		commits, resp, err := git.Commits.ListCommits(proj.ID, listOptions)
+		if err != nil {
+			log.Errorf("Failed to list commits: %v", err)
+			return "", err
+		}
+
+		for _, commit := range commits {
+			bVersion, err := gitlabLoadString(git, proj, commit.ID, "VERSION")
+			if err != nil {
+				log.Errorf("Failed to fetch version: %v", err)
+				return "", err
 			}
-			if sname[:1] == "[" {
+
+			commitNumber, err := strconv.Atoi(strings.Replace(string(bVersion), "\n", "", -1))
+			if err != nil {
 				continue
 			}
-			sec := make(map[string]interface{})
-			for _, k := range rulesFile.Section(sname).Keys() {
-				sec[k.Name()] = k.Value()
+
+			if commitNumber == number {
+				return commit.ID, nil
+			}
+
+			// the versions only grow, so an older commit can't hold it anymore
+			if commitNumber < number {
+				return "", ErrVersionNotFound
 			}
-			rules[sname] = sec
 		}
 
-		for _, aname := range rulesFile.ArrayStrings() {
-			a := rulesFile.Array(aname)
+		if resp.NextPage == 0 {
+			return "", ErrVersionNotFound
+		}
+		listOptions.Page = resp.NextPage
+	}
+}
 
-			arr := make([]map[string]interface{}, 0)
+// History lists the versions published for a rulesheet. Every commit made by `Save` bumps the VERSION
+// file, so the method walks the commits of the default branch that touched that file, from the newest
+// to the oldest, and loads the VERSION content at each one of them. If no GitLab token is provided, it
+// returns an empty list.
+func (gs *gitlabService) History(rulesheet *dtos.Rulesheet, options *FindOptions) (result []*dtos.Version, err error) {
+	result = make([]*dtos.Version, 0)
 
-			for _, s := range a.Sections() {
-				sec := make(map[string]interface{})
-				for _, k := range s.Keys() {
-					sec[k.Name()] = k.Value()
-				}
-				arr = append(arr, sec)
-			}
-			rules[aname[1:len(aname)-1]] = arr
+	if gs.cfg.GitlabToken == "" {
+		return
+	}
+
+	git, err := gs.Connect()
+	if err != nil {
+		log.Errorf("Error on connect the gitlab client: %v", err)
+		return
+	}
+
+	proj, err := gitlabFetchProject(git, gs.cfg, rulesheet)
+	if err != nil {
+		return
+	}
+
+	listOptions := &gitlab.ListCommitsOptions{
+		RefName: gitlab.String(gs.cfg.GitlabDefaultBranch),
+		Path:    gitlab.String("VERSION"),
+	}
+
+	if options != nil {
+		listOptions.Page = options.Page
+		listOptions.PerPage = options.Limit
+	}
+
+	commits, _, err := git.Commits.ListCommits(proj.ID, listOptions)
+	if err != nil {
+		log.Errorf("Failed to list commits: %v", err)
+		return
+	}
+
+	for _, commit := range commits {
+		bVersion, err := gitlabLoadString(git, proj, commit.ID, "VERSION")
+		if err != nil {
+			log.Errorf("Failed to fetch version: %v", err)
+			return nil, err
 		}
 
-		rulesheet.Rules = &rules
+		result = append(result, &dtos.Version{
+			Number:      strings.Replace(string(bVersion), "\n", "", -1),
+			Commit:      commit.ID,
+			Author:      commit.AuthorName,
+			AuthorEmail: commit.AuthorEmail,
+			Date:        commit.AuthoredDate,
+			Message:     commit.Message,
+		})
 	}
 
 	return
@@ -410,7 +565,49 @@ func (gs *gitlabService) Connect() (*gitlab.Client, error) {
 	return git, nil
 }
 
-// gitlabLoadJSON loads a JSON file from a GitLab project and decodes it into a given Go struct.
+// gitlabCreateTag creates the annotated tag of a version on the given ref, if the tag format is configured.
+// The version is already published by the commit, so a tag that fails is only logged: failing the save
+// would retry it and publish the version again.
+func gitlabCreateTag(git *gitlab.Client, cfg *config.Config, proj *gitlab.Project, version string, ref string, message string) {
+	if cfg.GitlabTagFormat == "" {
+		return
+	}
+
+	_, _, err := git.Tags.CreateTag(proj.ID, &gitlab.CreateTagOptions{
+		TagName: gitlab.String(versionTagName(cfg, version)),
+		Ref:     gitlab.String(ref),
+		Message: gitlab.String(message),
+	})
+	if err != nil {
+		log.Warnf("The version %s was published without its tag: %v", version, err)
+	}
+}
+
+// versionTagName returns the name of the tag of a version, following the configured tag format.
+func versionTagName(cfg *config.Config, version string) string {
+	return strings.ReplaceAll(cfg.GitlabTagFormat, "{version}", version)
+}
+
+// gitlabFetchProject fetches the GitLab project of a rulesheet, resolved by the configured namespace and
+// prefix followed by the rulesheet slug.
+func gitlabFetchProject(git *gitlab.Client, cfg *config.Config, rulesheet *dtos.Rulesheet) (*gitlab.Project, error) {
+	ns, _, err := git.Namespaces.GetNamespace(cfg.GitlabNamespace)
+	if err != nil {
+		log.Errorf("Failed to fetch namespace: %v", err)
+		return nil, err
+	}
+
+	proj, _, err := git.Projects.GetProject(fmt.Sprintf("%s/%s%s", ns.FullPath, cfg.GitlabPrefix, rulesheet.Slug), &gitlab.GetProjectOptions{})
+	if err != nil {
+		log.Errorf("Failed to fetch project: %v", err)
+		return nil, err
+	}
+
+	return proj, nil
+}
+
+// gitlabLoadJSON loads a JSON file from a GitLab project and decodes it into a given Go struct. It returns
+// the decoding error when the file doesn't match the struct.
 func gitlabLoadJSON(git *gitlab.Client, proj *gitlab.Project, ref string, fileName string, result interface{}) error {
 	rawDecodedText, err := gitlabLoadString(git, proj, ref, fileName)
 	if err != nil {
@@ -419,7 +616,12 @@ func gitlabLoadJSON(git *gitlab.Client, proj *gitlab.Project, ref string, fileNa
 	}
 
 	if len(rawDecodedText) > 0 {
-		json.Unmarshal(rawDecodedText, result)
+		// a file that can't be decoded fails, so its content isn't overwritten by an empty or partial one
+		err = json.Unmarshal(rawDecodedText, result)
+		if err != nil {
+			log.Errorf("Error on decode %s: %v", fileName, err)
+			return err
+		}
 	}
 
 	return nil
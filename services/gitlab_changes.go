@@ -0,0 +1,276 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bancodobrasil/featws-api/dtos"
+	log "github.com/sirupsen/logrus"
+	"github.com/xanzy/go-gitlab"
+)
+
+// gitlabChangeBranchPrefix is the prefix of the draft branches created by `Save` on review mode.
+const gitlabChangeBranchPrefix = "featws/change-"
+
+// gitlabBumpAttempts is the number of times the VERSION is read again when another change is published
+// while a change is bumping it.
+const gitlabBumpAttempts = 3
+
+// ErrChangeRequestNotFound is returned when a change request doesn't exist or isn't pending anymore.
+var ErrChangeRequestNotFound = errors.New("change request not found")
+
+// ErrChangeRequestNotApproved is returned when a change request is published without the approvals it requires.
+var ErrChangeRequestNotApproved = errors.New("change request not approved")
+
+// ErrChangeRequestNotMergeable is returned when a change request can't be merged, e.g. because of conflicts
+// with another change published before it.
+var ErrChangeRequestNotMergeable = errors.New("change request can't be merged")
+
+// ListChangeRequests lists the pending changes of a rulesheet, i.e. the opened merge requests of the draft
+// branches created by `Save` on review mode. If no GitLab token is provided, it returns an empty list.
+func (gs *gitlabService) ListChangeRequests(rulesheet *dtos.Rulesheet) (result []*dtos.ChangeRequest, err error) {
+	result = make([]*dtos.ChangeRequest, 0)
+
+	if gs.cfg.GitlabToken == "" {
+		return
+	}
+
+	git, err := gs.Connect()
+	if err != nil {
+		log.Errorf("Error on connect the gitlab client: %v", err)
+		return
+	}
+
+	proj, err := gitlabFetchProject(git, gs.cfg, rulesheet)
+	if err != nil {
+		return
+	}
+
+	mrs, _, err := git.MergeRequests.ListProjectMergeRequests(proj.ID, &gitlab.ListProjectMergeRequestsOptions{
+		State:        gitlab.String("opened"),
+		TargetBranch: gitlab.String(gs.cfg.GitlabDefaultBranch),
+	})
+	if err != nil {
+		log.Errorf("Failed to list merge requests: %v", err)
+		return
+	}
+
+	for _, mr := range mrs {
+		if !strings.HasPrefix(mr.SourceBranch, gitlabChangeBranchPrefix) {
+			continue
+		}
+
+		approvals, _, err := git.MergeRequestApprovals.GetConfiguration(proj.ID, mr.IID)
+		if err != nil {
+			log.Errorf("Failed to fetch merge request approvals: %v", err)
+			return nil, err
+		}
+
+		result = append(result, newChangeRequestDTO(mr, approvals))
+	}
+
+	return
+}
+
+// ApproveChangeRequest approves a pending change of a rulesheet on behalf of the user of the GitLab token.
+func (gs *gitlabService) ApproveChangeRequest(rulesheet *dtos.Rulesheet, id int) (*dtos.ChangeRequest, error) {
+	git, proj, mr, err := gs.fetchChangeRequest(rulesheet, id)
+	if err != nil {
+		return nil, err
+	}
+
+	approvals, _, err := git.MergeRequestApprovals.ApproveMergeRequest(proj.ID, mr.IID, &gitlab.ApproveMergeRequestOptions{
+		SHA: gitlab.String(mr.SHA),
+	})
+	if err != nil {
+		log.Errorf("Failed to approve merge request: %v", err)
+		return nil, err
+	}
+
+	return newChangeRequestDTO(mr, approvals), nil
+}
+
+// PublishChangeRequest merges an approved change of a rulesheet into the default branch, then bumps the
+// VERSION and tags the new version. It returns `ErrChangeRequestNotApproved` if the merge request still
+// requires approvals and `ErrChangeRequestNotMergeable` if GitLab refuses to merge it.
+func (gs *gitlabService) PublishChangeRequest(rulesheet *dtos.Rulesheet, id int) error {
+	git, proj, mr, err := gs.fetchChangeRequest(rulesheet, id)
+	if err != nil {
+		return err
+	}
+
+	approvals, _, err := git.MergeRequestApprovals.GetConfiguration(proj.ID, mr.IID)
+	if err != nil {
+		log.Errorf("Failed to fetch merge request approvals: %v", err)
+		return err
+	}
+
+	change := newChangeRequestDTO(mr, approvals)
+	if !change.Approved {
+		return ErrChangeRequestNotApproved
+	}
+
+	_, resp, err := git.MergeRequests.AcceptMergeRequest(proj.ID, mr.IID, &gitlab.AcceptMergeRequestOptions{
+		ShouldRemoveSourceBranch: gitlab.Bool(true),
+		SHA:                      gitlab.String(mr.SHA),
+	})
+	if err != nil {
+		if resp != nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotAcceptable || resp.StatusCode == http.StatusConflict) {
+			return ErrChangeRequestNotMergeable
+		}
+		log.Errorf("Failed to merge merge request: %v", err)
+		return err
+	}
+
+	message := fmt.Sprintf("%s\n\nAuthor: %s\nApproved by: %s", mr.Title, change.Author, strings.Join(change.ApprovedBy, ", "))
+
+	version, commit, err := gs.bumpVersion(git, proj, message)
+	if err != nil {
+		return err
+	}
+
+	gitlabCreateTag(git, gs.cfg, proj, version, commit, message)
+
+	return nil
+}
+
+// bumpVersion commits the next VERSION on the default branch, after a change is merged, and returns the
+// version and its commit. The version is decided only when the change is published, since the changes
+// published before it, after its draft was created, moved it too. A VERSION changed meanwhile by another
+// publication is read again.
+func (gs *gitlabService) bumpVersion(git *gitlab.Client, proj *gitlab.Project, message string) (string, string, error) {
+	for attempt := 1; ; attempt++ {
+		versionFile, _, err := git.RepositoryFiles.GetFile(proj.ID, "VERSION", &gitlab.GetFileOptions{
+			Ref: gitlab.String(gs.cfg.GitlabDefaultBranch),
+		})
+		if err != nil {
+			log.Errorf("Failed to resolve version: %v", err)
+			return "", "", err
+		}
+
+		bVersion, err := base64.StdEncoding.DecodeString(versionFile.Content)
+		if err != nil {
+			log.Errorf("Failed to decode base64: %v", err)
+			return "", "", err
+		}
+
+		current, err := strconv.Atoi(strings.Replace(string(bVersion), "\n", "", -1))
+		if err != nil {
+			log.Errorf("Failed to parse version: %v", err)
+			return "", "", err
+		}
+		version := fmt.Sprintf("%d", current+1)
+
+		commit, resp, err := git.Commits.CreateCommit(proj.ID, &gitlab.CreateCommitOptions{
+			Branch:        gitlab.String(gs.cfg.GitlabDefaultBranch),
+			CommitMessage: gitlab.String(message),
+			Actions: []*gitlab.CommitActionOptions{{
+				Action:       gitlab.FileAction(gitlab.FileUpdate),
+				FilePath:     gitlab.String("VERSION"),
+				Content:      gitlab.String(version + "\n"),
+				LastCommitID: gitlab.String(versionFile.LastCommitID),
+			}},
+		})
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusBadRequest && strings.Contains(err.Error(), "has changed since") && attempt < gitlabBumpAttempts {
+				continue
+			}
+			log.Errorf("Failed to commit version: %v", err)
+			return "", "", err
+		}
+
+		return version, commit.ID, nil
+	}
+}
+
+// DiscardChangeRequest closes the merge request of a pending change of a rulesheet and deletes its draft branch.
+func (gs *gitlabService) DiscardChangeRequest(rulesheet *dtos.Rulesheet, id int) error {
+	git, proj, mr, err := gs.fetchChangeRequest(rulesheet, id)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = git.MergeRequests.UpdateMergeRequest(proj.ID, mr.IID, &gitlab.UpdateMergeRequestOptions{
+		StateEvent: gitlab.String("close"),
+	})
+	if err != nil {
+		log.Errorf("Failed to close merge request: %v", err)
+		return err
+	}
+
+	resp, err := git.Branches.DeleteBranch(proj.ID, mr.SourceBranch)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		log.Errorf("Failed to delete branch: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// fetchChangeRequest fetches the merge request of a pending change of a rulesheet. It returns
+// `ErrChangeRequestNotFound` if the merge request doesn't exist, isn't opened or wasn't created by `Save`.
+func (gs *gitlabService) fetchChangeRequest(rulesheet *dtos.Rulesheet, id int) (*gitlab.Client, *gitlab.Project, *gitlab.MergeRequest, error) {
+	if gs.cfg.GitlabToken == "" {
+		return nil, nil, nil, ErrChangeRequestNotFound
+	}
+
+	git, err := gs.Connect()
+	if err != nil {
+		log.Errorf("Error on connect the gitlab client: %v", err)
+		return nil, nil, nil, err
+	}
+
+	proj, err := gitlabFetchProject(git, gs.cfg, rulesheet)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	mr, resp, err := git.MergeRequests.GetMergeRequest(proj.ID, id, &gitlab.GetMergeRequestsOptions{})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil, nil, ErrChangeRequestNotFound
+		}
+		log.Errorf("Failed to fetch merge request: %v", err)
+		return nil, nil, nil, err
+	}
+
+	if mr.State != "opened" || !strings.HasPrefix(mr.SourceBranch, gitlabChangeBranchPrefix) {
+		return nil, nil, nil, ErrChangeRequestNotFound
+	}
+
+	return git, proj, mr, nil
+}
+
+// The function creates a new DTO for a merge request and its approvals. A change is approved when it got
+// at least one approval and no approval rule is left.
+func newChangeRequestDTO(mr *gitlab.MergeRequest, approvals *gitlab.MergeRequestApprovals) *dtos.ChangeRequest {
+	dto := &dtos.ChangeRequest{
+		ID:          mr.IID,
+		Title:       mr.Title,
+		Description: mr.Description,
+		Branch:      mr.SourceBranch,
+		State:       mr.State,
+		WebURL:      mr.WebURL,
+		CreatedAt:   mr.CreatedAt,
+		ApprovedBy:  make([]string, 0),
+	}
+
+	if mr.Author != nil {
+		dto.Author = mr.Author.Name
+	}
+
+	if approvals != nil {
+		for _, approver := range approvals.ApprovedBy {
+			if approver.User != nil {
+				dto.ApprovedBy = append(dto.ApprovedBy, approver.User.Name)
+			}
+		}
+		dto.Approved = len(dto.ApprovedBy) > 0 && approvals.ApprovalsLeft == 0
+	}
+
+	return dto
+}
@@ -0,0 +1,281 @@
+package services_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bancodobrasil/featws-api/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/xanzy/go-gitlab"
+)
+
+// The function returns a mock GitLab server with the project of the test rulesheet and a pending change
+// request. The handler receives the requests not handled by the server.
+func SetupChangeRequestServer(t *testing.T, approvedBy string, handler http.HandlerFunc) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v4/namespaces/test" {
+			w.Write([]byte(`{"id":1,"name":"teste", "full_path":"testpath"}`))
+			return
+		}
+
+		if r.Method == "GET" && r.URL.Path == "/api/v4/projects/testpath/prefix-test" {
+			w.Write([]byte(`{"id":1,"description":"testeDesc","name":"teste"}`))
+			return
+		}
+
+		if r.Method == "GET" && r.URL.Path == "/api/v4/projects/1/merge_requests/5" {
+			w.Write([]byte(`{"iid":5,"state":"opened","title":"Update","source_branch":"featws/change-2-1","sha":"abc","author":{"name":"ana"}}`))
+			return
+		}
+
+		if r.Method == "GET" && r.URL.Path == "/api/v4/projects/1/merge_requests/6" {
+			w.Write([]byte(`{"iid":6,"state":"opened","title":"Other","source_branch":"feature/other","sha":"def"}`))
+			return
+		}
+
+		if r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/approvals") {
+			w.Write([]byte(`{"approvals_left":0,"approved_by":[` + approvedBy + `]}`))
+			return
+		}
+
+		handler(w, r)
+	}))
+}
+
+// This is a test function that checks if the changes are committed to a draft branch with a merge request
+// on review mode, instead of the default branch, keeping the published version.
+func TestSaveOnReviewMode(t *testing.T) {
+	dto := SetupRulesheet()
+
+	var branch string
+	merged := false
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v4/namespaces/test" {
+			w.Write([]byte(`{"id":1,"name":"teste", "full_path":"testpath"}`))
+			return
+		}
+		if r.Method == "POST" && r.URL.Path == "/api/v4/projects" {
+			data, _ := io.ReadAll(r.Body)
+			w.Write(data)
+			return
+		}
+		if r.Method == "GET" && r.URL.Path == "/api/v4/projects/0/repository/files/VERSION" {
+			file := gitlab.File{
+				Content: base64.StdEncoding.EncodeToString([]byte("1\n")),
+			}
+			data, _ := json.Marshal(file)
+			w.Write(data)
+			return
+		}
+		if r.Method == "POST" && r.URL.Path == "/api/v4/projects/0/repository/branches" {
+			data, _ := io.ReadAll(r.Body)
+			c := make(map[string]string)
+			json.Unmarshal(data, &c)
+
+			assert.Equal(t, "main", c["ref"])
+			branch = c["branch"]
+			w.Write([]byte(`{"name":"` + branch + `"}`))
+			return
+		}
+		if r.Method == "POST" && r.URL.Path == "/api/v4/projects/0/repository/commits" {
+			data, _ := io.ReadAll(r.Body)
+			c := make(map[string]interface{})
+			json.Unmarshal(data, &c)
+
+			assert.Equal(t, branch, c["branch"])
+			for _, action := range c["actions"].([]interface{}) {
+				assert.NotEqual(t, "VERSION", action.(map[string]interface{})["file_path"])
+			}
+			w.Write([]byte(`{"id":"sha2"}`))
+			return
+		}
+		if r.Method == "POST" && r.URL.Path == "/api/v4/projects/0/merge_requests" {
+			data, _ := io.ReadAll(r.Body)
+			c := make(map[string]interface{})
+			json.Unmarshal(data, &c)
+
+			assert.Equal(t, branch, c["source_branch"])
+			assert.Equal(t, "main", c["target_branch"])
+			assert.Equal(t, "test", c["title"])
+			merged = true
+			w.Write([]byte(`{"iid":1}`))
+			return
+		}
+		if r.Method == "POST" && r.URL.Path == "/api/v4/projects/0/repository/tags" {
+			t.Error("unexpected tag of a pending change")
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer s.Close()
+
+	cfg := SetupConfig(s)
+	cfg.GitlabDefaultBranch = "main"
+	cfg.GitlabReviewMode = true
+	cfg.GitlabTagFormat = "v{version}"
+
+	ngl := services.NewGitlab(cfg)
+	err := ngl.Save(dto, "test")
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(branch, "featws/change-1-"))
+	assert.True(t, merged)
+	assert.Equal(t, "1", dto.Version)
+}
+
+// This is a test function that checks if only the merge requests of the draft branches are listed as
+// pending changes.
+func TestListChangeRequests(t *testing.T) {
+	s := SetupChangeRequestServer(t, `{"user":{"name":"bob"}}`, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" && r.URL.Path == "/api/v4/projects/1/merge_requests" {
+			assert.Equal(t, "opened", r.URL.Query().Get("state"))
+			w.Write([]byte(`[{"iid":5,"state":"opened","title":"Update","source_branch":"featws/change-2-1"},{"iid":6,"state":"opened","source_branch":"feature/other"}]`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer s.Close()
+
+	cfg := SetupConfig(s)
+	cfg.GitlabDefaultBranch = "main"
+
+	ngl := services.NewGitlab(cfg)
+	changes, err := ngl.ListChangeRequests(SetupRulesheet())
+
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, 5, changes[0].ID)
+	assert.Equal(t, "featws/change-2-1", changes[0].Branch)
+	assert.True(t, changes[0].Approved)
+	assert.Equal(t, []string{"bob"}, changes[0].ApprovedBy)
+}
+
+// This is a test function that checks if an approved change is merged, then the version of the default
+// branch is bumped and tagged.
+func TestPublishChangeRequest(t *testing.T) {
+	tagged := false
+	merged := false
+
+	s := SetupChangeRequestServer(t, `{"user":{"name":"bob"}}`, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" && r.URL.Path == "/api/v4/projects/1/merge_requests/5/merge" {
+			merged = true
+			w.Write([]byte(`{"iid":5,"state":"merged","merge_commit_sha":"merged"}`))
+			return
+		}
+		if r.Method == "GET" && r.URL.Path == "/api/v4/projects/1/repository/files/VERSION" {
+			assert.True(t, merged)
+			assert.Equal(t, "main", r.URL.Query().Get("ref"))
+			file := gitlab.File{
+				Content:      base64.StdEncoding.EncodeToString([]byte("2\n")),
+				LastCommitID: "merged",
+			}
+			data, _ := json.Marshal(file)
+			w.Write(data)
+			return
+		}
+		if r.Method == "POST" && r.URL.Path == "/api/v4/projects/1/repository/commits" {
+			data, _ := io.ReadAll(r.Body)
+			c := make(map[string]interface{})
+			json.Unmarshal(data, &c)
+
+			assert.Equal(t, "main", c["branch"])
+			action := c["actions"].([]interface{})[0].(map[string]interface{})
+			assert.Equal(t, "VERSION", action["file_path"])
+			assert.Equal(t, "3\n", action["content"])
+			assert.Equal(t, "merged", action["last_commit_id"])
+			w.Write([]byte(`{"id":"bumped"}`))
+			return
+		}
+		if r.Method == "POST" && r.URL.Path == "/api/v4/projects/1/repository/tags" {
+			data, _ := io.ReadAll(r.Body)
+			c := make(map[string]interface{})
+			json.Unmarshal(data, &c)
+
+			assert.Equal(t, "v3", c["tag_name"])
+			assert.Equal(t, "bumped", c["ref"])
+			assert.Equal(t, "Update\n\nAuthor: ana\nApproved by: bob", c["message"])
+			tagged = true
+			w.Write([]byte(`{"name":"v3"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer s.Close()
+
+	cfg := SetupConfig(s)
+	cfg.GitlabDefaultBranch = "main"
+	cfg.GitlabTagFormat = "v{version}"
+
+	ngl := services.NewGitlab(cfg)
+	err := ngl.PublishChangeRequest(SetupRulesheet(), 5)
+
+	assert.NoError(t, err)
+	assert.True(t, tagged)
+}
+
+// This is a test function that checks if a change without approvals isn't merged.
+func TestPublishChangeRequestNotApproved(t *testing.T) {
+	s := SetupChangeRequestServer(t, ``, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" && r.URL.Path == "/api/v4/projects/1/merge_requests/5/merge" {
+			t.Error("unexpected merge of a change not approved")
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer s.Close()
+
+	cfg := SetupConfig(s)
+	cfg.GitlabDefaultBranch = "main"
+
+	ngl := services.NewGitlab(cfg)
+	err := ngl.PublishChangeRequest(SetupRulesheet(), 5)
+
+	assert.ErrorIs(t, err, services.ErrChangeRequestNotApproved)
+}
+
+// This is a test function that checks if a discarded change has its merge request closed and its draft
+// branch deleted, and that merge requests not created by the API aren't handled as changes.
+func TestDiscardChangeRequest(t *testing.T) {
+	closed := false
+	deleted := false
+
+	s := SetupChangeRequestServer(t, ``, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" && r.URL.Path == "/api/v4/projects/1/merge_requests/5" {
+			data, _ := io.ReadAll(r.Body)
+			c := make(map[string]interface{})
+			json.Unmarshal(data, &c)
+
+			assert.Equal(t, "close", c["state_event"])
+			closed = true
+			w.Write([]byte(`{"iid":5,"state":"closed"}`))
+			return
+		}
+		if r.Method == "DELETE" && r.URL.Path == "/api/v4/projects/1/repository/branches/featws/change-2-1" {
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer s.Close()
+
+	cfg := SetupConfig(s)
+	cfg.GitlabDefaultBranch = "main"
+
+	ngl := services.NewGitlab(cfg)
+	err := ngl.DiscardChangeRequest(SetupRulesheet(), 5)
+
+	assert.NoError(t, err)
+	assert.True(t, closed)
+	assert.True(t, deleted)
+
+	err = ngl.DiscardChangeRequest(SetupRulesheet(), 6)
+	assert.ErrorIs(t, err, services.ErrChangeRequestNotFound)
+
+	err = ngl.DiscardChangeRequest(SetupRulesheet(), 7)
+	assert.ErrorIs(t, err, services.ErrChangeRequestNotFound)
+}
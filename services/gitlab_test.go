@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/bancodobrasil/featws-api/config"
@@ -130,6 +131,79 @@ func TestSaveAndUpdateProject(t *testing.T) {
 
 }
 
+// This is a test function that checks if the legacy rules.featws file is removed on the commit that converts
+// the rules of a rulesheet to rules.json.
+func TestSaveRemovesFeatws(t *testing.T) {
+	dto := SetupRulesheet()
+	dto.RemoveLegacyRules = true
+
+	removed := false
+	for _, action := range saveGitlabActions(t, dto) {
+		a := action.(map[string]interface{})
+		if a["file_path"] == "rules.featws" {
+			assert.Equal(t, "delete", a["action"])
+			removed = true
+		}
+	}
+	assert.True(t, removed)
+}
+
+// This is a test function that checks if the legacy rules.featws file is kept on the commits that don't
+// convert the rules of a rulesheet.
+func TestSaveKeepsFeatws(t *testing.T) {
+	dto := SetupRulesheet()
+
+	for _, action := range saveGitlabActions(t, dto) {
+		a := action.(map[string]interface{})
+		assert.NotEqual(t, "rules.featws", a["file_path"])
+	}
+}
+
+// saveGitlabActions saves the rulesheet on a GitLab project that has the legacy rules.featws file and returns
+// the actions of the commit.
+func saveGitlabActions(t *testing.T, dto *dtos.Rulesheet) []interface{} {
+	namespace := "test"
+	var actions []interface{}
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v4/namespaces/"+namespace {
+			w.Write([]byte(`{"id":1,"name":"teste", "full_path":"test"}`))
+			return
+		}
+
+		if r.Method == "POST" && r.URL.Path == "/api/v4/projects" {
+			data, _ := io.ReadAll(r.Body)
+			w.Write(data)
+			return
+		}
+
+		if r.Method == "GET" && r.URL.Path == "/api/v4/projects/0/repository/files/rules.featws" {
+			w.Write([]byte(`{"file_path":"rules.featws"}`))
+			return
+		}
+
+		if r.Method == "POST" && r.URL.Path == "/api/v4/projects/0/repository/commits" {
+			data, _ := io.ReadAll(r.Body)
+			c := make(map[string]interface{})
+			json.Unmarshal(data, &c)
+			actions = c["actions"].([]interface{})
+			w.Write(data)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer s.Close()
+
+	cfg := SetupConfig(s)
+
+	ngl := services.NewGitlab(cfg)
+	ngl.Connect()
+	err := ngl.Save(dto, "test")
+	assert.NoError(t, err)
+
+	return actions
+}
+
 // This is a test function that tests the creation of test files in GitLab using Go.
 func TestSaveTestFilesCreation(t *testing.T) {
 	dto := SetupRulesheet()
@@ -181,22 +255,115 @@ func TestSaveTestFilesCreation(t *testing.T) {
 	}
 }
 
+// This is a test function that checks if an annotated tag is created on the commit of the new version.
+func TestSaveCreateTag(t *testing.T) {
+	dto := SetupRulesheet()
+
+	namespace := "test"
+	tagged := false
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v4/namespaces/"+namespace {
+			w.Write([]byte(`{"id":1,"name":"teste"}`))
+			return
+		}
+		if r.Method == "POST" && r.URL.Path == "/api/v4/projects" {
+
+			data, _ := io.ReadAll(r.Body)
+			w.Write(data)
+			return
+		}
+		if r.Method == "POST" && r.URL.Path == "/api/v4/projects/0/repository/commits" {
+			w.Write([]byte(`{"id":"sha1","author_name":"bot","author_email":"bot@example.com"}`))
+			return
+		}
+		if r.Method == "POST" && r.URL.Path == "/api/v4/projects/0/repository/tags" {
+			data, _ := io.ReadAll(r.Body)
+			c := make(map[string]interface{})
+			json.Unmarshal(data, &c)
+
+			assert.Equal(t, "rules-v1", c["tag_name"])
+			assert.Equal(t, "sha1", c["ref"])
+			assert.Equal(t, "test\n\nAuthor: bot <bot@example.com>", c["message"])
+			tagged = true
+
+			w.Write([]byte(`{"name":"rules-v1"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer s.Close()
+
+	cfg := SetupConfig(s)
+	cfg.GitlabTagFormat = "rules-v{version}"
+
+	ngl := services.NewGitlab(cfg)
+	err := ngl.Save(dto, "test")
+
+	assert.NoError(t, err)
+	assert.True(t, tagged)
+}
+
+// This is a test function that checks if a tag that fails doesn't fail the save, since the version is
+// already published by the commit.
+func TestSaveCreateTagFailure(t *testing.T) {
+	dto := SetupRulesheet()
+
+	namespace := "test"
+	commits := 0
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v4/namespaces/"+namespace {
+			w.Write([]byte(`{"id":1,"name":"teste"}`))
+			return
+		}
+		if r.Method == "POST" && r.URL.Path == "/api/v4/projects" {
+
+			data, _ := io.ReadAll(r.Body)
+			w.Write(data)
+			return
+		}
+		if r.Method == "POST" && r.URL.Path == "/api/v4/projects/0/repository/commits" {
+			commits++
+			w.Write([]byte(`{"id":"sha1","author_name":"bot","author_email":"bot@example.com"}`))
+			return
+		}
+		if r.Method == "POST" && r.URL.Path == "/api/v4/projects/0/repository/tags" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"Tag rules-v1 already exists"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer s.Close()
+
+	cfg := SetupConfig(s)
+	cfg.GitlabTagFormat = "rules-v{version}"
+
+	ngl := services.NewGitlab(cfg)
+	err := ngl.Save(dto, "test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1", dto.Version)
+	assert.Equal(t, 1, commits)
+}
+
 // This is a test function that checks if a GitLab API call saves test files with features correctly.
 func TestSaveTestFilesCreationWithFeatures(t *testing.T) {
 	dto := SetupRulesheet()
 
-	features := make([]map[string]interface{}, 0)
+	features := make([]dtos.Feature, 0)
 
-	features = append(features, map[string]interface{}{
-		"name": "test1",
+	features = append(features, dtos.Feature{
+		Name: "test1",
 	})
 
-	features = append(features, map[string]interface{}{
-		"name": "test2",
+	features = append(features, dtos.Feature{
+		Name: "test2",
 	})
 
-	features = append(features, map[string]interface{}{
-		"name": "test3",
+	features = append(features, dtos.Feature{
+		Name: "test3",
 	})
 
 	dto.Features = &features
@@ -251,18 +418,18 @@ func TestSaveTestFilesCreationWithFeatures(t *testing.T) {
 func TestSaveTestFilesCreationWithParameters(t *testing.T) {
 	dto := SetupRulesheet()
 
-	parameters := make([]map[string]interface{}, 0)
+	parameters := make([]dtos.Parameter, 0)
 
-	parameters = append(parameters, map[string]interface{}{
-		"name": "test1",
+	parameters = append(parameters, dtos.Parameter{
+		Name: "test1",
 	})
 
-	parameters = append(parameters, map[string]interface{}{
-		"name": "test2",
+	parameters = append(parameters, dtos.Parameter{
+		Name: "test2",
 	})
 
-	parameters = append(parameters, map[string]interface{}{
-		"name": "test3",
+	parameters = append(parameters, dtos.Parameter{
+		Name: "test3",
 	})
 
 	dto.Parameters = &parameters
@@ -643,6 +810,67 @@ func TestSaveTestFilesUpdate(t *testing.T) {
 	}
 }
 
+// This is a test function that checks if the commit of a new version only applies over the VERSION it was
+// read from, and if a rulesheet based on another version isn't saved.
+func TestSaveVersionConflict(t *testing.T) {
+	namespace := "test"
+	commits := 0
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v4/namespaces/"+namespace {
+			w.Write([]byte(`{"id":1,"name":"teste", "full_path":"testpath"}`))
+			return
+		}
+
+		if r.Method == "GET" && r.URL.Path == "/api/v4/projects/testpath/prefix-test" {
+			w.Write([]byte(`{"id":1,"description":"testeDesc","name":"teste"}`))
+			return
+		}
+
+		if r.Method == "GET" && r.URL.Path == "/api/v4/projects/1/repository/files/VERSION" {
+			file := gitlab.File{
+				Content:      base64.StdEncoding.EncodeToString([]byte("2\n")),
+				LastCommitID: "sha2",
+			}
+			data, _ := json.Marshal(file)
+			w.Write(data)
+			return
+		}
+
+		if r.Method == "POST" && r.URL.Path == "/api/v4/projects/1/repository/commits" {
+			commits++
+
+			c := make(map[string]interface{})
+			json.NewDecoder(r.Body).Decode(&c)
+
+			version := c["actions"].([]interface{})[0].(map[string]interface{})
+			assert.Equal(t, "3\n", version["content"])
+			assert.Equal(t, "sha2", version["last_commit_id"])
+
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"You are attempting to update a file that has changed since you started editing it."}`))
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer s.Close()
+
+	ngl := services.NewGitlab(SetupConfig(s))
+
+	dto := SetupRulesheet()
+	dto.BaseVersion = "1"
+	err := ngl.Save(dto, "test")
+	assert.ErrorIs(t, err, services.ErrVersionConflict)
+	assert.Equal(t, 0, commits)
+
+	dto = SetupRulesheet()
+	dto.BaseVersion = "2"
+	err = ngl.Save(dto, "test")
+	assert.ErrorIs(t, err, services.ErrVersionConflict)
+	assert.Equal(t, 1, commits)
+}
+
 // This is a test function that tests the functionality of filling a data transfer object with data
 // from GitLab API.
 func TestFill(t *testing.T) {
@@ -737,13 +965,13 @@ func TestFill(t *testing.T) {
 	}
 
 	param1 := (*dto.Parameters)[0]
-	if param1["name"] != "param1" || param1["type"] != "string" {
+	if param1.Name != "param1" || param1.Type != "string" {
 		t.Error("error on unmarshalling parameter 1")
 		return
 	}
 
 	param2 := (*dto.Parameters)[1]
-	if param2["name"] != "param2" || param1["type"] != "string" {
+	if param2.Name != "param2" || param1.Type != "string" {
 		t.Error("error on unmarshalling parameter 2")
 		return
 	}
@@ -754,13 +982,13 @@ func TestFill(t *testing.T) {
 	}
 
 	feat1 := (*dto.Features)[0]
-	if feat1["name"] != "feat1" || feat1["type"] != "string" {
+	if feat1.Name != "feat1" || feat1.Type != "string" {
 		t.Error("error on unmarshalling Feature 1")
 		return
 	}
 
 	feat2 := (*dto.Features)[1]
-	if feat2["name"] != "feat2" || param1["type"] != "string" {
+	if feat2.Name != "feat2" || param1.Type != "string" {
 		t.Error("error on unmarshalling Feature 2")
 		return
 	}
@@ -858,13 +1086,13 @@ func TestFillJSON(t *testing.T) {
 	}
 
 	param1 := (*dto.Parameters)[0]
-	if param1["name"] != "param1" || param1["type"] != "string" {
+	if param1.Name != "param1" || param1.Type != "string" {
 		t.Error("error on unmarshalling parameter 1")
 		return
 	}
 
 	param2 := (*dto.Parameters)[1]
-	if param2["name"] != "param2" || param1["type"] != "string" {
+	if param2.Name != "param2" || param1.Type != "string" {
 		t.Error("error on unmarshalling parameter 2")
 		return
 	}
@@ -875,13 +1103,13 @@ func TestFillJSON(t *testing.T) {
 	}
 
 	feat1 := (*dto.Features)[0]
-	if feat1["name"] != "feat1" || feat1["type"] != "string" {
+	if feat1.Name != "feat1" || feat1.Type != "string" {
 		t.Error("error on unmarshalling Feature 1")
 		return
 	}
 
 	feat2 := (*dto.Features)[1]
-	if feat2["name"] != "feat2" || param1["type"] != "string" {
+	if feat2.Name != "feat2" || param1.Type != "string" {
 		t.Error("error on unmarshalling Feature 2")
 		return
 	}
@@ -1178,3 +1406,323 @@ func TestSaveErrorOnParseVersion(t *testing.T) {
 		t.Error("unexpected error")
 	}
 }
+
+// This is a test function that checks if the history of a rulesheet is listed from the commits that
+// changed the VERSION file on the default branch.
+func TestHistory(t *testing.T) {
+
+	namespace := "test"
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v4/namespaces/"+namespace {
+			w.Write([]byte(`{"id":1,"name":"teste", "full_path":"testpath"}`))
+			return
+		}
+
+		if r.Method == "GET" && r.URL.Path == "/api/v4/projects/testpath/prefix-test" {
+			w.Write([]byte(`{"id":1,"description":"testeDesc","name":"teste"}`))
+			return
+		}
+
+		if r.Method == "GET" && r.URL.Path == "/api/v4/projects/1/repository/commits" {
+			assert.Equal(t, "main", r.URL.Query().Get("ref_name"))
+			assert.Equal(t, "VERSION", r.URL.Query().Get("path"))
+			assert.Equal(t, "2", r.URL.Query().Get("page"))
+			assert.Equal(t, "5", r.URL.Query().Get("per_page"))
+			w.Write([]byte(`[
+				{"id":"sha2","author_name":"Second","author_email":"second@test","authored_date":"2023-02-01T10:00:00Z","message":"second"},
+				{"id":"sha1","author_name":"First","author_email":"first@test","authored_date":"2023-01-01T10:00:00Z","message":"first"}
+			]`))
+			return
+		}
+
+		if r.Method == "GET" && r.URL.Path == "/api/v4/projects/1/repository/files/VERSION" {
+			version := map[string]string{"sha1": "1\n", "sha2": "2\n"}[r.URL.Query().Get("ref")]
+
+			file := gitlab.File{
+				Content: base64.StdEncoding.EncodeToString([]byte(version)),
+			}
+			data, _ := json.Marshal(file)
+			w.Write(data)
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer s.Close()
+
+	cfg := SetupConfig(s)
+	cfg.GitlabDefaultBranch = "main"
+
+	ngl := services.NewGitlab(cfg)
+	versions, err := ngl.History(SetupRulesheet(), &services.FindOptions{Limit: 5, Page: 2})
+	if err != nil {
+		t.Errorf("unexpected error on history: %s", err.Error())
+		return
+	}
+
+	assert.Len(t, versions, 2)
+	assert.Equal(t, "2", versions[0].Number)
+	assert.Equal(t, "sha2", versions[0].Commit)
+	assert.Equal(t, "Second", versions[0].Author)
+	assert.Equal(t, "second@test", versions[0].AuthorEmail)
+	assert.Equal(t, "second", versions[0].Message)
+	assert.Equal(t, "1", versions[1].Number)
+	assert.Equal(t, "sha1", versions[1].Commit)
+}
+
+// This is a test function that checks if an empty history is returned when the gitlab token is nil.
+func TestHistoryGitlabTokenNil(t *testing.T) {
+	cfg := config.Config{
+		GitlabURL:       "test",
+		GitlabNamespace: "test",
+	}
+
+	ngl := services.NewGitlab(&cfg)
+	versions, err := ngl.History(SetupRulesheet(), nil)
+	if err != nil {
+		t.Error("expected nil return if gitlab token is nil")
+	}
+	assert.Empty(t, versions)
+}
+
+// This is a test function that checks if a version number is resolved to the commit that published it and
+// if a commit SHA is checked on the project.
+func TestResolveVersion(t *testing.T) {
+
+	namespace := "test"
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v4/namespaces/"+namespace {
+			w.Write([]byte(`{"id":1,"name":"teste", "full_path":"testpath"}`))
+			return
+		}
+
+		if r.Method == "GET" && r.URL.Path == "/api/v4/projects/testpath/prefix-test" {
+			w.Write([]byte(`{"id":1,"description":"testeDesc","name":"teste"}`))
+			return
+		}
+
+		if r.Method == "GET" && r.URL.Path == "/api/v4/projects/1/repository/commits" {
+			w.Write([]byte(`[{"id":"sha3"},{"id":"sha2"},{"id":"sha1"}]`))
+			return
+		}
+
+		if r.Method == "GET" && r.URL.Path == "/api/v4/projects/1/repository/commits/abc123" {
+			w.Write([]byte(`{"id":"abc123fullsha"}`))
+			return
+		}
+
+		if r.Method == "GET" && r.URL.Path == "/api/v4/projects/1/repository/files/VERSION" {
+			version := map[string]string{"sha1": "1\n", "sha2": "2\n", "sha3": "4\n"}[r.URL.Query().Get("ref")]
+
+			file := gitlab.File{
+				Content: base64.StdEncoding.EncodeToString([]byte(version)),
+			}
+			data, _ := json.Marshal(file)
+			w.Write(data)
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer s.Close()
+
+	cfg := SetupConfig(s)
+	cfg.GitlabDefaultBranch = "main"
+
+	ngl := services.NewGitlab(cfg)
+
+	ref, err := ngl.ResolveVersion(SetupRulesheet(), "2")
+	assert.NoError(t, err)
+	assert.Equal(t, "sha2", ref)
+
+	ref, err = ngl.ResolveVersion(SetupRulesheet(), "abc123")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc123fullsha", ref)
+
+	_, err = ngl.ResolveVersion(SetupRulesheet(), "3")
+	assert.ErrorIs(t, err, services.ErrVersionNotFound)
+
+	_, err = ngl.ResolveVersion(SetupRulesheet(), "unknown")
+	assert.ErrorIs(t, err, services.ErrVersionNotFound)
+}
+
+// This is a test function that checks if a version number is resolved through its tag, falling back to the
+// commits for the versions published before the tags were enabled.
+func TestResolveVersionWithTag(t *testing.T) {
+
+	namespace := "test"
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v4/namespaces/"+namespace {
+			w.Write([]byte(`{"id":1,"name":"teste", "full_path":"testpath"}`))
+			return
+		}
+
+		if r.Method == "GET" && r.URL.Path == "/api/v4/projects/testpath/prefix-test" {
+			w.Write([]byte(`{"id":1,"description":"testeDesc","name":"teste"}`))
+			return
+		}
+
+		if r.Method == "GET" && r.URL.Path == "/api/v4/projects/1/repository/tags/v2" {
+			w.Write([]byte(`{"name":"v2","commit":{"id":"sha2"}}`))
+			return
+		}
+
+		if r.Method == "GET" && r.URL.Path == "/api/v4/projects/1/repository/commits" {
+			w.Write([]byte(`[{"id":"sha2"},{"id":"sha1"}]`))
+			return
+		}
+
+		if r.Method == "GET" && r.URL.Path == "/api/v4/projects/1/repository/files/VERSION" {
+			version := map[string]string{"sha1": "1\n", "sha2": "2\n"}[r.URL.Query().Get("ref")]
+
+			file := gitlab.File{
+				Content: base64.StdEncoding.EncodeToString([]byte(version)),
+			}
+			data, _ := json.Marshal(file)
+			w.Write(data)
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer s.Close()
+
+	cfg := SetupConfig(s)
+	cfg.GitlabDefaultBranch = "main"
+	cfg.GitlabTagFormat = "v{version}"
+
+	ngl := services.NewGitlab(cfg)
+
+	ref, err := ngl.ResolveVersion(SetupRulesheet(), "2")
+	assert.NoError(t, err)
+	assert.Equal(t, "sha2", ref)
+
+	ref, err = ngl.ResolveVersion(SetupRulesheet(), "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "sha1", ref)
+}
+
+// This is a test function that checks if the content of a rulesheet is read from the given ref.
+func TestFillRef(t *testing.T) {
+
+	namespace := "test"
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v4/namespaces/"+namespace {
+			w.Write([]byte(`{"id":1,"name":"teste", "full_path":"testpath"}`))
+			return
+		}
+
+		if r.Method == "GET" && r.URL.Path == "/api/v4/projects/testpath/prefix-test" {
+			w.Write([]byte(`{"id":1,"description":"testeDesc","name":"teste"}`))
+			return
+		}
+
+		if r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/api/v4/projects/1/repository/files/") {
+			assert.Equal(t, "sha1", r.URL.Query().Get("ref"))
+
+			content := map[string]string{
+				"VERSION":    "1\n",
+				"rules.json": `{"regra": "$old"}`,
+			}[strings.TrimPrefix(r.URL.Path, "/api/v4/projects/1/repository/files/")]
+			if content == "" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			file := gitlab.File{
+				Content: base64.StdEncoding.EncodeToString([]byte(content)),
+			}
+			data, _ := json.Marshal(file)
+			w.Write(data)
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer s.Close()
+
+	cfg := SetupConfig(s)
+
+	ngl := services.NewGitlab(cfg)
+	dto := SetupRulesheet()
+	err := ngl.FillRef(dto, "sha1")
+	if err != nil {
+		t.Errorf("unexpected error on fill: %s", err.Error())
+		return
+	}
+
+	assert.Equal(t, "1", dto.Version)
+	assert.Equal(t, "$old", (*dto.Rules)["regra"])
+}
+
+// This is a test function that checks if the members of the features and parameters that aren't modeled are
+// kept when a rulesheet is filled and saved back, and if a file that doesn't match them fails the fill.
+func TestFillAndSaveKeepsExtraMembers(t *testing.T) {
+	namespace := "test"
+	files := map[string]string{
+		"VERSION":         "1\n",
+		"features.json":   `[{"name":"feat1","type":"string","owner":"team-a"}]`,
+		"parameters.json": `[{"name":"param1","resolver":"user","source":{"kind":"header"}}]`,
+		"rules.json":      `{"feat1":{"value":"x"}}`,
+	}
+	saved := make(map[string]string)
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v4/namespaces/"+namespace {
+			w.Write([]byte(`{"id":1,"name":"teste", "full_path":"test"}`))
+			return
+		}
+
+		if r.Method == "GET" && r.URL.Path == "/api/v4/projects/test/prefix-test" {
+			w.Write([]byte(`{"id":1,"name":"test"}`))
+			return
+		}
+
+		if r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/api/v4/projects/1/repository/files/") {
+			content, ok := files[strings.TrimPrefix(r.URL.Path, "/api/v4/projects/1/repository/files/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			data, _ := json.Marshal(gitlab.File{Content: base64.StdEncoding.EncodeToString([]byte(content)), LastCommitID: "sha1"})
+			w.Write(data)
+			return
+		}
+
+		if r.Method == "POST" && r.URL.Path == "/api/v4/projects/1/repository/commits" {
+			data, _ := io.ReadAll(r.Body)
+			c := make(map[string]interface{})
+			json.Unmarshal(data, &c)
+			for _, action := range c["actions"].([]interface{}) {
+				a := action.(map[string]interface{})
+				saved[a["file_path"].(string)], _ = a["content"].(string)
+			}
+			w.Write([]byte(`{"id":"sha2"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer s.Close()
+
+	cfg := SetupConfig(s)
+
+	ngl := services.NewGitlab(cfg)
+	ngl.Connect()
+	dto := SetupRulesheet()
+	assert.NoError(t, ngl.Fill(dto))
+	assert.NoError(t, ngl.Save(dto, "test"))
+
+	assert.JSONEq(t, `[{"name":"feat1","type":"string","owner":"team-a"}]`, saved["features.json"])
+	assert.JSONEq(t, `[{"name":"param1","resolver":"user","source":{"kind":"header"}}]`, saved["parameters.json"])
+
+	// a mistyped member fails the fill, instead of loading a feature that would be saved without it
+	files["features.json"] = `[{"name":"feat1","type":1}]`
+	saved = make(map[string]string)
+	dto = SetupRulesheet()
+	assert.Error(t, ngl.Fill(dto))
+	assert.Empty(t, saved)
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bancodobrasil/featws-api/dtos"
+	payloads "github.com/bancodobrasil/featws-api/payloads/v1"
+)
+
+// ErrInvalidImport is returned when the files uploaded to import a rulesheet can't be read.
+var ErrInvalidImport = errors.New("the imported files are invalid")
+
+// ReadImportFiles reads the files uploaded to import a rulesheet, keyed by their names, into a payload with
+// its features, parameters, rules and test cases. The rules are read from a legacy `.featws` file or from a
+// rules.json file, together with the features.json, parameters.json and tests.json files of a bundle, as
+// they're committed by the storage backends. The string rules of a `.featws` file are converted into
+// structured rules by `dtos.ConvertStringRules`, returning the ones that can't be parsed as problems. It
+// returns `ErrInvalidImport` if a file is unknown, can't be parsed or there's no file at all.
+func ReadImportFiles(files map[string][]byte) (*payloads.Rulesheet, []*dtos.RuleError, error) {
+	if len(files) == 0 {
+		return nil, nil, fmt.Errorf("%w: no file was uploaded", ErrInvalidImport)
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	payload := &payloads.Rulesheet{}
+	problems := make([]*dtos.RuleError, 0)
+	rulesFile := ""
+
+	for _, name := range names {
+		content := files[name]
+
+		var err error
+		switch {
+		case strings.EqualFold(filepath.Ext(name), ".featws"), name == "rules.json":
+			if rulesFile != "" {
+				return nil, nil, fmt.Errorf("%w: the rules are on both %s and %s", ErrInvalidImport, rulesFile, name)
+			}
+			rulesFile = name
+
+			if name == "rules.json" {
+				err = json.Unmarshal(content, &payload.Rules)
+				break
+			}
+
+			var rules *map[string]interface{}
+			rules, err = loadFeatwsRules(content)
+			if err == nil {
+				converted, found := dtos.ConvertStringRules(*rules)
+				payload.Rules = &converted
+				problems = append(problems, found...)
+			}
+		case name == "features.json":
+			err = json.Unmarshal(content, &payload.Features)
+		case name == "parameters.json":
+			err = json.Unmarshal(content, &payload.Parameters)
+		case name == "tests.json":
+			err = json.Unmarshal(content, &payload.Tests)
+		default:
+			return nil, nil, fmt.Errorf("%w: unknown file %s", ErrInvalidImport, name)
+		}
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %s: %v", ErrInvalidImport, name, err)
+		}
+	}
+
+	return payload, problems, nil
+}
@@ -0,0 +1,42 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/bancodobrasil/featws-api/dtos"
+	payloads "github.com/bancodobrasil/featws-api/payloads/v1"
+	"github.com/bancodobrasil/featws-api/services"
+	"github.com/stretchr/testify/assert"
+)
+
+// This is a test function that checks if a bundle of files is read into a payload, with the string rules of
+// a .featws file converted into structured rules.
+func TestReadImportFiles(t *testing.T) {
+	payload, problems, err := services.ReadImportFiles(map[string][]byte{
+		"legacy.featws":   []byte("a = $total > 1\nb = $total >\n\n[limits]\nmax = 100\n"),
+		"parameters.json": []byte(`[{"name":"total","type":"decimal"}]`),
+		"tests.json":      []byte(`[{"name":"case","expected":{"a":true}}]`),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &dtos.Rule{Dynamic: "$total > 1"}, (*payload.Rules)["a"])
+	assert.Equal(t, map[string]interface{}{"max": &dtos.Rule{Value: int64(100)}}, (*payload.Rules)["limits"])
+	assert.Equal(t, []payloads.Parameter{{Name: "total", Type: "decimal"}}, *payload.Parameters)
+	assert.Equal(t, "case", (*payload.Tests)[0].Name)
+	if assert.Len(t, problems, 1) {
+		assert.Equal(t, "rules.b", problems[0].Path)
+	}
+}
+
+// This is a test function that checks if the uploads that can't be read are rejected.
+func TestReadImportFilesWithInvalidFiles(t *testing.T) {
+	for name, files := range map[string]map[string][]byte{
+		"none":    {},
+		"unknown": {"rules.yaml": []byte("a: 1")},
+		"twice":   {"rules.featws": []byte("a = 1"), "rules.json": []byte(`{}`)},
+		"broken":  {"features.json": []byte(`{`)},
+		"untyped": {"features.json": []byte(`[{"name":1}]`)},
+	} {
+		_, _, err := services.ReadImportFiles(files)
+		assert.ErrorIs(t, err, services.ErrInvalidImport, name)
+	}
+}
@@ -0,0 +1,360 @@
+package services
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bancodobrasil/featws-api/config"
+	"github.com/bancodobrasil/featws-api/dtos"
+	log "github.com/sirupsen/logrus"
+)
+
+// localHistoryFile is the file, on the directory of a rulesheet, that records the versions published for it.
+const localHistoryFile = "history.json"
+
+// localVersionsDir is the directory, on the directory of a rulesheet, that keeps a snapshot of each version.
+const localVersionsDir = "versions"
+
+// localCommit represents a version recorded on the history file of the local backend.
+//
+// Property:
+//   - Number: the version number.
+//   - Commit: a SHA-1 that identifies the version, like the SHA of a git commit.
+//   - Date: the moment the version was saved.
+//   - Message: the commit message used when the version was saved.
+type localCommit struct {
+	Number  string     `json:"number"`
+	Commit  string     `json:"commit"`
+	Date    *time.Time `json:"date"`
+	Message string     `json:"message"`
+}
+
+// localService stores the content of the rulesheets on the local filesystem. Each rulesheet has a directory,
+// named by its slug under the configured path, with the same files committed to GitLab: VERSION,
+// features.json, parameters.json, rules.json and tests.json. A snapshot of each version is kept under the `versions`
+// directory and the versions are recorded on the `history.json` file.
+//
+// Property:
+//   - cfg: The configuration, where the `LocalPath` property defines the root directory of the rulesheets.
+//   - mutex: Serializes the writes, so concurrent saves don't publish the same version twice.
+type localService struct {
+	cfg   *config.Config
+	mutex sync.Mutex
+}
+
+// NewLocal creates a new instance of the local filesystem backend using the provided configuration.
+func NewLocal(cfg *config.Config) Backend {
+	return &localService{
+		cfg: cfg,
+	}
+}
+
+// Save stores the content of the rulesheet as a new version: the files are written on the directory of the
+// rulesheet and on a snapshot of the version, and the version is recorded on the history.
+func (ls *localService) Save(rulesheet *dtos.Rulesheet, commitMessage string) error {
+	ls.mutex.Lock()
+	defer ls.mutex.Unlock()
+
+	dir := ls.dir(rulesheet)
+
+	history, err := ls.loadHistory(dir)
+	if err != nil {
+		return err
+	}
+
+	version := 0
+	if len(history) > 0 {
+		version, err = strconv.Atoi(history[len(history)-1].Number)
+		if err != nil {
+			log.Errorf("Failed to parse version: %v", err)
+			return err
+		}
+	}
+
+	err = checkBaseVersion(rulesheet, fmt.Sprintf("%d", version))
+	if err != nil {
+		return err
+	}
+	rulesheet.Version = fmt.Sprintf("%d", version+1)
+
+	features, parameters, rules, err := marshalRulesheetFiles(rulesheet)
+	if err != nil {
+		return err
+	}
+
+	files := map[string][]byte{
+		"VERSION":         []byte(rulesheet.Version + "\n"),
+		"features.json":   features,
+		"parameters.json": parameters,
+		"rules.json":      rules,
+	}
+
+	tests, err := marshalRulesheetTests(rulesheet)
+	if err != nil {
+		return err
+	}
+	if tests == nil {
+		// the test cases that weren't given are kept, also on the snapshot of the version
+		tests, err = os.ReadFile(filepath.Join(dir, "tests.json"))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Errorf("Failed to read tests: %v", err)
+			return err
+		}
+	}
+	if tests != nil {
+		files["tests.json"] = tests
+	}
+
+	now := time.Now()
+	hash := sha1.New()
+	fmt.Fprintf(hash, "%s\n%s\n%s\n", rulesheet.Version, now.Format(time.RFC3339Nano), commitMessage)
+	for _, name := range []string{"features.json", "parameters.json", "rules.json"} {
+		hash.Write(files[name])
+	}
+
+	for _, target := range []string{filepath.Join(dir, localVersionsDir, rulesheet.Version), dir} {
+		err = os.MkdirAll(target, 0755)
+		if err != nil {
+			log.Errorf("Failed to create directory: %v", err)
+			return err
+		}
+
+		for name, content := range files {
+			err = localWriteFile(filepath.Join(target, name), content)
+			if err != nil {
+				log.Errorf("Failed to write %s: %v", name, err)
+				return err
+			}
+		}
+	}
+
+	// the legacy rules are replaced by rules.json only when they're converted
+	if rulesheet.RemoveLegacyRules {
+		err = os.Remove(filepath.Join(dir, "rules.featws"))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Errorf("Failed to remove rules.featws: %v", err)
+			return err
+		}
+	}
+
+	history = append(history, &localCommit{
+		Number:  rulesheet.Version,
+		Commit:  hex.EncodeToString(hash.Sum(nil)),
+		Date:    &now,
+		Message: commitMessage,
+	})
+
+	content, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		log.Errorf("Failed to marshal history: %v", err)
+		return err
+	}
+
+	err = localWriteFile(filepath.Join(dir, localHistoryFile), content)
+	if err != nil {
+		log.Errorf("Failed to write history: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Fill fills the rulesheet with its current content. A rulesheet that was never saved is left empty.
+func (ls *localService) Fill(rulesheet *dtos.Rulesheet) error {
+	return ls.FillRef(rulesheet, "")
+}
+
+// FillRef fills the rulesheet with the content of the version identified by the ref, as returned by
+// `ResolveVersion`. An empty ref reads the current content.
+func (ls *localService) FillRef(rulesheet *dtos.Rulesheet, ref string) error {
+	dir := ls.dir(rulesheet)
+
+	if ref != "" {
+		commit, err := ls.findCommit(dir, ref)
+		if err != nil {
+			return err
+		}
+		dir = filepath.Join(dir, localVersionsDir, commit.Number)
+	}
+
+	bVersion, err := os.ReadFile(filepath.Join(dir, "VERSION"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && ref == "" {
+			return nil
+		}
+		log.Errorf("Failed to read version: %v", err)
+		return err
+	}
+
+	rulesheet.Version = strings.Replace(string(bVersion), "\n", "", -1)
+
+	err = localReadJSON(filepath.Join(dir, "features.json"), &rulesheet.Features)
+	if err != nil {
+		log.Errorf("Failed to read features: %v", err)
+		return err
+	}
+
+	err = localReadJSON(filepath.Join(dir, "parameters.json"), &rulesheet.Parameters)
+	if err != nil {
+		log.Errorf("Failed to read parameters: %v", err)
+		return err
+	}
+
+	err = localReadJSON(filepath.Join(dir, "tests.json"), &rulesheet.Tests)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Errorf("Failed to read tests: %v", err)
+		return err
+	}
+
+	bRulesJSON, err := os.ReadFile(filepath.Join(dir, "rules.json"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Errorf("Failed to read rules: %v", err)
+		return err
+	}
+
+	if len(bRulesJSON) > 0 {
+		err = json.Unmarshal(bRulesJSON, &rulesheet.Rules)
+		if err != nil {
+			log.Errorf("Failed to read rules: %v", err)
+			return err
+		}
+		return nil
+	}
+
+	bRules, err := os.ReadFile(filepath.Join(dir, "rules.featws"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		log.Errorf("Failed to read rules.featws: %v", err)
+		return err
+	}
+
+	rulesheet.Rules, err = loadFeatwsRules(bRules)
+	if err != nil {
+		return err
+	}
+
+	rulesheet.HasStringRule = len(bRules) > 0
+	return nil
+}
+
+// ResolveVersion resolves a version number, or the ID of a version (or a prefix of it, like a short git SHA),
+// to the ID of the version. It returns `ErrVersionNotFound` if the version doesn't exist.
+func (ls *localService) ResolveVersion(rulesheet *dtos.Rulesheet, version string) (string, error) {
+	commit, err := ls.findCommit(ls.dir(rulesheet), version)
+	if err != nil {
+		return "", err
+	}
+	return commit.Commit, nil
+}
+
+// History lists the versions saved for a rulesheet, from the newest to the oldest.
+func (ls *localService) History(rulesheet *dtos.Rulesheet, options *FindOptions) (result []*dtos.Version, err error) {
+	result = make([]*dtos.Version, 0)
+
+	history, err := ls.loadHistory(ls.dir(rulesheet))
+	if err != nil {
+		return
+	}
+
+	for i := len(history) - 1; i >= 0; i-- {
+		result = append(result, &dtos.Version{
+			Number:  history[i].Number,
+			Commit:  history[i].Commit,
+			Date:    history[i].Date,
+			Message: history[i].Message,
+		})
+	}
+
+	if options != nil && options.Limit > 0 {
+		page := options.Page
+		if page < 1 {
+			page = 1
+		}
+
+		start := (page - 1) * options.Limit
+		if start > len(result) {
+			start = len(result)
+		}
+		end := start + options.Limit
+		if end > len(result) {
+			end = len(result)
+		}
+		result = result[start:end]
+	}
+
+	return
+}
+
+// dir returns the directory of a rulesheet.
+func (ls *localService) dir(rulesheet *dtos.Rulesheet) string {
+	return filepath.Join(ls.cfg.LocalPath, filepath.Base(rulesheet.Slug))
+}
+
+// loadHistory reads the versions recorded for the rulesheet on the given directory, from the oldest to the newest.
+func (ls *localService) loadHistory(dir string) ([]*localCommit, error) {
+	history := make([]*localCommit, 0)
+
+	err := localReadJSON(filepath.Join(dir, localHistoryFile), &history)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Errorf("Failed to read history: %v", err)
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// findCommit finds the version recorded for the rulesheet on the given directory by its number or its ID.
+func (ls *localService) findCommit(dir string, version string) (*localCommit, error) {
+	history, err := ls.loadHistory(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, commit := range history {
+		if commit.Number == version || commit.Commit == version {
+			return commit, nil
+		}
+	}
+
+	if len(version) >= 7 {
+		for _, commit := range history {
+			if strings.HasPrefix(commit.Commit, version) {
+				return commit, nil
+			}
+		}
+	}
+
+	return nil, ErrVersionNotFound
+}
+
+// localReadJSON reads a JSON file into the result.
+func localReadJSON(fileName string, result interface{}) error {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, result)
+}
+
+// localWriteFile writes a file through a temporary file, so a failure never leaves it half written.
+func localWriteFile(fileName string, content []byte) error {
+	tmp := fileName + ".tmp"
+
+	err := os.WriteFile(tmp, content, 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, fileName)
+}
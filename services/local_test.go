@@ -0,0 +1,203 @@
+package services_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bancodobrasil/featws-api/config"
+	"github.com/bancodobrasil/featws-api/dtos"
+	"github.com/bancodobrasil/featws-api/services"
+	"github.com/stretchr/testify/assert"
+)
+
+// This is a test function that checks if the local backend stores each version of a rulesheet and reads
+// it back, both the current content and the content of a previous version.
+func TestLocalSaveAndFill(t *testing.T) {
+	cfg := &config.Config{
+		LocalPath: t.TempDir(),
+	}
+	backend := services.NewLocal(cfg)
+
+	dto := SetupRulesheet()
+	features := []dtos.Feature{{Name: "b"}, {Name: "a"}}
+	dto.Features = &features
+	rules := map[string]interface{}{"a": &dtos.Rule{Condition: "$x > 1", Value: true}}
+	dto.Rules = &rules
+
+	err := backend.Save(dto, "first")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", dto.Version)
+
+	rules = map[string]interface{}{"a": false}
+	dto.Rules = &rules
+	err = backend.Save(dto, "second")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", dto.Version)
+
+	current := SetupRulesheet()
+	err = backend.Fill(current)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", current.Version)
+	assert.Equal(t, []dtos.Feature{{Name: "a"}, {Name: "b"}}, *current.Features)
+	assert.Equal(t, map[string]interface{}{"a": false}, *current.Rules)
+
+	history, err := backend.History(SetupRulesheet(), nil)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, "2", history[0].Number)
+	assert.Equal(t, "second", history[0].Message)
+	assert.Equal(t, "1", history[1].Number)
+
+	page, err := backend.History(SetupRulesheet(), &services.FindOptions{Limit: 1, Page: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []*dtos.Version{history[1]}, page)
+
+	ref, err := backend.ResolveVersion(SetupRulesheet(), "1")
+	assert.NoError(t, err)
+	assert.Equal(t, history[1].Commit, ref)
+
+	ref, err = backend.ResolveVersion(SetupRulesheet(), history[1].Commit[:8])
+	assert.NoError(t, err)
+	assert.Equal(t, history[1].Commit, ref)
+
+	_, err = backend.ResolveVersion(SetupRulesheet(), "3")
+	assert.ErrorIs(t, err, services.ErrVersionNotFound)
+
+	previous := SetupRulesheet()
+	err = backend.FillRef(previous, ref)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", previous.Version)
+	assert.Equal(t, map[string]interface{}{"a": map[string]interface{}{"condition": "$x > 1", "value": true}}, *previous.Rules)
+}
+
+// This is a test function that checks if the local backend keeps the committed test cases of a rulesheet
+// when a later version is saved without them.
+func TestLocalSaveKeepsTests(t *testing.T) {
+	cfg := &config.Config{
+		LocalPath: t.TempDir(),
+	}
+	backend := services.NewLocal(cfg)
+
+	dto := SetupRulesheet()
+	tests := []dtos.TestCase{{Name: "adult", Parameters: map[string]interface{}{"age": 30.0}, Expected: map[string]interface{}{"a": true}}}
+	dto.Tests = &tests
+
+	err := backend.Save(dto, "first")
+	assert.NoError(t, err)
+
+	dto.Tests = nil
+	err = backend.Save(dto, "second")
+	assert.NoError(t, err)
+
+	for _, version := range []string{"1", "2"} {
+		ref, err := backend.ResolveVersion(SetupRulesheet(), version)
+		assert.NoError(t, err)
+
+		filled := SetupRulesheet()
+		err = backend.FillRef(filled, ref)
+		assert.NoError(t, err)
+		if assert.NotNil(t, filled.Tests, version) {
+			assert.Equal(t, tests, *filled.Tests, version)
+		}
+	}
+}
+
+// This is a test function that checks if the local backend refuses to save a rulesheet over a version other
+// than the one its changes were based on.
+func TestLocalSaveVersionConflict(t *testing.T) {
+	cfg := &config.Config{
+		LocalPath: t.TempDir(),
+	}
+	backend := services.NewLocal(cfg)
+
+	err := backend.Save(SetupRulesheet(), "first")
+	assert.NoError(t, err)
+
+	dto := SetupRulesheet()
+	dto.BaseVersion = "1"
+	err = backend.Save(dto, "second")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", dto.Version)
+
+	dto = SetupRulesheet()
+	dto.BaseVersion = "1"
+	err = backend.Save(dto, "third")
+	assert.ErrorIs(t, err, services.ErrVersionConflict)
+
+	history, err := backend.History(SetupRulesheet(), nil)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+}
+
+// This is a test function that checks if a rulesheet that was never saved is left empty by the local backend.
+func TestLocalFillNotSaved(t *testing.T) {
+	cfg := &config.Config{
+		LocalPath: t.TempDir(),
+	}
+	backend := services.NewLocal(cfg)
+
+	dto := SetupRulesheet()
+	err := backend.Fill(dto)
+	assert.NoError(t, err)
+	assert.Equal(t, "", dto.Version)
+	assert.Nil(t, dto.Rules)
+
+	history, err := backend.History(dto, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+// This is a test function that checks if the local backend reads the legacy rules.featws file when there's
+// no rules.json.
+func TestLocalFillFeatws(t *testing.T) {
+	cfg := &config.Config{
+		LocalPath: t.TempDir(),
+	}
+	backend := services.NewLocal(cfg)
+
+	dir := filepath.Join(cfg.LocalPath, "test")
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "VERSION"), []byte("1\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "features.json"), []byte("[]"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "parameters.json"), []byte("[]"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "rules.featws"), []byte("a = $x > 1\n\n[b]\nc = true\n"), 0644))
+
+	dto := SetupRulesheet()
+	err := backend.Fill(dto)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", dto.Version)
+	assert.Equal(t, map[string]interface{}{"a": "$x > 1", "b": map[string]interface{}{"c": "true"}}, *dto.Rules)
+	assert.True(t, dto.HasStringRule)
+}
+
+// This is a test function that checks if the storage backend is selected by the configuration.
+func TestNewBackend(t *testing.T) {
+	backend, err := services.NewBackend(&config.Config{Storage: services.StorageGitlab})
+	assert.NoError(t, err)
+	_, ok := backend.(services.Gitlab)
+	assert.True(t, ok)
+
+	backend, err = services.NewBackend(&config.Config{Storage: services.StorageLocal})
+	assert.NoError(t, err)
+	_, ok = backend.(services.ChangeRequests)
+	assert.False(t, ok)
+
+	_, err = services.NewBackend(&config.Config{Storage: services.StorageGithub})
+	assert.Error(t, err)
+
+	backend, err = services.NewBackend(&config.Config{Storage: services.StorageGithub, GithubToken: "token"})
+	assert.NoError(t, err)
+	_, ok = backend.(services.ChangeRequests)
+	assert.False(t, ok)
+
+	_, err = services.NewBackend(&config.Config{Storage: services.StorageGitea, GiteaToken: "token"})
+	assert.Error(t, err)
+
+	backend, err = services.NewBackend(&config.Config{Storage: services.StorageGitea, GiteaToken: "token", GiteaURL: "http://gitea"})
+	assert.NoError(t, err)
+	assert.NotNil(t, backend)
+
+	_, err = services.NewBackend(&config.Config{Storage: "unknown"})
+	assert.Error(t, err)
+}
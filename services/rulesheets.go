@@ -2,67 +2,298 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/bancodobrasil/featws-api/dtos"
 	"github.com/bancodobrasil/featws-api/models"
+	payloads "github.com/bancodobrasil/featws-api/payloads/v1"
 	"github.com/bancodobrasil/featws-api/repository"
 	"github.com/gosimple/slug"
 	log "github.com/sirupsen/logrus"
 )
 
-// FindOptions type defines options for limiting and paging search results.
+// FindOptions type defines options for sorting, limiting and paging search results.
 //
 // Property
 //   - Limit: property is an integer that specifies the maximum number of results to be returned by a search or query. It is often used in conjunction with the `Page` property to implement pagination. For example, if `Limit` is set to 10 and there are 50 results, the
 //   - Page: property is an integer that represents the current page number in a paginated result set. It is often used in combination with the `Limit` property to determine which subset of data to return. For example, if `Limit` is set to 10 and `Page` is set
+//   - Sort: the fields the results are ordered by, parsed by `ParseSort`. The results are ordered by the ID after them.
+//   - Cursor: the cursor of the last result of the previous page, built by `NextCursor`, to find the results after it with the keyset pagination. The `Page` is ignored when it's set.
 type FindOptions struct {
-	Limit int
-	Page  int
+	Limit  int
+	Page   int
+	Sort   []SortField
+	Cursor string
 }
 
+// SortField is a field the rulesheets are ordered by.
+//
+// Property:
+//   - Field: the name of the field, one of `id`, `name`, `slug`, `createdAt` and `updatedAt`.
+//   - Desc: whether the rulesheets are ordered from the greatest value to the lowest one.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// rulesheetSortColumns are the columns of the fields the rulesheets can be ordered by.
+var rulesheetSortColumns = map[string]string{
+	"id":        "id",
+	"name":      "name",
+	"slug":      "slug",
+	"createdAt": "created_at",
+	"updatedAt": "updated_at",
+}
+
+// ErrInvalidSort is returned when the rulesheets are ordered by an unknown field.
+var ErrInvalidSort = errors.New("the sort is invalid")
+
+// ErrInvalidCursor is returned when the cursor of a page of rulesheets can't be read, like when it was built
+// for another sort.
+var ErrInvalidCursor = errors.New("the cursor is invalid")
+
+// ParseSort reads the fields the rulesheets are ordered by from a list separated by commas, like
+// `name,-updatedAt`, where the fields prefixed by `-` are ordered from the greatest value to the lowest
+// one. It returns `ErrInvalidSort` if a field is unknown or repeated.
+func ParseSort(value string) ([]SortField, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	result := make([]SortField, 0)
+
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		field := SortField{Field: strings.TrimPrefix(item, "-"), Desc: strings.HasPrefix(item, "-")}
+
+		if _, ok := rulesheetSortColumns[field.Field]; !ok {
+			return nil, fmt.Errorf("%w: unknown field '%s'", ErrInvalidSort, field.Field)
+		}
+		if seen[field.Field] {
+			return nil, fmt.Errorf("%w: the field '%s' is repeated", ErrInvalidSort, field.Field)
+		}
+		seen[field.Field] = true
+
+		result = append(result, field)
+	}
+
+	return result, nil
+}
+
+// NextCursor builds the cursor of the page after the given rulesheet, with the values of the fields the
+// rulesheets are ordered by, for the keyset pagination.
+func NextCursor(rulesheet *dtos.Rulesheet, sort []SortField) string {
+	values := make([]interface{}, 0, len(sort)+1)
+	for _, field := range sortWithID(sort) {
+		switch field.Field {
+		case "id":
+			values = append(values, rulesheet.ID)
+		case "name":
+			values = append(values, rulesheet.Name)
+		case "slug":
+			values = append(values, rulesheet.Slug)
+		case "createdAt":
+			values = append(values, rulesheet.CreatedAt)
+		case "updatedAt":
+			values = append(values, rulesheet.UpdatedAt)
+		}
+	}
+
+	data, _ := json.Marshal(values)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// The function reads the values of a cursor built by `NextCursor` for the given sort.
+func decodeCursor(cursor string, sort []SortField) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil || len(raw) != len(sort) {
+		return nil, ErrInvalidCursor
+	}
+
+	values := make([]interface{}, len(sort))
+	for index, field := range sort {
+		var err error
+		switch field.Field {
+		case "id":
+			var id uint
+			err = json.Unmarshal(raw[index], &id)
+			values[index] = id
+		case "createdAt", "updatedAt":
+			var moment time.Time
+			err = json.Unmarshal(raw[index], &moment)
+			values[index] = moment
+		default:
+			var text string
+			err = json.Unmarshal(raw[index], &text)
+			values[index] = text
+		}
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	return values, nil
+}
+
+// The function returns the fields the rulesheets are ordered by, with the ID after them, if it isn't one of
+// them, so the order is stable.
+func sortWithID(sort []SortField) []SortField {
+	result := make([]SortField, 0, len(sort)+1)
+	for _, field := range sort {
+		result = append(result, field)
+		if field.Field == "id" {
+			return result
+		}
+	}
+	return append(result, SortField{Field: "id"})
+}
+
+// The states of the deleted rulesheets matched by a RulesheetFilter.
+const (
+	DeletedExclude = repository.DeletedExclude
+	DeletedOnly    = repository.DeletedOnly
+	DeletedInclude = repository.DeletedInclude
+)
+
+// RulesheetFilter type defines the criteria of the rulesheets found or counted. The empty fields aren't
+// checked, so an empty filter matches every rulesheet that isn't deleted.
+//
+// Property:
+//   - Name: the prefix of the name of the rulesheets, ignoring the case.
+//   - Slug: the prefix of the slug of the rulesheets, ignoring the case.
+//   - Description: a text contained in the description of the rulesheets, ignoring the case.
+//   - Search: a text contained in the name, slug or description of the rulesheets, ignoring the case.
+//   - CreatedFrom, CreatedTo: the inclusive range of the creation date of the rulesheets.
+//   - UpdatedFrom, UpdatedTo: the inclusive range of the last update date of the rulesheets.
+//   - Deleted: `DeletedExclude`, which is the default, to ignore the deleted rulesheets, `DeletedOnly` to find only them or `DeletedInclude` to find them along the others.
+type RulesheetFilter struct {
+	Name        string
+	Slug        string
+	Description string
+	Search      string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+	Deleted     string
+}
+
+// ErrStringRulesNotSupported is returned when evaluating, or testing, a rulesheet that only has the string rules of the
+// legacy rules.featws, which are compiled by the ruller but aren't understood by the evaluator.
+var ErrStringRulesNotSupported = errors.New("the string rules of the rulesheet can't be evaluated")
+
+// FormatFeatws is the legacy rules.featws format, the INI-like format parsed by go-featws.
+const FormatFeatws = "featws"
+
+// The formats of the dependency graph of a rulesheet: the JSON nodes and edges, the Graphviz DOT language or
+// a Mermaid flowchart.
+const (
+	FormatJSON    = "json"
+	FormatDOT     = "dot"
+	FormatMermaid = "mermaid"
+)
+
+// ErrFormatNotSupported is returned when exporting a rulesheet to a format other than the ones supported.
+var ErrFormatNotSupported = errors.New("the format isn't supported")
+
+// ErrNotExportable is returned when the rules of a rulesheet can't be written on the format it's exported to,
+// like the structured rules on the legacy rules.featws format.
+var ErrNotExportable = errors.New("the rules of the rulesheet can't be exported")
+
+// ErrNotLegacy is returned when converting a rulesheet that has no string rules, so it's already structured.
+var ErrNotLegacy = errors.New("the rulesheet has no string rules to convert")
+
+// ErrLegacyRules is returned when a rulesheet is changed with the string rules of the legacy rules.featws,
+// which can't be saved on rules.json. The rulesheet must be converted by `ConvertLegacy` before it's changed.
+var ErrLegacyRules = errors.New("the string rules of rules.featws can't be changed, the rulesheet must be converted to structured rules first")
+
 // Rulesheets defines an interface for CRUD operations on rulesheets.
 // Property:
 //   - Create: Create is a method that creates a new rulesheet in the database. It takes a context and a pointer to a dtos.Rulesheet object as input and returns an error if the operation fails.
 //   - Find: method is used to retrieve a list of Rulesheets based on a filter and options. The filter parameter is used to specify the criteria for selecting Rulesheets, while the options parameter is used to specify additional options such as sorting and pagination. The method returns a slice of Rulesheet DTOs and
 //   - Count: method is used to count the number of documents that match a given filter in the database collection. It takes a context.Context object and an entity interface{} as input parameters and returns the count of documents as an int64 and an error object. The entity parameter is used to specify the type of
 //   - Get: method is used to retrieve a single Rulesheet entity by its unique identifier (id). It takes in a context.Context object and the id of the Rulesheet to be retrieved as parameters, and returns a pointer to the dtos.Rulesheet object and an error object. If the Rulesheet
-//   - Update: is a method defined in the Rulesheets interface that takes a context.Context and a dtos.Rulesheet entity as input parameters and returns a pointer to a dtos.Rulesheet and an error. This method is used to update an existing rulesheet entity in the data store.
+//   - GetBySlug: method is used to retrieve a single Rulesheet like `Get`, by its slug instead of its id. It returns nil if there's no rulesheet with the slug.
+//   - Update: is a method defined in the Rulesheets interface that takes a context.Context and a dtos.Rulesheet entity as input parameters and returns a pointer to a dtos.Rulesheet and an error. This method is used to update an existing rulesheet entity in the data store. It returns `ErrLegacyRules` if the rulesheet has the string rules of the legacy rules.featws.
 //   - Delete: method is used to delete a rulesheet from the database. It takes a context.Context and a string id as input parameters and returns a boolean value and an error. The boolean value indicates whether the deletion was successful or not. The error value indicates any error that occurred during the deletion process.
+//   - History: method is used to list the versions published for a rulesheet. It takes a context.Context, the id of the rulesheet and the pagination options, and returns the versions from the newest to the oldest.
+//   - GetVersion: method is used to retrieve a rulesheet with the features, parameters and rules as they were at a given version number or commit SHA. It returns `ErrVersionNotFound` if the version doesn't exist.
+//   - Diff: method is used to compare two versions of a rulesheet. It returns the features, parameters and rules that were added, removed or changed between the `from` and `to` versions.
+//   - Evaluate: method is used to compute the features of a rulesheet for a set of parameters, without publishing it, against the current content or the given version. It returns `ErrStringRulesNotSupported` if the rulesheet only has the string rules of the legacy rules.featws.
+//   - RunTests: method is used to run the test cases committed on the tests.json file of a rulesheet, against the current content or the given version, comparing the computed features with the expected ones. It returns `ErrStringRulesNotSupported` like `Evaluate`.
+//   - Export: method is used to write the rules of a rulesheet, at its current content or at the given version, on another format. The only format supported is `featws`, the legacy rules.featws format, which can hold the string rules and the structured rules with only a plain value, a string, number or boolean. It returns `ErrFormatNotSupported` for the other formats and `ErrNotExportable` if the rules can't be written.
+//   - Graph: method is used to build the dependency graph between the rules, features and parameters of a rulesheet, at its current content or at the given version. The string rules of the legacy rules.featws are converted like `ConvertLegacy` does, without saving them.
+//   - ConvertLegacy: method is used to convert the string rules of a legacy rulesheet, loaded from its rules.featws file, into structured rules. It previews the result and, when `commit` is set and no problem is found, saves it on rules.json and removes the rules.featws file. It returns `ErrNotLegacy` if the rulesheet has no string rules.
+//   - ConvertAllLegacy: method is used to convert every legacy rulesheet like `ConvertLegacy`, skipping the ones that are already structured.
+//   - ListChanges: method is used to list the pending changes of a rulesheet, committed to draft branches on review mode and waiting on a merge request. The change methods return `ErrNotSupported` if the storage backend doesn't support the review mode.
+//   - ApproveChange: method is used to approve a pending change of a rulesheet. It returns `ErrChangeRequestNotFound` if the change isn't pending.
+//   - PublishChange: method is used to publish (merge) an approved pending change of a rulesheet, returning the rulesheet with the published content.
+//   - DiscardChange: method is used to discard a pending change of a rulesheet, closing its merge request.
 type Rulesheets interface {
 	Create(context.Context, *dtos.Rulesheet) error
-	Find(ctx context.Context, filter interface{}, options *FindOptions) ([]*dtos.Rulesheet, error)
-	Count(ctx context.Context, entity interface{}) (count int64, err error)
+	Find(ctx context.Context, filter *RulesheetFilter, options *FindOptions) ([]*dtos.Rulesheet, error)
+	Count(ctx context.Context, filter *RulesheetFilter) (count int64, err error)
 	Get(ctx context.Context, id string) (*dtos.Rulesheet, error)
+	GetBySlug(ctx context.Context, slug string) (*dtos.Rulesheet, error)
 	Update(ctx context.Context, entity dtos.Rulesheet) (*dtos.Rulesheet, error)
 	Delete(ctx context.Context, id string) (bool, error)
+	History(ctx context.Context, id string, options *FindOptions) ([]*dtos.Version, error)
+	GetVersion(ctx context.Context, id string, version string) (*dtos.Rulesheet, error)
+	Diff(ctx context.Context, id string, from string, to string) (*dtos.Diff, error)
+	Evaluate(ctx context.Context, id string, version string, params map[string]interface{}) (*dtos.Evaluation, error)
+	RunTests(ctx context.Context, id string, version string) (*dtos.TestRun, error)
+	Export(ctx context.Context, id string, version string, format string) ([]byte, error)
+	Graph(ctx context.Context, id string, version string) (*dtos.Graph, error)
+	ConvertLegacy(ctx context.Context, id string, commit bool) (*dtos.Conversion, error)
+	ConvertAllLegacy(ctx context.Context, commit bool) ([]*dtos.Conversion, error)
+	ListChanges(ctx context.Context, id string) ([]*dtos.ChangeRequest, error)
+	ApproveChange(ctx context.Context, id string, change int) (*dtos.ChangeRequest, error)
+	PublishChange(ctx context.Context, id string, change int) (*dtos.Rulesheet, error)
+	DiscardChange(ctx context.Context, id string, change int) error
 }
 
-// rulesheets contains a Gitlab service and a repository for rulesheets.
+// rulesheets contains a storage backend and a repository for rulesheets.
 //
 // Property:
-//   - gitlabService: It seems that `gitlabService` is a variable of type `Gitlab`, which could be a struct or an interface. It is likely used to interact with GitLab API or services related to GitLab. However, without more context or code, it's difficult to determine its exact purpose.
+//   - backend: is the storage `Backend` of the content of the rulesheets, i.e. the VERSION, features, parameters and rules, like GitLab or the local filesystem. The review mode operations are available only when it also implements `ChangeRequests`.
 //   - repository: property is of type `repository.Rulesheets`. It is likely a reference to a repository object that contains information about rulesheets, such as their names, contents, and metadata. This object may be used to perform various operations on the rulesheets, such as retrieving, updating,
+//   - syncs: is the outbox where the changes of the rulesheets are written, within the same transaction of the repository, to be pushed to the storage backend.
 type rulesheets struct {
-	gitlabService Gitlab
-	repository    repository.Rulesheets
+	backend    Backend
+	repository repository.Rulesheets
+	syncs      Syncs
 }
 
-// NewRulesheets creates a new instance of a rulesheets struct with a given repository and Gitlab service.
-func NewRulesheets(repository repository.Rulesheets, gitlabService Gitlab) Rulesheets {
+// NewRulesheets creates a new instance of a rulesheets struct with a given repository, storage backend and
+// outbox of changes.
+func NewRulesheets(repository repository.Rulesheets, backend Backend, syncs Syncs) Rulesheets {
 	return rulesheets{
-		repository:    repository,
-		gitlabService: gitlabService,
+		repository: repository,
+		backend:    backend,
+		syncs:      syncs,
 	}
 }
 
 // CreateRulesheet is responsible for creating a new rulesheet. It takes in a `context.Context` object
 // and a `*dtos.Rulesheet` object as parameters. It first converts the `*dtos.Rulesheet` object to a
 // `models.Rulesheet` object using the `models.NewRulesheetV1` function. It then generates a slug for
-// the rulesheet if it doesn't already have one. It creates the rulesheet in the repository using the
-// `rs.repository.Create` function and saves it to GitLab using the `rs.gitlabService.Save` function.
-// Finally, it fills the `*dtos.Rulesheet` object with GitLab information using the`rs.gitlabService.Fill`
-// function. If any errors occur during the process, it logs the error and returns it.
+// the rulesheet if it doesn't already have one. It creates the rulesheet in the repository and writes
+// its content on the outbox within the same transaction, then pushes it to the storage backend using the
+// `rs.syncs.SyncRulesheet` function. When it's synced, it fills the `*dtos.Rulesheet` object with GitLab
+// information using the `rs.backend.Fill` function. If any errors occur during the process, it logs the
+// error and returns it.
 func (rs rulesheets) Create(ctx context.Context, rulesheetDTO *dtos.Rulesheet) (err error) {
 
 	rulesheet, _ := models.NewRulesheetV1(*rulesheetDTO)
@@ -70,22 +301,37 @@ func (rs rulesheets) Create(ctx context.Context, rulesheetDTO *dtos.Rulesheet) (
 		rulesheet.Slug = slug.Make(rulesheet.Name)
 	}
 
-	fmt.Print(rulesheet.Slug)
+	db := rs.repository.GetDB()
+
+	tx := db.Begin()
 
-	err = rs.repository.Create(ctx, &rulesheet)
+	err = rs.repository.CreateInTransaction(ctx, tx, &rulesheet)
 	if err != nil {
+		tx.Rollback()
 		log.Errorf("Error on create rulesheet into repository: %v", err)
 		return
 	}
 	rulesheetDTO.ID = rulesheet.ID
 	rulesheetDTO.Slug = rulesheet.Slug
-	err = rs.gitlabService.Save(rulesheetDTO, "[FEATWS BOT] Create Repo")
+
+	err = rs.syncs.EnqueueInTransaction(ctx, tx, rulesheetDTO, "[FEATWS BOT] Create Repo")
 	if err != nil {
-		log.Errorf("Error on save rulesheet into repository: %v", err)
+		tx.Rollback()
+		return
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		log.Errorf("Error on commit the rulesheet creation: %v", err)
+		return
+	}
+
+	err = rs.sync(ctx, rulesheetDTO)
+	if err != nil || rulesheetDTO.Sync == nil || rulesheetDTO.Sync.Status != models.SyncSynced {
 		return
 	}
 
-	err = rs.gitlabService.Fill(rulesheetDTO)
+	err = rs.backend.Fill(rulesheetDTO)
 	if err != nil {
 		log.Errorf("Error on fill rulesheet with gitlab information: %v", err)
 		return
@@ -95,10 +341,11 @@ func (rs rulesheets) Create(ctx context.Context, rulesheetDTO *dtos.Rulesheet) (
 }
 
 // Find is responsible for finding rulesheets based on a filter and returning them as an array
-// of `dtos.Rulesheet` objects. It takes in a `context.Context` object, a filter interface, and a
-// `FindOptions` object as parameters. The `FindOptions` object is used to specify the limit and page
-// number for pagination.
-func (rs rulesheets) Find(ctx context.Context, filter interface{}, options *FindOptions) (result []*dtos.Rulesheet, err error) {
+// of `dtos.Rulesheet` objects. It takes in a `context.Context` object, a `RulesheetFilter`, and a
+// `FindOptions` object as parameters. A nil filter matches every rulesheet that isn't deleted. The `FindOptions` object is used to specify the limit and page
+// number for pagination, the fields the rulesheets are ordered by and the cursor of the keyset pagination.
+// It returns `ErrInvalidSort` or `ErrInvalidCursor` if they can't be read.
+func (rs rulesheets) Find(ctx context.Context, filter *RulesheetFilter, options *FindOptions) (result []*dtos.Rulesheet, err error) {
 
 	var opts *repository.FindOptions = nil
 
@@ -107,9 +354,25 @@ func (rs rulesheets) Find(ctx context.Context, filter interface{}, options *Find
 			Limit: options.Limit,
 			Page:  options.Page,
 		}
+
+		sort := sortWithID(options.Sort)
+		for _, field := range sort {
+			column, ok := rulesheetSortColumns[field.Field]
+			if !ok {
+				return nil, fmt.Errorf("%w: unknown field '%s'", ErrInvalidSort, field.Field)
+			}
+			opts.Sort = append(opts.Sort, repository.Sort{Column: column, Desc: field.Desc})
+		}
+
+		if options.Cursor != "" {
+			opts.After, err = decodeCursor(options.Cursor, sort)
+			if err != nil {
+				return
+			}
+		}
 	}
 
-	entities, err := rs.repository.Find(ctx, filter, opts)
+	entities, err := rs.repository.Find(ctx, newRulesheetCriteria(filter), opts)
 	if err != nil {
 		log.Errorf("Error on fetch the rulesheets(find): %v", err)
 		return
@@ -125,15 +388,15 @@ func (rs rulesheets) Find(ctx context.Context, filter interface{}, options *Find
 }
 
 // Count is a method of the `rulesheets` struct that implements the `Rulesheets` interface. It
-// takes a `context.Context` object and an `entity` interface{} as input parameters and returns the
-// count of documents as an `int64` and an error object. The `entity` parameter is used to specify the
-// type of entity to count in the database collection. The function calls the `Count` method of the
-// `repository` property of the `rulesheets` struct, passing in the `ctx` and `entity` parameters. If
+// takes a `context.Context` object and a `RulesheetFilter` as input parameters and returns the
+// count of documents as an `int64` and an error object. The `filter` parameter is used to specify the
+// rulesheets to count in the database collection. The function calls the `Count` method of the
+// `repository` property of the `rulesheets` struct, passing in the `ctx` and the criteria of the `filter`. If
 // an error occurs during the count operation, the function logs the error and returns it. Otherwise,
 // it returns the count of documents.
-func (rs rulesheets) Count(ctx context.Context, entity interface{}) (count int64, err error) {
+func (rs rulesheets) Count(ctx context.Context, filter *RulesheetFilter) (count int64, err error) {
 
-	count, err = rs.repository.Count(ctx, entity)
+	count, err = rs.repository.Count(ctx, newRulesheetCriteria(filter))
 	if err != nil {
 		log.Errorf("Error on count the entities(find): %v", err)
 		return
@@ -142,13 +405,52 @@ func (rs rulesheets) Count(ctx context.Context, entity interface{}) (count int64
 	return
 }
 
+// The function converts a filter of rulesheets into the criteria of the repository.
+func newRulesheetCriteria(filter *RulesheetFilter) *repository.Criteria {
+	if filter == nil {
+		return nil
+	}
+
+	criteria := &repository.Criteria{
+		Conditions: make([]repository.Condition, 0),
+		Deleted:    filter.Deleted,
+	}
+
+	if filter.Name != "" {
+		criteria.Conditions = append(criteria.Conditions, repository.Prefix("name", filter.Name))
+	}
+	if filter.Slug != "" {
+		criteria.Conditions = append(criteria.Conditions, repository.Prefix("slug", filter.Slug))
+	}
+	if filter.Description != "" {
+		criteria.Conditions = append(criteria.Conditions, repository.Contains(filter.Description, "description"))
+	}
+	if filter.Search != "" {
+		criteria.Conditions = append(criteria.Conditions, repository.Contains(filter.Search, "name", "slug", "description"))
+	}
+	if filter.CreatedFrom != nil {
+		criteria.Conditions = append(criteria.Conditions, repository.From("created_at", *filter.CreatedFrom))
+	}
+	if filter.CreatedTo != nil {
+		criteria.Conditions = append(criteria.Conditions, repository.To("created_at", *filter.CreatedTo))
+	}
+	if filter.UpdatedFrom != nil {
+		criteria.Conditions = append(criteria.Conditions, repository.From("updated_at", *filter.UpdatedFrom))
+	}
+	if filter.UpdatedTo != nil {
+		criteria.Conditions = append(criteria.Conditions, repository.To("updated_at", *filter.UpdatedTo))
+	}
+
+	return criteria
+}
+
 // Get function is a method of the `rulesheets` struct that implements the `Rulesheets`
 // interface. It takes a `context.Context` object and a `string` id as input parameters and returns a
 // pointer to a `dtos.Rulesheet` object and an error object. The function retrieves a single rulesheet
 // entity by its unique identifier (id) from the repository using the `rs.repository.Get` function. It
 // then converts the `models.Rulesheet` object to a `dtos.Rulesheet` object using the `newRulesheetDTO`
 // function. Finally, it fills the `*dtos.Rulesheet` object with GitLab information using the
-// `rs.gitlabService.Fill` function. If any errors occur during the process, it logs the error and
+// `rs.backend.Fill` function. If any errors occur during the process, it logs the error and
 // returns it.
 func (rs rulesheets) Get(ctx context.Context, id string) (result *dtos.Rulesheet, err error) {
 
@@ -158,14 +460,43 @@ func (rs rulesheets) Get(ctx context.Context, id string) (result *dtos.Rulesheet
 		return
 	}
 
+	return rs.load(ctx, entity)
+}
+
+// GetBySlug retrieves a single rulesheet by its slug, filled like `Get` does. It returns nil if there's no
+// rulesheet with the slug.
+func (rs rulesheets) GetBySlug(ctx context.Context, slug string) (*dtos.Rulesheet, error) {
+
+	entity, err := rs.repository.GetBySlug(ctx, slug)
+	if err != nil {
+		log.Errorf("Error on fetch rulesheet(get by slug): %v", err)
+		return nil, err
+	}
+
+	if entity == nil {
+		return nil, nil
+	}
+
+	return rs.load(ctx, entity)
+}
+
+// load converts a rulesheet entity to a `dtos.Rulesheet` and fills it with the content of the storage backend
+// and the status of its last sync.
+func (rs rulesheets) load(ctx context.Context, entity *models.Rulesheet) (result *dtos.Rulesheet, err error) {
+
 	result = newRulesheetDTO(entity)
 
 	if result != nil {
-		err = rs.gitlabService.Fill(result)
+		err = rs.backend.Fill(result)
 		if err != nil {
 			log.Errorf("Error on fill rulesheet with gitlab information: %v", err)
 			return
 		}
+
+		result.Sync, err = rs.syncs.Last(ctx, result.ID)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return
@@ -173,28 +504,138 @@ func (rs rulesheets) Get(ctx context.Context, id string) (result *dtos.Rulesheet
 
 // UpdateRulesheet function is a method of the `rulesheets` struct that implements the `Rulesheets`
 // interface. It takes a `context.Context` object and a `dtos.Rulesheet` object as input parameters and
-// returns a pointer to a `dtos.Rulesheet` object and an error object.
+// returns a pointer to a `dtos.Rulesheet` object and an error object. The changes are committed with the
+// `CommitMessage` of the rulesheet, if it's set.
 func (rs rulesheets) Update(ctx context.Context, rulesheetDTO dtos.Rulesheet) (result *dtos.Rulesheet, err error) {
+	commitMessage := "[FEATWS BOT] Update Repo"
+	if rulesheetDTO.CommitMessage != "" {
+		commitMessage = rulesheetDTO.CommitMessage
+	}
+	return rs.update(ctx, rulesheetDTO, commitMessage)
+}
+
+// update persists the rulesheet on the repository and writes its content on the outbox, with the given
+// commit message, within the same transaction, then pushes it to the storage backend. It's shared by every
+// operation that changes an existing rulesheet. It returns `ErrLegacyRules` if the rulesheet has string
+// rules, since they would be saved as an empty rules.json.
+func (rs rulesheets) update(ctx context.Context, rulesheetDTO dtos.Rulesheet, commitMessage string) (result *dtos.Rulesheet, err error) {
+
+	if rulesheetDTO.HasStringRule {
+		return nil, ErrLegacyRules
+	}
+
+	// the storage backend is read before the transaction is opened, so its locks aren't held meanwhile
+	err = rs.checkBackendVersion(&rulesheetDTO)
+	if err != nil {
+		return
+	}
 
 	entity, _ := models.NewRulesheetV1(rulesheetDTO)
 
-	_, err = rs.repository.Update(ctx, entity)
+	db := rs.repository.GetDB()
+
+	tx := db.Begin()
+
+	_, err = rs.repository.UpdateInTransaction(ctx, tx, entity)
 	if err != nil {
+		tx.Rollback()
 		log.Errorf("Error on update the rulesheet from repository: %v", err)
 		return
 	}
 
-	err = rs.gitlabService.Save(&rulesheetDTO, "[FEATWS BOT] Update Repo")
+	// the outbox is checked once the row is locked by the update, so a change based on an outdated
+	// version is rolled back instead of being left on the outbox
+	err = rs.checkOutbox(ctx, &rulesheetDTO)
 	if err != nil {
-		log.Errorf("Error on save the rulesheet into repository: %v", err)
+		tx.Rollback()
 		return
 	}
 
+	err = rs.syncs.EnqueueInTransaction(ctx, tx, &rulesheetDTO, commitMessage)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		log.Errorf("Error on commit the rulesheet update: %v", err)
+		return
+	}
+
+	err = rs.sync(ctx, &rulesheetDTO)
+	if err != nil {
+		return
+	}
+
+	// the version is known only after the change is published
+	rulesheetDTO.Version = ""
+	if rulesheetDTO.Sync != nil && rulesheetDTO.Sync.Status == models.SyncSynced {
+		rulesheetDTO.Version = rulesheetDTO.Sync.Version
+	}
+
 	result = &rulesheetDTO
 
 	return
 }
 
+// checkBackendVersion checks that the version the changes of the rulesheet are based on is the current one
+// on the storage backend. It returns `ErrVersionConflict` otherwise. There's nothing to check when the
+// `BaseVersion` isn't set. The backend checks it again when the change is pushed, so a change published
+// after this check still isn't overwritten.
+func (rs rulesheets) checkBackendVersion(rulesheetDTO *dtos.Rulesheet) error {
+	if rulesheetDTO.BaseVersion == "" {
+		return nil
+	}
+
+	current := &dtos.Rulesheet{ID: rulesheetDTO.ID, Slug: rulesheetDTO.Slug}
+	err := rs.backend.Fill(current)
+	if err != nil {
+		log.Errorf("Error on fill rulesheet with gitlab information: %v", err)
+		return err
+	}
+
+	return checkBaseVersion(rulesheetDTO, current.Version)
+}
+
+// checkOutbox checks that no other change of the rulesheet is waiting on the outbox, since the version the
+// changes are based on wouldn't be the current one once it's published. It returns `ErrVersionConflict`
+// otherwise. There's nothing to check when the `BaseVersion` isn't set.
+func (rs rulesheets) checkOutbox(ctx context.Context, rulesheetDTO *dtos.Rulesheet) error {
+	if rulesheetDTO.BaseVersion == "" {
+		return nil
+	}
+
+	last, err := rs.syncs.Last(ctx, rulesheetDTO.ID)
+	if err != nil {
+		return err
+	}
+	if last != nil && last.Status != models.SyncSynced && last.Status != models.SyncDiscarded {
+		return fmt.Errorf("%w: the rulesheet has changes that weren't published yet", ErrVersionConflict)
+	}
+
+	return nil
+}
+
+// sync pushes the pending changes of the rulesheet to the storage backend right away and records its sync
+// status on the DTO. A failed attempt doesn't fail the operation, since the change is kept on the outbox and
+// retried by the sync worker, unless it's a version conflict, which won't be solved by retrying.
+func (rs rulesheets) sync(ctx context.Context, rulesheetDTO *dtos.Rulesheet) error {
+
+	sync, err := rs.syncs.SyncRulesheet(ctx, rulesheetDTO.ID)
+	if sync != nil {
+		rulesheetDTO.Sync = sync
+	}
+	if err != nil {
+		if errors.Is(err, ErrVersionConflict) {
+			return err
+		}
+		log.Warnf("Error on sync the rulesheet, it will be retried: %v", err)
+	}
+
+	return nil
+}
+
 // Delete function is a method of the `rulesheets` struct that implements the `Rulesheets`
 // interface. It takes a `context.Context` object and a `string` id as input parameters and returns a
 // boolean value and an error object. The function is responsible for deleting a rulesheet from the db.
@@ -237,6 +678,462 @@ func (rs rulesheets) Delete(ctx context.Context, id string) (bool, error) {
 	return true, tx.Commit().Error
 }
 
+// History function is a method of the `rulesheets` struct that implements the `Rulesheets` interface.
+// It retrieves the rulesheet entity by its id from the repository and lists the versions published for it
+// using the `rs.backend.History` function.
+func (rs rulesheets) History(ctx context.Context, id string, options *FindOptions) (result []*dtos.Version, err error) {
+
+	entity, err := rs.repository.Get(ctx, id)
+	if err != nil {
+		log.Errorf("Error on fetch rulesheet(get): %v", err)
+		return
+	}
+
+	result, err = rs.backend.History(newRulesheetDTO(entity), options)
+	if err != nil {
+		log.Errorf("Error on fetch rulesheet history: %v", err)
+		return
+	}
+
+	return
+}
+
+// GetVersion function is a method of the `rulesheets` struct that implements the `Rulesheets` interface.
+// It retrieves the rulesheet entity by its id from the repository, resolves the version number or commit
+// SHA to a commit using the `rs.backend.ResolveVersion` function and fills the `*dtos.Rulesheet`
+// object with the content of that commit using the `rs.backend.FillRef` function.
+func (rs rulesheets) GetVersion(ctx context.Context, id string, version string) (result *dtos.Rulesheet, err error) {
+
+	entity, err := rs.repository.Get(ctx, id)
+	if err != nil {
+		log.Errorf("Error on fetch rulesheet(get): %v", err)
+		return
+	}
+
+	result = newRulesheetDTO(entity)
+
+	err = rs.fillVersion(result, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// The function resolves the version number or commit SHA to a commit and fills the rulesheet with the
+// content of that commit.
+func (rs rulesheets) fillVersion(rulesheet *dtos.Rulesheet, version string) error {
+
+	ref, err := rs.backend.ResolveVersion(rulesheet, version)
+	if err != nil {
+		log.Errorf("Error on resolve rulesheet version: %v", err)
+		return err
+	}
+
+	err = rs.backend.FillRef(rulesheet, ref)
+	if err != nil {
+		log.Errorf("Error on fill rulesheet with gitlab information: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Diff function is a method of the `rulesheets` struct that implements the `Rulesheets` interface.
+// It loads the content of the rulesheet at both versions and compares them using the
+// `dtos.DiffRulesheets` function.
+func (rs rulesheets) Diff(ctx context.Context, id string, from string, to string) (result *dtos.Diff, err error) {
+
+	entity, err := rs.repository.Get(ctx, id)
+	if err != nil {
+		log.Errorf("Error on fetch rulesheet(get): %v", err)
+		return
+	}
+
+	fromDTO := newRulesheetDTO(entity)
+	err = rs.fillVersion(fromDTO, from)
+	if err != nil {
+		return
+	}
+
+	toDTO := newRulesheetDTO(entity)
+	err = rs.fillVersion(toDTO, to)
+	if err != nil {
+		return
+	}
+
+	result, err = dtos.DiffRulesheets(fromDTO, toDTO)
+	if err != nil {
+		log.Errorf("Error on diff rulesheet versions: %v", err)
+		return
+	}
+
+	return
+}
+
+// Evaluate function is a method of the `rulesheets` struct that implements the `Rulesheets` interface.
+// It loads the current content of the rulesheet, or its content at the given version, and computes its
+// features for the given parameters using the `dtos.EvaluateRulesheet` function. It returns nil if the
+// rulesheet doesn't exist.
+func (rs rulesheets) Evaluate(ctx context.Context, id string, version string, params map[string]interface{}) (result *dtos.Evaluation, err error) {
+
+	target, err := rs.loadStructuredRules(ctx, id, version)
+	if err != nil || target == nil {
+		return
+	}
+
+	return dtos.EvaluateRulesheet(target, params), nil
+}
+
+// RunTests function is a method of the `rulesheets` struct that implements the `Rulesheets` interface.
+// It loads the current content of the rulesheet, or its content at the given version, and runs the test
+// cases committed on its tests.json file using the `dtos.RunTests` function. It returns nil if the
+// rulesheet doesn't exist.
+func (rs rulesheets) RunTests(ctx context.Context, id string, version string) (result *dtos.TestRun, err error) {
+
+	target, err := rs.loadStructuredRules(ctx, id, version)
+	if err != nil || target == nil {
+		return
+	}
+
+	result, err = dtos.RunTests(target)
+	if err != nil {
+		log.Errorf("Error on run rulesheet tests: %v", err)
+		return
+	}
+
+	return
+}
+
+// Export function is a method of the `rulesheets` struct that implements the `Rulesheets` interface.
+// It loads the current content of the rulesheet, or its content at the given version, and writes its rules
+// on the given format. It returns nil if the rulesheet doesn't exist.
+func (rs rulesheets) Export(ctx context.Context, id string, version string, format string) (result []byte, err error) {
+
+	if format != FormatFeatws {
+		return nil, ErrFormatNotSupported
+	}
+
+	target, err := rs.loadVersion(ctx, id, version)
+	if err != nil || target == nil {
+		return
+	}
+
+	rules := make(map[string]interface{})
+	if target.Rules != nil {
+		rules = *target.Rules
+	}
+
+	return encodeFeatwsRules(rules, !target.HasStringRule)
+}
+
+// Graph loads the rulesheet with the ID passed, at its current content or at the given version, and builds
+// the dependency graph of its rules with `dtos.BuildGraph`. The string rules of a legacy rulesheet are
+// converted into structured rules first, so their references are found too.
+func (rs rulesheets) Graph(ctx context.Context, id string, version string) (result *dtos.Graph, err error) {
+
+	target, err := rs.loadVersion(ctx, id, version)
+	if err != nil || target == nil {
+		return
+	}
+
+	rules := target.Rules
+	if target.HasStringRule && rules != nil {
+		converted, _ := dtos.ConvertStringRules(*rules)
+		rules = &converted
+	}
+
+	dto, err := dtos.NewRulesheetV1(payloads.Rulesheet{
+		ID:         target.ID,
+		Name:       target.Name,
+		Slug:       target.Slug,
+		Version:    target.Version,
+		Features:   newFeaturesPayload(target.Features),
+		Parameters: newParametersPayload(target.Parameters),
+		Rules:      rules,
+	})
+	if err != nil {
+		log.Errorf("Error on define rulesheet entity: %v", err)
+		return
+	}
+
+	return dtos.BuildGraph(&dto), nil
+}
+
+// The function loads the current content of the rulesheet or, when the version is given, its content at
+// that version.
+func (rs rulesheets) loadVersion(ctx context.Context, id string, version string) (*dtos.Rulesheet, error) {
+	if version == "" {
+		return rs.Get(ctx, id)
+	}
+	return rs.GetVersion(ctx, id, version)
+}
+
+// The function loads the current content of the rulesheet, or its content at the given version, and
+// rebuilds it the same way a payload received by the API is built, so its rules can be evaluated. It
+// returns `ErrStringRulesNotSupported` if the rulesheet only has string rules.
+func (rs rulesheets) loadStructuredRules(ctx context.Context, id string, version string) (*dtos.Rulesheet, error) {
+
+	target, err := rs.loadVersion(ctx, id, version)
+	if err != nil || target == nil {
+		return nil, err
+	}
+
+	dto, err := dtos.NewRulesheetV1(payloads.Rulesheet{
+		ID:          target.ID,
+		Name:        target.Name,
+		Description: target.Description,
+		Slug:        target.Slug,
+		Version:     target.Version,
+		Features:    newFeaturesPayload(target.Features),
+		Parameters:  newParametersPayload(target.Parameters),
+		Rules:       target.Rules,
+		Tests:       newTestCasesPayload(target.Tests),
+	})
+	if err != nil {
+		log.Errorf("Error on define rulesheet entity: %v", err)
+		return nil, err
+	}
+
+	if dto.HasStringRule {
+		return nil, ErrStringRulesNotSupported
+	}
+
+	return &dto, nil
+}
+
+// ConvertLegacy function is a method of the `rulesheets` struct that implements the `Rulesheets` interface.
+// It loads the current content of the rulesheet, converts its string rules using the
+// `dtos.ConvertStringRules` function and validates the result like an update. It returns nil if the
+// rulesheet doesn't exist.
+func (rs rulesheets) ConvertLegacy(ctx context.Context, id string, commit bool) (result *dtos.Conversion, err error) {
+
+	target, err := rs.Get(ctx, id)
+	if err != nil || target == nil {
+		return
+	}
+
+	if !target.HasStringRule {
+		return nil, ErrNotLegacy
+	}
+
+	return rs.convertLegacy(ctx, target, commit)
+}
+
+// ConvertAllLegacy function is a method of the `rulesheets` struct that implements the `Rulesheets`
+// interface. It loads every rulesheet and converts the ones that have string rules, returning their
+// conversions in the order they're stored.
+func (rs rulesheets) ConvertAllLegacy(ctx context.Context, commit bool) (result []*dtos.Conversion, err error) {
+
+	entities, err := rs.repository.Find(ctx, nil, nil)
+	if err != nil {
+		log.Errorf("Error on fetch the rulesheets(find): %v", err)
+		return
+	}
+
+	result = make([]*dtos.Conversion, 0)
+
+	for _, entity := range entities {
+		target := newRulesheetDTO(entity)
+
+		err = rs.backend.Fill(target)
+		if err != nil {
+			log.Errorf("Error on fill rulesheet with gitlab information: %v", err)
+			return nil, err
+		}
+
+		if !target.HasStringRule {
+			continue
+		}
+
+		conversion, err := rs.convertLegacy(ctx, target, commit)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, conversion)
+	}
+
+	return
+}
+
+// The function converts the string rules of a loaded rulesheet and, when `commit` is set and no problem
+// is found, saves the converted rules as an update.
+func (rs rulesheets) convertLegacy(ctx context.Context, target *dtos.Rulesheet, commit bool) (*dtos.Conversion, error) {
+
+	var rules map[string]interface{}
+	problems := make([]*dtos.RuleError, 0)
+	if target.Rules != nil {
+		rules, problems = dtos.ConvertStringRules(*target.Rules)
+	}
+
+	dto, err := dtos.NewRulesheetV1(payloads.Rulesheet{
+		ID:          target.ID,
+		Name:        target.Name,
+		Description: target.Description,
+		Slug:        target.Slug,
+		Features:    newFeaturesPayload(target.Features),
+		Parameters:  newParametersPayload(target.Parameters),
+		Rules:       &rules,
+		Tests:       newTestCasesPayload(target.Tests),
+	})
+	if err != nil {
+		log.Errorf("Error on define rulesheet entity: %v", err)
+		return nil, err
+	}
+
+	problems = append(problems, dtos.ValidateRules(&dto)...)
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Path < problems[j].Path
+	})
+
+	result := &dtos.Conversion{
+		Rulesheet: &dto,
+		Errors:    problems,
+	}
+
+	if !commit {
+		return result, nil
+	}
+
+	for _, problem := range problems {
+		if !problem.Warning {
+			return result, nil
+		}
+	}
+
+	// only the conversion replaces the legacy rules
+	dto.RemoveLegacyRules = true
+
+	updated, err := rs.update(ctx, dto, "[FEATWS BOT] Convert rules.featws to rules.json")
+	if err != nil {
+		return nil, err
+	}
+
+	result.Rulesheet = updated
+	result.Committed = true
+
+	return result, nil
+}
+
+// ListChanges function is a method of the `rulesheets` struct that implements the `Rulesheets` interface.
+// It retrieves the rulesheet entity by its id from the repository and lists its pending changes using the
+// `ChangeRequests.ListChangeRequests` function.
+func (rs rulesheets) ListChanges(ctx context.Context, id string) (result []*dtos.ChangeRequest, err error) {
+
+	changes, err := rs.changeRequests()
+	if err != nil {
+		return
+	}
+
+	entity, err := rs.repository.Get(ctx, id)
+	if err != nil {
+		log.Errorf("Error on fetch rulesheet(get): %v", err)
+		return
+	}
+
+	result, err = changes.ListChangeRequests(newRulesheetDTO(entity))
+	if err != nil {
+		log.Errorf("Error on list rulesheet changes: %v", err)
+		return
+	}
+
+	return
+}
+
+// ApproveChange function is a method of the `rulesheets` struct that implements the `Rulesheets` interface.
+// It retrieves the rulesheet entity by its id from the repository and approves the pending change using
+// the `ChangeRequests.ApproveChangeRequest` function.
+func (rs rulesheets) ApproveChange(ctx context.Context, id string, change int) (result *dtos.ChangeRequest, err error) {
+
+	changes, err := rs.changeRequests()
+	if err != nil {
+		return
+	}
+
+	entity, err := rs.repository.Get(ctx, id)
+	if err != nil {
+		log.Errorf("Error on fetch rulesheet(get): %v", err)
+		return
+	}
+
+	result, err = changes.ApproveChangeRequest(newRulesheetDTO(entity), change)
+	if err != nil {
+		log.Errorf("Error on approve rulesheet change: %v", err)
+		return
+	}
+
+	return
+}
+
+// PublishChange function is a method of the `rulesheets` struct that implements the `Rulesheets` interface.
+// It retrieves the rulesheet entity by its id from the repository, publishes the pending change using the
+// `ChangeRequests.PublishChangeRequest` function and fills the rulesheet with the published content.
+func (rs rulesheets) PublishChange(ctx context.Context, id string, change int) (result *dtos.Rulesheet, err error) {
+
+	changes, err := rs.changeRequests()
+	if err != nil {
+		return
+	}
+
+	entity, err := rs.repository.Get(ctx, id)
+	if err != nil {
+		log.Errorf("Error on fetch rulesheet(get): %v", err)
+		return
+	}
+
+	result = newRulesheetDTO(entity)
+
+	err = changes.PublishChangeRequest(result, change)
+	if err != nil {
+		log.Errorf("Error on publish rulesheet change: %v", err)
+		return nil, err
+	}
+
+	err = rs.backend.Fill(result)
+	if err != nil {
+		log.Errorf("Error on fill rulesheet with gitlab information: %v", err)
+		return nil, err
+	}
+
+	return
+}
+
+// DiscardChange function is a method of the `rulesheets` struct that implements the `Rulesheets` interface.
+// It retrieves the rulesheet entity by its id from the repository and discards the pending change using the
+// `ChangeRequests.DiscardChangeRequest` function.
+func (rs rulesheets) DiscardChange(ctx context.Context, id string, change int) error {
+
+	changes, err := rs.changeRequests()
+	if err != nil {
+		return err
+	}
+
+	entity, err := rs.repository.Get(ctx, id)
+	if err != nil {
+		log.Errorf("Error on fetch rulesheet(get): %v", err)
+		return err
+	}
+
+	err = changes.DiscardChangeRequest(newRulesheetDTO(entity), change)
+	if err != nil {
+		log.Errorf("Error on discard rulesheet change: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// The function returns the storage backend as `ChangeRequests`, or `ErrNotSupported` if it doesn't support
+// the review mode.
+func (rs rulesheets) changeRequests() (ChangeRequests, error) {
+	changes, ok := rs.backend.(ChangeRequests)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	return changes, nil
+}
+
 // The function creates a new DTO for a rulesheet entity
 func newRulesheetDTO(entity *models.Rulesheet) *dtos.Rulesheet {
 	return &dtos.Rulesheet{
@@ -245,5 +1142,85 @@ func newRulesheetDTO(entity *models.Rulesheet) *dtos.Rulesheet {
 		Description:   entity.Description,
 		Slug:          entity.Slug,
 		HasStringRule: entity.HasStringRule,
+		CreatedAt:     entity.CreatedAt,
+		UpdatedAt:     entity.UpdatedAt,
+	}
+}
+
+// NewRulesheetPayload copies the content of a rulesheet to a payload, so it can be changed and rebuilt by
+// `dtos.NewRulesheetV1`, like when a previous version is restored or the rulesheet is patched.
+func NewRulesheetPayload(rulesheet *dtos.Rulesheet) payloads.Rulesheet {
+	return payloads.Rulesheet{
+		ID:          rulesheet.ID,
+		Name:        rulesheet.Name,
+		Description: rulesheet.Description,
+		Slug:        rulesheet.Slug,
+		Features:    newFeaturesPayload(rulesheet.Features),
+		Parameters:  newParametersPayload(rulesheet.Parameters),
+		Rules:       rulesheet.Rules,
+		Tests:       newTestCasesPayload(rulesheet.Tests),
+	}
+}
+
+// The function copies the features of a rulesheet to a payload, so they're kept when the rulesheet is
+// rebuilt by `dtos.NewRulesheetV1`.
+func newFeaturesPayload(features *[]dtos.Feature) *[]payloads.Feature {
+	if features == nil {
+		return nil
+	}
+
+	payload := make([]payloads.Feature, len(*features))
+	for index, feature := range *features {
+		payload[index] = payloads.Feature{
+			Name:        feature.Name,
+			Type:        feature.Type,
+			Description: feature.Description,
+			Default:     feature.Default,
+			Extra:       feature.Extra,
+		}
+	}
+
+	return &payload
+}
+
+// The function copies the parameters of a rulesheet to a payload, so they're kept when the rulesheet is
+// rebuilt by `dtos.NewRulesheetV1`.
+func newParametersPayload(parameters *[]dtos.Parameter) *[]payloads.Parameter {
+	if parameters == nil {
+		return nil
 	}
+
+	payload := make([]payloads.Parameter, len(*parameters))
+	for index, parameter := range *parameters {
+		payload[index] = payloads.Parameter{
+			Name:        parameter.Name,
+			Type:        parameter.Type,
+			Description: parameter.Description,
+			Default:     parameter.Default,
+			Required:    parameter.Required,
+			Resolver:    parameter.Resolver,
+			Extra:       parameter.Extra,
+		}
+	}
+
+	return &payload
+}
+
+// The function copies the test cases of a rulesheet to a payload, so they're kept when the rulesheet is
+// rebuilt by `dtos.NewRulesheetV1`.
+func newTestCasesPayload(tests *[]dtos.TestCase) *[]payloads.TestCase {
+	if tests == nil {
+		return nil
+	}
+
+	payload := make([]payloads.TestCase, len(*tests))
+	for index, testCase := range *tests {
+		payload[index] = payloads.TestCase{
+			Name:       testCase.Name,
+			Parameters: testCase.Parameters,
+			Expected:   testCase.Expected,
+		}
+	}
+
+	return &payload
 }
@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"log"
+	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/bancodobrasil/featws-api/config"
 	"github.com/bancodobrasil/featws-api/dtos"
 	mocks_repository "github.com/bancodobrasil/featws-api/mocks/repository"
 	mocks_services "github.com/bancodobrasil/featws-api/mocks/services"
@@ -20,6 +24,30 @@ import (
 	"gorm.io/gorm"
 )
 
+// The function opens a connection to a fake db that expects a transaction, which is committed or rolled back.
+func setupTransactionDB(t *testing.T, commit bool) *gorm.DB {
+	conn, mocks, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	mocks.ExpectBegin()
+	if commit {
+		mocks.ExpectCommit()
+	} else {
+		mocks.ExpectRollback()
+	}
+
+	dialector := mysql.New(mysql.Config{
+		DriverName:                "mysql",
+		Conn:                      conn,
+		SkipInitializeWithVersion: true,
+	})
+
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	assert.NoError(t, err)
+
+	return db
+}
+
 // This's a unit test that checks if an error is returned when a Gitlab service fails to fill a rulesheet entity.
 func TestGetWithErrorOnFill(t *testing.T) {
 	ctx := context.Background()
@@ -39,7 +67,7 @@ func TestGetWithErrorOnFill(t *testing.T) {
 	gitlabService := new(mocks_services.Gitlab)
 	gitlabService.On("Fill", dto).Return(errors.New("error on fill"))
 
-	services := services.NewRulesheets(repository, gitlabService)
+	services := services.NewRulesheets(repository, gitlabService, nil)
 	_, err = services.Get(ctx, "1")
 
 	if err == nil || err.Error() != "error on fill" {
@@ -63,11 +91,52 @@ func TestGetSucess(t *testing.T) {
 	repository.On("Get", ctx, "1").Return(&entity, nil)
 	gitlabService := new(mocks_services.Gitlab)
 	gitlabService.On("Fill", dto).Return(nil)
-	service := services.NewRulesheets(repository, gitlabService)
-	_, err = service.Get(ctx, "1")
+	syncs := new(mocks_services.Syncs)
+	syncs.On("Last", ctx, uint(1)).Return(&dtos.Sync{Status: models.SyncFailed, Error: "error on save"}, nil)
+	service := services.NewRulesheets(repository, gitlabService, syncs)
+	result, err := service.Get(ctx, "1")
 	if err != nil {
 		t.Error("unexpected error on get")
+		return
 	}
+	assert.Equal(t, models.SyncFailed, result.Sync.Status)
+}
+
+// This tests the retrieval of a rulesheet by its slug, filled like the retrieval by ID.
+func TestGetBySlugSuccess(t *testing.T) {
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID:   1,
+		Slug: "test",
+	}
+	entity, err := models.NewRulesheetV1(*dto)
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("GetBySlug", ctx, "test").Return(&entity, nil)
+	gitlabService := new(mocks_services.Gitlab)
+	gitlabService.On("Fill", dto).Return(nil)
+	syncs := new(mocks_services.Syncs)
+	syncs.On("Last", ctx, uint(1)).Return(nil, nil)
+	service := services.NewRulesheets(repository, gitlabService, syncs)
+	result, err := service.GetBySlug(ctx, "test")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), result.ID)
+	gitlabService.AssertExpectations(t)
+}
+
+// This tests that the retrieval of an unknown slug returns nil without filling anything.
+func TestGetBySlugNotFound(t *testing.T) {
+	ctx := context.Background()
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("GetBySlug", ctx, "unknown").Return(nil, nil)
+	gitlabService := new(mocks_services.Gitlab)
+	service := services.NewRulesheets(repository, gitlabService, nil)
+	result, err := service.GetBySlug(ctx, "unknown")
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+	gitlabService.AssertNotCalled(t, "Fill", mock.Anything)
 }
 
 // This's a Get function of a Rulesheets service, which tests for an error on
@@ -87,7 +156,7 @@ func TestGetWithErrorOnCreateModel(t *testing.T) {
 	repository.On("Get", ctx, "1").Return(&entity, errors.New("error on model creation"))
 	gitlabService := new(mocks_services.Gitlab)
 	gitlabService.On("Fill", dto).Return(nil)
-	service := services.NewRulesheets(repository, gitlabService)
+	service := services.NewRulesheets(repository, gitlabService, nil)
 	_, err = service.Get(ctx, "1")
 	if err == nil || err.Error() != "error on model creation" {
 		t.Error("unexpected error on get")
@@ -106,19 +175,22 @@ func TestCreateWithErrorOnFill(t *testing.T) {
 		t.Error("unexpected error on model creation")
 	}
 	repository := new(mocks_repository.Rulesheets)
-	repository.On("Create", ctx, &entity).Return(nil)
+	repository.On("GetDB").Return(setupTransactionDB(t, true))
+	repository.On("CreateInTransaction", ctx, mock.Anything, &entity).Return(nil)
 	gitlabService := new(mocks_services.Gitlab)
-	gitlabService.On("Save", dto, "[FEATWS BOT] Create Repo").Return(nil)
 	gitlabService.On("Fill", dto).Return(errors.New("error on fill"))
-	service := services.NewRulesheets(repository, gitlabService)
+	syncs := new(mocks_services.Syncs)
+	syncs.On("EnqueueInTransaction", ctx, mock.Anything, dto, "[FEATWS BOT] Create Repo").Return(nil)
+	syncs.On("SyncRulesheet", ctx, uint(1)).Return(&dtos.Sync{Status: models.SyncSynced}, nil)
+	service := services.NewRulesheets(repository, gitlabService, syncs)
 	err = service.Create(ctx, dto)
 	if err == nil || err.Error() != "error on fill" {
 		t.Error("expected error on fill")
 	}
 }
 
-// TestCreateSuccess is a function that tests the successful creation of a rulesheet entity and its
-// corresponding GitLab repository.
+// TestCreateSuccess is a function that tests the successful creation of a rulesheet entity, written on the
+// outbox within the same transaction and synced to its GitLab repository right away.
 func TestCreateSuccess(t *testing.T) {
 	ctx := context.Background()
 	dto := &dtos.Rulesheet{
@@ -129,19 +201,24 @@ func TestCreateSuccess(t *testing.T) {
 		t.Error("unexpected error on model creation")
 	}
 	repository := new(mocks_repository.Rulesheets)
-	repository.On("Create", ctx, &entity).Return(nil)
+	repository.On("GetDB").Return(setupTransactionDB(t, true))
+	repository.On("CreateInTransaction", ctx, mock.Anything, &entity).Return(nil)
 	gitlabService := new(mocks_services.Gitlab)
-	gitlabService.On("Save", dto, "[FEATWS BOT] Create Repo").Return(nil)
 	gitlabService.On("Fill", dto).Return(nil)
-	service := services.NewRulesheets(repository, gitlabService)
+	syncs := new(mocks_services.Syncs)
+	syncs.On("EnqueueInTransaction", ctx, mock.Anything, dto, "[FEATWS BOT] Create Repo").Return(nil)
+	syncs.On("SyncRulesheet", ctx, uint(1)).Return(&dtos.Sync{Status: models.SyncSynced, Version: "1"}, nil)
+	service := services.NewRulesheets(repository, gitlabService, syncs)
 	err = service.Create(ctx, dto)
 	if err != nil {
 		t.Error("unexpected error on create")
+		return
 	}
-
+	assert.Equal(t, models.SyncSynced, dto.Sync.Status)
 }
 
-// This tests the Create method of a Rulesheets service with an expected error.
+// This tests the Create method of a Rulesheets service with an expected error, which rolls back the
+// transaction without writing on the outbox.
 func TestCreateWithError(t *testing.T) {
 	ctx := context.Background()
 	dto := &dtos.Rulesheet{
@@ -153,18 +230,19 @@ func TestCreateWithError(t *testing.T) {
 		t.Error("unexpected error on model creation")
 	}
 	repository := new(mocks_repository.Rulesheets)
-	repository.On("Create", ctx, &entity).Return(errors.New("error on create"))
-	gitlabService := new(mocks_services.Gitlab)
-	gitlabService.On("Save", dto, "[FEATWS BOT] Create Repo").Return(nil)
-	gitlabService.On("Fill", dto).Return(nil)
-	service := services.NewRulesheets(repository, gitlabService)
+	repository.On("GetDB").Return(setupTransactionDB(t, false))
+	repository.On("CreateInTransaction", ctx, mock.Anything, &entity).Return(errors.New("error on create"))
+	syncs := new(mocks_services.Syncs)
+	service := services.NewRulesheets(repository, new(mocks_services.Gitlab), syncs)
 	err = service.Create(ctx, dto)
 	if err == nil || err.Error() != "error on create" {
 		t.Error("expected error on create")
 	}
+	syncs.AssertNotCalled(t, "EnqueueInTransaction", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
-// This checks if an error is returned when attempting to save a new rulesheet entity.
+// This checks that a failed save of a new rulesheet doesn't fail its creation: the change is kept on the
+// outbox as pending, to be retried by the sync worker.
 func TestCreateWithErroOnSave(t *testing.T) {
 	ctx := context.Background()
 	dto := &dtos.Rulesheet{
@@ -176,38 +254,131 @@ func TestCreateWithErroOnSave(t *testing.T) {
 		t.Error("unexpected error on model creation")
 	}
 	repository := new(mocks_repository.Rulesheets)
-	repository.On("Create", ctx, &entity).Return(nil)
+	repository.On("GetDB").Return(setupTransactionDB(t, true))
+	repository.On("CreateInTransaction", ctx, mock.Anything, &entity).Return(nil)
 	gitlabService := new(mocks_services.Gitlab)
-	gitlabService.On("Save", dto, "[FEATWS BOT] Create Repo").Return(errors.New("error on save"))
-	gitlabService.On("Fill", dto).Return(nil)
-	service := services.NewRulesheets(repository, gitlabService)
+	syncs := new(mocks_services.Syncs)
+	syncs.On("EnqueueInTransaction", ctx, mock.Anything, dto, "[FEATWS BOT] Create Repo").Return(nil)
+	syncs.On("SyncRulesheet", ctx, uint(1)).Return(&dtos.Sync{Status: models.SyncPending, Attempts: 1, Error: "error on save"}, errors.New("error on save"))
+	service := services.NewRulesheets(repository, gitlabService, syncs)
 	err = service.Create(ctx, dto)
-	if err == nil || err.Error() != "error on save" {
-		t.Error("expected error on save")
+	if err != nil {
+		t.Error("unexpected error on create")
+		return
 	}
+	assert.Equal(t, models.SyncPending, dto.Sync.Status)
+	assert.Equal(t, "error on save", dto.Sync.Error)
+	gitlabService.AssertNotCalled(t, "Fill", mock.Anything)
 }
 
-// This test Update method of a Rulesheets service, checking if an error is returned when there is an error on saving the data to Gitlab.
+// This test Update method of a Rulesheets service, checking that an error on saving the data to Gitlab
+// leaves the change pending on the outbox, with no version published yet.
 func TestUpdateWithErrorOnSave(t *testing.T) {
 
 	ctx := context.Background()
 	dto := &dtos.Rulesheet{
-		ID: 1,
+		ID:      1,
+		Version: "1",
+	}
+	entity, err := models.NewRulesheetV1(*dto)
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("GetDB").Return(setupTransactionDB(t, true))
+	repository.On("UpdateInTransaction", ctx, mock.Anything, entity).Return(nil, nil)
+	syncs := new(mocks_services.Syncs)
+	syncs.On("EnqueueInTransaction", ctx, mock.Anything, mock.Anything, "[FEATWS BOT] Update Repo").Return(nil)
+	syncs.On("SyncRulesheet", ctx, uint(1)).Return(&dtos.Sync{Status: models.SyncPending, Attempts: 1, Error: "error on save"}, errors.New("error on save"))
+	service := services.NewRulesheets(repository, new(mocks_services.Gitlab), syncs)
+	result, err := service.Update(ctx, *dto)
+	if err != nil {
+		t.Error("unexpected error on update")
+		return
+	}
+	assert.Equal(t, models.SyncPending, result.Sync.Status)
+	assert.Equal(t, "", result.Version)
+}
+
+// This checks that an update based on an outdated version on the storage backend fails before the
+// transaction is opened, so the backend isn't read while the rulesheet is locked and the change isn't
+// written on the outbox.
+func TestUpdateWithVersionConflict(t *testing.T) {
+
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID:          1,
+		BaseVersion: "1",
+	}
+	repository := new(mocks_repository.Rulesheets)
+	gitlabService := new(mocks_services.Gitlab)
+	gitlabService.On("Fill", mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(0).(*dtos.Rulesheet).Version = "2"
+	}).Return(nil)
+	syncs := new(mocks_services.Syncs)
+	service := services.NewRulesheets(repository, gitlabService, syncs)
+	_, err := service.Update(ctx, *dto)
+	assert.ErrorIs(t, err, services.ErrVersionConflict)
+	repository.AssertNotCalled(t, "GetDB")
+	repository.AssertNotCalled(t, "UpdateInTransaction", mock.Anything, mock.Anything, mock.Anything)
+	syncs.AssertNotCalled(t, "EnqueueInTransaction", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// This checks that an update based on a version is rolled back while another change of the rulesheet is
+// waiting to be published, since that change will publish a new version.
+func TestUpdateWithUnpublishedChanges(t *testing.T) {
+
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID:          1,
+		BaseVersion: "1",
 	}
 	entity, err := models.NewRulesheetV1(*dto)
 	if err != nil {
 		t.Error("unexpected error on model creation")
 	}
 	repository := new(mocks_repository.Rulesheets)
-	repository.On("Update", ctx, entity).Return(nil, nil)
+	repository.On("GetDB").Return(setupTransactionDB(t, false))
+	repository.On("UpdateInTransaction", ctx, mock.Anything, entity).Return(nil, nil)
 	gitlabService := new(mocks_services.Gitlab)
-	gitlabService.On("Save", dto, "[FEATWS BOT] Update Repo").Return(errors.New("error on save"))
-	// gitlabService.On("Fill", dto).Return(errors.New("error on fill"))
-	service := services.NewRulesheets(repository, gitlabService)
+	gitlabService.On("Fill", mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(0).(*dtos.Rulesheet).Version = "1"
+	}).Return(nil)
+	syncs := new(mocks_services.Syncs)
+	syncs.On("Last", ctx, uint(1)).Return(&dtos.Sync{Status: models.SyncFailed}, nil)
+	service := services.NewRulesheets(repository, gitlabService, syncs)
 	_, err = service.Update(ctx, *dto)
-	if err == nil || err.Error() != "error on save" {
-		t.Error("expected error on save")
+	assert.ErrorIs(t, err, services.ErrVersionConflict)
+	syncs.AssertNotCalled(t, "EnqueueInTransaction", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// This checks that a version conflict on saving the data to Gitlab, when it's changed outside the API,
+// fails the update, since retrying won't solve it.
+func TestUpdateWithVersionConflictOnSync(t *testing.T) {
+
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID:          1,
+		BaseVersion: "1",
+	}
+	entity, err := models.NewRulesheetV1(*dto)
+	if err != nil {
+		t.Error("unexpected error on model creation")
 	}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("GetDB").Return(setupTransactionDB(t, true))
+	repository.On("UpdateInTransaction", ctx, mock.Anything, entity).Return(nil, nil)
+	gitlabService := new(mocks_services.Gitlab)
+	gitlabService.On("Fill", mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(0).(*dtos.Rulesheet).Version = "1"
+	}).Return(nil)
+	syncs := new(mocks_services.Syncs)
+	syncs.On("Last", ctx, uint(1)).Return(nil, nil)
+	syncs.On("EnqueueInTransaction", ctx, mock.Anything, mock.Anything, "[FEATWS BOT] Update Repo").Return(nil)
+	syncs.On("SyncRulesheet", ctx, uint(1)).Return(&dtos.Sync{Status: models.SyncFailed}, services.ErrVersionConflict)
+	service := services.NewRulesheets(repository, gitlabService, syncs)
+	_, err = service.Update(ctx, *dto)
+	assert.ErrorIs(t, err, services.ErrVersionConflict)
 }
 
 // This is a tests the successful finding of a rulesheet entity using mocked repository and service objects.
@@ -221,12 +392,22 @@ func TestFindSuccess(t *testing.T) {
 		t.Error("unexpected error on model creation")
 	}
 	repo := new(mocks_repository.Rulesheets)
-	repoFindOptions := repository.FindOptions{}
+	repoFindOptions := repository.FindOptions{Sort: []repository.Sort{{Column: "id"}}}
 	entities := []*models.Rulesheet{&entity}
-	repo.On("Find", ctx, dto.ID, &repoFindOptions).Return(entities, nil)
-	service := services.NewRulesheets(repo, nil)
-	serviceFindOptions := services.FindOptions{0, 0}
-	_, err = service.Find(ctx, dto.ID, &serviceFindOptions)
+	from := time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC)
+	criteria := &repository.Criteria{
+		Conditions: []repository.Condition{
+			repository.Prefix("name", "test"),
+			repository.Contains("gold", "name", "slug", "description"),
+			repository.From("updated_at", from),
+		},
+		Deleted: services.DeletedInclude,
+	}
+	repo.On("Find", ctx, criteria, &repoFindOptions).Return(entities, nil)
+	service := services.NewRulesheets(repo, nil, nil)
+	serviceFindOptions := services.FindOptions{}
+	filter := &services.RulesheetFilter{Name: "test", Search: "gold", UpdatedFrom: &from, Deleted: services.DeletedInclude}
+	_, err = service.Find(ctx, filter, &serviceFindOptions)
 	if err != nil {
 		t.Error("unexpected error on find")
 	}
@@ -243,18 +424,77 @@ func TestFindWithError(t *testing.T) {
 		t.Error("unexpected error on model creation")
 	}
 	repo := new(mocks_repository.Rulesheets)
-	repoFindOptions := repository.FindOptions{}
+	repoFindOptions := repository.FindOptions{Sort: []repository.Sort{{Column: "id"}}}
 	entities := []*models.Rulesheet{&entity}
-	repo.On("Find", ctx, dto.ID, &repoFindOptions).Return(entities, errors.New("error on find"))
-	service := services.NewRulesheets(repo, nil)
-	serviceFindOptions := services.FindOptions{0, 0}
-	_, err = service.Find(ctx, dto.ID, &serviceFindOptions)
+	repo.On("Find", ctx, (*repository.Criteria)(nil), &repoFindOptions).Return(entities, errors.New("error on find"))
+	service := services.NewRulesheets(repo, nil, nil)
+	serviceFindOptions := services.FindOptions{}
+	_, err = service.Find(ctx, nil, &serviceFindOptions)
 	if err != nil && err.Error() != "error on find" {
 		t.Error("unexpected error on find")
 	}
 
 }
 
+// This tests that the Find method orders the rulesheets by the sort and the ID, and finds the ones after the
+// cursor built for the last rulesheet of the previous page.
+func TestFindWithSortAndCursor(t *testing.T) {
+	ctx := context.Background()
+	updatedAt := time.Date(2023, 1, 31, 10, 0, 0, 0, time.UTC)
+	sort, err := services.ParseSort("name,-updatedAt")
+	assert.NoError(t, err)
+	cursor := services.NextCursor(&dtos.Rulesheet{ID: 7, Name: "gold", UpdatedAt: &updatedAt}, sort)
+
+	repo := new(mocks_repository.Rulesheets)
+	repoFindOptions := repository.FindOptions{
+		Limit: 10,
+		Sort: []repository.Sort{
+			{Column: "name"},
+			{Column: "updated_at", Desc: true},
+			{Column: "id"},
+		},
+		After: []interface{}{"gold", updatedAt, uint(7)},
+	}
+	repo.On("Find", ctx, (*repository.Criteria)(nil), &repoFindOptions).Return([]*models.Rulesheet{}, nil)
+	service := services.NewRulesheets(repo, nil, nil)
+
+	_, err = service.Find(ctx, nil, &services.FindOptions{Limit: 10, Sort: sort, Cursor: cursor})
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+// This tests that the Find method rejects the cursors that can't be read or were built for another sort.
+func TestFindWithInvalidCursor(t *testing.T) {
+	ctx := context.Background()
+	service := services.NewRulesheets(new(mocks_repository.Rulesheets), nil, nil)
+
+	cursor := services.NextCursor(&dtos.Rulesheet{ID: 7, Name: "gold"}, []services.SortField{{Field: "name"}})
+	for _, options := range []*services.FindOptions{
+		{Cursor: "not a cursor"},
+		{Cursor: cursor},
+		{Cursor: cursor, Sort: []services.SortField{{Field: "createdAt"}}},
+	} {
+		_, err := service.Find(ctx, nil, options)
+		assert.ErrorIs(t, err, services.ErrInvalidCursor)
+	}
+}
+
+// This tests the parsing of the fields the rulesheets are ordered by.
+func TestParseSort(t *testing.T) {
+	sort, err := services.ParseSort("name, -updatedAt")
+	assert.NoError(t, err)
+	assert.Equal(t, []services.SortField{{Field: "name"}, {Field: "updatedAt", Desc: true}}, sort)
+
+	sort, err = services.ParseSort("")
+	assert.NoError(t, err)
+	assert.Empty(t, sort)
+
+	for _, value := range []string{"unknown", "name,-name", "name,"} {
+		_, err = services.ParseSort(value)
+		assert.ErrorIs(t, err, services.ErrInvalidSort, value)
+	}
+}
+
 // This tests the Count method of a Rulesheets service.
 func TestCountSuccess(t *testing.T) {
 	ctx := context.Background()
@@ -266,8 +506,8 @@ func TestCountSuccess(t *testing.T) {
 		t.Error("unexpected error on model creation")
 	}
 	repository := new(mocks_repository.Rulesheets)
-	repository.On("Count", ctx, nil).Return(int64(1), nil)
-	service := services.NewRulesheets(repository, nil)
+	repository.On("Count", ctx, mock.AnythingOfType("*repository.Criteria")).Return(int64(1), nil)
+	service := services.NewRulesheets(repository, nil, nil)
 	_, err = service.Count(ctx, nil)
 	if err != nil {
 		t.Error("unexpected error on count")
@@ -285,15 +525,15 @@ func TestCountWithError(t *testing.T) {
 		t.Error("unexpected error on model creation")
 	}
 	repository := new(mocks_repository.Rulesheets)
-	repository.On("Count", ctx, nil).Return(int64(0), errors.New("error on count"))
-	service := services.NewRulesheets(repository, nil)
+	repository.On("Count", ctx, mock.AnythingOfType("*repository.Criteria")).Return(int64(0), errors.New("error on count"))
+	service := services.NewRulesheets(repository, nil, nil)
 	_, err = service.Count(ctx, nil)
 	if err == nil || err.Error() != "error on count" {
 		t.Error("expected error on count")
 	}
 }
 
-// This tests the successful update of a rulesheet entity using mocked repository and Gitlab services.
+// This tests the successful update of a rulesheet entity using mocked repository and outbox services.
 func TestUpdateSuccess(t *testing.T) {
 	ctx := context.Background()
 	dto := &dtos.Rulesheet{
@@ -304,15 +544,41 @@ func TestUpdateSuccess(t *testing.T) {
 		t.Error("unexpected error on model creation")
 	}
 	repository := new(mocks_repository.Rulesheets)
-	repository.On("Update", ctx, entity).Return(nil, nil)
-	gitlabService := new(mocks_services.Gitlab)
-	gitlabService.On("Save", dto, "[FEATWS BOT] Update Repo").Return(nil)
-	gitlabService.On("Fill", dto).Return(nil)
-	service := services.NewRulesheets(repository, gitlabService)
-	_, err = service.Update(ctx, *dto)
+	repository.On("GetDB").Return(setupTransactionDB(t, true))
+	repository.On("UpdateInTransaction", ctx, mock.Anything, entity).Return(nil, nil)
+	syncs := new(mocks_services.Syncs)
+	syncs.On("EnqueueInTransaction", ctx, mock.Anything, mock.Anything, "[FEATWS BOT] Update Repo").Return(nil)
+	syncs.On("SyncRulesheet", ctx, uint(1)).Return(&dtos.Sync{Status: models.SyncSynced, Version: "2"}, nil)
+	service := services.NewRulesheets(repository, new(mocks_services.Gitlab), syncs)
+	result, err := service.Update(ctx, *dto)
 	if err != nil {
 		t.Error("unexpected error on update")
+		return
 	}
+	assert.Equal(t, "2", result.Version)
+}
+
+// This tests that the update is committed with the commit message of the rulesheet, when it's set.
+func TestUpdateWithCommitMessage(t *testing.T) {
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID:            1,
+		CommitMessage: "[FEATWS BOT] Update rule discount",
+	}
+	entity, err := models.NewRulesheetV1(*dto)
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("GetDB").Return(setupTransactionDB(t, true))
+	repository.On("UpdateInTransaction", ctx, mock.Anything, entity).Return(nil, nil)
+	syncs := new(mocks_services.Syncs)
+	syncs.On("EnqueueInTransaction", ctx, mock.Anything, mock.Anything, "[FEATWS BOT] Update rule discount").Return(nil)
+	syncs.On("SyncRulesheet", ctx, uint(1)).Return(&dtos.Sync{Status: models.SyncSynced, Version: "2"}, nil)
+	service := services.NewRulesheets(repository, new(mocks_services.Gitlab), syncs)
+	_, err = service.Update(ctx, *dto)
+	assert.NoError(t, err)
+	syncs.AssertExpectations(t)
 }
 
 // The function tests the update method of a Rulesheets service with an error scenario.
@@ -326,15 +592,15 @@ func TestUpdateWithError(t *testing.T) {
 		t.Error("unexpected error on model creation")
 	}
 	repository := new(mocks_repository.Rulesheets)
-	repository.On("Update", ctx, entity).Return(nil, errors.New("error on update"))
-	gitlabService := new(mocks_services.Gitlab)
-	gitlabService.On("Save", dto, "[FEATWS BOT] Update Repo").Return(nil)
-	gitlabService.On("Fill", dto).Return(nil)
-	service := services.NewRulesheets(repository, gitlabService)
+	repository.On("GetDB").Return(setupTransactionDB(t, false))
+	repository.On("UpdateInTransaction", ctx, mock.Anything, entity).Return(nil, errors.New("error on update"))
+	syncs := new(mocks_services.Syncs)
+	service := services.NewRulesheets(repository, new(mocks_services.Gitlab), syncs)
 	_, err = service.Update(ctx, *dto)
 	if err == nil || err.Error() != "error on update" {
 		t.Error("expected error on update")
 	}
+	syncs.AssertNotCalled(t, "EnqueueInTransaction", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
 // func (s *RepositorySuite) SetupSuite() {
@@ -405,7 +671,7 @@ func TestDeleteSuccess(t *testing.T) {
 	repository.On("DeleteInTransaction", ctx, mock.Anything, "1").Return(true, nil)
 	gitlabService := new(mocks_services.Gitlab)
 	gitlabService.On("Delete", dto).Return(true, nil)
-	service := services.NewRulesheets(repository, gitlabService)
+	service := services.NewRulesheets(repository, gitlabService, nil)
 	_, err = service.Delete(ctx, "1")
 	if err != nil {
 		t.Error("unexpected error on delete")
@@ -450,7 +716,7 @@ func TestDeleteWithError(t *testing.T) {
 	repository.On("UpdateInTransaction", ctx, mock.Anything, mock.Anything).Return(nil, errors.New("error on update"))
 	gitlabService := new(mocks_services.Gitlab)
 	gitlabService.On("Delete", dto).Return(true, nil)
-	service := services.NewRulesheets(repository, gitlabService)
+	service := services.NewRulesheets(repository, gitlabService, nil)
 	_, err = service.Delete(ctx, "1")
 	if err == nil || err.Error() != "error on update" {
 		log.Println(err)
@@ -494,7 +760,7 @@ func TestDeleteWithRollBackOnGet(t *testing.T) {
 	repository.On("Get", ctx, newID).Return(nil, errors.New("error on get"))
 	gitlabService := new(mocks_services.Gitlab)
 	gitlabService.On("Delete", dto).Return(true, nil)
-	service := services.NewRulesheets(repository, gitlabService)
+	service := services.NewRulesheets(repository, gitlabService, nil)
 	_, err = service.Delete(ctx, "1")
 	if err == nil || err.Error() != "error on get" {
 		t.Error("expected error on get")
@@ -540,9 +806,782 @@ func TestDeleteWithRollBackOnDeleteInTransaction(t *testing.T) {
 	repository.On("DeleteInTransaction", ctx, mock.Anything, "1").Return(false, errors.New("error on delete"))
 	gitlabService := new(mocks_services.Gitlab)
 	gitlabService.On("Delete", dto).Return(true, nil)
-	service := services.NewRulesheets(repository, gitlabService)
+	service := services.NewRulesheets(repository, gitlabService, nil)
 	_, err = service.Delete(ctx, "1")
 	if err == nil || err.Error() != "error on delete" {
 		t.Error("expected error on delete")
 	}
 }
+
+// This tests the successful listing of the versions of a rulesheet using mocked repository and Gitlab services.
+func TestHistorySuccess(t *testing.T) {
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID: 1,
+	}
+	entity, err := models.NewRulesheetV1(*dto)
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	opts := &services.FindOptions{Limit: 10, Page: 1}
+	versions := []*dtos.Version{{Number: "1", Commit: "sha1"}}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("Get", ctx, "1").Return(&entity, nil)
+	gitlabService := new(mocks_services.Gitlab)
+	gitlabService.On("History", dto, opts).Return(versions, nil)
+	service := services.NewRulesheets(repository, gitlabService, nil)
+	result, err := service.History(ctx, "1", opts)
+	if err != nil {
+		t.Error("unexpected error on history")
+	}
+	assert.Equal(t, versions, result)
+}
+
+// This tests the error handling of the History method in a Rulesheets service when the rulesheet isn't found.
+func TestHistoryWithErrorOnGet(t *testing.T) {
+	ctx := context.Background()
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("Get", ctx, "1").Return(nil, errors.New("error on get"))
+	gitlabService := new(mocks_services.Gitlab)
+	service := services.NewRulesheets(repository, gitlabService, nil)
+	_, err := service.History(ctx, "1", nil)
+	if err == nil || err.Error() != "error on get" {
+		t.Error("expected error on get")
+	}
+}
+
+// This tests the successful retrieval of a rulesheet at a given version using mocked repository and Gitlab services.
+func TestGetVersionSuccess(t *testing.T) {
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID: 1,
+	}
+	entity, err := models.NewRulesheetV1(*dto)
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("Get", ctx, "1").Return(&entity, nil)
+	gitlabService := new(mocks_services.Gitlab)
+	gitlabService.On("ResolveVersion", dto, "3").Return("sha3", nil)
+	gitlabService.On("FillRef", dto, "sha3").Return(nil)
+	service := services.NewRulesheets(repository, gitlabService, nil)
+	_, err = service.GetVersion(ctx, "1", "3")
+	if err != nil {
+		t.Error("unexpected error on get version")
+	}
+	gitlabService.AssertExpectations(t)
+}
+
+// This tests the error handling of the GetVersion method in a Rulesheets service when the version doesn't exist.
+func TestGetVersionWithVersionNotFound(t *testing.T) {
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID: 1,
+	}
+	entity, err := models.NewRulesheetV1(*dto)
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("Get", ctx, "1").Return(&entity, nil)
+	gitlabService := new(mocks_services.Gitlab)
+	gitlabService.On("ResolveVersion", dto, "9").Return("", services.ErrVersionNotFound)
+	service := services.NewRulesheets(repository, gitlabService, nil)
+	_, err = service.GetVersion(ctx, "1", "9")
+	assert.ErrorIs(t, err, services.ErrVersionNotFound)
+}
+
+// This tests the diff between two versions of a rulesheet: features and parameters are compared by name and
+// rules by their dotted path, regardless of the order of the keys.
+func TestDiffSuccess(t *testing.T) {
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID: 1,
+	}
+	entity, err := models.NewRulesheetV1(*dto)
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("Get", ctx, "1").Return(&entity, nil)
+	gitlabService := new(mocks_services.Gitlab)
+	gitlabService.On("ResolveVersion", dto, "1").Return("sha1", nil)
+	gitlabService.On("ResolveVersion", dto, "2").Return("sha2", nil)
+	gitlabService.On("FillRef", dto, "sha1").Run(func(args mock.Arguments) {
+		rulesheet := args.Get(0).(*dtos.Rulesheet)
+		rulesheet.Version = "1"
+		features := []dtos.Feature{{Name: "discount", Type: "decimal"}, {Name: "gold", Type: "boolean"}}
+		rulesheet.Features = &features
+		parameters := []dtos.Parameter{{Name: "age", Type: "integer"}}
+		rulesheet.Parameters = &parameters
+		rules := map[string]interface{}{
+			"gold": &dtos.Rule{Condition: "$age > 18", Value: true},
+			"discount": map[string]interface{}{
+				"gold":   &dtos.Rule{Value: 10},
+				"silver": &dtos.Rule{Value: 5},
+			},
+		}
+		rulesheet.Rules = &rules
+	}).Return(nil)
+	gitlabService.On("FillRef", dto, "sha2").Run(func(args mock.Arguments) {
+		rulesheet := args.Get(0).(*dtos.Rulesheet)
+		rulesheet.Version = "2"
+		features := []dtos.Feature{{Type: "boolean", Name: "gold"}, {Name: "discount", Type: "integer"}}
+		rulesheet.Features = &features
+		parameters := []dtos.Parameter{{Name: "age", Type: "integer"}, {Name: "income", Type: "decimal"}}
+		rulesheet.Parameters = &parameters
+		rules := map[string]interface{}{
+			"gold": map[string]interface{}{"value": true, "condition": "$age > 18"},
+			"discount": map[string]interface{}{
+				"gold": &dtos.Rule{Value: 15},
+			},
+		}
+		rulesheet.Rules = &rules
+	}).Return(nil)
+	service := services.NewRulesheets(repository, gitlabService, nil)
+	diff, err := service.Diff(ctx, "1", "1", "2")
+	if err != nil {
+		t.Error("unexpected error on diff")
+		return
+	}
+
+	assert.Equal(t, "1", diff.From)
+	assert.Equal(t, "2", diff.To)
+	assert.Equal(t, []*dtos.Change{
+		{Kind: dtos.ChangeChanged, Name: "discount", Old: map[string]interface{}{"name": "discount", "type": "decimal"}, New: map[string]interface{}{"name": "discount", "type": "integer"}},
+	}, diff.Features)
+	assert.Equal(t, []*dtos.Change{
+		{Kind: dtos.ChangeAdded, Name: "income", New: map[string]interface{}{"name": "income", "type": "decimal"}},
+	}, diff.Parameters)
+	assert.Equal(t, []*dtos.Change{
+		{Kind: dtos.ChangeChanged, Name: "discount.gold", Old: map[string]interface{}{"value": float64(10)}, New: map[string]interface{}{"value": float64(15)}},
+		{Kind: dtos.ChangeRemoved, Name: "discount.silver", Old: map[string]interface{}{"value": float64(5)}},
+	}, diff.Rules)
+}
+
+// This tests that the rules with only a dynamic value are compared as rules, like `buildRule` reads them,
+// instead of as maps of nested rules.
+func TestDiffWithDynamicRules(t *testing.T) {
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID: 1,
+	}
+	entity, err := models.NewRulesheetV1(*dto)
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("Get", ctx, "1").Return(&entity, nil)
+	gitlabService := new(mocks_services.Gitlab)
+	gitlabService.On("ResolveVersion", dto, "1").Return("sha1", nil)
+	gitlabService.On("ResolveVersion", dto, "2").Return("sha2", nil)
+	gitlabService.On("FillRef", dto, "sha1").Run(func(args mock.Arguments) {
+		rules := map[string]interface{}{
+			"total": &dtos.Rule{Condition: "$a > 1", Dynamic: "$a * 2"},
+		}
+		args.Get(0).(*dtos.Rulesheet).Rules = &rules
+	}).Return(nil)
+	gitlabService.On("FillRef", dto, "sha2").Run(func(args mock.Arguments) {
+		rules := map[string]interface{}{
+			"total": map[string]interface{}{"condition": "$a > 1", "dynamic": "$a * 3"},
+		}
+		args.Get(0).(*dtos.Rulesheet).Rules = &rules
+	}).Return(nil)
+	service := services.NewRulesheets(repository, gitlabService, nil)
+	diff, err := service.Diff(ctx, "1", "1", "2")
+	if err != nil {
+		t.Error("unexpected error on diff")
+		return
+	}
+
+	assert.Equal(t, []*dtos.Change{
+		{Kind: dtos.ChangeChanged, Name: "total", Old: map[string]interface{}{"condition": "$a > 1", "dynamic": "$a * 2"}, New: map[string]interface{}{"condition": "$a > 1", "dynamic": "$a * 3"}},
+	}, diff.Rules)
+}
+
+// This tests the error handling of the Diff method in a Rulesheets service when one of the versions doesn't exist.
+func TestDiffWithVersionNotFound(t *testing.T) {
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID: 1,
+	}
+	entity, err := models.NewRulesheetV1(*dto)
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("Get", ctx, "1").Return(&entity, nil)
+	gitlabService := new(mocks_services.Gitlab)
+	gitlabService.On("ResolveVersion", dto, "1").Return("sha1", nil)
+	gitlabService.On("FillRef", dto, "sha1").Return(nil)
+	gitlabService.On("ResolveVersion", mock.Anything, "9").Return("", services.ErrVersionNotFound)
+	service := services.NewRulesheets(repository, gitlabService, nil)
+	_, err = service.Diff(ctx, "1", "1", "9")
+	assert.ErrorIs(t, err, services.ErrVersionNotFound)
+}
+
+// This tests the evaluation of a version of a rulesheet: the first matching rule of a list gives the value
+// of the feature, and the features referenced by other rules are computed on demand.
+func TestEvaluateSuccess(t *testing.T) {
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID: 1,
+	}
+	entity, err := models.NewRulesheetV1(*dto)
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("Get", ctx, "1").Return(&entity, nil)
+	gitlabService := new(mocks_services.Gitlab)
+	gitlabService.On("ResolveVersion", dto, "2").Return("sha2", nil)
+	gitlabService.On("FillRef", dto, "sha2").Run(func(args mock.Arguments) {
+		rulesheet := args.Get(0).(*dtos.Rulesheet)
+		rulesheet.Version = "2"
+		rules := map[string]interface{}{
+			"discount": []interface{}{
+				map[string]interface{}{"condition": "#gold", "value": 0.2},
+				map[string]interface{}{"condition": "$total > 100", "value": 0.1},
+				map[string]interface{}{"value": 0},
+			},
+			"gold": map[string]interface{}{"condition": "$age >= 60", "value": true},
+		}
+		rulesheet.Rules = &rules
+	}).Return(nil)
+	service := services.NewRulesheets(repository, gitlabService, nil)
+	result, err := service.Evaluate(ctx, "1", "2", map[string]interface{}{"age": 30, "total": 150.0})
+	if err != nil {
+		t.Error("unexpected error on evaluate")
+		return
+	}
+
+	assert.Equal(t, "2", result.Version)
+	assert.Equal(t, 0.1, result.Features["discount"])
+	assert.Nil(t, result.Features["gold"])
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, []*dtos.RuleResult{
+		{Path: "rules.gold", Condition: "$age >= 60", Matched: false},
+		{Path: "rules.discount[0]", Condition: "#gold", Matched: false},
+		{Path: "rules.discount[1]", Condition: "$total > 100", Matched: true},
+	}, result.Rules)
+}
+
+// This tests that the evaluation resolves only the string values that are a single reference to a declared
+// parameter or feature, keeping the others as text.
+func TestEvaluateWithTextReferences(t *testing.T) {
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID: 1,
+	}
+	entity, err := models.NewRulesheetV1(*dto)
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("Get", ctx, "1").Return(&entity, nil)
+	gitlabService := new(mocks_services.Gitlab)
+	gitlabService.On("ResolveVersion", dto, "2").Return("sha2", nil)
+	gitlabService.On("FillRef", dto, "sha2").Run(func(args mock.Arguments) {
+		rulesheet := args.Get(0).(*dtos.Rulesheet)
+		rulesheet.Version = "2"
+		rules := map[string]interface{}{
+			"color": map[string]interface{}{"value": "#FF0000"},
+			"link":  map[string]interface{}{"value": "https://x/#section"},
+			"price": map[string]interface{}{"value": "US$ price"},
+			"limit": map[string]interface{}{"value": "$total"},
+		}
+		parameters := []dtos.Parameter{{Name: "total"}}
+		rulesheet.Rules = &rules
+		rulesheet.Parameters = &parameters
+	}).Return(nil)
+	service := services.NewRulesheets(repository, gitlabService, nil)
+	result, err := service.Evaluate(ctx, "1", "2", map[string]interface{}{"total": 150.0})
+	if err != nil {
+		t.Error("unexpected error on evaluate")
+		return
+	}
+
+	assert.Equal(t, "#FF0000", result.Features["color"])
+	assert.Equal(t, "https://x/#section", result.Features["link"])
+	assert.Equal(t, "US$ price", result.Features["price"])
+	assert.Equal(t, 150.0, result.Features["limit"])
+	assert.Empty(t, result.Errors)
+}
+
+// This tests the error handling of the Evaluate method when the rulesheet only has string rules.
+func TestEvaluateWithStringRules(t *testing.T) {
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID: 1,
+	}
+	entity, err := models.NewRulesheetV1(*dto)
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("Get", ctx, "1").Return(&entity, nil)
+	gitlabService := new(mocks_services.Gitlab)
+	gitlabService.On("ResolveVersion", dto, "1").Return("sha1", nil)
+	gitlabService.On("FillRef", dto, "sha1").Run(func(args mock.Arguments) {
+		rules := map[string]interface{}{"feat": "$a > 1"}
+		args.Get(0).(*dtos.Rulesheet).Rules = &rules
+	}).Return(nil)
+	service := services.NewRulesheets(repository, gitlabService, nil)
+	_, err = service.Evaluate(ctx, "1", "1", nil)
+	assert.ErrorIs(t, err, services.ErrStringRulesNotSupported)
+}
+
+// This tests that the rules exported to the rules.featws format are read back as the same rules, including
+// the values that need to be quoted.
+func TestExportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	entity, err := models.NewRulesheetV1(dtos.Rulesheet{ID: 1, Slug: "test"})
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("Get", ctx, "1").Return(&entity, nil)
+	syncs := new(mocks_services.Syncs)
+	syncs.On("Last", ctx, uint(1)).Return(nil, nil)
+
+	cfg := &config.Config{
+		LocalPath: t.TempDir(),
+	}
+	backend := services.NewLocal(cfg)
+	file := filepath.Join(cfg.LocalPath, "test", "rules.featws")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+	for name, content := range map[string]string{"VERSION": "1\n", "features.json": "[]", "parameters.json": "[]"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(file), name), []byte(content), 0644))
+	}
+	assert.NoError(t, os.WriteFile(file, []byte(
+		"a = $x > 1\nb = `x; y`\nc = `'gold'`\nd = ` padded `\nm = `line1\nline2`\n`k:ey` = 3\n\n"+
+			"[sec]\ne = #a && true\n\n[[list]]\nf = 1\n\n[[list]]\nf = 2\ng = `ends\\`\n"), 0644))
+
+	original := &dtos.Rulesheet{ID: 1, Slug: "test"}
+	assert.NoError(t, backend.Fill(original))
+	assert.Equal(t, "x; y", (*original.Rules)["b"])
+	assert.Equal(t, "line1\nline2", (*original.Rules)["m"])
+
+	service := services.NewRulesheets(repository, backend, syncs)
+	content, err := service.Export(ctx, "1", "", services.FormatFeatws)
+	if err != nil {
+		t.Error("unexpected error on export")
+		return
+	}
+
+	assert.NoError(t, os.WriteFile(file, content, 0644))
+	exported := &dtos.Rulesheet{ID: 1, Slug: "test"}
+	assert.NoError(t, backend.Fill(exported))
+	assert.Equal(t, *original.Rules, *exported.Rules)
+
+	_, err = service.Export(ctx, "1", "", "yaml")
+	assert.ErrorIs(t, err, services.ErrFormatNotSupported)
+}
+
+// This tests that a rulesheet with the legacy rules.featws file isn't changed before it's converted, so its
+// rules are kept.
+func TestUpdateLegacyRulesheet(t *testing.T) {
+	ctx := context.Background()
+	repository := new(mocks_repository.Rulesheets)
+	syncs := new(mocks_services.Syncs)
+
+	cfg := &config.Config{
+		LocalPath: t.TempDir(),
+	}
+	backend := services.NewLocal(cfg)
+	file := filepath.Join(cfg.LocalPath, "test", "rules.featws")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+	for name, content := range map[string]string{"VERSION": "1\n", "features.json": "[]", "parameters.json": "[]"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(file), name), []byte(content), 0644))
+	}
+	assert.NoError(t, os.WriteFile(file, []byte("a = $x > 1\nb = true\n"), 0644))
+
+	dto := &dtos.Rulesheet{ID: 1, Slug: "test"}
+	assert.NoError(t, backend.Fill(dto))
+	assert.True(t, dto.HasStringRule)
+	description := "changed"
+	dto.Description = description
+
+	service := services.NewRulesheets(repository, backend, syncs)
+	_, err := service.Update(ctx, *dto)
+	assert.ErrorIs(t, err, services.ErrLegacyRules)
+	repository.AssertNotCalled(t, "UpdateInTransaction", mock.Anything, mock.Anything, mock.Anything)
+
+	_, err = os.Stat(file)
+	assert.NoError(t, err)
+	filled := &dtos.Rulesheet{ID: 1, Slug: "test"}
+	assert.NoError(t, backend.Fill(filled))
+	assert.Equal(t, "1", filled.Version)
+	assert.Equal(t, map[string]interface{}{"a": "$x > 1", "b": "true"}, *filled.Rules)
+}
+
+// This tests that the structured rules with a condition or a dynamic value aren't exported to the
+// rules.featws format, and that the error names their paths.
+func TestExportWithStructuredRules(t *testing.T) {
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID: 1,
+	}
+	entity, err := models.NewRulesheetV1(*dto)
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("Get", ctx, "1").Return(&entity, nil)
+	gitlabService := new(mocks_services.Gitlab)
+	gitlabService.On("Fill", dto).Run(func(args mock.Arguments) {
+		rules := map[string]interface{}{
+			"a":        "$x > 1",
+			"discount": []interface{}{map[string]interface{}{"condition": "$x > 1", "value": 0.1}},
+			"limits":   map[string]interface{}{"max": map[string]interface{}{"dynamic": "$x * 2"}, "min": map[string]interface{}{"value": 1}},
+		}
+		args.Get(0).(*dtos.Rulesheet).Rules = &rules
+	}).Return(nil)
+	syncs := new(mocks_services.Syncs)
+	syncs.On("Last", ctx, uint(1)).Return(nil, nil)
+	service := services.NewRulesheets(repository, gitlabService, syncs)
+	_, err = service.Export(ctx, "1", "", services.FormatFeatws)
+	assert.ErrorIs(t, err, services.ErrNotExportable)
+	assert.Contains(t, err.Error(), "rules.discount[0]: the rules with a condition or a dynamic value can't be written")
+	assert.Contains(t, err.Error(), "rules.limits.max: the rules with a condition or a dynamic value can't be written")
+	assert.NotContains(t, err.Error(), "rules.limits.min")
+	assert.NotContains(t, err.Error(), "rules.a")
+}
+
+// This tests that the structured rules with only a plain value are exported to the rules.featws format and
+// read back, through the conversion of the string rules, as the same rules.
+func TestExportStructuredRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	entity, err := models.NewRulesheetV1(dtos.Rulesheet{ID: 1, Slug: "test"})
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("Get", ctx, "1").Return(&entity, nil)
+	syncs := new(mocks_services.Syncs)
+	syncs.On("Last", ctx, uint(1)).Return(nil, nil)
+
+	cfg := &config.Config{
+		LocalPath: t.TempDir(),
+	}
+	backend := services.NewLocal(cfg)
+	dir := filepath.Join(cfg.LocalPath, "test")
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+	for name, content := range map[string]string{"VERSION": "1\n", "features.json": "[]", "parameters.json": "[]"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "rules.json"), []byte(
+		`{"active":{"value":true},"color":{"value":"#FF0000"},"label":{"value":"it's a \\ test; ok\nnext"},`+
+			`"limits":{"max":{"value":10},"rate":{"value":0.5}},"tiers":[{"name":{"value":"gold"}},{"name":{"value":"silver"}}]}`), 0644))
+
+	service := services.NewRulesheets(repository, backend, syncs)
+	content, err := service.Export(ctx, "1", "", services.FormatFeatws)
+	if err != nil {
+		t.Errorf("unexpected error on export: %v", err)
+		return
+	}
+
+	assert.NoError(t, os.Remove(filepath.Join(dir, "rules.json")))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "rules.featws"), content, 0644))
+	exported := &dtos.Rulesheet{ID: 1, Slug: "test"}
+	assert.NoError(t, backend.Fill(exported))
+	assert.True(t, exported.HasStringRule)
+
+	rules, problems := dtos.ConvertStringRules(*exported.Rules)
+	assert.Empty(t, problems)
+	assert.Equal(t, map[string]interface{}{
+		"active": &dtos.Rule{Value: true},
+		"color":  &dtos.Rule{Value: "#FF0000"},
+		"label":  &dtos.Rule{Value: "it's a \\ test; ok\nnext"},
+		"limits": map[string]interface{}{"max": &dtos.Rule{Value: int64(10)}, "rate": &dtos.Rule{Value: 0.5}},
+		"tiers": []interface{}{
+			map[string]interface{}{"name": &dtos.Rule{Value: "gold"}},
+			map[string]interface{}{"name": &dtos.Rule{Value: "silver"}},
+		},
+	}, rules)
+}
+
+// This tests the preview of the conversion of the string rules of a legacy rulesheet, which parses each
+// This is a test function that checks if the dependency graph of a rulesheet has the references of its
+// nested rules and the cycles between them.
+func TestGraphSuccess(t *testing.T) {
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID: 1,
+	}
+	entity, err := models.NewRulesheetV1(*dto)
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("Get", ctx, "1").Return(&entity, nil)
+	gitlabService := new(mocks_services.Gitlab)
+	gitlabService.On("Fill", dto).Run(func(args mock.Arguments) {
+		rules := map[string]interface{}{
+			"discount": []interface{}{map[string]interface{}{"condition": "#gold && $age > 18", "value": 0.1}, map[string]interface{}{"value": 0}},
+			"gold":     map[string]interface{}{"level": map[string]interface{}{"dynamic": "#silver"}},
+			"silver":   map[string]interface{}{"value": "#gold"},
+		}
+		features := []dtos.Feature{{Name: "vip"}}
+		parameters := []dtos.Parameter{{Name: "age"}}
+		rulesheet := args.Get(0).(*dtos.Rulesheet)
+		rulesheet.Rules = &rules
+		rulesheet.Features = &features
+		rulesheet.Parameters = &parameters
+	}).Return(nil)
+	syncs := new(mocks_services.Syncs)
+	syncs.On("Last", ctx, uint(1)).Return(nil, nil)
+	service := services.NewRulesheets(repository, gitlabService, syncs)
+	graph, err := service.Graph(ctx, "1", "")
+	assert.NoError(t, err)
+
+	ids := make([]string, 0)
+	for _, node := range graph.Nodes {
+		ids = append(ids, node.ID)
+	}
+	assert.Equal(t, []string{"rule:discount", "rule:gold", "rule:silver", "feature:vip", "parameter:age"}, ids)
+	assert.Equal(t, []*dtos.GraphEdge{
+		{From: "rule:discount", To: "parameter:age", Paths: []string{"rules.discount[0].condition"}},
+		{From: "rule:discount", To: "rule:gold", Paths: []string{"rules.discount[0].condition"}},
+		{From: "rule:gold", To: "rule:silver", Paths: []string{"rules.gold.level.dynamic"}},
+		{From: "rule:silver", To: "rule:gold", Paths: []string{"rules.silver.value"}},
+	}, graph.Edges)
+	assert.Equal(t, [][]string{{"gold", "silver", "gold"}}, graph.Cycles)
+	assert.Contains(t, graph.DOT(), "\t\"rule:gold\" -> \"rule:silver\" [color=red];\n")
+	assert.Contains(t, graph.Mermaid(), "    n4[/\"age\"/]\n")
+}
+
+// This is a test function that checks if the string rules of a legacy rulesheet are converted to build its
+// dependency graph.
+func TestGraphWithStringRules(t *testing.T) {
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID: 1,
+	}
+	entity, err := models.NewRulesheetV1(*dto)
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("Get", ctx, "1").Return(&entity, nil)
+	gitlabService := new(mocks_services.Gitlab)
+	gitlabService.On("Fill", dto).Run(func(args mock.Arguments) {
+		rules := map[string]interface{}{"a": "$x > 1", "b": "#a"}
+		rulesheet := args.Get(0).(*dtos.Rulesheet)
+		rulesheet.Rules = &rules
+		rulesheet.HasStringRule = true
+	}).Return(nil)
+	syncs := new(mocks_services.Syncs)
+	syncs.On("Last", ctx, uint(1)).Return(nil, nil)
+	service := services.NewRulesheets(repository, gitlabService, syncs)
+	graph, err := service.Graph(ctx, "1", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []*dtos.GraphEdge{
+		{From: "rule:a", To: "parameter:x", Paths: []string{"rules.a.dynamic"}},
+		{From: "rule:b", To: "rule:a", Paths: []string{"rules.b.dynamic"}},
+	}, graph.Edges)
+	assert.Empty(t, graph.Cycles)
+}
+
+// rule as an expression and reports the ones that can't be parsed.
+func TestConvertLegacyPreview(t *testing.T) {
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID: 1,
+	}
+	entity, err := models.NewRulesheetV1(*dto)
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("Get", ctx, "1").Return(&entity, nil)
+	gitlabService := new(mocks_services.Gitlab)
+	gitlabService.On("Fill", dto).Run(func(args mock.Arguments) {
+		rulesheet := args.Get(0).(*dtos.Rulesheet)
+		parameters := []dtos.Parameter{{Name: "total", Type: "decimal"}}
+		rulesheet.Parameters = &parameters
+		rules := map[string]interface{}{
+			"discount": "$total * 0.1",
+			"level":    "'gold'",
+			"off":      "false",
+			"broken":   "$total >",
+			"limits":   map[string]interface{}{"max": "100"},
+		}
+		rulesheet.Rules = &rules
+		rulesheet.HasStringRule = true
+	}).Return(nil)
+	syncs := new(mocks_services.Syncs)
+	syncs.On("Last", ctx, uint(1)).Return(nil, nil)
+	service := services.NewRulesheets(repository, gitlabService, syncs)
+	result, err := service.ConvertLegacy(ctx, "1", false)
+	if err != nil {
+		t.Error("unexpected error on convert")
+		return
+	}
+
+	rules := *result.Rulesheet.Rules
+	assert.Equal(t, &dtos.Rule{Dynamic: "$total * 0.1"}, rules["discount"])
+	assert.Equal(t, &dtos.Rule{Value: "gold"}, rules["level"])
+	assert.Equal(t, &dtos.Rule{Dynamic: "false"}, rules["off"])
+	assert.Equal(t, map[string]interface{}{"max": &dtos.Rule{Value: int64(100)}}, rules["limits"])
+	assert.False(t, result.Rulesheet.HasStringRule)
+	assert.False(t, result.Committed)
+	if assert.Len(t, result.Errors, 2) {
+		assert.Equal(t, "rules.broken", result.Errors[0].Path)
+		assert.Equal(t, dtos.RuleErrorExpression, result.Errors[0].Tag)
+		// the broken expression is kept as text, so its reference isn't resolved
+		assert.Equal(t, "rules.broken.value", result.Errors[1].Path)
+		assert.Equal(t, dtos.RuleErrorReference, result.Errors[1].Tag)
+		assert.True(t, result.Errors[1].Warning)
+	}
+	repository.AssertNotCalled(t, "UpdateInTransaction")
+}
+
+// This tests that a rulesheet that is already structured isn't converted.
+func TestConvertLegacyWithStructuredRules(t *testing.T) {
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID: 1,
+	}
+	entity, err := models.NewRulesheetV1(*dto)
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("Get", ctx, "1").Return(&entity, nil)
+	gitlabService := new(mocks_services.Gitlab)
+	gitlabService.On("Fill", dto).Return(nil)
+	syncs := new(mocks_services.Syncs)
+	syncs.On("Last", ctx, uint(1)).Return(nil, nil)
+	service := services.NewRulesheets(repository, gitlabService, syncs)
+	_, err = service.ConvertLegacy(ctx, "1", true)
+	assert.ErrorIs(t, err, services.ErrNotLegacy)
+}
+
+// This tests the run of the test cases committed on a rulesheet, reporting the features whose computed
+// values don't match the expected ones.
+func TestRunTestsSuccess(t *testing.T) {
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID: 1,
+	}
+	entity, err := models.NewRulesheetV1(*dto)
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("Get", ctx, "1").Return(&entity, nil)
+	gitlabService := new(mocks_services.Gitlab)
+	gitlabService.On("ResolveVersion", dto, "2").Return("sha2", nil)
+	gitlabService.On("FillRef", dto, "sha2").Run(func(args mock.Arguments) {
+		rulesheet := args.Get(0).(*dtos.Rulesheet)
+		rulesheet.Version = "2"
+		rules := map[string]interface{}{
+			"discount": []interface{}{
+				map[string]interface{}{"condition": "$total > 100", "value": 0.1},
+				map[string]interface{}{"value": 0},
+			},
+		}
+		rulesheet.Rules = &rules
+		tests := []dtos.TestCase{
+			{Name: "big order", Parameters: map[string]interface{}{"total": 150.0}, Expected: map[string]interface{}{"discount": 0.1}},
+			{Name: "small order", Parameters: map[string]interface{}{"total": 50.0}, Expected: map[string]interface{}{"discount": 0.05}},
+		}
+		rulesheet.Tests = &tests
+	}).Return(nil)
+	service := services.NewRulesheets(repository, gitlabService, nil)
+	result, err := service.RunTests(ctx, "1", "2")
+	if err != nil {
+		t.Error("unexpected error on run tests")
+		return
+	}
+
+	assert.Equal(t, "2", result.Version)
+	assert.Equal(t, 1, result.Passed)
+	assert.Equal(t, 1, result.Failed)
+	assert.True(t, result.Results[0].Passed)
+	assert.False(t, result.Results[1].Passed)
+	assert.Equal(t, []*dtos.TestFailure{{Feature: "discount", Expected: 0.05, Actual: 0.0}}, result.Results[1].Failures)
+}
+
+// This tests the listing of the pending changes of a rulesheet.
+func TestListChangesSuccess(t *testing.T) {
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID: 1,
+	}
+	entity, err := models.NewRulesheetV1(*dto)
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	changes := []*dtos.ChangeRequest{{ID: 5, Branch: "featws/change-2-1"}}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("Get", ctx, "1").Return(&entity, nil)
+	gitlabService := new(mocks_services.Gitlab)
+	gitlabService.On("ListChangeRequests", dto).Return(changes, nil)
+	service := services.NewRulesheets(repository, gitlabService, nil)
+	result, err := service.ListChanges(ctx, "1")
+	if err != nil {
+		t.Error("unexpected error on list changes")
+	}
+	assert.Equal(t, changes, result)
+}
+
+// This tests the publishing of a pending change: the rulesheet is returned with the published content.
+func TestPublishChangeSuccess(t *testing.T) {
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID: 1,
+	}
+	entity, err := models.NewRulesheetV1(*dto)
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("Get", ctx, "1").Return(&entity, nil)
+	gitlabService := new(mocks_services.Gitlab)
+	gitlabService.On("PublishChangeRequest", dto, 5).Return(nil)
+	gitlabService.On("Fill", dto).Run(func(args mock.Arguments) {
+		args.Get(0).(*dtos.Rulesheet).Version = "2"
+	}).Return(nil)
+	service := services.NewRulesheets(repository, gitlabService, nil)
+	result, err := service.PublishChange(ctx, "1", 5)
+	if err != nil {
+		t.Error("unexpected error on publish change")
+		return
+	}
+	assert.Equal(t, "2", result.Version)
+}
+
+// This tests the error handling of the PublishChange method in a Rulesheets service when the change isn't approved.
+func TestPublishChangeWithNotApproved(t *testing.T) {
+	ctx := context.Background()
+	dto := &dtos.Rulesheet{
+		ID: 1,
+	}
+	entity, err := models.NewRulesheetV1(*dto)
+	if err != nil {
+		t.Error("unexpected error on model creation")
+	}
+	repository := new(mocks_repository.Rulesheets)
+	repository.On("Get", ctx, "1").Return(&entity, nil)
+	gitlabService := new(mocks_services.Gitlab)
+	gitlabService.On("PublishChangeRequest", dto, 5).Return(services.ErrChangeRequestNotApproved)
+	service := services.NewRulesheets(repository, gitlabService, nil)
+	_, err = service.PublishChange(ctx, "1", 5)
+	assert.ErrorIs(t, err, services.ErrChangeRequestNotApproved)
+	gitlabService.AssertNotCalled(t, "Fill", mock.Anything)
+}
+
+// This tests that the change methods return `ErrNotSupported` when the storage backend doesn't support the review mode.
+func TestListChangesWithBackendNotSupported(t *testing.T) {
+	ctx := context.Background()
+	repository := new(mocks_repository.Rulesheets)
+	backend := new(mocks_services.Backend)
+	service := services.NewRulesheets(repository, backend, nil)
+	_, err := service.ListChanges(ctx, "1")
+	assert.ErrorIs(t, err, services.ErrNotSupported)
+	err = service.DiscardChange(ctx, "1", 5)
+	assert.ErrorIs(t, err, services.ErrNotSupported)
+}
@@ -0,0 +1,425 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bancodobrasil/featws-api/config"
+	"github.com/bancodobrasil/featws-api/dtos"
+	"github.com/bancodobrasil/featws-api/models"
+	payloads "github.com/bancodobrasil/featws-api/payloads/v1"
+	"github.com/bancodobrasil/featws-api/repository"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// syncLease is how long a worker holds the lock of a sync it's pushing. A sync locked by a worker that
+// died is pushed again once the lock expires.
+const syncLease = 5 * time.Minute
+
+// syncBatchSize is the number of rulesheets pushed by each run of the sync worker.
+const syncBatchSize = 100
+
+// ErrSyncNotFound is returned when a sync doesn't exist on the outbox.
+var ErrSyncNotFound = errors.New("sync not found")
+
+// ErrSyncNotFailed is returned when a sync that didn't fail is retried or discarded.
+var ErrSyncNotFailed = errors.New("only a failed sync can be retried or discarded")
+
+// Syncs interface defines the methods of the outbox of the changes of the rulesheets. Each change is written
+// on the outbox within the transaction that changes the rulesheet on the database and is pushed to the
+// storage backend afterwards, right away or by the sync worker, retrying the failed attempts.
+//
+// Property:
+//   - EnqueueInTransaction: The method writes a change of a rulesheet on the outbox, within the transaction of the database that changes the rulesheet, with the commit message used to save it.
+//   - SyncRulesheet: The method pushes the pending changes of a rulesheet, in the order they were written, and returns its last sync. The error is the one of the attempt that failed, if any.
+//   - Process: The method pushes the pending changes that are due, of every rulesheet, and returns the number of rulesheets processed. It's called by the sync worker.
+//   - Last: The method returns the last sync of a rulesheet, which holds its sync status, or nil if there's none.
+//   - Find: The method lists the syncs with the given status, or all of them if the status is empty.
+//   - Retry: The method marks a failed sync as pending again and pushes it right away. A sync that failed on a version conflict fails again, unless it's forced, when it's saved over the current version. It returns `ErrSyncNotFound` if the sync doesn't exist and `ErrSyncNotFailed` if it didn't fail.
+//   - Discard: The method marks a failed sync as discarded, so it's never pushed, and pushes the next changes of its rulesheet. It returns `ErrSyncNotFound` if the sync doesn't exist and `ErrSyncNotFailed` if it didn't fail.
+type Syncs interface {
+	EnqueueInTransaction(ctx context.Context, db *gorm.DB, rulesheet *dtos.Rulesheet, commitMessage string) error
+	SyncRulesheet(ctx context.Context, rulesheetID uint) (*dtos.Sync, error)
+	Process(ctx context.Context) (int, error)
+	Last(ctx context.Context, rulesheetID uint) (*dtos.Sync, error)
+	Find(ctx context.Context, status string, options *FindOptions) ([]*dtos.Sync, error)
+	Retry(ctx context.Context, id string, force bool) (*dtos.Sync, error)
+	Discard(ctx context.Context, id string) (*dtos.Sync, error)
+}
+
+// syncPayload is the content of a rulesheet written on the outbox. The rulesheet is kept as it's received by
+// the API, so it's rebuilt the same way when it's pushed.
+//
+// Property:
+//   - Rulesheet: the rulesheet as received by the API.
+//   - BaseVersion: the version the changes of the rulesheet were based on, if any.
+//   - RemoveLegacyRules: whether the legacy rules.featws file is removed, on the conversion of its string rules.
+type syncPayload struct {
+	Rulesheet         payloads.Rulesheet `json:"rulesheet"`
+	BaseVersion       string             `json:"baseVersion,omitempty"`
+	RemoveLegacyRules bool               `json:"removeLegacyRules,omitempty"`
+}
+
+// syncs implements the `Syncs` interface.
+//
+// Property:
+//   - cfg: The configuration, where `SyncInterval` and `SyncMaxAttempts` define the retries of the failed attempts.
+//   - repository: The repository of the outbox.
+//   - backend: The storage backend where the changes are pushed.
+type syncs struct {
+	cfg        *config.Config
+	repository repository.Syncs
+	backend    Backend
+}
+
+// NewSyncs creates a new instance of the outbox of the changes of the rulesheets.
+func NewSyncs(cfg *config.Config, repository repository.Syncs, backend Backend) Syncs {
+	return &syncs{
+		cfg:        cfg,
+		repository: repository,
+		backend:    backend,
+	}
+}
+
+// EnqueueInTransaction writes a pending sync with the content of the rulesheet on the outbox.
+func (ss *syncs) EnqueueInTransaction(ctx context.Context, db *gorm.DB, rulesheet *dtos.Rulesheet, commitMessage string) error {
+	payload, err := json.Marshal(syncPayload{
+		Rulesheet: payloads.Rulesheet{
+			ID:          rulesheet.ID,
+			Name:        rulesheet.Name,
+			Description: rulesheet.Description,
+			Slug:        rulesheet.Slug,
+			Features:    newFeaturesPayload(rulesheet.Features),
+			Parameters:  newParametersPayload(rulesheet.Parameters),
+			Rules:       rulesheet.Rules,
+			Tests:       newTestCasesPayload(rulesheet.Tests),
+		},
+		BaseVersion:       rulesheet.BaseVersion,
+		RemoveLegacyRules: rulesheet.RemoveLegacyRules,
+	})
+	if err != nil {
+		log.Errorf("Error on marshal the sync payload: %v", err)
+		return err
+	}
+
+	entity := &models.Sync{
+		RulesheetID:   rulesheet.ID,
+		CommitMessage: commitMessage,
+		Payload:       string(payload),
+		Status:        models.SyncPending,
+	}
+
+	err = ss.repository.CreateInTransaction(ctx, db, entity)
+	if err != nil {
+		log.Errorf("Error on create sync into repository: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// SyncRulesheet pushes the pending changes of a rulesheet and returns its last sync.
+func (ss *syncs) SyncRulesheet(ctx context.Context, rulesheetID uint) (*dtos.Sync, error) {
+	err := ss.push(ctx, rulesheetID)
+
+	last, lastErr := ss.Last(ctx, rulesheetID)
+	if lastErr != nil {
+		return nil, lastErr
+	}
+
+	return last, err
+}
+
+// Process pushes the pending changes that are due. A failed attempt is recorded on its sync and doesn't stop
+// the changes of the other rulesheets from being pushed.
+func (ss *syncs) Process(ctx context.Context) (int, error) {
+	ids, err := ss.repository.FindDue(ctx, time.Now(), syncBatchSize)
+	if err != nil {
+		log.Errorf("Error on find the pending syncs: %v", err)
+		return 0, err
+	}
+
+	for _, id := range ids {
+		err = ss.push(ctx, id)
+		if err != nil {
+			log.Warnf("Error on sync the rulesheet %d: %v", id, err)
+		}
+	}
+
+	return len(ids), nil
+}
+
+// Last returns the last sync of a rulesheet.
+func (ss *syncs) Last(ctx context.Context, rulesheetID uint) (*dtos.Sync, error) {
+	entity, err := ss.repository.GetLast(ctx, rulesheetID)
+	if err != nil {
+		log.Errorf("Error on fetch the last sync: %v", err)
+		return nil, err
+	}
+
+	if entity == nil {
+		return nil, nil
+	}
+
+	return newSyncDTO(entity), nil
+}
+
+// Find lists the syncs with the given status.
+func (ss *syncs) Find(ctx context.Context, status string, options *FindOptions) (result []*dtos.Sync, err error) {
+	var opts *repository.FindOptions
+
+	if options != nil {
+		opts = &repository.FindOptions{
+			Limit: options.Limit,
+			Page:  options.Page,
+		}
+	}
+
+	criteria := &repository.Criteria{}
+	if status != "" {
+		criteria.Conditions = append(criteria.Conditions, repository.Equal("status", status))
+	}
+
+	entities, err := ss.repository.Find(ctx, criteria, opts)
+	if err != nil {
+		log.Errorf("Error on fetch the syncs(find): %v", err)
+		return
+	}
+
+	result = make([]*dtos.Sync, 0)
+
+	for _, entity := range entities {
+		result = append(result, newSyncDTO(entity))
+	}
+
+	return
+}
+
+// Retry marks a failed sync as pending again, with no failed attempts, and pushes the changes of its
+// rulesheet, starting from it. The sync keeps the version its change is based on, so a sync that failed on a
+// version conflict fails again, unless it's forced: then it's retried without it, saving the change over the
+// current version.
+func (ss *syncs) Retry(ctx context.Context, id string, force bool) (*dtos.Sync, error) {
+	entity, err := ss.getFailed(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if force {
+		var payload syncPayload
+
+		err = json.Unmarshal([]byte(entity.Payload), &payload)
+		if err != nil {
+			log.Errorf("Error on unmarshal the sync payload: %v", err)
+			return nil, err
+		}
+
+		payload.BaseVersion = ""
+
+		content, err := json.Marshal(payload)
+		if err != nil {
+			log.Errorf("Error on marshal the sync payload: %v", err)
+			return nil, err
+		}
+		entity.Payload = string(content)
+	}
+
+	entity.Status = models.SyncPending
+	entity.Attempts = 0
+	entity.NextAttemptAt = nil
+
+	return ss.resume(ctx, entity)
+}
+
+// Discard marks a failed sync as discarded, so its change is never saved on the storage backend, and pushes
+// the next changes of its rulesheet, which were waiting for it.
+func (ss *syncs) Discard(ctx context.Context, id string) (*dtos.Sync, error) {
+	entity, err := ss.getFailed(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	entity.Status = models.SyncDiscarded
+	entity.NextAttemptAt = nil
+
+	return ss.resume(ctx, entity)
+}
+
+// getFailed returns the sync with the given ID, checking that it failed.
+func (ss *syncs) getFailed(ctx context.Context, id string) (*models.Sync, error) {
+	entity, err := ss.repository.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSyncNotFound
+		}
+		log.Errorf("Error on fetch sync(get): %v", err)
+		return nil, err
+	}
+
+	if entity.Status != models.SyncFailed {
+		return nil, ErrSyncNotFailed
+	}
+
+	return entity, nil
+}
+
+// resume updates a failed sync that was retried or discarded and pushes the changes of its rulesheet,
+// returning the sync as it's after the push.
+func (ss *syncs) resume(ctx context.Context, entity *models.Sync) (*dtos.Sync, error) {
+	_, err := ss.repository.Update(ctx, *entity)
+	if err != nil {
+		log.Errorf("Error on update sync: %v", err)
+		return nil, err
+	}
+
+	err = ss.push(ctx, entity.RulesheetID)
+	if err != nil {
+		log.Warnf("Error on push the syncs of the rulesheet %d: %v", entity.RulesheetID, err)
+	}
+
+	id := fmt.Sprintf("%d", entity.ID)
+	entity, err = ss.repository.Get(ctx, id)
+	if err != nil {
+		log.Errorf("Error on fetch sync(get): %v", err)
+		return nil, err
+	}
+
+	return newSyncDTO(entity), nil
+}
+
+// push pushes the pending changes of a rulesheet in order, stopping at the first one that isn't due, is
+// locked by another worker or fails. A failed sync holds the next ones until it's retried or discarded, so
+// the changes are never published out of order.
+func (ss *syncs) push(ctx context.Context, rulesheetID uint) error {
+	for {
+		entity, err := ss.repository.GetFirstUnsynced(ctx, rulesheetID)
+		if err != nil || entity == nil {
+			return err
+		}
+
+		now := time.Now()
+
+		if entity.Status == models.SyncFailed || (entity.NextAttemptAt != nil && entity.NextAttemptAt.After(now)) {
+			return nil
+		}
+
+		claimed, err := ss.repository.Claim(ctx, entity.ID, now, now.Add(syncLease))
+		if err != nil || !claimed {
+			return err
+		}
+
+		err = ss.save(entity)
+
+		entity.LockedUntil = nil
+		if err == nil {
+			entity.Status = models.SyncSynced
+			entity.Error = ""
+			entity.NextAttemptAt = nil
+			entity.SyncedAt = &now
+		} else {
+			entity.Attempts++
+			entity.Error = err.Error()
+
+			// a conflict won't go away by trying again
+			if errors.Is(err, ErrVersionConflict) || entity.Attempts >= ss.cfg.SyncMaxAttempts {
+				entity.Status = models.SyncFailed
+				entity.NextAttemptAt = nil
+			} else {
+				next := now.Add(ss.backoff(entity.Attempts))
+				entity.NextAttemptAt = &next
+			}
+		}
+
+		_, updateErr := ss.repository.Update(ctx, *entity)
+		if updateErr != nil {
+			log.Errorf("Error on update sync: %v", updateErr)
+			return updateErr
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// save saves the content of a sync on the storage backend, recording the version it published.
+func (ss *syncs) save(entity *models.Sync) error {
+	var payload syncPayload
+
+	err := json.Unmarshal([]byte(entity.Payload), &payload)
+	if err != nil {
+		log.Errorf("Error on unmarshal the sync payload: %v", err)
+		return err
+	}
+
+	rulesheet, err := dtos.NewRulesheetV1(payload.Rulesheet)
+	if err != nil {
+		log.Errorf("Error on define entity: %v", err)
+		return err
+	}
+	rulesheet.BaseVersion = payload.BaseVersion
+	rulesheet.RemoveLegacyRules = payload.RemoveLegacyRules
+
+	err = ss.backend.Save(&rulesheet, entity.CommitMessage)
+	if err != nil {
+		log.Errorf("Error on save the rulesheet into repository: %v", err)
+		return err
+	}
+
+	entity.Version = rulesheet.Version
+
+	return nil
+}
+
+// backoff returns how long to wait before the next attempt: twice as long as the previous one, starting
+// from the interval of the sync worker.
+func (ss *syncs) backoff(attempts int) time.Duration {
+	if attempts > 10 {
+		attempts = 10
+	}
+	return ss.cfg.SyncInterval * time.Duration(1<<(attempts-1))
+}
+
+// RunSyncWorker runs the sync worker, which pushes the pending changes of the rulesheets every interval,
+// until the context is done. The worker doesn't run without a positive interval.
+func RunSyncWorker(ctx context.Context, syncs Syncs, interval time.Duration) {
+	if interval <= 0 {
+		log.Errorf("The sync worker isn't running, the interval must be positive: %s", interval)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, err := syncs.Process(ctx)
+			if err != nil {
+				log.Errorf("Error on process the pending syncs: %v", err)
+			}
+		}
+	}
+}
+
+// The function creates a new DTO for a sync of the outbox.
+func newSyncDTO(entity *models.Sync) *dtos.Sync {
+	return &dtos.Sync{
+		ID:            entity.ID,
+		RulesheetID:   entity.RulesheetID,
+		CommitMessage: entity.CommitMessage,
+		Status:        entity.Status,
+		Attempts:      entity.Attempts,
+		Error:         entity.Error,
+		Version:       entity.Version,
+		NextAttemptAt: entity.NextAttemptAt,
+		CreatedAt:     &entity.CreatedAt,
+		SyncedAt:      entity.SyncedAt,
+	}
+}
@@ -0,0 +1,385 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bancodobrasil/featws-api/config"
+	"github.com/bancodobrasil/featws-api/dtos"
+	mocks_repository "github.com/bancodobrasil/featws-api/mocks/repository"
+	mocks_services "github.com/bancodobrasil/featws-api/mocks/services"
+	"github.com/bancodobrasil/featws-api/models"
+	"github.com/bancodobrasil/featws-api/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// The function returns a pointer to a configuration object with the retries of the sync worker.
+func SetupSyncConfig() *config.Config {
+	return &config.Config{
+		SyncInterval:    time.Minute,
+		SyncMaxAttempts: 3,
+	}
+}
+
+// The function returns a pending sync of the rulesheet 1 with the given attempts.
+func SetupPendingSync(attempts int) *models.Sync {
+	sync := &models.Sync{
+		RulesheetID:   1,
+		CommitMessage: "[FEATWS BOT] Update Repo",
+		Payload:       `{"rulesheet":{"id":1,"name":"test","rules":{"feat":{"condition":"$a > 1","value":true}}},"baseVersion":"1"}`,
+		Status:        models.SyncPending,
+		Attempts:      attempts,
+	}
+	sync.ID = 10
+	return sync
+}
+
+// The function returns the sync of the last call of the `Update` method of the repository mock.
+func lastUpdatedSync(repository *mocks_repository.Syncs) models.Sync {
+	var updated models.Sync
+	for _, call := range repository.Calls {
+		if call.Method == "Update" {
+			updated = call.Arguments.Get(1).(models.Sync)
+		}
+	}
+	return updated
+}
+
+// This tests that a rulesheet is written on the outbox as received by the API, so it's rebuilt the same way
+// when it's pushed.
+func TestEnqueueInTransactionSuccess(t *testing.T) {
+	ctx := context.Background()
+	rules := map[string]interface{}{
+		"feat": &dtos.Rule{Condition: "$a > 1", Value: true},
+	}
+	dto := &dtos.Rulesheet{
+		ID:          1,
+		Name:        "test",
+		Rules:       &rules,
+		BaseVersion: "1",
+	}
+
+	repository := new(mocks_repository.Syncs)
+	repository.On("CreateInTransaction", ctx, mock.Anything, mock.Anything).Return(nil)
+	service := services.NewSyncs(SetupSyncConfig(), repository, nil)
+
+	err := service.EnqueueInTransaction(ctx, nil, dto, "[FEATWS BOT] Update Repo")
+	assert.NoError(t, err)
+
+	created := repository.Calls[0].Arguments.Get(2).(*models.Sync)
+	assert.Equal(t, uint(1), created.RulesheetID)
+	assert.Equal(t, models.SyncPending, created.Status)
+	assert.Equal(t, "[FEATWS BOT] Update Repo", created.CommitMessage)
+	assert.JSONEq(t, SetupPendingSync(0).Payload, created.Payload)
+}
+
+// This tests that the pending syncs of a rulesheet are saved on the storage backend and marked as synced,
+// with the version they published.
+func TestSyncRulesheetSuccess(t *testing.T) {
+	ctx := context.Background()
+	sync := SetupPendingSync(0)
+
+	repository := new(mocks_repository.Syncs)
+	repository.On("GetFirstUnsynced", ctx, uint(1)).Return(sync, nil).Once()
+	repository.On("GetFirstUnsynced", ctx, uint(1)).Return(nil, nil)
+	repository.On("Claim", ctx, uint(10), mock.Anything, mock.Anything).Return(true, nil)
+	repository.On("Update", ctx, mock.Anything).Return(nil, nil)
+	repository.On("GetLast", ctx, uint(1)).Return(sync, nil)
+
+	backend := new(mocks_services.Backend)
+	backend.On("Save", mock.Anything, "[FEATWS BOT] Update Repo").Run(func(args mock.Arguments) {
+		args.Get(0).(*dtos.Rulesheet).Version = "2"
+	}).Return(nil)
+
+	service := services.NewSyncs(SetupSyncConfig(), repository, backend)
+	result, err := service.SyncRulesheet(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, models.SyncSynced, result.Status)
+	assert.Equal(t, "2", result.Version)
+
+	saved := backend.Calls[0].Arguments.Get(0).(*dtos.Rulesheet)
+	assert.Equal(t, "1", saved.BaseVersion)
+	assert.Equal(t, &dtos.Rule{Condition: "$a > 1", Value: true}, (*saved.Rules)["feat"])
+
+	updated := lastUpdatedSync(repository)
+	assert.Equal(t, models.SyncSynced, updated.Status)
+	assert.NotNil(t, updated.SyncedAt)
+	assert.Nil(t, updated.LockedUntil)
+}
+
+// This tests that a failed attempt keeps the sync pending, to be retried after the interval of the worker.
+func TestSyncRulesheetWithErrorOnSave(t *testing.T) {
+	ctx := context.Background()
+	sync := SetupPendingSync(0)
+
+	repository := new(mocks_repository.Syncs)
+	repository.On("GetFirstUnsynced", ctx, uint(1)).Return(sync, nil)
+	repository.On("Claim", ctx, uint(10), mock.Anything, mock.Anything).Return(true, nil)
+	repository.On("Update", ctx, mock.Anything).Return(nil, nil)
+	repository.On("GetLast", ctx, uint(1)).Return(sync, nil)
+
+	backend := new(mocks_services.Backend)
+	backend.On("Save", mock.Anything, mock.Anything).Return(errors.New("error on save"))
+
+	service := services.NewSyncs(SetupSyncConfig(), repository, backend)
+	result, err := service.SyncRulesheet(ctx, 1)
+	assert.EqualError(t, err, "error on save")
+	assert.Equal(t, models.SyncPending, result.Status)
+	assert.Equal(t, 1, result.Attempts)
+	assert.Equal(t, "error on save", result.Error)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), *result.NextAttemptAt, 5*time.Second)
+}
+
+// This tests that a sync is marked as failed when it reaches the max attempts.
+func TestSyncRulesheetWithMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	sync := SetupPendingSync(2)
+
+	repository := new(mocks_repository.Syncs)
+	repository.On("GetFirstUnsynced", ctx, uint(1)).Return(sync, nil)
+	repository.On("Claim", ctx, uint(10), mock.Anything, mock.Anything).Return(true, nil)
+	repository.On("Update", ctx, mock.Anything).Return(nil, nil)
+	repository.On("GetLast", ctx, uint(1)).Return(sync, nil)
+
+	backend := new(mocks_services.Backend)
+	backend.On("Save", mock.Anything, mock.Anything).Return(errors.New("error on save"))
+
+	service := services.NewSyncs(SetupSyncConfig(), repository, backend)
+	result, err := service.SyncRulesheet(ctx, 1)
+	assert.Error(t, err)
+	assert.Equal(t, models.SyncFailed, result.Status)
+	assert.Equal(t, 3, result.Attempts)
+	assert.Nil(t, result.NextAttemptAt)
+}
+
+// This tests that a version conflict fails the sync right away, since retrying won't solve it.
+func TestSyncRulesheetWithVersionConflict(t *testing.T) {
+	ctx := context.Background()
+	sync := SetupPendingSync(0)
+
+	repository := new(mocks_repository.Syncs)
+	repository.On("GetFirstUnsynced", ctx, uint(1)).Return(sync, nil)
+	repository.On("Claim", ctx, uint(10), mock.Anything, mock.Anything).Return(true, nil)
+	repository.On("Update", ctx, mock.Anything).Return(nil, nil)
+	repository.On("GetLast", ctx, uint(1)).Return(sync, nil)
+
+	backend := new(mocks_services.Backend)
+	backend.On("Save", mock.Anything, mock.Anything).Return(services.ErrVersionConflict)
+
+	service := services.NewSyncs(SetupSyncConfig(), repository, backend)
+	result, err := service.SyncRulesheet(ctx, 1)
+	assert.ErrorIs(t, err, services.ErrVersionConflict)
+	assert.Equal(t, models.SyncFailed, result.Status)
+}
+
+// This tests that a failed sync holds the next changes of the rulesheet until it's retried.
+func TestSyncRulesheetHeldByFailedSync(t *testing.T) {
+	ctx := context.Background()
+	sync := SetupPendingSync(3)
+	sync.Status = models.SyncFailed
+
+	repository := new(mocks_repository.Syncs)
+	repository.On("GetFirstUnsynced", ctx, uint(1)).Return(sync, nil)
+	repository.On("GetLast", ctx, uint(1)).Return(SetupPendingSync(0), nil)
+
+	backend := new(mocks_services.Backend)
+
+	service := services.NewSyncs(SetupSyncConfig(), repository, backend)
+	result, err := service.SyncRulesheet(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, models.SyncPending, result.Status)
+	repository.AssertNotCalled(t, "Claim", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	backend.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+// This tests that a sync claimed by another worker isn't pushed twice.
+func TestSyncRulesheetClaimedByAnotherWorker(t *testing.T) {
+	ctx := context.Background()
+	sync := SetupPendingSync(0)
+
+	repository := new(mocks_repository.Syncs)
+	repository.On("GetFirstUnsynced", ctx, uint(1)).Return(sync, nil)
+	repository.On("Claim", ctx, uint(10), mock.Anything, mock.Anything).Return(false, nil)
+	repository.On("GetLast", ctx, uint(1)).Return(sync, nil)
+
+	backend := new(mocks_services.Backend)
+
+	service := services.NewSyncs(SetupSyncConfig(), repository, backend)
+	_, err := service.SyncRulesheet(ctx, 1)
+	assert.NoError(t, err)
+	backend.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+// This tests that the worker pushes the due syncs of every rulesheet, even when one of them fails.
+func TestProcessSuccess(t *testing.T) {
+	ctx := context.Background()
+	first := SetupPendingSync(0)
+	second := SetupPendingSync(0)
+	second.ID = 11
+	second.RulesheetID = 2
+
+	repository := new(mocks_repository.Syncs)
+	repository.On("FindDue", ctx, mock.Anything, mock.Anything).Return([]uint{1, 2}, nil)
+	repository.On("GetFirstUnsynced", ctx, uint(1)).Return(first, nil)
+	repository.On("GetFirstUnsynced", ctx, uint(2)).Return(second, nil).Once()
+	repository.On("GetFirstUnsynced", ctx, uint(2)).Return(nil, nil)
+	repository.On("Claim", ctx, mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	repository.On("Update", ctx, mock.Anything).Return(nil, nil)
+
+	backend := new(mocks_services.Backend)
+	backend.On("Save", mock.Anything, mock.Anything).Return(errors.New("error on save")).Once()
+	backend.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	service := services.NewSyncs(SetupSyncConfig(), repository, backend)
+	processed, err := service.Process(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, processed)
+	backend.AssertNumberOfCalls(t, "Save", 2)
+}
+
+// This tests that a failed sync is marked as pending again, with no attempts, and pushed right away.
+func TestRetrySuccess(t *testing.T) {
+	ctx := context.Background()
+	sync := SetupPendingSync(3)
+	sync.Status = models.SyncFailed
+	sync.Error = "error on save"
+
+	repository := new(mocks_repository.Syncs)
+	repository.On("Get", ctx, "10").Return(sync, nil)
+	repository.On("Update", ctx, mock.Anything).Return(nil, nil)
+	repository.On("GetFirstUnsynced", ctx, uint(1)).Return(sync, nil).Once()
+	repository.On("GetFirstUnsynced", ctx, uint(1)).Return(nil, nil)
+	repository.On("Claim", ctx, uint(10), mock.Anything, mock.Anything).Return(true, nil)
+
+	backend := new(mocks_services.Backend)
+	backend.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	service := services.NewSyncs(SetupSyncConfig(), repository, backend)
+	result, err := service.Retry(ctx, "10", false)
+	assert.NoError(t, err)
+	assert.Equal(t, models.SyncSynced, result.Status)
+	assert.Equal(t, "", result.Error)
+	assert.Equal(t, 0, result.Attempts)
+}
+
+// This tests that only a failed sync can be retried.
+func TestRetryWithSyncNotFailed(t *testing.T) {
+	ctx := context.Background()
+
+	repository := new(mocks_repository.Syncs)
+	repository.On("Get", ctx, "10").Return(SetupPendingSync(1), nil)
+
+	service := services.NewSyncs(SetupSyncConfig(), repository, nil)
+	_, err := service.Retry(ctx, "10", false)
+	assert.ErrorIs(t, err, services.ErrSyncNotFailed)
+	repository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// This tests that a sync that failed on a version conflict keeps the version its change is based on when
+// it's retried, so it fails again instead of overwriting the current version.
+func TestRetryWithVersionConflict(t *testing.T) {
+	ctx := context.Background()
+	sync := SetupPendingSync(1)
+	sync.Status = models.SyncFailed
+	sync.Error = services.ErrVersionConflict.Error() + ": the changes are based on version 1, but the current version is 2"
+
+	repository := new(mocks_repository.Syncs)
+	repository.On("Get", ctx, "10").Return(sync, nil)
+	repository.On("Update", ctx, mock.Anything).Return(nil, nil)
+	repository.On("GetFirstUnsynced", ctx, uint(1)).Return(sync, nil)
+	repository.On("Claim", ctx, uint(10), mock.Anything, mock.Anything).Return(true, nil)
+
+	backend := new(mocks_services.Backend)
+	backend.On("Save", mock.MatchedBy(func(rulesheet *dtos.Rulesheet) bool {
+		return rulesheet.BaseVersion == "1"
+	}), "[FEATWS BOT] Update Repo").Return(services.ErrVersionConflict)
+
+	service := services.NewSyncs(SetupSyncConfig(), repository, backend)
+	result, _ := service.Retry(ctx, "10", false)
+	if assert.NotNil(t, result) {
+		assert.Equal(t, models.SyncFailed, result.Status)
+	}
+	assert.Contains(t, lastUpdatedSync(repository).Payload, `"baseVersion":"1"`)
+	backend.AssertNumberOfCalls(t, "Save", 1)
+}
+
+// This tests that a forced retry of a sync that failed on a version conflict saves the change over the
+// current version, without the version it's based on.
+func TestRetryForcedWithVersionConflict(t *testing.T) {
+	ctx := context.Background()
+	sync := SetupPendingSync(1)
+	sync.Status = models.SyncFailed
+	sync.Error = services.ErrVersionConflict.Error() + ": the changes are based on version 1, but the current version is 2"
+
+	repository := new(mocks_repository.Syncs)
+	repository.On("Get", ctx, "10").Return(sync, nil)
+	repository.On("Update", ctx, mock.Anything).Return(nil, nil)
+	repository.On("GetFirstUnsynced", ctx, uint(1)).Return(sync, nil).Once()
+	repository.On("GetFirstUnsynced", ctx, uint(1)).Return(nil, nil)
+	repository.On("Claim", ctx, uint(10), mock.Anything, mock.Anything).Return(true, nil)
+
+	backend := new(mocks_services.Backend)
+	backend.On("Save", mock.MatchedBy(func(rulesheet *dtos.Rulesheet) bool {
+		return rulesheet.BaseVersion == ""
+	}), "[FEATWS BOT] Update Repo").Return(nil)
+
+	service := services.NewSyncs(SetupSyncConfig(), repository, backend)
+	result, err := service.Retry(ctx, "10", true)
+	assert.NoError(t, err)
+	assert.Equal(t, models.SyncSynced, result.Status)
+	backend.AssertNumberOfCalls(t, "Save", 1)
+}
+
+// This tests that a failed sync is discarded without being saved and the next changes of its rulesheet,
+// which were waiting for it, are pushed.
+func TestDiscardSuccess(t *testing.T) {
+	ctx := context.Background()
+	sync := SetupPendingSync(3)
+	sync.Status = models.SyncFailed
+	sync.Error = "error on save"
+	next := SetupPendingSync(0)
+	next.ID = 11
+
+	repository := new(mocks_repository.Syncs)
+	repository.On("Get", ctx, "10").Return(sync, nil)
+	repository.On("Update", ctx, mock.Anything).Return(nil, nil)
+	repository.On("GetFirstUnsynced", ctx, uint(1)).Return(next, nil).Once()
+	repository.On("GetFirstUnsynced", ctx, uint(1)).Return(nil, nil)
+	repository.On("Claim", ctx, uint(11), mock.Anything, mock.Anything).Return(true, nil)
+
+	backend := new(mocks_services.Backend)
+	backend.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	service := services.NewSyncs(SetupSyncConfig(), repository, backend)
+	result, err := service.Discard(ctx, "10")
+	assert.NoError(t, err)
+	assert.Equal(t, models.SyncDiscarded, result.Status)
+	assert.Equal(t, models.SyncDiscarded, repository.Calls[1].Arguments.Get(1).(models.Sync).Status)
+	assert.Equal(t, models.SyncSynced, lastUpdatedSync(repository).Status)
+	assert.Equal(t, uint(11), lastUpdatedSync(repository).ID)
+	backend.AssertNumberOfCalls(t, "Save", 1)
+}
+
+// This tests that only a failed sync can be discarded.
+func TestDiscardWithSyncNotFailed(t *testing.T) {
+	ctx := context.Background()
+
+	repository := new(mocks_repository.Syncs)
+	repository.On("Get", ctx, "10").Return(SetupPendingSync(1), nil)
+
+	service := services.NewSyncs(SetupSyncConfig(), repository, nil)
+	_, err := service.Discard(ctx, "10")
+	assert.ErrorIs(t, err, services.ErrSyncNotFailed)
+	repository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// This tests that the sync worker doesn't run, instead of panicking, without a positive interval.
+func TestRunSyncWorkerWithoutInterval(t *testing.T) {
+	syncs := new(mocks_services.Syncs)
+
+	services.RunSyncWorker(context.Background(), syncs, 0)
+	syncs.AssertNotCalled(t, "Process", mock.Anything)
+}
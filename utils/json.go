@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// UnmarshalWithExtra decodes a JSON object into the fields of a struct, given as a pointer to a type without
+// a custom `UnmarshalJSON`, and returns the members of the object that aren't fields of the struct, so they
+// can be written back by `MarshalWithExtra`. It returns nil when there's no such member.
+func UnmarshalWithExtra(data []byte, fields interface{}) (map[string]json.RawMessage, error) {
+	err := json.Unmarshal(data, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make(map[string]json.RawMessage)
+	err = json.Unmarshal(data, &members)
+	if err != nil {
+		return nil, err
+	}
+
+	known := jsonFieldNames(reflect.TypeOf(fields).Elem())
+	for name := range members {
+		for _, field := range known {
+			// the fields are matched like encoding/json does, without case sensitivity
+			if strings.EqualFold(name, field) {
+				delete(members, name)
+				break
+			}
+		}
+	}
+
+	if len(members) == 0 {
+		return nil, nil
+	}
+	return members, nil
+}
+
+// MarshalWithExtra encodes the fields of a struct, given as a value of a type without a custom
+// `MarshalJSON`, as a JSON object with the extra members returned by `UnmarshalWithExtra`. The fields take
+// precedence over the extra members with the same name.
+func MarshalWithExtra(fields interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(fields)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	members := make(map[string]json.RawMessage, len(extra))
+	for name, value := range extra {
+		members[name] = value
+	}
+
+	known := make(map[string]json.RawMessage)
+	err = json.Unmarshal(data, &known)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range known {
+		members[name] = value
+	}
+
+	return json.Marshal(members)
+}
+
+// The function returns the names of the members of a JSON object that are decoded into the fields of a
+// struct type.
+func jsonFieldNames(t reflect.Type) []string {
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}